@@ -51,6 +51,8 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
   const mysqlTopology = Form.useWatch('mysqlTopology', form) || 'single';
   const mongoTopology = Form.useWatch('mongoTopology', form) || 'single';
   const mongoSrv = Form.useWatch('mongoSrv', form) || false;
+  const redisTopology = Form.useWatch('redisTopology', form) || 'single';
+  const redisUseTLS = Form.useWatch('useTLS', form) || false;
 
   const parseHostPort = (raw: string, defaultPort: number): { host: string; port: number } | null => {
       const text = String(raw || '').trim();
@@ -465,6 +467,8 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
               const primaryPort = primaryAddress?.port || Number(config.port || defaultPort);
               const mysqlReplicaHosts = (configType === 'mysql' || configType === 'mariadb' || configType === 'sphinx') ? normalizedHosts.slice(1) : [];
               const mongoHosts = configType === 'mongodb' ? normalizedHosts.slice(1) : [];
+              const redisIsCluster = configType === 'redis' && String(config.topology || '').toLowerCase() === 'cluster';
+              const redisIsSentinel = configType === 'redis' && String(config.topology || '').toLowerCase() === 'sentinel';
               const mysqlIsReplica = String(config.topology || '').toLowerCase() === 'replica' || mysqlReplicaHosts.length > 0;
               const mongoIsReplica = String(config.topology || '').toLowerCase() === 'replica' || mongoHosts.length > 0 || !!config.replicaSet;
               form.setFieldsValue({
@@ -500,13 +504,25 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
                   mongoAuthMechanism: config.mongoAuthMechanism || '',
                   savePassword: config.savePassword !== false,
                   mongoReplicaUser: config.mongoReplicaUser || '',
-                  mongoReplicaPassword: config.mongoReplicaPassword || ''
+                  mongoReplicaPassword: config.mongoReplicaPassword || '',
+                  redisTopology: redisIsCluster ? 'cluster' : (redisIsSentinel ? 'sentinel' : 'single'),
+                  redisClusterHosts: redisIsCluster ? normalizedHosts.slice(1) : [],
+                  redisSentinelHosts: redisIsSentinel ? normalizedHosts.slice(1) : [],
+                  redisUser: config.redisUser || '',
+                  sentinelMaster: config.sentinelMaster || '',
+                  sentinelPassword: config.sentinelPassword || '',
+                  useTLS: !!config.useTLS,
+                  tlsCAPath: config.tls?.caPath || '',
+                  tlsCertPath: config.tls?.certPath || '',
+                  tlsKeyPath: config.tls?.keyPath || '',
+                  tlsServerName: config.tls?.serverName || '',
+                  tlsSkipVerify: !!config.tls?.skipVerify
               });
               setUseSSH(config.useSSH || false);
               setDbType(configType);
               // 如果是 Redis 编辑模式，设置已保存的 Redis 数据库列表
               if (configType === 'redis') {
-                  setRedisDbList(Array.from({ length: 16 }, (_, i) => i));
+                  setRedisDbList(redisIsCluster ? [0] : Array.from({ length: 16 }, (_, i) => i));
               }
           } else {
               // Create mode: Start at step 1
@@ -597,8 +613,8 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
           if (res.success) {
               setTestResult({ type: 'success', message: res.message });
               if (isRedisType) {
-                  // Redis: generate database list 0-15
-                  setRedisDbList(Array.from({ length: 16 }, (_, i) => i));
+                  // Redis: generate database list 0-15, cluster mode only has db0
+                  setRedisDbList(values.redisTopology === 'cluster' ? [0] : Array.from({ length: 16 }, (_, i) => i));
               } else {
                   // Other databases: fetch database list
                   const dbRes = await DBGetDatabases(config as any);
@@ -696,7 +712,7 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
       const primaryPort = parsedPrimary?.port || defaultPort;
 
       let hosts: string[] = [];
-      let topology: 'single' | 'replica' | undefined;
+      let topology: 'single' | 'replica' | 'cluster' | undefined;
       let replicaSet = '';
       let authSource = '';
       let readPreference = '';
@@ -750,6 +766,22 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
           mongoAuthMechanism = String(mergedValues.mongoAuthMechanism || '').trim().toUpperCase();
       }
 
+      if (type === 'redis') {
+          if (mergedValues.redisTopology === 'cluster') {
+              const seeds = normalizeAddressList(mergedValues.redisClusterHosts, defaultPort);
+              hosts = normalizeAddressList([`${primaryHost}:${primaryPort}`, ...seeds], defaultPort);
+              topology = 'cluster';
+          } else if (mergedValues.redisTopology === 'sentinel') {
+              const sentinels = normalizeAddressList(mergedValues.redisSentinelHosts, 26379);
+              hosts = normalizeAddressList([`${primaryHost}:${primaryPort}`, ...sentinels], 26379);
+              topology = 'sentinel';
+          } else {
+              topology = 'single';
+          }
+      }
+      const isRedisConfig = type === 'redis';
+      const redisSentinel = isRedisConfig && topology === 'sentinel';
+
       const sshConfig = mergedValues.useSSH ? {
           host: mergedValues.sshHost,
           port: Number(mergedValues.sshPort),
@@ -785,6 +817,17 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
           mongoAuthMechanism: mongoAuthMechanism,
           mongoReplicaUser: mongoReplicaUser,
           mongoReplicaPassword: keepPassword ? mongoReplicaPassword : "",
+          redisUser: isRedisConfig ? String(mergedValues.redisUser || '').trim() : "",
+          sentinelMaster: redisSentinel ? String(mergedValues.sentinelMaster || '').trim() : "",
+          sentinelPassword: redisSentinel && keepPassword ? (mergedValues.sentinelPassword || "") : "",
+          useTLS: isRedisConfig && !!mergedValues.useTLS,
+          tls: isRedisConfig && mergedValues.useTLS ? {
+              caPath: String(mergedValues.tlsCAPath || '').trim(),
+              certPath: String(mergedValues.tlsCertPath || '').trim(),
+              keyPath: String(mergedValues.tlsKeyPath || '').trim(),
+              serverName: String(mergedValues.tlsServerName || '').trim(),
+              skipVerify: !!mergedValues.tlsSkipVerify,
+          } : {},
       };
   };
 
@@ -810,6 +853,13 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
               mysqlReplicaPassword: '',
               mongoReplicaUser: '',
               mongoReplicaPassword: '',
+              redisTopology: 'single',
+              redisClusterHosts: [],
+              redisSentinelHosts: [],
+              redisUser: '',
+              sentinelMaster: '',
+              sentinelPassword: '',
+              useTLS: false,
           });
       }
 
@@ -921,6 +971,10 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
             mysqlReplicaPassword: '',
             mongoReplicaUser: '',
             mongoReplicaPassword: '',
+            redisTopology: 'single',
+            redisClusterHosts: [],
+            redisSentinelHosts: [],
+            useTLS: false,
         }}
         onValuesChange={(changed) => {
             if (testResult) {
@@ -931,6 +985,12 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
                 setUriFeedback(null);
             }
             if (changed.useSSH !== undefined) setUseSSH(changed.useSSH);
+            if (changed.redisTopology !== undefined) {
+                // Sentinels listen on 26379 by default
+                const port = Number(form.getFieldValue('port'));
+                if (changed.redisTopology === 'sentinel' && port === 6379) form.setFieldsValue({ port: 26379 });
+                if (changed.redisTopology !== 'sentinel' && port === 26379) form.setFieldsValue({ port: 6379 });
+            }
             // Type change handled by step 1, but keep sync if select changes (hidden now)
             if (changed.type !== undefined) setDbType(changed.type);
             if (
@@ -1159,12 +1219,85 @@ const ConnectionModal: React.FC<{ open: boolean; onClose: () => void; initialVal
         </>
         )}
 
-        {/* Redis specific: password only, no username */}
+        {/* Redis specific: optional ACL username, sentinel and TLS */}
         {isRedis && (
         <>
-            <Form.Item name="password" label="密码 (可选)">
-              <Input.Password placeholder="Redis 密码（如果设置了 requirepass）" />
+            <Form.Item name="redisTopology" label="连接模式">
+                <Select
+                    options={[
+                        { value: 'single', label: '单机模式' },
+                        { value: 'cluster', label: '集群模式（Redis Cluster）' },
+                        { value: 'sentinel', label: '哨兵模式（Sentinel）' },
+                    ]}
+                />
+            </Form.Item>
+            {redisTopology === 'cluster' && (
+                <Form.Item
+                    name="redisClusterHosts"
+                    label="其他集群节点"
+                    help="上方主机地址作为第一个种子节点；可再填写其余节点，格式：host:port。集群模式只有 db0"
+                >
+                    <Select mode="tags" placeholder="例如：10.10.0.31:6379、10.10.0.32:6379" tokenSeparators={[',', ';', ' ']} />
+                </Form.Item>
+            )}
+            {redisTopology === 'sentinel' && (
+            <>
+                <Form.Item
+                    name="redisSentinelHosts"
+                    label="其他哨兵地址"
+                    help="上方主机地址填写第一个哨兵（默认端口 26379）；可再填写其余哨兵，格式：host:port"
+                >
+                    <Select mode="tags" placeholder="例如：10.10.0.41:26379、10.10.0.42:26379" tokenSeparators={[',', ';', ' ']} />
+                </Form.Item>
+                <div style={{ display: 'flex', gap: 16 }}>
+                    <Form.Item
+                        name="sentinelMaster"
+                        label="主节点名称 (Master Name)"
+                        rules={[{ required: true, message: '请输入 Sentinel 主节点名称' }]}
+                        style={{ flex: 1 }}
+                    >
+                        <Input placeholder="例如：mymaster" />
+                    </Form.Item>
+                    <Form.Item name="sentinelPassword" label="哨兵密码 (可选)" style={{ flex: 1 }}>
+                        <Input.Password placeholder="Sentinel 的 requirepass" />
+                    </Form.Item>
+                </div>
+            </>
+            )}
+            <div style={{ display: 'flex', gap: 16 }}>
+                <Form.Item name="redisUser" label="用户名 (可选)" help="Redis 6+ ACL 用户，留空使用 default" style={{ flex: 1 }}>
+                    <Input placeholder="default" />
+                </Form.Item>
+                <Form.Item name="password" label="密码 (可选)" style={{ flex: 1 }}>
+                  <Input.Password placeholder="Redis 密码（如果设置了 requirepass）" />
+                </Form.Item>
+            </div>
+            <Form.Item name="useTLS" valuePropName="checked" style={{ marginBottom: redisUseTLS ? 12 : undefined }}>
+                <Checkbox>使用 TLS 加密连接</Checkbox>
             </Form.Item>
+            {redisUseTLS && (
+                <div style={{ padding: '12px', background: '#f5f5f5', borderRadius: 6, marginBottom: 12 }}>
+                    <Form.Item name="tlsCAPath" label="CA 证书路径 (可选)" help="留空使用系统证书">
+                        <Input placeholder="绝对路径，例如: /path/to/ca.pem" />
+                    </Form.Item>
+                    <div style={{ display: 'flex', gap: 16 }}>
+                        <Form.Item name="tlsCertPath" label="客户端证书 (可选)" style={{ flex: 1 }}>
+                            <Input placeholder="/path/to/client.crt" />
+                        </Form.Item>
+                        <Form.Item name="tlsKeyPath" label="客户端私钥 (可选)" style={{ flex: 1 }}>
+                            <Input placeholder="/path/to/client.key" />
+                        </Form.Item>
+                    </div>
+                    <div style={{ display: 'flex', gap: 16, alignItems: 'flex-end' }}>
+                        <Form.Item name="tlsServerName" label="服务器名称 (可选)" help="留空使用主机地址；通过 SSH 隧道时用于校验证书" style={{ flex: 1 }}>
+                            <Input placeholder="例如: redis.example.com" />
+                        </Form.Item>
+                        <Form.Item name="tlsSkipVerify" valuePropName="checked">
+                            <Checkbox>跳过证书校验</Checkbox>
+                        </Form.Item>
+                    </div>
+                </div>
+            )}
             <Form.Item name="includeRedisDatabases" label="显示数据库 (留空显示全部)" help="连接测试成功后可选择">
                 <Select mode="multiple" placeholder="选择显示的数据库 (0-15)" allowClear>
                     {redisDbList.map(db => <Select.Option key={db} value={db}>db{db}</Select.Option>)}
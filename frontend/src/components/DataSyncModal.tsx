@@ -1,7 +1,7 @@
 import React, { useState, useEffect, useRef } from 'react';
-import { Modal, Form, Select, Button, message, Steps, Transfer, Card, Alert, Divider, Typography, Progress, Checkbox, Table, Drawer, Tabs } from 'antd';
+import { Modal, Form, Select, Button, message, Steps, Transfer, Card, Alert, Divider, Typography, Progress, Checkbox, Table, Drawer, Tabs, Input, Tag } from 'antd';
 import { useStore } from '../store';
-import { DBGetDatabases, DBGetTables, DataSync, DataSyncAnalyze, DataSyncPreview } from '../../wailsjs/go/app/App';
+import { DBGetDatabases, DBGetTables, DataSync, DataSyncAnalyze, DataSyncPreview, SchemaCompare, SaveSQLScript, DBExecuteScript, ListSyncJobs, SaveSyncJob, DeleteSyncJob, RunSyncJob, ListSyncJobRuns, CancelSync, PauseSync, ResumeSync } from '../../wailsjs/go/app/App';
 import { SavedConnection } from '../types';
 import { EventsOn } from '../../wailsjs/runtime/runtime';
 
@@ -10,25 +10,59 @@ const { Step } = Steps;
 const { Option } = Select;
 
 type SyncLogEvent = { jobId: string; level?: string; message?: string; ts?: number };
-type SyncProgressEvent = { jobId: string; percent?: number; current?: number; total?: number; table?: string; stage?: string };
+type SyncProgressEvent = { jobId: string; percent?: number; current?: number; total?: number; table?: string; stage?: string; tableRows?: number; totalRows?: number };
 type SyncLogItem = { level: string; message: string; ts?: number };
 type TableDiffSummary = {
   table: string;
   pkColumn?: string;
+  keyColumns?: string[];
+  matchIndex?: string;
+  uniqueIndexes?: string[];
   canSync?: boolean;
   inserts?: number;
   updates?: number;
   deletes?: number;
   same?: number;
   message?: string;
+  chunksTotal?: number;
+  chunksSkipped?: number;
 };
-type TableOps = {
+type SchemaChange = { object: string; name: string; action: string; detail?: string };
+type TableSchemaDiff = { table: string; status: string; changes: SchemaChange[]; message?: string };
+type SchemaCompareResult = { success: boolean; message: string; tables: TableSchemaDiff[]; statements: string[]; script: string; notes?: string[] };
+type SyncJobSchedule = { type: 'manual' | 'cron' | 'interval'; cron?: string; intervalSeconds?: number };
+type SyncJob = { id: string; name: string; config: any; schedule: SyncJobSchedule; enabled: boolean; createdAt: number; updatedAt: number; lastRunAt?: number; nextRunAt?: number; lastRunId?: string };
+type SyncRunRecord = {
+  id: string;
+  jobId: string;
+  jobName: string;
+  trigger: string;
+  startedAt: number;
+  finishedAt: number;
+  durationMs: number;
+  success: boolean;
+  message: string;
+  tablesSynced: number;
+  rowsInserted: number;
+  rowsUpdated: number;
+  rowsDeleted: number;
+  resumable?: boolean;
+  cancelled?: boolean;
+  errorCount: number;
+  errors?: string[];
+  logs?: string[];
+};
+type CompareOptions = { floatEpsilon?: number; timePrecision?: '' | 'second' | 'millisecond' | 'microsecond'; whitespace?: '' | 'trim' | 'collapse'; ignoreCase?: boolean };
+type ColumnTransform = { column: string; type: 'constant' | 'expression' | 'mask' | 'cast'; value?: string };
+type TableMapping = { targetTable?: string; columnMap?: Record<string, string>; excludeColumns?: string[]; transforms?: ColumnTransform[]; where?: string; watermarkColumn?: string };
+type TableOps = TableMapping & {
   insert: boolean;
   update: boolean;
   delete: boolean;
   selectedInsertPks?: string[];
   selectedUpdatePks?: string[];
   selectedDeletePks?: string[];
+  matchIndex?: string;
 };
 
 const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open, onClose }) => {
@@ -53,26 +87,60 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
   const [syncContent, setSyncContent] = useState<'data' | 'schema' | 'both'>('data');
   const [syncMode, setSyncMode] = useState<string>('insert_update');
   const [autoAddColumns, setAutoAddColumns] = useState<boolean>(true);
+  const [dryRun, setDryRun] = useState<boolean>(false);
+  const [fullRefresh, setFullRefresh] = useState<boolean>(false);
+  // 并发与限流：0 表示使用默认值/不限制
+  const [throttle, setThrottle] = useState<{ workers: number; batchSize: number; maxRowsPerSecond: number; batchPauseMs: number }>({ workers: 1, batchSize: 0, maxRowsPerSecond: 0, batchPauseMs: 0 });
+  const [compareMode, setCompareMode] = useState<'row' | 'checksum'>('row');
+  const [compareOptions, setCompareOptions] = useState<CompareOptions>({});
   const [showSameTables, setShowSameTables] = useState<boolean>(false);
   const [analyzing, setAnalyzing] = useState<boolean>(false);
   const [diffTables, setDiffTables] = useState<TableDiffSummary[]>([]);
   const [tableOptions, setTableOptions] = useState<Record<string, TableOps>>({});
+  // 无主键表手动选择的唯一索引，重新对比差异时保留
+  const [matchIndexes, setMatchIndexes] = useState<Record<string, string>>({});
+  // 表名/字段映射、取值转换、过滤条件与增量水位，重新对比差异时保留
+  const [tableMappings, setTableMappings] = useState<Record<string, TableMapping>>({});
+  const [mappingTable, setMappingTable] = useState<string>('');
+  const [mappingDraft, setMappingDraft] = useState<{ targetTable: string; renames: string; exclude: string[]; transforms: string; where: string; watermarkColumn: string }>({ targetTable: '', renames: '', exclude: [], transforms: '', where: '', watermarkColumn: '' });
 
   const [previewOpen, setPreviewOpen] = useState(false);
   const [previewTable, setPreviewTable] = useState<string>('');
   const [previewLoading, setPreviewLoading] = useState(false);
   const [previewData, setPreviewData] = useState<any>(null);
 
+  // 结构对比：生成把目标结构调整为与源一致的迁移脚本
+  const [schemaOpen, setSchemaOpen] = useState(false);
+  const [schemaLoading, setSchemaLoading] = useState(false);
+  const [schemaResult, setSchemaResult] = useState<SchemaCompareResult | null>(null);
+  const [schemaScript, setSchemaScript] = useState<string>('');
+  const [schemaExecuting, setSchemaExecuting] = useState(false);
+
+  // 定时同步任务：把当前配置保存到后端，按 cron 或固定间隔运行
+  const [jobFormOpen, setJobFormOpen] = useState(false);
+  const [jobName, setJobName] = useState<string>('');
+  const [jobSchedule, setJobSchedule] = useState<SyncJobSchedule>({ type: 'manual', cron: '0 2 * * *', intervalSeconds: 3600 });
+  const [jobEnabled, setJobEnabled] = useState<boolean>(true);
+  const [jobSaving, setJobSaving] = useState(false);
+  const [jobsOpen, setJobsOpen] = useState(false);
+  const [jobs, setJobs] = useState<SyncJob[]>([]);
+  const [jobRuns, setJobRuns] = useState<SyncRunRecord[]>([]);
+  const [jobsLoading, setJobsLoading] = useState(false);
+  const [runningJobId, setRunningJobId] = useState<string>('');
+
   // Step 3: Result
   const [syncResult, setSyncResult] = useState<any>(null);
   const [syncing, setSyncing] = useState(false);
+  const [syncPaused, setSyncPaused] = useState(false);
+  const [cancelling, setCancelling] = useState(false);
   const [syncLogs, setSyncLogs] = useState<SyncLogItem[]>([]);
-  const [syncProgress, setSyncProgress] = useState<{ percent: number; current: number; total: number; table: string; stage: string }>({
+  const [syncProgress, setSyncProgress] = useState<{ percent: number; current: number; total: number; table: string; stage: string; totalRows: number }>({
       percent: 0,
       current: 0,
       total: 0,
       table: '',
-      stage: ''
+      stage: '',
+      totalRows: 0
   });
   const jobIdRef = useRef<string>('');
   const logBoxRef = useRef<HTMLDivElement>(null);
@@ -105,6 +173,7 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
               total: typeof event.total === 'number' ? event.total : prev.total,
               table: typeof event.table === 'string' ? event.table : prev.table,
               stage: typeof event.stage === 'string' ? event.stage : prev.stage,
+              totalRows: typeof event.totalRows === 'number' ? event.totalRows : prev.totalRows,
           }));
       });
 
@@ -131,6 +200,8 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
         setSyncContent('data');
         setSyncMode('insert_update');
         setAutoAddColumns(true);
+        setCompareMode('row');
+        setCompareOptions({});
         setShowSameTables(false);
         setAnalyzing(false);
         setDiffTables([]);
@@ -139,10 +210,15 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
         setPreviewTable('');
         setPreviewLoading(false);
         setPreviewData(null);
+        setSchemaOpen(false);
+        setSchemaLoading(false);
+        setSchemaResult(null);
+        setSchemaScript('');
+        setSchemaExecuting(false);
         setSyncResult(null);
         setSyncing(false);
         setSyncLogs([]);
-        setSyncProgress({ percent: 0, current: 0, total: 0, table: '', stage: '' });
+        setSyncProgress({ percent: 0, current: 0, total: 0, table: '', stage: '', totalRows: 0 });
         jobIdRef.current = '';
         autoScrollRef.current = true;
     }
@@ -195,6 +271,8 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
                   // DBGetTables returns [{Table: "name"}, ...]
                   const tables = (res.data as any[]).map((row: any) => row.Table || row.table || row.TABLE_NAME || Object.values(row)[0]);
                   setAllTables(tables as string[]);
+                  setMatchIndexes({});
+                  setTableMappings({});
                   setCurrentStep(1);
               } else {
                   message.error(res.message);
@@ -211,6 +289,53 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       }));
   };
 
+  const hasMapping = (m?: TableMapping) =>
+      !!m && (!!m.targetTable || Object.keys(m.columnMap || {}).length > 0 || (m.excludeColumns || []).length > 0 || (m.transforms || []).length > 0 || !!m.where || !!m.watermarkColumn);
+
+  const openMapping = (table: string) => {
+      const m = tableMappings[table] || {};
+      setMappingDraft({
+          targetTable: m.targetTable || '',
+          renames: Object.entries(m.columnMap || {}).map(([src, dst]) => `${src}=${dst}`).join('\n'),
+          exclude: m.excludeColumns || [],
+          transforms: (m.transforms || []).map(t => [t.column, t.type, t.value || ''].join(':')).join('\n'),
+          where: m.where || '',
+          watermarkColumn: m.watermarkColumn || '',
+      });
+      setMappingTable(table);
+  };
+
+  const saveMapping = () => {
+      const columnMap: Record<string, string> = {};
+      for (const line of mappingDraft.renames.split('\n')) {
+          if (!line.trim()) continue;
+          const idx = line.indexOf('=');
+          if (idx <= 0 || !line.slice(idx + 1).trim()) return message.error(`字段改名格式应为 源字段=目标字段：${line}`);
+          columnMap[line.slice(0, idx).trim()] = line.slice(idx + 1).trim();
+      }
+      const transforms: ColumnTransform[] = [];
+      for (const line of mappingDraft.transforms.split('\n')) {
+          if (!line.trim()) continue;
+          const [column, type, ...rest] = line.split(':');
+          if (!column?.trim() || !['constant', 'expression', 'mask', 'cast'].includes((type || '').trim())) {
+              return message.error(`取值转换格式应为 目标字段:类型:值：${line}`);
+          }
+          transforms.push({ column: column.trim(), type: type.trim() as ColumnTransform['type'], value: rest.join(':') });
+      }
+      const mapping: TableMapping = {
+          targetTable: mappingDraft.targetTable.trim() || undefined,
+          columnMap,
+          excludeColumns: mappingDraft.exclude.map(c => c.trim()).filter(Boolean),
+          transforms,
+          where: mappingDraft.where.trim() || undefined,
+          watermarkColumn: mappingDraft.watermarkColumn.trim() || undefined,
+      };
+      setTableMappings(prev => ({ ...prev, [mappingTable]: mapping }));
+      setTableOptions(prev => ({ ...prev, [mappingTable]: { ...(prev[mappingTable] || { insert: true, update: true, delete: false }), ...mapping } }));
+      setMappingTable('');
+      message.info('表设置已变更，重新对比差异后生效');
+  };
+
   const analyzeDiff = async () => {
       if (selectedTables.length === 0) return;
       if (!sourceConnId || !targetConnId) return message.error("Select connections first");
@@ -227,7 +352,7 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       const jobId = `analyze-${Date.now()}-${Math.random().toString(16).slice(2, 8)}`;
       jobIdRef.current = jobId;
       autoScrollRef.current = true;
-      setSyncProgress({ percent: 0, current: 0, total: selectedTables.length, table: '', stage: '差异分析' });
+      setSyncProgress({ percent: 0, current: 0, total: selectedTables.length, table: '', stage: '差异分析', totalRows: 0 });
 
       const config = {
           sourceConfig: normalizeConnConfig(sConn, sourceDb),
@@ -236,6 +361,12 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
           content: syncContent,
           mode: "insert_update",
           autoAddColumns,
+          tableOptions: Object.fromEntries(
+              Array.from(new Set([...Object.keys(matchIndexes), ...Object.keys(tableMappings)])).map(table => [table, { ...tableMappings[table], matchIndex: matchIndexes[table] }])
+          ),
+          compareMode,
+          compareOptions,
+          fullRefresh,
           jobId,
       };
 
@@ -254,6 +385,8 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
                       selectedInsertPks: [],
                       selectedUpdatePks: [],
                       selectedDeletePks: [],
+                      matchIndex: t.matchIndex || undefined,
+                      ...tableMappings[t.table],
                   };
               });
               setTableOptions(init);
@@ -286,6 +419,10 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
           content: "data",
           mode: "insert_update",
           autoAddColumns,
+          tableOptions,
+          compareMode,
+          compareOptions,
+          fullRefresh,
       };
 
       try {
@@ -302,12 +439,191 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       setPreviewLoading(false);
   };
 
-  const runSync = async () => {
+  const compareSchema = async () => {
+      if (selectedTables.length === 0) return;
+      if (!sourceConnId || !targetConnId) return message.error("Select connections first");
+      if (!sourceDb || !targetDb) return message.error("Select databases first");
+
+      const sConn = connections.find(c => c.id === sourceConnId)!;
+      const tConn = connections.find(c => c.id === targetConnId)!;
+      setSchemaOpen(true);
+      setSchemaLoading(true);
+      setSchemaResult(null);
+      setSchemaScript('');
+
+      const config = {
+          sourceConfig: normalizeConnConfig(sConn, sourceDb),
+          targetConfig: normalizeConnConfig(tConn, targetDb),
+          tables: selectedTables,
+          content: "schema",
+          mode: "insert_update",
+          jobId: `schema-${Date.now()}-${Math.random().toString(16).slice(2, 8)}`,
+      };
+
+      try {
+          const res = await SchemaCompare(config as any);
+          if (res.success) {
+              const data = res.data as SchemaCompareResult;
+              setSchemaResult(data);
+              setSchemaScript(data?.script || '');
+          } else {
+              message.error(res.message || "结构对比失败");
+          }
+      } catch (e: any) {
+          message.error("结构对比失败: " + (e?.message || ""));
+      }
+      setSchemaLoading(false);
+  };
+
+  const loadJobs = async () => {
+      setJobsLoading(true);
+      try {
+          const [jobsRes, runsRes] = await Promise.all([ListSyncJobs(), ListSyncJobRuns('', 100)]);
+          if (jobsRes.success) setJobs((jobsRes.data as SyncJob[]) || []);
+          else message.error(jobsRes.message || "加载同步任务失败");
+          if (runsRes.success) setJobRuns((runsRes.data as SyncRunRecord[]) || []);
+      } catch (e: any) {
+          message.error("加载同步任务失败: " + (e?.message || ""));
+      }
+      setJobsLoading(false);
+  };
+
+  const saveAsJob = async () => {
+      if (!jobName.trim()) return message.error("请输入任务名称");
+      const sConn = connections.find(c => c.id === sourceConnId)!;
+      const tConn = connections.find(c => c.id === targetConnId)!;
+      const job = {
+          id: '',
+          name: jobName.trim(),
+          enabled: jobEnabled,
+          schedule: jobSchedule,
+          config: {
+              sourceConfig: normalizeConnConfig(sConn, sourceDb),
+              targetConfig: normalizeConnConfig(tConn, targetDb),
+              tables: selectedTables,
+              content: syncContent,
+              mode: syncMode,
+              autoAddColumns,
+              tableOptions,
+              compareMode,
+              compareOptions,
+              ...throttle,
+          },
+      };
+      setJobSaving(true);
+      try {
+          const res = await SaveSyncJob(job as any);
+          if (res.success) {
+              message.success(`同步任务已保存：${jobName.trim()}`);
+              setJobFormOpen(false);
+          } else {
+              message.error(res.message || "保存同步任务失败");
+          }
+      } catch (e: any) {
+          message.error("保存同步任务失败: " + (e?.message || ""));
+      }
+      setJobSaving(false);
+  };
+
+  const toggleJob = async (job: SyncJob, enabled: boolean) => {
+      const res = await SaveSyncJob({ ...job, enabled } as any);
+      if (!res.success) message.error(res.message || "保存同步任务失败");
+      loadJobs();
+  };
+
+  const runJobNow = async (job: SyncJob) => {
+      setRunningJobId(job.id);
+      try {
+          const res = await RunSyncJob(job.id);
+          if (res.success) message.success(`任务 ${job.name} 运行完成`);
+          else message.error(res.message || `任务 ${job.name} 运行失败`);
+      } catch (e: any) {
+          message.error("运行同步任务失败: " + (e?.message || ""));
+      }
+      setRunningJobId('');
+      loadJobs();
+  };
+
+  const removeJob = (job: SyncJob) => {
+      Modal.confirm({
+          title: `删除同步任务 ${job.name}`,
+          content: '任务的运行记录会一并删除。',
+          okText: '删除',
+          okButtonProps: { danger: true },
+          cancelText: '取消',
+          onOk: async () => {
+              const res = await DeleteSyncJob(job.id);
+              if (!res.success) message.error(res.message || "删除同步任务失败");
+              loadJobs();
+          },
+      });
+  };
+
+  const formatSchedule = (schedule: SyncJobSchedule) => {
+      if (schedule?.type === 'cron') return `cron: ${schedule.cron}`;
+      if (schedule?.type === 'interval') return `每 ${Math.round((schedule.intervalSeconds || 0) / 60)} 分钟`;
+      return '手动';
+  };
+
+  const formatTime = (ms?: number) => (ms ? new Date(ms).toLocaleString('zh-CN', { hour12: false }) : '-');
+
+  const saveSchemaScript = async () => {
+      if (!schemaScript.trim()) return;
+      const res = await SaveSQLScript(schemaScript, `schema_migration_${targetDb || 'target'}`);
+      if (res.success) {
+          message.success(`脚本已保存：${res.data}`);
+      } else if (res.message !== 'Cancelled') {
+          message.error(res.message || "保存脚本失败");
+      }
+  };
+
+  const executeSchemaScript = async () => {
+      if (!schemaResult || (schemaResult.statements || []).length === 0) return;
+      const ok = await new Promise<boolean>((resolve) => {
+          Modal.confirm({
+              title: '确认在目标库执行迁移脚本',
+              content: '脚本会修改目标库的表结构（包括删除字段、索引等），执行前请确认已备份目标库。',
+              okText: '执行',
+              okButtonProps: { danger: true },
+              cancelText: '取消',
+              onOk: () => resolve(true),
+              onCancel: () => resolve(false),
+          });
+      });
+      if (!ok) return;
+
+      const tConn = connections.find(c => c.id === targetConnId)!;
+      setSchemaExecuting(true);
+      try {
+          const res = await DBExecuteScript(normalizeConnConfig(tConn, targetDb) as any, targetDb, schemaScript, `schema-exec-${Date.now()}`, false);
+          if (res.success) {
+              message.success(res.message || "迁移脚本执行完成");
+          } else {
+              const failed = ((res.data as any[]) || []).find((r: any) => !r.success && !r.skipped);
+              Modal.error({
+                  title: '迁移脚本执行失败',
+                  width: 700,
+                  content: (
+                      <div>
+                          <div>{res.message}</div>
+                          {failed && <pre style={{ marginTop: 8, maxHeight: 240, overflow: 'auto', background: '#f5f5f5', padding: 8 }}>{`第 ${failed.index} 条：${failed.message}\n\n${failed.sql}`}</pre>}
+                      </div>
+                  ),
+              });
+          }
+      } catch (e: any) {
+          message.error("迁移脚本执行失败: " + (e?.message || ""));
+      }
+      setSchemaExecuting(false);
+  };
+
+  // resumeFrom 为上次未完成任务的 jobId，后端跳过已完成的表并从断点继续
+  const runSync = async (resumeFrom?: string) => {
       if (syncContent !== 'schema' && diffTables.length === 0) {
           message.error("请先对比差异，再开始同步");
           return;
       }
-      if (syncContent !== 'schema' && syncMode === 'full_overwrite') {
+      if (!resumeFrom && syncContent !== 'schema' && syncMode === 'full_overwrite') {
           const ok = await new Promise<boolean>((resolve) => {
               Modal.confirm({
                   title: '确认全量覆盖',
@@ -326,6 +642,8 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       setCurrentStep(2);
       setSyncResult(null);
       setSyncLogs([]);
+      setSyncPaused(false);
+      setCancelling(false);
 
       const sConn = connections.find(c => c.id === sourceConnId)!;
       const tConn = connections.find(c => c.id === targetConnId)!;
@@ -339,6 +657,7 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
           total: selectedTables.length,
           table: '',
           stage: '准备开始',
+          totalRows: 0,
       });
       
       const config = {
@@ -363,6 +682,12 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
           mode: syncMode,
           autoAddColumns,
           tableOptions,
+          compareMode,
+          compareOptions,
+          dryRun,
+          fullRefresh,
+          ...throttle,
+          resumeFrom: resumeFrom || '',
           jobId,
       };
 
@@ -386,6 +711,37 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       }
       setLoading(false);
       setSyncing(false);
+      setSyncPaused(false);
+      setCancelling(false);
+  };
+
+  const togglePause = async () => {
+      const jobId = jobIdRef.current;
+      if (!jobId) return;
+      const res = syncPaused ? await ResumeSync(jobId) : await PauseSync(jobId);
+      if (!res.success) {
+          message.error(res.message || '操作失败');
+          return;
+      }
+      setSyncPaused(!syncPaused);
+  };
+
+  const cancelSync = () => {
+      Modal.confirm({
+          title: '取消同步',
+          content: '已写入目标库的批次会保留，正在处理的批次将被放弃，之后可从断点继续。确定取消吗？',
+          okText: '取消同步',
+          okButtonProps: { danger: true },
+          cancelText: '继续同步',
+          onOk: async () => {
+              const res = await CancelSync(jobIdRef.current);
+              if (!res.success) {
+                  message.error(res.message || '取消失败');
+                  return;
+              }
+              setCancelling(true);
+          },
+      });
   };
 
   const renderSyncLogItem = (item: SyncLogItem) => {
@@ -477,9 +833,57 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
                               <Option value="full_overwrite">全量覆盖（清空目标表后插入）</Option>
                           </Select>
                       </Form.Item>
+                      <Form.Item label="差异对比方式">
+                          <Select value={compareMode} onChange={setCompareMode} disabled={syncContent === 'schema'}>
+                              <Option value="row">逐行对比</Option>
+                              <Option value="checksum">校验和快速对比（两端同类数据库时先比较分块校验和，仅逐行对比不一致的分块）</Option>
+                          </Select>
+                      </Form.Item>
+                      <Form.Item label="取值比较规则" extra="按两端字段类型比较取值（如 1.50 与 1.5、tinyint 与 bool、定长字符尾部空格视为相同），以下容差可选">
+                          <div style={{ display: 'flex', gap: 8 }}>
+                              <Input type="number" min={0} step="any" addonBefore="浮点误差" placeholder="0" value={compareOptions.floatEpsilon || ''} disabled={syncContent === 'schema'}
+                                  onChange={(e) => setCompareOptions(prev => ({ ...prev, floatEpsilon: Math.max(0, Number(e.target.value) || 0) }))} />
+                              <Select value={compareOptions.timePrecision || ''} onChange={(v) => setCompareOptions(prev => ({ ...prev, timePrecision: v }))} disabled={syncContent === 'schema'} style={{ width: 160 }}>
+                                  <Option value="">时间精确比较</Option>
+                                  <Option value="second">时间精确到秒</Option>
+                                  <Option value="millisecond">时间精确到毫秒</Option>
+                                  <Option value="microsecond">时间精确到微秒</Option>
+                              </Select>
+                              <Select value={compareOptions.whitespace || ''} onChange={(v) => setCompareOptions(prev => ({ ...prev, whitespace: v }))} disabled={syncContent === 'schema'} style={{ width: 180 }}>
+                                  <Option value="">空白严格比较</Option>
+                                  <Option value="trim">忽略首尾空白</Option>
+                                  <Option value="collapse">忽略首尾与连续空白</Option>
+                              </Select>
+                              <Checkbox checked={!!compareOptions.ignoreCase} onChange={(e) => setCompareOptions(prev => ({ ...prev, ignoreCase: e.target.checked }))} disabled={syncContent === 'schema'} style={{ alignSelf: 'center', whiteSpace: 'nowrap' }}>
+                                  忽略大小写
+                              </Checkbox>
+                          </div>
+                      </Form.Item>
                       <Form.Item>
                           <Checkbox checked={autoAddColumns} onChange={(e) => setAutoAddColumns(e.target.checked)}>
-                              自动补齐目标表缺失字段（仅 MySQL 目标）
+                              自动补齐目标表缺失字段（字段类型按目标数据库映射）
+                          </Checkbox>
+                      </Form.Item>
+                      <Form.Item>
+                          <Checkbox checked={dryRun} onChange={(e) => setDryRun(e.target.checked)}>
+                              演练模式（生成 SQL 脚本，不修改目标库）
+                          </Checkbox>
+                      </Form.Item>
+                      <Form.Item label="并发与限流" extra="多张表并行同步时每个线程使用独立连接；速率为所有表合计，0 表示不限制">
+                          <div style={{ display: 'flex', gap: 8 }}>
+                              <Input type="number" min={1} max={8} addonBefore="并行表数" value={throttle.workers}
+                                  onChange={(e) => setThrottle(prev => ({ ...prev, workers: Math.min(8, Math.max(1, Number(e.target.value) || 1)) }))} />
+                              <Input type="number" min={0} addonBefore="每批行数" placeholder="2000" value={throttle.batchSize || ''}
+                                  onChange={(e) => setThrottle(prev => ({ ...prev, batchSize: Math.max(0, Number(e.target.value) || 0) }))} />
+                              <Input type="number" min={0} addonBefore="行/秒" value={throttle.maxRowsPerSecond}
+                                  onChange={(e) => setThrottle(prev => ({ ...prev, maxRowsPerSecond: Math.max(0, Number(e.target.value) || 0) }))} />
+                              <Input type="number" min={0} addonBefore="批间暂停(ms)" value={throttle.batchPauseMs}
+                                  onChange={(e) => setThrottle(prev => ({ ...prev, batchPauseMs: Math.max(0, Number(e.target.value) || 0) }))} />
+                          </div>
+                      </Form.Item>
+                      <Form.Item>
+                          <Checkbox checked={fullRefresh} onChange={(e) => setFullRefresh(e.target.checked)} disabled={syncContent === 'schema'}>
+                              全量对比（忽略已记录的增量水位，可检测删除，完成后更新水位）
                           </Checkbox>
                       </Form.Item>
                       {syncContent !== 'schema' && syncMode === 'full_overwrite' && (
@@ -534,6 +938,30 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
                           })}
                           columns={[
                               { title: '表名', dataIndex: 'table', key: 'table', ellipsis: true },
+                              {
+                                  title: '匹配键',
+                                  key: 'matchKey',
+                                  width: 160,
+                                  render: (_: any, r: any) => {
+                                      const candidates: string[] = Array.isArray(r.uniqueIndexes) ? r.uniqueIndexes : [];
+                                      if (candidates.length === 0) return r.pkColumn ? String(r.pkColumn) : '-';
+                                      return (
+                                          <Select
+                                              size="small"
+                                              style={{ width: '100%' }}
+                                              value={matchIndexes[r.table] || r.matchIndex || undefined}
+                                              disabled={analyzing}
+                                              placeholder="选择唯一索引"
+                                              onChange={(value: string) => {
+                                                  setMatchIndexes(prev => ({ ...prev, [r.table]: value }));
+                                                  message.info('匹配键已变更，重新对比差异后生效');
+                                              }}
+                                          >
+                                              {candidates.map(name => <Option key={name} value={name}>{name}</Option>)}
+                                          </Select>
+                                      );
+                                  }
+                              },
                               {
                                   title: '插入',
                                   key: 'inserts',
@@ -589,6 +1017,22 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
                                   }
                               },
                               { title: '相同', dataIndex: 'same', key: 'same', width: 70, render: (v: any) => Number(v || 0) },
+                              {
+                                  title: '设置',
+                                  key: 'mapping',
+                                  width: 80,
+                                  render: (_: any, r: TableDiffSummary) => (
+                                      <Button size="small" type={hasMapping(tableMappings[r.table]) ? 'primary' : 'default'} disabled={analyzing} onClick={() => openMapping(r.table)}>
+                                          {hasMapping(tableMappings[r.table]) ? '已配置' : '配置'}
+                                      </Button>
+                                  ),
+                              },
+                              {
+                                  title: '跳过分块',
+                                  key: 'chunksSkipped',
+                                  width: 90,
+                                  render: (_: any, r: TableDiffSummary) => (r.chunksTotal ? `${Number(r.chunksSkipped || 0)}/${r.chunksTotal}` : '-'),
+                              },
                               { title: '消息', dataIndex: 'message', key: 'message', ellipsis: true, render: (v: any) => (v ? String(v) : '') },
                               {
                                   title: '预览',
@@ -615,20 +1059,20 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
       {currentStep === 2 && (
           <div>
               <Alert
-                  message={syncing ? "正在同步" : (syncResult?.success ? "同步完成" : "同步失败")}
+                  message={syncing ? (cancelling ? "正在取消" : (syncPaused ? "已暂停" : "正在同步")) : (syncResult?.cancelled ? "同步已取消" : (syncResult?.success ? (syncResult?.scriptPath ? "演练完成" : "同步完成") : "同步失败"))}
                   description={
                       syncing
-                          ? `当前阶段：${syncProgress.stage || '执行中'}${syncProgress.table ? `，表：${syncProgress.table}` : ''}`
+                          ? `当前阶段：${syncProgress.stage || '执行中'}${syncProgress.table ? `，表：${syncProgress.table}` : ''}${syncProgress.totalRows > 0 ? `，累计已处理 ${syncProgress.totalRows} 行` : ''}`
                           : (syncResult?.message || `成功同步 ${syncResult?.tablesSynced || 0} 张表. 插入: ${syncResult?.rowsInserted || 0}, 更新: ${syncResult?.rowsUpdated || 0}`)
                   }
-                  type={syncing ? "info" : (syncResult?.success ? "success" : "error")}
+                  type={syncing ? "info" : (syncResult?.cancelled ? "warning" : (syncResult?.success ? "success" : "error"))}
                   showIcon
               />
 
               <div style={{ marginTop: 12 }}>
                   <Progress
                       percent={syncProgress.percent}
-                      status={syncing ? "active" : (syncResult?.success ? "success" : "exception")}
+                      status={syncing ? (syncPaused ? "normal" : "active") : (syncResult?.success ? "success" : (syncResult?.cancelled ? "normal" : "exception"))}
                       format={() => `${syncProgress.current}/${syncProgress.total}`}
                   />
               </div>
@@ -651,17 +1095,26 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
 
       <div style={{ marginTop: 24, textAlign: 'right' }}>
           {currentStep === 0 && (
-              <Button type="primary" onClick={nextToTables} loading={loading}>下一步</Button>
+              <>
+                  <Button onClick={() => { setJobsOpen(true); loadJobs(); }} style={{ marginRight: 8 }}>同步任务</Button>
+                  <Button type="primary" onClick={nextToTables} loading={loading}>下一步</Button>
+              </>
           )}
 	          {currentStep === 1 && (
 	              <>
 	                <Button onClick={() => setCurrentStep(0)} style={{ marginRight: 8 }}>上一步</Button>
+	                <Button onClick={compareSchema} loading={schemaLoading} disabled={selectedTables.length === 0 || analyzing} style={{ marginRight: 8 }}>
+	                    结构对比
+	                </Button>
+	                <Button onClick={() => { setJobName(`${sourceDb} -> ${targetDb}`); setJobFormOpen(true); }} disabled={selectedTables.length === 0 || analyzing} style={{ marginRight: 8 }}>
+	                    保存为任务
+	                </Button>
 	                <Button onClick={analyzeDiff} loading={loading} disabled={syncContent === 'schema' || selectedTables.length === 0 || analyzing} style={{ marginRight: 8 }}>
 	                    对比差异
 	                </Button>
 	                <Button
 	                    type="primary"
-	                    onClick={runSync}
+	                    onClick={() => runSync()}
                     loading={loading}
                     disabled={selectedTables.length === 0 || (syncContent !== 'schema' && diffTables.length === 0)}
                 >
@@ -671,6 +1124,15 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
           )}
           {currentStep === 2 && (
               <>
+                  {syncing && (
+                      <>
+                          <Button onClick={togglePause} disabled={cancelling} style={{ marginRight: 8 }}>{syncPaused ? '继续' : '暂停'}</Button>
+                          <Button danger onClick={cancelSync} loading={cancelling} style={{ marginRight: 8 }}>取消同步</Button>
+                      </>
+                  )}
+                  {!syncing && syncResult?.resumable && (
+                      <Button onClick={() => runSync(jobIdRef.current)} style={{ marginRight: 8 }}>从断点继续</Button>
+                  )}
                   <Button disabled={syncing} onClick={() => setCurrentStep(1)} style={{ marginRight: 8 }}>继续同步</Button>
                   <Button type="primary" disabled={syncing} onClick={onClose}>关闭</Button>
               </>
@@ -800,6 +1262,265 @@ const DataSyncModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open,
             </div>
         )}
     </Drawer>
+    <Drawer
+        title="结构对比"
+        open={schemaOpen}
+        onClose={() => { if (!schemaExecuting) setSchemaOpen(false); }}
+        width={960}
+        extra={
+            <>
+                <Button onClick={saveSchemaScript} disabled={schemaLoading || !schemaScript.trim()} style={{ marginRight: 8 }}>保存脚本</Button>
+                <Button
+                    type="primary"
+                    danger
+                    onClick={executeSchemaScript}
+                    loading={schemaExecuting}
+                    disabled={schemaLoading || !schemaResult || (schemaResult.statements || []).length === 0}
+                >
+                    在目标库执行
+                </Button>
+            </>
+        }
+    >
+        {schemaLoading && <Alert type="info" showIcon message="正在对比表结构..." />}
+        {!schemaLoading && schemaResult && (
+            <div>
+                <Alert type={(schemaResult.statements || []).length > 0 ? 'warning' : 'success'} showIcon message={schemaResult.message} />
+                {(schemaResult.notes || []).length > 0 && (
+                    <Alert
+                        style={{ marginTop: 8 }}
+                        type="info"
+                        showIcon
+                        message="以下内容无法自动迁移，请手工处理"
+                        description={<div>{(schemaResult.notes || []).map((n, i) => <div key={i}>{n}</div>)}</div>}
+                    />
+                )}
+                <Table
+                    size="small"
+                    style={{ marginTop: 12 }}
+                    rowKey={(r: TableSchemaDiff) => r.table}
+                    dataSource={(schemaResult.tables || []).filter(t => t.status !== 'same')}
+                    pagination={false}
+                    locale={{ emptyText: '所选表结构一致' }}
+                    columns={[
+                        { title: '表', dataIndex: 'table', key: 'table', width: 200, ellipsis: true },
+                        {
+                            title: '状态',
+                            dataIndex: 'status',
+                            key: 'status',
+                            width: 100,
+                            render: (v: string) => {
+                                if (v === 'missing') return <Tag color="green">目标缺表</Tag>;
+                                if (v === 'extra') return <Tag color="red">目标多余</Tag>;
+                                if (v === 'error') return <Tag color="red">失败</Tag>;
+                                return <Tag color="orange">有差异</Tag>;
+                            }
+                        },
+                        {
+                            title: '差异',
+                            key: 'changes',
+                            render: (_: any, r: TableSchemaDiff) => {
+                                if (r.status === 'error') return <Text type="danger">{r.message}</Text>;
+                                const actionLabel: Record<string, string> = { add: '新增', drop: '删除', modify: '修改' };
+                                const objectLabel: Record<string, string> = { table: '表', column: '字段', primaryKey: '主键', index: '索引', foreignKey: '外键', trigger: '触发器', option: '表选项' };
+                                return (
+                                    <div>
+                                        {(r.changes || []).map((c, i) => (
+                                            <div key={i}>
+                                                {actionLabel[c.action] || c.action}{objectLabel[c.object] || c.object} <Text code>{c.name}</Text>
+                                                {c.detail && <Text type="secondary"> {c.detail}</Text>}
+                                            </div>
+                                        ))}
+                                    </div>
+                                );
+                            }
+                        }
+                    ]}
+                />
+                <Divider />
+                <Text type="secondary">迁移脚本（可编辑后保存或执行）</Text>
+                <Input.TextArea
+                    style={{ marginTop: 8, fontFamily: 'monospace' }}
+                    value={schemaScript}
+                    onChange={(e) => setSchemaScript(e.target.value)}
+                    autoSize={{ minRows: 12, maxRows: 28 }}
+                />
+            </div>
+        )}
+    </Drawer>
+    <Modal
+        title={`表设置：${mappingTable}`}
+        open={!!mappingTable}
+        onCancel={() => setMappingTable('')}
+        onOk={saveMapping}
+        okText="确定"
+        cancelText="取消"
+    >
+        <Form layout="vertical">
+            <Form.Item label="目标表" extra="留空表示与源表同名">
+                <Input value={mappingDraft.targetTable} placeholder={mappingTable} onChange={(e) => setMappingDraft(prev => ({ ...prev, targetTable: e.target.value }))} />
+            </Form.Item>
+            <Form.Item label="字段改名" extra="每行一个：源字段=目标字段">
+                <Input.TextArea value={mappingDraft.renames} autoSize={{ minRows: 2, maxRows: 8 }} onChange={(e) => setMappingDraft(prev => ({ ...prev, renames: e.target.value }))} />
+            </Form.Item>
+            <Form.Item label="排除字段" extra="不对比、不写入的源字段">
+                <Select mode="tags" value={mappingDraft.exclude} onChange={(v: string[]) => setMappingDraft(prev => ({ ...prev, exclude: v }))} open={false} tokenSeparators={[',', ' ']} />
+            </Form.Item>
+            <Form.Item
+                label="取值转换"
+                extra="每行一个：目标字段:类型:值。类型为 constant（常量）、expression（如 concat(first_name, ' ', last_name)）、mask（留空全部脱敏，或 keep:3,4、email、hash）、cast（string/int/float/bool/date/datetime）"
+            >
+                <Input.TextArea
+                    value={mappingDraft.transforms}
+                    autoSize={{ minRows: 2, maxRows: 8 }}
+                    placeholder={'phone:mask:keep:3,4\nsource:constant:legacy'}
+                    onChange={(e) => setMappingDraft(prev => ({ ...prev, transforms: e.target.value }))}
+                />
+            </Form.Item>
+            <Form.Item label="过滤条件" extra="只同步满足条件的源表行，如 status = 1 AND created_at >= '2024-01-01'（使用源表字段名，不含 WHERE）">
+                <Input.TextArea value={mappingDraft.where} autoSize={{ minRows: 1, maxRows: 4 }} onChange={(e) => setMappingDraft(prev => ({ ...prev, where: e.target.value }))} />
+            </Form.Item>
+            <Form.Item label="增量水位字段" extra="如 updated_at 或自增 id：首次全量同步并记录最大值，之后只读取更大的行，增量同步不检测删除">
+                <Input value={mappingDraft.watermarkColumn} onChange={(e) => setMappingDraft(prev => ({ ...prev, watermarkColumn: e.target.value }))} />
+            </Form.Item>
+        </Form>
+    </Modal>
+    <Modal
+        title="保存为同步任务"
+        open={jobFormOpen}
+        onCancel={() => setJobFormOpen(false)}
+        onOk={saveAsJob}
+        confirmLoading={jobSaving}
+        okText="保存"
+        cancelText="取消"
+    >
+        <Form layout="vertical">
+            <Form.Item label="任务名称" required>
+                <Input value={jobName} onChange={(e) => setJobName(e.target.value)} />
+            </Form.Item>
+            <Form.Item label="运行方式">
+                <Select value={jobSchedule.type} onChange={(v) => setJobSchedule(prev => ({ ...prev, type: v }))}>
+                    <Option value="manual">手动运行</Option>
+                    <Option value="cron">cron 表达式</Option>
+                    <Option value="interval">固定间隔</Option>
+                </Select>
+            </Form.Item>
+            {jobSchedule.type === 'cron' && (
+                <Form.Item label="cron 表达式" extra="5 段：分 时 日 月 周，按本机时区；也可使用 @hourly、@daily 等">
+                    <Input value={jobSchedule.cron} onChange={(e) => setJobSchedule(prev => ({ ...prev, cron: e.target.value }))} placeholder="0 2 * * *" />
+                </Form.Item>
+            )}
+            {jobSchedule.type === 'interval' && (
+                <Form.Item label="间隔（分钟）">
+                    <Input
+                        type="number"
+                        min={1}
+                        value={Math.round((jobSchedule.intervalSeconds || 0) / 60)}
+                        onChange={(e) => setJobSchedule(prev => ({ ...prev, intervalSeconds: Math.max(1, Number(e.target.value) || 1) * 60 }))}
+                    />
+                </Form.Item>
+            )}
+            <Form.Item>
+                <Checkbox checked={jobEnabled} onChange={(e) => setJobEnabled(e.target.checked)}>启用定时运行（仅在应用打开期间运行）</Checkbox>
+            </Form.Item>
+        </Form>
+    </Modal>
+    <Drawer
+        title="同步任务"
+        open={jobsOpen}
+        onClose={() => setJobsOpen(false)}
+        width={960}
+        extra={<Button onClick={loadJobs} loading={jobsLoading}>刷新</Button>}
+    >
+        <Tabs
+            items={[
+                {
+                    key: 'jobs',
+                    label: `任务 (${jobs.length})`,
+                    children: (
+                        <Table
+                            size="small"
+                            rowKey={(r: SyncJob) => r.id}
+                            loading={jobsLoading}
+                            dataSource={jobs}
+                            pagination={false}
+                            locale={{ emptyText: '暂无同步任务，可在选择表后点击“保存为任务”' }}
+                            columns={[
+                                { title: '名称', dataIndex: 'name', key: 'name', ellipsis: true },
+                                { title: '调度', key: 'schedule', width: 160, render: (_: any, r: SyncJob) => formatSchedule(r.schedule) },
+                                { title: '表数', key: 'tables', width: 60, render: (_: any, r: SyncJob) => (r.config?.tables || []).length },
+                                { title: '上次运行', key: 'lastRunAt', width: 160, render: (_: any, r: SyncJob) => formatTime(r.lastRunAt) },
+                                { title: '下次运行', key: 'nextRunAt', width: 160, render: (_: any, r: SyncJob) => formatTime(r.nextRunAt) },
+                                {
+                                    title: '启用',
+                                    key: 'enabled',
+                                    width: 60,
+                                    render: (_: any, r: SyncJob) => <Checkbox checked={r.enabled} onChange={(e) => toggleJob(r, e.target.checked)} />
+                                },
+                                {
+                                    title: '操作',
+                                    key: 'actions',
+                                    width: 140,
+                                    render: (_: any, r: SyncJob) => (
+                                        <>
+                                            <Button size="small" type="link" loading={runningJobId === r.id} disabled={!!runningJobId && runningJobId !== r.id} onClick={() => runJobNow(r)}>立即运行</Button>
+                                            <Button size="small" type="link" danger onClick={() => removeJob(r)}>删除</Button>
+                                        </>
+                                    )
+                                }
+                            ]}
+                        />
+                    )
+                },
+                {
+                    key: 'runs',
+                    label: '运行记录',
+                    children: (
+                        <Table
+                            size="small"
+                            rowKey={(r: SyncRunRecord) => r.id}
+                            loading={jobsLoading}
+                            dataSource={jobRuns}
+                            pagination={{ pageSize: 20 }}
+                            columns={[
+                                { title: '任务', dataIndex: 'jobName', key: 'jobName', ellipsis: true },
+                                { title: '开始时间', key: 'startedAt', width: 160, render: (_: any, r: SyncRunRecord) => formatTime(r.startedAt) },
+                                { title: '耗时', key: 'durationMs', width: 80, render: (_: any, r: SyncRunRecord) => `${(r.durationMs / 1000).toFixed(1)}s` },
+                                { title: '触发', dataIndex: 'trigger', key: 'trigger', width: 60, render: (v: string) => (v === 'schedule' ? '定时' : '手动') },
+                                {
+                                    title: '结果',
+                                    key: 'success',
+                                    width: 130,
+                                    render: (_: any, r: SyncRunRecord) => (
+                                        <>
+                                            {r.cancelled ? <Tag>已取消</Tag> : (r.success ? <Tag color="green">成功</Tag> : <Tag color="red">失败</Tag>)}
+                                            {r.resumable && <Tag color="orange">未完成</Tag>}
+                                        </>
+                                    )
+                                },
+                                {
+                                    title: '统计',
+                                    key: 'stats',
+                                    render: (_: any, r: SyncRunRecord) => `表 ${r.tablesSynced}，插入 ${r.rowsInserted}，更新 ${r.rowsUpdated}，删除 ${r.rowsDeleted}${r.errorCount > 0 ? `，错误 ${r.errorCount}` : ''}`
+                                }
+                            ]}
+                            expandable={{
+                                expandedRowRender: (r: SyncRunRecord) => (
+                                    <div>
+                                        {r.message && <div>{r.message}</div>}
+                                        {(r.errors || []).map((e, i) => <div key={`e-${i}`}><Text type="danger">{e}</Text></div>)}
+                                        <pre style={{ marginTop: 8, maxHeight: 240, overflow: 'auto', background: '#f5f5f5', padding: 8, whiteSpace: 'pre-wrap' }}>
+                                            {(r.logs || []).join('\n')}
+                                        </pre>
+                                    </div>
+                                ),
+                            }}
+                        />
+                    )
+                }
+            ]}
+        />
+    </Drawer>
     </>
   );
 };
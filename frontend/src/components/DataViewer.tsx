@@ -81,7 +81,7 @@ const DataViewer: React.FC<{ tab: TabData }> = ({ tab }) => {
     try {
         const executeDataQuery = async (querySql: string, attemptLabel: string) => {
             const startTime = Date.now();
-            const result = await DBQuery(config as any, dbName, querySql);
+            const result = await DBQuery(config as any, dbName, querySql, '');
             addSqlLog({
                 id: `log-${Date.now()}-data`,
                 timestamp: Date.now(),
@@ -178,7 +178,7 @@ const DataViewer: React.FC<{ tab: TabData }> = ({ tab }) => {
                     // 这里为统计请求设置更短的超时，避免“后台统计”长期占用资源。
                     const countConfig: any = { ...(config as any), timeout: 5 };
 
-                    DBQuery(countConfig, dbName, countSql)
+                    DBQuery(countConfig, dbName, countSql, '')
                         .then((resCount: any) => {
                             const countDuration = Date.now() - countStart;
 
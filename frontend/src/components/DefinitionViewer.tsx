@@ -138,7 +138,7 @@ const DefinitionViewer: React.FC<DefinitionViewerProps> = ({ tab }) => {
             const sql = String(query || '').trim();
             if (!sql) continue;
             try {
-                const result = await DBQuery(config as any, dbName, sql);
+                const result = await DBQuery(config as any, dbName, sql, '');
                 if (!result.success || !Array.isArray(result.data)) {
                     lastMessage = result.message || lastMessage;
                     continue;
@@ -164,7 +164,7 @@ const DefinitionViewer: React.FC<DefinitionViewerProps> = ({ tab }) => {
         ];
         for (const query of candidates) {
             try {
-                const result = await DBQuery(config as any, dbName, query);
+                const result = await DBQuery(config as any, dbName, query, '');
                 if (!result.success || !Array.isArray(result.data) || result.data.length === 0) {
                     continue;
                 }
@@ -1,11 +1,11 @@
 import React, { useState, useEffect, useRef } from 'react';
 import Editor, { OnMount } from '@monaco-editor/react';
 import { Button, message, Modal, Input, Form, Dropdown, MenuProps, Tooltip, Select, Tabs } from 'antd';
-import { PlayCircleOutlined, SaveOutlined, FormatPainterOutlined, SettingOutlined, CloseOutlined } from '@ant-design/icons';
+import { PlayCircleOutlined, SaveOutlined, FormatPainterOutlined, SettingOutlined, CloseOutlined, StopOutlined } from '@ant-design/icons';
 import { format } from 'sql-formatter';
 import { TabData, ColumnDefinition } from '../types';
 import { useStore } from '../store';
-import { DBQuery, DBGetTables, DBGetAllColumns, DBGetDatabases, DBGetColumns } from '../../wailsjs/go/app/App';
+import { DBQuery, CancelQuery, DBGetTables, DBGetAllColumns, DBGetDatabases, DBGetColumns } from '../../wailsjs/go/app/App';
 import DataGrid, { GONAVI_ROW_KEY } from './DataGrid';
 
 const QueryEditor: React.FC<{ tab: TabData }> = ({ tab }) => {
@@ -29,6 +29,7 @@ const QueryEditor: React.FC<{ tab: TabData }> = ({ tab }) => {
   
   const [loading, setLoading] = useState(false);
   const runSeqRef = useRef(0);
+  const runningQueryIdRef = useRef<string>('');
   const [isSaveModalOpen, setIsSaveModalOpen] = useState(false);
   const [saveForm] = Form.useForm();
   
@@ -1016,7 +1017,10 @@ const QueryEditor: React.FC<{ tab: TabData }> = ({ tab }) => {
             const limited = limitApplied ? applyAutoLimit(rawStatement, dbType, probeLimit) : { sql: rawStatement, applied: false, maxRows: probeLimit };
             const executedSql = limited.sql;
             const startTime = Date.now();
-            const res = await DBQuery(config as any, currentDb, executedSql);
+            const queryId = `query-${tab.id}-${runSeq}-${idx + 1}`;
+            runningQueryIdRef.current = queryId;
+            const res = await DBQuery(config as any, currentDb, executedSql, queryId);
+            runningQueryIdRef.current = '';
             const duration = Date.now() - startTime;
 
             addSqlLog({
@@ -1133,10 +1137,20 @@ const QueryEditor: React.FC<{ tab: TabData }> = ({ tab }) => {
         setResultSets([]);
         setActiveResultKey('');
     } finally {
+        runningQueryIdRef.current = '';
         if (runSeqRef.current === runSeq) setLoading(false);
     }
   };
 
+  const handleCancel = async () => {
+    const queryId = runningQueryIdRef.current;
+    if (!queryId) return;
+    const res = await CancelQuery(queryId);
+    if (!res.success) {
+        message.warning(res.message);
+    }
+  };
+
   const handleSave = async () => {
       try {
           const values = await saveForm.validateFields();
@@ -1251,6 +1265,11 @@ const QueryEditor: React.FC<{ tab: TabData }> = ({ tab }) => {
         <Button type="primary" icon={<PlayCircleOutlined />} onClick={handleRun} loading={loading}>
           运行
         </Button>
+        {loading && (
+          <Button danger icon={<StopOutlined />} onClick={handleCancel}>
+            停止
+          </Button>
+        )}
         <Button icon={<SaveOutlined />} onClick={() => {
             saveForm.setFieldsValue({ name: tab.title.replace('Query (', '').replace(')', '') });
             setIsSaveModalOpen(true);
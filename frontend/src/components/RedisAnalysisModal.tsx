@@ -0,0 +1,276 @@
+import React, { useState, useEffect, useRef } from 'react';
+import { Modal, Form, Input, InputNumber, Button, Progress, Alert, Tabs, Table, Tag, Space } from 'antd';
+import { RedisAnalyzeKeys, CancelQuery } from '../../wailsjs/go/app/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import { SavedConnection } from '../types';
+
+interface RedisAnalysisModalProps {
+    open: boolean;
+    connection: SavedConnection | null;
+    redisDB: number;
+    onClose: () => void;
+}
+
+interface PrefixNode {
+    name: string;
+    prefix: string;
+    keys: number;
+    bytes: number;
+    elements: number;
+    children?: PrefixNode[];
+}
+
+interface KeyStat {
+    key: string;
+    type: string;
+    ttl: number;
+    bytes: number;
+    elements: number;
+}
+
+interface AnalysisReport {
+    totalKeys: number;
+    totalBytes: number;
+    prefixes: PrefixNode[];
+    topKeys: KeyStat[];
+    ttl: Array<{ label: string; keys: number; bytes: number }>;
+    types: Array<{ type: string; keys: number; bytes: number }>;
+    cancelled?: boolean;
+    truncated?: boolean;
+    durationMs: number;
+}
+
+interface AnalysisProgress {
+    analysisId: string;
+    scanned: number;
+    total: number;
+    bytes: number;
+}
+
+const formatBytes = (bytes: number) => {
+    if (bytes < 1024) return `${bytes} B`;
+    const units = ['KB', 'MB', 'GB', 'TB'];
+    let value = bytes / 1024;
+    let unit = 0;
+    while (value >= 1024 && unit < units.length - 1) {
+        value /= 1024;
+        unit++;
+    }
+    return `${value.toFixed(value >= 100 ? 0 : 1)} ${units[unit]}`;
+};
+
+const formatTTL = (ttl: number) => {
+    if (ttl === -1) return '永不过期';
+    if (ttl < 0) return '-';
+    if (ttl < 3600) return `${ttl} 秒`;
+    if (ttl < 86400) return `${(ttl / 3600).toFixed(1)} 小时`;
+    return `${(ttl / 86400).toFixed(1)} 天`;
+};
+
+const RedisAnalysisModal: React.FC<RedisAnalysisModalProps> = ({ open, connection, redisDB, onClose }) => {
+    const [form] = Form.useForm();
+    const [running, setRunning] = useState(false);
+    const [progress, setProgress] = useState<AnalysisProgress | null>(null);
+    const [report, setReport] = useState<AnalysisReport | null>(null);
+    const [error, setError] = useState<string | null>(null);
+    const analysisIdRef = useRef<string>('');
+
+    useEffect(() => {
+        if (!open) return;
+        setReport(null);
+        setProgress(null);
+        setError(null);
+        const off = EventsOn('redis:analysis-progress', (event: AnalysisProgress) => {
+            if (event.analysisId === analysisIdRef.current) {
+                setProgress(event);
+            }
+        });
+        return () => off();
+    }, [open]);
+
+    const startAnalysis = async () => {
+        if (!connection) return;
+        const values = form.getFieldsValue();
+        const config = {
+            ...connection.config,
+            port: Number(connection.config.port),
+            password: connection.config.password || "",
+            useSSH: connection.config.useSSH || false,
+            ssh: connection.config.ssh || { host: "", port: 22, user: "", password: "", keyPath: "" },
+            redisDB: redisDB
+        };
+        const analysisId = `redis-analysis-${Date.now()}`;
+        analysisIdRef.current = analysisId;
+        setRunning(true);
+        setReport(null);
+        setProgress(null);
+        setError(null);
+        try {
+            const res = await RedisAnalyzeKeys(config as any, {
+                pattern: values.pattern || '*',
+                delimiter: values.delimiter || ':',
+                maxDepth: Number(values.maxDepth || 3),
+                topN: Number(values.topN || 50),
+                scanCount: Number(values.scanCount || 500),
+                memorySamples: Number(values.memorySamples || 0),
+                maxKeys: Number(values.maxKeys || 0),
+            } as any, analysisId);
+            if (res.success) {
+                setReport(res.data as AnalysisReport);
+            } else {
+                setError(res.message);
+            }
+        } catch (e: any) {
+            setError('分析失败: ' + (e?.message || String(e)));
+        } finally {
+            setRunning(false);
+        }
+    };
+
+    const cancelAnalysis = async () => {
+        if (analysisIdRef.current) {
+            await CancelQuery(analysisIdRef.current);
+        }
+    };
+
+    const handleClose = () => {
+        if (running) {
+            cancelAnalysis();
+        }
+        onClose();
+    };
+
+    const percent = progress && progress.total > 0
+        ? Math.min(99, Math.floor(progress.scanned * 100 / progress.total))
+        : 0;
+
+    const share = (bytes: number) => report && report.totalBytes > 0
+        ? `${(bytes * 100 / report.totalBytes).toFixed(1)}%`
+        : '-';
+
+    const prefixColumns = [
+        { title: '前缀', dataIndex: 'prefix', key: 'prefix', render: (prefix: string, node: PrefixNode) => prefix || node.name },
+        { title: '键数量', dataIndex: 'keys', key: 'keys', width: 100 },
+        { title: '内存', dataIndex: 'bytes', key: 'bytes', width: 100, render: (bytes: number) => formatBytes(bytes) },
+        { title: '占比', key: 'share', width: 80, render: (_: any, node: PrefixNode) => share(node.bytes) },
+        { title: '元素数', dataIndex: 'elements', key: 'elements', width: 100 },
+    ];
+
+    const keyColumns = [
+        { title: 'Key', dataIndex: 'key', key: 'key', ellipsis: true },
+        { title: '类型', dataIndex: 'type', key: 'type', width: 80, render: (type: string) => <Tag>{type}</Tag> },
+        { title: '内存', dataIndex: 'bytes', key: 'bytes', width: 100, render: (bytes: number) => formatBytes(bytes) },
+        { title: '元素数', dataIndex: 'elements', key: 'elements', width: 100 },
+        { title: 'TTL', dataIndex: 'ttl', key: 'ttl', width: 110, render: (ttl: number) => formatTTL(ttl) },
+    ];
+
+    const bucketColumns = (title: string, field: string) => [
+        { title, dataIndex: field, key: field },
+        { title: '键数量', dataIndex: 'keys', key: 'keys', width: 120 },
+        { title: '内存', dataIndex: 'bytes', key: 'bytes', width: 120, render: (bytes: number) => formatBytes(bytes) },
+        { title: '占比', key: 'share', width: 100, render: (_: any, row: any) => share(row.bytes) },
+    ];
+
+    return (
+        <Modal
+            title={`内存分析 - ${connection?.name || ''} db${redisDB}`}
+            open={open}
+            onCancel={handleClose}
+            width={1000}
+            footer={[
+                running
+                    ? <Button key="cancel" danger onClick={cancelAnalysis}>取消分析</Button>
+                    : <Button key="start" type="primary" onClick={startAnalysis}>开始分析</Button>,
+                <Button key="close" onClick={handleClose}>关闭</Button>,
+            ]}
+        >
+            <Form
+                form={form}
+                layout="inline"
+                disabled={running}
+                initialValues={{ pattern: '*', delimiter: ':', maxDepth: 3, topN: 50, scanCount: 500, memorySamples: 0, maxKeys: 0 }}
+                style={{ marginBottom: 12, rowGap: 8 }}
+            >
+                <Form.Item name="pattern" label="匹配模式"><Input style={{ width: 140 }} /></Form.Item>
+                <Form.Item name="delimiter" label="分隔符"><Input style={{ width: 60 }} /></Form.Item>
+                <Form.Item name="maxDepth" label="前缀层级"><InputNumber min={1} max={10} style={{ width: 70 }} /></Form.Item>
+                <Form.Item name="topN" label="大 Key 数"><InputNumber min={1} max={1000} style={{ width: 80 }} /></Form.Item>
+                <Form.Item name="scanCount" label="每批扫描"><InputNumber min={10} max={10000} style={{ width: 90 }} /></Form.Item>
+                <Form.Item name="memorySamples" label="采样数" tooltip="MEMORY USAGE 对嵌套类型的采样元素数，0 使用服务端默认值（5）">
+                    <InputNumber min={0} style={{ width: 80 }} />
+                </Form.Item>
+                <Form.Item name="maxKeys" label="最多分析" tooltip="0 表示扫描整个数据库">
+                    <InputNumber min={0} style={{ width: 110 }} />
+                </Form.Item>
+            </Form>
+
+            {running && (
+                <div style={{ marginBottom: 12 }}>
+                    <Progress percent={percent} status="active" />
+                    <span style={{ color: '#999', fontSize: 12 }}>
+                        已分析 {progress?.scanned || 0}{progress?.total ? ` / 约 ${progress.total}` : ''} 个键，累计 {formatBytes(progress?.bytes || 0)}
+                    </span>
+                </div>
+            )}
+            {error && <Alert type="error" showIcon message={error} style={{ marginBottom: 12 }} />}
+            {report && (
+                <>
+                    {(report.cancelled || report.truncated) && (
+                        <Alert
+                            type="warning"
+                            showIcon
+                            style={{ marginBottom: 12 }}
+                            message={report.cancelled ? '分析已取消，以下为已扫描部分的结果' : '已达到最多分析键数，以下为部分结果'}
+                        />
+                    )}
+                    <Space size={16} style={{ marginBottom: 12 }}>
+                        <span>键数量：{report.totalKeys}</span>
+                        <span>总内存：{formatBytes(report.totalBytes)}</span>
+                        <span>耗时：{(report.durationMs / 1000).toFixed(1)} 秒</span>
+                    </Space>
+                    <Tabs
+                        items={[
+                            {
+                                key: 'prefixes',
+                                label: '前缀分布',
+                                children: (
+                                    <Table
+                                        size="small"
+                                        rowKey={(node: PrefixNode) => `${node.prefix}|${node.name}`}
+                                        dataSource={report.prefixes}
+                                        columns={prefixColumns}
+                                        pagination={false}
+                                        scroll={{ y: 400 }}
+                                    />
+                                )
+                            },
+                            {
+                                key: 'top-keys',
+                                label: `大 Key (Top ${report.topKeys.length})`,
+                                children: (
+                                    <Table size="small" rowKey="key" dataSource={report.topKeys} columns={keyColumns} pagination={false} scroll={{ y: 400 }} />
+                                )
+                            },
+                            {
+                                key: 'ttl',
+                                label: 'TTL 分布',
+                                children: (
+                                    <Table size="small" rowKey="label" dataSource={report.ttl} columns={bucketColumns('TTL', 'label')} pagination={false} />
+                                )
+                            },
+                            {
+                                key: 'types',
+                                label: '类型分布',
+                                children: (
+                                    <Table size="small" rowKey="type" dataSource={report.types} columns={bucketColumns('类型', 'type')} pagination={false} />
+                                )
+                            },
+                        ]}
+                    />
+                </>
+            )}
+        </Modal>
+    );
+};
+
+export default RedisAnalysisModal;
@@ -0,0 +1,138 @@
+import React, { useState, useEffect } from 'react';
+import { Modal, Table, Alert, Tag, Button, Descriptions } from 'antd';
+import { ReloadOutlined } from '@ant-design/icons';
+import { RedisGetClusterNodes } from '../../wailsjs/go/app/App';
+import { SavedConnection } from '../types';
+
+interface RedisClusterNodesModalProps {
+    open: boolean;
+    connection: SavedConnection | null;
+    onClose: () => void;
+}
+
+interface ClusterNode {
+    id: string;
+    addr: string;
+    role: string;
+    masterId?: string;
+    flags: string;
+    linkState: string;
+    slots?: string[];
+    keys: number;
+    usedMemory?: string;
+    info?: Record<string, string>;
+}
+
+const INFO_FIELDS = ['redis_version', 'uptime_in_days', 'connected_clients', 'used_memory_human', 'maxmemory_human', 'instantaneous_ops_per_sec', 'master_link_status'];
+
+const RedisClusterNodesModal: React.FC<RedisClusterNodesModalProps> = ({ open, connection, onClose }) => {
+    const [loading, setLoading] = useState(false);
+    const [nodes, setNodes] = useState<ClusterNode[]>([]);
+    const [error, setError] = useState<string | null>(null);
+
+    const loadNodes = async () => {
+        if (!connection) return;
+        setLoading(true);
+        setError(null);
+        try {
+            const config = {
+                ...connection.config,
+                port: Number(connection.config.port),
+                password: connection.config.password || "",
+                useSSH: connection.config.useSSH || false,
+                ssh: connection.config.ssh || { host: "", port: 22, user: "", password: "", keyPath: "" },
+                redisDB: 0
+            };
+            const res = await RedisGetClusterNodes(config as any);
+            if (res.success) {
+                setNodes((res.data as ClusterNode[]) || []);
+            } else {
+                setNodes([]);
+                setError(res.message);
+            }
+        } catch (e: any) {
+            setNodes([]);
+            setError('获取集群节点失败: ' + (e?.message || String(e)));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        if (open) {
+            loadNodes();
+        }
+    }, [open, connection]);
+
+    const masterAddr = (id?: string) => nodes.find(n => n.id === id)?.addr || id;
+
+    const columns = [
+        { title: '节点地址', dataIndex: 'addr', key: 'addr', width: 170 },
+        {
+            title: '角色',
+            dataIndex: 'role',
+            key: 'role',
+            width: 90,
+            render: (role: string) => role === 'master' ? <Tag color="red">主节点</Tag> : <Tag color="blue">从节点</Tag>
+        },
+        {
+            title: '所属主节点',
+            dataIndex: 'masterId',
+            key: 'masterId',
+            width: 170,
+            render: (id?: string) => id ? masterAddr(id) : '-'
+        },
+        {
+            title: '槽位',
+            dataIndex: 'slots',
+            key: 'slots',
+            render: (slots?: string[]) => slots && slots.length > 0 ? slots.join(', ') : '-'
+        },
+        { title: '键数量', dataIndex: 'keys', key: 'keys', width: 90 },
+        { title: '内存', dataIndex: 'usedMemory', key: 'usedMemory', width: 90, render: (v?: string) => v || '-' },
+        {
+            title: '状态',
+            key: 'state',
+            width: 140,
+            render: (_: any, node: ClusterNode) => {
+                const failed = node.flags.includes('fail') || node.linkState !== 'connected';
+                return <Tag color={failed ? 'error' : 'success'}>{failed ? node.flags : node.linkState}</Tag>;
+            }
+        },
+    ];
+
+    return (
+        <Modal
+            title={`集群节点 - ${connection?.name || ''}`}
+            open={open}
+            onCancel={onClose}
+            width={960}
+            footer={[
+                <Button key="refresh" icon={<ReloadOutlined />} onClick={loadNodes} loading={loading}>刷新</Button>,
+                <Button key="close" onClick={onClose}>关闭</Button>,
+            ]}
+        >
+            {error && <Alert type="error" showIcon message={error} style={{ marginBottom: 12 }} />}
+            <Table
+                size="small"
+                rowKey="id"
+                loading={loading}
+                dataSource={nodes}
+                columns={columns}
+                pagination={false}
+                expandable={{
+                    rowExpandable: (node: ClusterNode) => !!node.info,
+                    expandedRowRender: (node: ClusterNode) => (
+                        <Descriptions size="small" column={3}>
+                            {INFO_FIELDS.filter(f => node.info?.[f] !== undefined).map(f => (
+                                <Descriptions.Item key={f} label={f}>{node.info![f]}</Descriptions.Item>
+                            ))}
+                        </Descriptions>
+                    )
+                }}
+            />
+        </Modal>
+    );
+};
+
+export default RedisClusterNodesModal;
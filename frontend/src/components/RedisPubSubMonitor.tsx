@@ -0,0 +1,324 @@
+import React, { useState, useEffect, useCallback, useRef } from 'react';
+import { Button, Space, Select, Input, Checkbox, Table, Tag, Alert, Tooltip, message } from 'antd';
+import { PlayCircleOutlined, PauseCircleOutlined, ClearOutlined, SendOutlined, StopOutlined } from '@ant-design/icons';
+import { useStore } from '../store';
+import { RedisSubscribe, RedisUnsubscribe, RedisListSubscriptions, RedisPublish } from '../../wailsjs/go/app/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+
+interface RedisPubSubMonitorProps {
+    connectionId: string;
+    redisDB: number;
+}
+
+interface PubSubMessage {
+    channel: string;
+    pattern?: string;
+    payload: string;
+    time: number;
+}
+
+interface MonitorMessage extends PubSubMessage {
+    seq: number;
+    subscriptionId: string;
+}
+
+interface SubscriptionInfo {
+    id: string;
+    summary: string;
+    channels: string[];
+    patterns: string[];
+    startedAt: number;
+    received: number;
+}
+
+const MAX_MESSAGES = 2000;
+
+const KEY_EVENT_OPTIONS = ['expired', 'evicted', 'set', 'del', 'expire', 'new', 'rename_to', 'hset', 'lpush', 'sadd', 'zadd', 'xadd', '*']
+    .map(e => ({ label: e, value: e }));
+
+const formatTime = (ms: number) => {
+    const d = new Date(ms);
+    const pad = (n: number, len = 2) => String(n).padStart(len, '0');
+    return `${pad(d.getHours())}:${pad(d.getMinutes())}:${pad(d.getSeconds())}.${pad(d.getMilliseconds(), 3)}`;
+};
+
+const RedisPubSubMonitor: React.FC<RedisPubSubMonitorProps> = ({ connectionId, redisDB }) => {
+    const { connections } = useStore();
+    const connection = connections.find(c => c.id === connectionId);
+    const isCluster = connection?.config.topology === 'cluster';
+
+    const [channels, setChannels] = useState<string[]>([]);
+    const [patterns, setPatterns] = useState<string[]>([]);
+    const [keyEvents, setKeyEvents] = useState<string[]>([]);
+    const [keyPattern, setKeyPattern] = useState('');
+    const [enableNotify, setEnableNotify] = useState(false);
+    const [subscribing, setSubscribing] = useState(false);
+    const [sessions, setSessions] = useState<SubscriptionInfo[]>([]);
+    const [messages, setMessages] = useState<MonitorMessage[]>([]);
+    const [dropped, setDropped] = useState(0);
+    const [paused, setPaused] = useState(false);
+    const [filter, setFilter] = useState('');
+    const [warning, setWarning] = useState<string | null>(null);
+    const [publishChannel, setPublishChannel] = useState('');
+    const [publishMessage, setPublishMessage] = useState('');
+    const [publishing, setPublishing] = useState(false);
+
+    const sessionIdsRef = useRef<Set<string>>(new Set());
+    const ownIdsRef = useRef<Set<string>>(new Set());
+    const pausedRef = useRef(false);
+    const seqRef = useRef(0);
+
+    const getConfig = useCallback(() => {
+        if (!connection) return null;
+        return {
+            ...connection.config,
+            port: Number(connection.config.port),
+            password: connection.config.password || "",
+            useSSH: connection.config.useSSH || false,
+            ssh: connection.config.ssh || { host: "", port: 22, user: "", password: "", keyPath: "" },
+            redisDB: redisDB
+        };
+    }, [connection, redisDB]);
+
+    const loadSessions = useCallback(async () => {
+        const config = getConfig();
+        if (!config) return;
+        try {
+            const res = await RedisListSubscriptions(config as any);
+            if (res.success) {
+                const list = (res.data as SubscriptionInfo[]) || [];
+                sessionIdsRef.current = new Set(list.map(s => s.id));
+                setSessions(list);
+            }
+        } catch (e) {
+            console.error('Failed to load subscriptions:', e);
+        }
+    }, [getConfig]);
+
+    const loadSessionsRef = useRef(loadSessions);
+    loadSessionsRef.current = loadSessions;
+
+    useEffect(() => {
+        pausedRef.current = paused;
+    }, [paused]);
+
+    useEffect(() => {
+        loadSessionsRef.current();
+        const offMessage = EventsOn('redis:pubsub-message', (event: { subscriptionId: string; messages: PubSubMessage[] | null; dropped: number }) => {
+            if (!sessionIdsRef.current.has(event.subscriptionId) || pausedRef.current) return;
+            const incoming = (event.messages || []).map(m => ({ ...m, subscriptionId: event.subscriptionId, seq: ++seqRef.current }));
+            if (incoming.length > 0) {
+                setMessages(prev => [...incoming.reverse(), ...prev].slice(0, MAX_MESSAGES));
+            }
+            if (event.dropped > 0) {
+                setDropped(prev => prev + event.dropped);
+            }
+        });
+        const offClosed = EventsOn('redis:pubsub-closed', (event: { subscriptionId: string; message: string }) => {
+            if (!sessionIdsRef.current.has(event.subscriptionId)) return;
+            ownIdsRef.current.delete(event.subscriptionId);
+            if (event.message) {
+                message.warning(`订阅已断开：${event.message}`);
+            }
+            loadSessionsRef.current();
+        });
+        return () => {
+            offMessage();
+            offClosed();
+            // 关闭标签页时结束本页发起的订阅
+            ownIdsRef.current.forEach(id => RedisUnsubscribe(id));
+            ownIdsRef.current.clear();
+        };
+    }, []);
+
+    const handleSubscribe = async () => {
+        const config = getConfig();
+        if (!config) return;
+        if (channels.length === 0 && patterns.length === 0 && keyEvents.length === 0 && !keyPattern.trim()) {
+            message.warning('请至少填写一个频道、模式或键空间事件');
+            return;
+        }
+        const subscriptionId = `redis-sub-${connectionId}-${Date.now()}`;
+        setSubscribing(true);
+        try {
+            const res = await RedisSubscribe(config as any, {
+                channels,
+                patterns,
+                keyEvents,
+                keyPattern: keyPattern.trim(),
+                enableNotify,
+            } as any, subscriptionId);
+            if (res.success) {
+                ownIdsRef.current.add(subscriptionId);
+                sessionIdsRef.current.add(subscriptionId);
+                setWarning(res.message && res.message !== '订阅成功' ? res.message : null);
+                message.success('订阅成功');
+                loadSessions();
+            } else {
+                message.error(res.message);
+            }
+        } catch (e: any) {
+            message.error('订阅失败: ' + (e?.message || String(e)));
+        } finally {
+            setSubscribing(false);
+        }
+    };
+
+    const handleUnsubscribe = async (id: string) => {
+        const res = await RedisUnsubscribe(id);
+        ownIdsRef.current.delete(id);
+        if (!res.success) {
+            message.warning(res.message);
+        }
+        loadSessions();
+    };
+
+    const handlePublish = async () => {
+        const config = getConfig();
+        if (!config) return;
+        if (!publishChannel.trim()) {
+            message.warning('请输入频道');
+            return;
+        }
+        setPublishing(true);
+        try {
+            const res = await RedisPublish(config as any, publishChannel.trim(), publishMessage);
+            if (res.success) {
+                message.success(res.message);
+            } else {
+                message.error(res.message);
+            }
+        } catch (e: any) {
+            message.error('发布失败: ' + (e?.message || String(e)));
+        } finally {
+            setPublishing(false);
+        }
+    };
+
+    const handleClear = () => {
+        setMessages([]);
+        setDropped(0);
+    };
+
+    if (!connection) {
+        return <div style={{ padding: 20 }}>连接不存在</div>;
+    }
+
+    const keyword = filter.trim().toLowerCase();
+    const visibleMessages = keyword
+        ? messages.filter(m => m.channel.toLowerCase().includes(keyword) || m.payload.toLowerCase().includes(keyword))
+        : messages;
+
+    const columns = [
+        { title: '时间', dataIndex: 'time', key: 'time', width: 110, render: (t: number) => formatTime(t) },
+        {
+            title: '频道',
+            dataIndex: 'channel',
+            key: 'channel',
+            width: 260,
+            ellipsis: true,
+            render: (channel: string, record: MonitorMessage) => (
+                <Tooltip title={record.pattern ? `模式：${record.pattern}` : undefined}>
+                    <span>{channel}</span>
+                </Tooltip>
+            )
+        },
+        {
+            title: '消息',
+            dataIndex: 'payload',
+            key: 'payload',
+            render: (payload: string) => <span style={{ fontFamily: 'monospace', whiteSpace: 'pre-wrap', wordBreak: 'break-all' }}>{payload}</span>
+        },
+    ];
+
+    return (
+        <div style={{ display: 'flex', flexDirection: 'column', height: '100%' }}>
+            {/* Subscribe */}
+            <div style={{ padding: '8px 12px', borderBottom: '1px solid #f0f0f0' }}>
+                <Space wrap style={{ marginBottom: 8 }}>
+                    <span style={{ fontWeight: 500 }}>Pub/Sub 监控</span>
+                    <span style={{ color: '#999', fontSize: 12 }}>db{redisDB}</span>
+                    <Select mode="tags" placeholder="频道 (SUBSCRIBE)" value={channels} onChange={setChannels} style={{ minWidth: 200 }} tokenSeparators={[',', ' ']} open={false} />
+                    <Select mode="tags" placeholder="模式 (PSUBSCRIBE)" value={patterns} onChange={setPatterns} style={{ minWidth: 200 }} tokenSeparators={[',', ' ']} open={false} />
+                </Space>
+                <Space wrap>
+                    <Select
+                        mode="tags"
+                        placeholder="键事件，如 expired"
+                        value={keyEvents}
+                        onChange={setKeyEvents}
+                        options={KEY_EVENT_OPTIONS}
+                        style={{ minWidth: 240 }}
+                        disabled={isCluster}
+                    />
+                    <Input
+                        placeholder="键空间模式，如 user:*"
+                        value={keyPattern}
+                        onChange={e => setKeyPattern(e.target.value)}
+                        style={{ width: 200 }}
+                        disabled={isCluster}
+                    />
+                    <Tooltip title="订阅前通过 CONFIG SET 补齐 notify-keyspace-events 所需标志">
+                        <Checkbox checked={enableNotify} onChange={e => setEnableNotify(e.target.checked)} disabled={isCluster}>开启键空间通知</Checkbox>
+                    </Tooltip>
+                    <Button type="primary" icon={<PlayCircleOutlined />} onClick={handleSubscribe} loading={subscribing}>订阅</Button>
+                </Space>
+                {isCluster && <div style={{ color: '#999', fontSize: 12, marginTop: 4 }}>集群模式下键空间通知由各节点单独发布，仅支持频道订阅</div>}
+                {warning && <Alert type="warning" showIcon closable message={warning} onClose={() => setWarning(null)} style={{ marginTop: 8 }} />}
+                {sessions.length > 0 && (
+                    <div style={{ marginTop: 8 }}>
+                        {sessions.map(s => (
+                            <Tag
+                                key={s.id}
+                                color="processing"
+                                closable
+                                closeIcon={<StopOutlined />}
+                                onClose={(e) => { e.preventDefault(); handleUnsubscribe(s.id); }}
+                                style={{ marginBottom: 4 }}
+                            >
+                                {[...(s.channels || []), ...(s.patterns || [])].join(', ')}
+                            </Tag>
+                        ))}
+                    </div>
+                )}
+            </div>
+
+            {/* Messages */}
+            <div style={{ padding: '6px 12px', display: 'flex', justifyContent: 'space-between', alignItems: 'center', borderBottom: '1px solid #f0f0f0' }}>
+                <Space>
+                    <Input.Search placeholder="过滤频道或消息" allowClear onSearch={setFilter} style={{ width: 220 }} />
+                    <span style={{ color: '#999', fontSize: 12 }}>
+                        {messages.length} 条{messages.length >= MAX_MESSAGES ? `（仅保留最近 ${MAX_MESSAGES} 条）` : ''}{dropped > 0 ? `，因消息过快丢弃 ${dropped} 条` : ''}
+                    </span>
+                </Space>
+                <Space>
+                    <Button icon={paused ? <PlayCircleOutlined /> : <PauseCircleOutlined />} onClick={() => setPaused(!paused)}>
+                        {paused ? '继续' : '暂停'}
+                    </Button>
+                    <Button icon={<ClearOutlined />} onClick={handleClear}>清空</Button>
+                </Space>
+            </div>
+            <div style={{ flex: 1, overflow: 'auto' }}>
+                <Table
+                    size="small"
+                    rowKey="seq"
+                    dataSource={visibleMessages}
+                    columns={columns}
+                    pagination={false}
+                    locale={{ emptyText: sessions.length > 0 ? '等待消息...' : '订阅频道或键空间事件后，收到的消息将显示在这里' }}
+                />
+            </div>
+
+            {/* Publish */}
+            <div style={{ padding: '8px 12px', borderTop: '1px solid #f0f0f0', background: '#fafafa' }}>
+                <Space.Compact style={{ width: '100%' }}>
+                    <Input placeholder="频道" value={publishChannel} onChange={e => setPublishChannel(e.target.value)} style={{ width: 200 }} />
+                    <Input placeholder="消息内容" value={publishMessage} onChange={e => setPublishMessage(e.target.value)} onPressEnter={handlePublish} />
+                    <Button type="primary" icon={<SendOutlined />} onClick={handlePublish} loading={publishing}>发布</Button>
+                </Space.Compact>
+            </div>
+        </div>
+    );
+};
+
+export default RedisPubSubMonitor;
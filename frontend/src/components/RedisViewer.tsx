@@ -423,6 +423,10 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
     const [selectedKey, setSelectedKey] = useState<string | null>(null);
     const [keyValue, setKeyValue] = useState<RedisValue | null>(null);
     const [valueLoading, setValueLoading] = useState(false);
+    // Hash/Set/ZSet 成员分页：游标不为 0 表示还有未加载的成员
+    const [memberCursor, setMemberCursor] = useState<number>(0);
+    const [memberPattern, setMemberPattern] = useState('');
+    const [memberLoading, setMemberLoading] = useState(false);
     const [editModalOpen, setEditModalOpen] = useState(false);
     const [newKeyModalOpen, setNewKeyModalOpen] = useState(false);
     const [newKeyForm] = Form.useForm();
@@ -523,6 +527,8 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
             if (res.success) {
                 setKeyValue(res.data);
                 setSelectedKey(key);
+                setMemberCursor(res.data.cursor || 0);
+                setMemberPattern('');
             } else {
                 message.error('获取值失败: ' + res.message);
             }
@@ -533,6 +539,41 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
         }
     };
 
+    // 按游标分页加载 Hash/Set/ZSet 成员，pattern 为服务端 MATCH 过滤条件；append 为 false 时替换已加载的成员
+    const scanMembers = async (pattern: string, fromCursor: number, append: boolean) => {
+        const config = getConfig();
+        if (!config || !selectedKey || !keyValue) return;
+        const method = keyValue.type === 'hash' ? 'RedisScanHash' : (keyValue.type === 'set' ? 'RedisScanSet' : 'RedisScanZSet');
+
+        setMemberLoading(true);
+        try {
+            const res = await (window as any).go.app.App[method](config, selectedKey, pattern || '*', fromCursor, 1000);
+            if (res.success) {
+                const page = res.data;
+                setKeyValue(prev => {
+                    if (!prev) return prev;
+                    let value = page.value;
+                    if (append && prev.type === 'hash') {
+                        value = { ...(prev.value as Record<string, string>), ...page.value };
+                    } else if (append && prev.type === 'set') {
+                        value = Array.from(new Set([...(prev.value as string[]), ...page.value]));
+                    } else if (append) {
+                        const seen = new Set((prev.value as Array<{ member: string }>).map(item => item.member));
+                        value = [...prev.value, ...(page.value as Array<{ member: string }>).filter(item => !seen.has(item.member))];
+                    }
+                    return { ...prev, value, length: page.length, cursor: page.cursor };
+                });
+                setMemberCursor(page.cursor || 0);
+            } else {
+                message.error('加载成员失败: ' + res.message);
+            }
+        } catch (e: any) {
+            message.error('加载成员失败: ' + (e?.message || String(e)));
+        } finally {
+            setMemberLoading(false);
+        }
+    };
+
     const handleDeleteKeys = async (keysToDelete: string[]) => {
         const config = getConfig();
         if (!config) return;
@@ -812,6 +853,32 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
             );
         };
 
+        const renderMemberPager = () => {
+            const loaded = keyValue.type === 'hash'
+                ? Object.keys(keyValue.value || {}).length
+                : (keyValue.value as any[]).length;
+            return (
+                <div style={{ marginBottom: 8, display: 'flex', gap: 8, alignItems: 'center' }}>
+                    <Search
+                        size="small"
+                        allowClear
+                        placeholder="服务端过滤，如 user:*"
+                        style={{ width: 260 }}
+                        value={memberPattern}
+                        onChange={(e) => setMemberPattern(e.target.value)}
+                        onSearch={(value) => scanMembers(value.trim(), 0, false)}
+                        loading={memberLoading}
+                    />
+                    <span style={{ color: '#999', fontSize: 12 }}>已加载 {loaded} / 共 {keyValue.length}</span>
+                    {memberCursor !== 0 && (
+                        <Button size="small" loading={memberLoading} onClick={() => scanMembers(memberPattern.trim(), memberCursor, true)}>
+                            加载更多
+                        </Button>
+                    )}
+                </div>
+            );
+        };
+
         const renderHashValue = () => {
             // 根据查看模式处理值
             const processValue = (value: string) => {
@@ -905,6 +972,7 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
                             <Radio.Button value="hex">十六进制</Radio.Button>
                         </Radio.Group>
                     </div>
+                    {renderMemberPager()}
                     <Table
                         dataSource={data}
                         columns={[
@@ -1240,6 +1308,7 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
                             <Radio.Button value="hex">十六进制</Radio.Button>
                         </Radio.Group>
                     </div>
+                    {renderMemberPager()}
                     <Table
                         dataSource={data}
                         columns={[
@@ -1393,6 +1462,7 @@ const RedisViewer: React.FC<RedisViewerProps> = ({ connectionId, redisDB }) => {
                             <Radio.Button value="hex">十六进制</Radio.Button>
                         </Radio.Group>
                     </div>
+                    {renderMemberPager()}
                     <Table
                         dataSource={data}
                         columns={[
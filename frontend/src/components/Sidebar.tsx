@@ -26,12 +26,17 @@ import { Tree, message, Dropdown, MenuProps, Input, Button, Modal, Form, Badge,
   DisconnectOutlined,
   CloudOutlined,
   CheckSquareOutlined,
-  CodeOutlined
+  CodeOutlined,
+  ClusterOutlined,
+  PieChartOutlined,
+  NotificationOutlined
 	} from '@ant-design/icons';
 	import { useStore } from '../store';
 	import { SavedConnection } from '../types';
 	import { DBGetDatabases, DBGetTables, DBQuery, DBShowCreateTable, ExportTable, OpenSQLFile, CreateDatabase, RenameDatabase, DropDatabase, RenameTable, DropTable, DropView, DropFunction, RenameView } from '../../wailsjs/go/app/App';
   import { normalizeOpacityForPlatform } from '../utils/appearance';
+import RedisClusterNodesModal from './RedisClusterNodesModal';
+import RedisAnalysisModal from './RedisAnalysisModal';
 
 const { Search } = Input;
 
@@ -132,6 +137,12 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
   const [batchConnContext, setBatchConnContext] = useState<any>(null);
   const [selectedDbConnection, setSelectedDbConnection] = useState<string>('');
 
+  // Redis Cluster nodes Modal
+  const [clusterNodesConn, setClusterNodesConn] = useState<SavedConnection | null>(null);
+
+  // Redis memory analysis Modal
+  const [analysisTarget, setAnalysisTarget] = useState<{ conn: SavedConnection; redisDB: number } | null>(null);
+
   useEffect(() => {
       // Refresh queries for expanded databases
       const findNode = (nodes: TreeNode[], k: React.Key): TreeNode | null => {
@@ -458,7 +469,7 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
 
       for (const spec of normalizedSpecs) {
           try {
-              const result = await DBQuery(config as any, dbName, spec.sql);
+              const result = await DBQuery(config as any, dbName, spec.sql, '');
               if (!result.success || !Array.isArray(result.data)) {
                   continue;
               }
@@ -1699,7 +1710,7 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
                   break;
           }
           if (query) {
-              const result = await DBQuery(config as any, dbName, query);
+              const result = await DBQuery(config as any, dbName, query, '');
               if (result.success && Array.isArray(result.data) && result.data.length > 0) {
                   const row = result.data[0] as Record<string, any>;
                   const def = row.view_definition || row.VIEW_DEFINITION || Object.values(row).find(v => typeof v === 'string' && String(v).length > 10) || '';
@@ -1858,7 +1869,7 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
               }
           }
           if (query) {
-              const result = await DBQuery(config as any, dbName, query);
+              const result = await DBQuery(config as any, dbName, query, '');
               if (result.success && Array.isArray(result.data) && result.data.length > 0) {
                   if (dialect === 'oracle' || dialect === 'dm') {
                       const lines = result.data.map((row: any) => row.text || row.TEXT || Object.values(row)[0] || '').join('');
@@ -2072,6 +2083,26 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
                         });
                     }
                 },
+                {
+                    key: 'pubsub',
+                    label: 'Pub/Sub 监控',
+                    icon: <NotificationOutlined />,
+                    onClick: () => {
+                        addTab({
+                            id: `redis-pubsub-${node.key}-db0`,
+                            title: `Pub/Sub - ${node.title}`,
+                            type: 'redis-pubsub',
+                            connectionId: node.key,
+                            redisDB: 0
+                        });
+                    }
+                },
+                ...(conn.config.topology === 'cluster' ? [{
+                    key: 'cluster-nodes',
+                    label: '集群节点',
+                    icon: <ClusterOutlined />,
+                    onClick: () => setClusterNodesConn(conn)
+                }] : []),
                 { type: 'divider' },
                 {
                     key: 'edit',
@@ -2236,6 +2267,26 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
                         redisDB: redisDB
                     });
                 }
+            },
+            {
+                key: 'pubsub',
+                label: 'Pub/Sub 监控',
+                icon: <NotificationOutlined />,
+                onClick: () => {
+                    addTab({
+                        id: `redis-pubsub-${id}-db${redisDB}`,
+                        title: `Pub/Sub - db${redisDB}`,
+                        type: 'redis-pubsub',
+                        connectionId: id,
+                        redisDB: redisDB
+                    });
+                }
+            },
+            {
+                key: 'memory-analysis',
+                label: '内存分析',
+                icon: <PieChartOutlined />,
+                onClick: () => setAnalysisTarget({ conn: node.dataRef as SavedConnection, redisDB })
             }
         ];
     } else if (node.type === 'database') {
@@ -2848,6 +2899,19 @@ const Sidebar: React.FC<{ onEditConnection?: (conn: SavedConnection) => void }>
                 </>
             )}
         </Modal>
+
+        <RedisClusterNodesModal
+            open={!!clusterNodesConn}
+            connection={clusterNodesConn}
+            onClose={() => setClusterNodesConn(null)}
+        />
+
+        <RedisAnalysisModal
+            open={!!analysisTarget}
+            connection={analysisTarget?.conn || null}
+            redisDB={analysisTarget?.redisDB || 0}
+            onClose={() => setAnalysisTarget(null)}
+        />
     </div>
   );
 };
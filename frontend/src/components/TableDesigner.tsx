@@ -621,7 +621,7 @@ ${selectedTrigger.statement}`;
         const dropSql = buildDropTriggerSql(selectedTrigger.name);
 
         try {
-          const res = await DBQuery(config as any, tab.dbName || '', dropSql);
+          const res = await DBQuery(config as any, tab.dbName || '', dropSql, '');
           if (res.success) {
             message.success('触发器删除成功');
             setSelectedTrigger(null);
@@ -658,7 +658,7 @@ ${selectedTrigger.statement}`;
       // 如果是编辑模式，先删除旧触发器
       if (triggerEditMode === 'edit' && selectedTrigger) {
         const dropSql = buildDropTriggerSql(selectedTrigger.name);
-        const dropRes = await DBQuery(config as any, tab.dbName || '', dropSql);
+        const dropRes = await DBQuery(config as any, tab.dbName || '', dropSql, '');
         if (!dropRes.success) {
           message.error('删除旧触发器失败: ' + dropRes.message);
           setTriggerExecuting(false);
@@ -667,7 +667,7 @@ ${selectedTrigger.statement}`;
       }
 
       // 执行创建语句
-      const res = await DBQuery(config as any, tab.dbName || '', triggerEditSql);
+      const res = await DBQuery(config as any, tab.dbName || '', triggerEditSql, '');
       if (res.success) {
         message.success(triggerEditMode === 'create' ? '触发器创建成功' : '触发器修改成功');
         setIsTriggerEditModalOpen(false);
@@ -840,7 +840,7 @@ ${selectedTrigger.statement}`;
 	      const conn = connections.find(c => c.id === tab.connectionId);
 	      if (!conn) return;
 	      const config = { ...conn.config, port: Number(conn.config.port), password: conn.config.password || "", database: conn.config.database || "", useSSH: conn.config.useSSH || false, ssh: conn.config.ssh || { host: "", port: 22, user: "", password: "", keyPath: "" } };
-	      const res = await DBQuery(config as any, tab.dbName || '', previewSql);
+	      const res = await DBQuery(config as any, tab.dbName || '', previewSql, '');
 	      if (res.success) {
 	          message.success(isNewTable ? "表创建成功！" : "表结构修改成功！");
 	          setIsPreviewOpen(false);
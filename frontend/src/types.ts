@@ -6,6 +6,14 @@ export interface SSHConfig {
   keyPath?: string;
 }
 
+export interface TLSConfig {
+  caPath?: string;
+  certPath?: string;
+  keyPath?: string;
+  serverName?: string;
+  skipVerify?: boolean;
+}
+
 export interface ConnectionConfig {
   type: string;
   host: string;
@@ -16,13 +24,18 @@ export interface ConnectionConfig {
   database?: string;
   useSSH?: boolean;
   ssh?: SSHConfig;
+  useTLS?: boolean;
+  tls?: TLSConfig;
   driver?: string;
   dsn?: string;
   timeout?: number;
   redisDB?: number; // Redis database index (0-15)
+  redisUser?: string; // Redis ACL username (Redis 6+)
+  sentinelMaster?: string; // Redis Sentinel master name
+  sentinelPassword?: string;
   uri?: string; // Connection URI for copy/paste
   hosts?: string[]; // Multi-host addresses: host:port
-  topology?: 'single' | 'replica';
+  topology?: 'single' | 'replica' | 'cluster' | 'sentinel';
   mysqlReplicaUser?: string;
   mysqlReplicaPassword?: string;
   replicaSet?: string;
@@ -87,7 +100,7 @@ export interface TriggerDefinition {
 export interface TabData {
   id: string;
   title: string;
-  type: 'query' | 'table' | 'design' | 'redis-keys' | 'redis-command' | 'trigger' | 'view-def' | 'routine-def';
+  type: 'query' | 'table' | 'design' | 'redis-keys' | 'redis-command' | 'redis-pubsub' | 'trigger' | 'view-def' | 'routine-def';
   connectionId: string;
   dbName?: string;
   tableName?: string;
@@ -135,6 +148,7 @@ export interface RedisValue {
   ttl: number;
   value: any;
   length: number;
+  cursor?: number; // Non-zero when value is only the first page of a hash/set/zset
 }
 
 export interface RedisDBInfo {
@@ -6,12 +6,22 @@ import {redis} from '../models';
 
 export function ApplyChanges(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:connection.ChangeSet):Promise<connection.QueryResult>;
 
+export function BeginSession(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:string,arg5:number):Promise<connection.QueryResult>;
+
+export function CancelQuery(arg1:string):Promise<connection.QueryResult>;
+
+export function CancelSync(arg1:string):Promise<connection.QueryResult>;
+
 export function CheckForUpdates():Promise<connection.QueryResult>;
 
+export function CommitSession(arg1:string):Promise<connection.QueryResult>;
+
 export function CreateDatabase(arg1:connection.ConnectionConfig,arg2:string):Promise<connection.QueryResult>;
 
 export function DBConnect(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
+export function DBExecuteScript(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:string,arg5:boolean):Promise<connection.QueryResult>;
+
 export function DBGetAllColumns(arg1:connection.ConnectionConfig,arg2:string):Promise<connection.QueryResult>;
 
 export function DBGetColumns(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
@@ -26,7 +36,7 @@ export function DBGetTables(arg1:connection.ConnectionConfig,arg2:string):Promis
 
 export function DBGetTriggers(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
 
-export function DBQuery(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
+export function DBQuery(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:string):Promise<connection.QueryResult>;
 
 export function DBShowCreateTable(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
 
@@ -36,6 +46,8 @@ export function DataSyncAnalyze(arg1:sync.SyncConfig):Promise<connection.QueryRe
 
 export function DataSyncPreview(arg1:sync.SyncConfig,arg2:string,arg3:number):Promise<connection.QueryResult>;
 
+export function DeleteSyncJob(arg1:string):Promise<connection.QueryResult>;
+
 export function DownloadUpdate():Promise<connection.QueryResult>;
 
 export function DropDatabase(arg1:connection.ConnectionConfig,arg2:string):Promise<connection.QueryResult>;
@@ -60,6 +72,8 @@ export function ExportTablesSQL(arg1:connection.ConnectionConfig,arg2:string,arg
 
 export function GetAppInfo():Promise<connection.QueryResult>;
 
+export function GetRunningSyncJobs():Promise<connection.QueryResult>;
+
 export function ImportConfigFile():Promise<connection.QueryResult>;
 
 export function ImportData(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
@@ -68,6 +82,12 @@ export function ImportDataWithProgress(arg1:connection.ConnectionConfig,arg2:str
 
 export function InstallUpdateAndRestart():Promise<connection.QueryResult>;
 
+export function ListSessions():Promise<connection.QueryResult>;
+
+export function ListSyncJobRuns(arg1:string,arg2:number):Promise<connection.QueryResult>;
+
+export function ListSyncJobs():Promise<connection.QueryResult>;
+
 export function MongoDiscoverMembers(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
 export function MySQLConnect(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
@@ -82,8 +102,12 @@ export function MySQLShowCreateTable(arg1:connection.ConnectionConfig,arg2:strin
 
 export function OpenSQLFile():Promise<connection.QueryResult>;
 
+export function PauseSync(arg1:string):Promise<connection.QueryResult>;
+
 export function PreviewImportFile(arg1:string):Promise<connection.QueryResult>;
 
+export function RedisAnalyzeKeys(arg1:connection.ConnectionConfig,arg2:redis.RedisAnalysisOptions,arg3:string):Promise<connection.QueryResult>;
+
 export function RedisConnect(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
 export function RedisDeleteHashField(arg1:connection.ConnectionConfig,arg2:string,arg3:Array<string>):Promise<connection.QueryResult>;
@@ -94,6 +118,8 @@ export function RedisExecuteCommand(arg1:connection.ConnectionConfig,arg2:string
 
 export function RedisFlushDB(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
+export function RedisGetClusterNodes(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
+
 export function RedisGetDatabases(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
 export function RedisGetServerInfo(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
@@ -104,10 +130,20 @@ export function RedisListPush(arg1:connection.ConnectionConfig,arg2:string,arg3:
 
 export function RedisListSet(arg1:connection.ConnectionConfig,arg2:string,arg3:number,arg4:string):Promise<connection.QueryResult>;
 
+export function RedisListSubscriptions(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
+
+export function RedisPublish(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
+
 export function RedisRenameKey(arg1:connection.ConnectionConfig,arg2:string,arg3:string):Promise<connection.QueryResult>;
 
+export function RedisScanHash(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:number,arg5:number):Promise<connection.QueryResult>;
+
 export function RedisScanKeys(arg1:connection.ConnectionConfig,arg2:string,arg3:number,arg4:number):Promise<connection.QueryResult>;
 
+export function RedisScanSet(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:number,arg5:number):Promise<connection.QueryResult>;
+
+export function RedisScanZSet(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:number,arg5:number):Promise<connection.QueryResult>;
+
 export function RedisSelectDB(arg1:connection.ConnectionConfig,arg2:number):Promise<connection.QueryResult>;
 
 export function RedisSetAdd(arg1:connection.ConnectionConfig,arg2:string,arg3:Array<string>):Promise<connection.QueryResult>;
@@ -124,8 +160,12 @@ export function RedisStreamAdd(arg1:connection.ConnectionConfig,arg2:string,arg3
 
 export function RedisStreamDelete(arg1:connection.ConnectionConfig,arg2:string,arg3:Array<string>):Promise<connection.QueryResult>;
 
+export function RedisSubscribe(arg1:connection.ConnectionConfig,arg2:redis.RedisSubscribeOptions,arg3:string):Promise<connection.QueryResult>;
+
 export function RedisTestConnection(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
 
+export function RedisUnsubscribe(arg1:string):Promise<connection.QueryResult>;
+
 export function RedisZSetAdd(arg1:connection.ConnectionConfig,arg2:string,arg3:Array<redis.ZSetMember>):Promise<connection.QueryResult>;
 
 export function RedisZSetRemove(arg1:connection.ConnectionConfig,arg2:string,arg3:Array<string>):Promise<connection.QueryResult>;
@@ -136,6 +176,20 @@ export function RenameTable(arg1:connection.ConnectionConfig,arg2:string,arg3:st
 
 export function RenameView(arg1:connection.ConnectionConfig,arg2:string,arg3:string,arg4:string):Promise<connection.QueryResult>;
 
+export function ResumeSync(arg1:string):Promise<connection.QueryResult>;
+
+export function RollbackSession(arg1:string):Promise<connection.QueryResult>;
+
+export function RunSyncJob(arg1:string):Promise<connection.QueryResult>;
+
+export function SaveSQLScript(arg1:string,arg2:string):Promise<connection.QueryResult>;
+
+export function SaveSyncJob(arg1:sync.SyncJob):Promise<connection.QueryResult>;
+
+export function SchemaCompare(arg1:sync.SyncConfig):Promise<connection.QueryResult>;
+
+export function SessionQuery(arg1:string,arg2:string,arg3:string):Promise<connection.QueryResult>;
+
 export function SetWindowTranslucency(arg1:number,arg2:number):Promise<void>;
 
 export function TestConnection(arg1:connection.ConnectionConfig):Promise<connection.QueryResult>;
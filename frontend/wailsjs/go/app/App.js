@@ -6,10 +6,26 @@ export function ApplyChanges(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['ApplyChanges'](arg1, arg2, arg3, arg4);
 }
 
+export function BeginSession(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['BeginSession'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CancelQuery(arg1) {
+  return window['go']['app']['App']['CancelQuery'](arg1);
+}
+
+export function CancelSync(arg1) {
+  return window['go']['app']['App']['CancelSync'](arg1);
+}
+
 export function CheckForUpdates() {
   return window['go']['app']['App']['CheckForUpdates']();
 }
 
+export function CommitSession(arg1) {
+  return window['go']['app']['App']['CommitSession'](arg1);
+}
+
 export function CreateDatabase(arg1, arg2) {
   return window['go']['app']['App']['CreateDatabase'](arg1, arg2);
 }
@@ -18,6 +34,10 @@ export function DBConnect(arg1) {
   return window['go']['app']['App']['DBConnect'](arg1);
 }
 
+export function DBExecuteScript(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['DBExecuteScript'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function DBGetAllColumns(arg1, arg2) {
   return window['go']['app']['App']['DBGetAllColumns'](arg1, arg2);
 }
@@ -46,8 +66,8 @@ export function DBGetTriggers(arg1, arg2, arg3) {
   return window['go']['app']['App']['DBGetTriggers'](arg1, arg2, arg3);
 }
 
-export function DBQuery(arg1, arg2, arg3) {
-  return window['go']['app']['App']['DBQuery'](arg1, arg2, arg3);
+export function DBQuery(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['DBQuery'](arg1, arg2, arg3, arg4);
 }
 
 export function DBShowCreateTable(arg1, arg2, arg3) {
@@ -66,6 +86,10 @@ export function DataSyncPreview(arg1, arg2, arg3) {
   return window['go']['app']['App']['DataSyncPreview'](arg1, arg2, arg3);
 }
 
+export function DeleteSyncJob(arg1) {
+  return window['go']['app']['App']['DeleteSyncJob'](arg1);
+}
+
 export function DownloadUpdate() {
   return window['go']['app']['App']['DownloadUpdate']();
 }
@@ -114,6 +138,10 @@ export function GetAppInfo() {
   return window['go']['app']['App']['GetAppInfo']();
 }
 
+export function GetRunningSyncJobs() {
+  return window['go']['app']['App']['GetRunningSyncJobs']();
+}
+
 export function ImportConfigFile() {
   return window['go']['app']['App']['ImportConfigFile']();
 }
@@ -130,6 +158,18 @@ export function InstallUpdateAndRestart() {
   return window['go']['app']['App']['InstallUpdateAndRestart']();
 }
 
+export function ListSessions() {
+  return window['go']['app']['App']['ListSessions']();
+}
+
+export function ListSyncJobRuns(arg1, arg2) {
+  return window['go']['app']['App']['ListSyncJobRuns'](arg1, arg2);
+}
+
+export function ListSyncJobs() {
+  return window['go']['app']['App']['ListSyncJobs']();
+}
+
 export function MongoDiscoverMembers(arg1) {
   return window['go']['app']['App']['MongoDiscoverMembers'](arg1);
 }
@@ -158,10 +198,18 @@ export function OpenSQLFile() {
   return window['go']['app']['App']['OpenSQLFile']();
 }
 
+export function PauseSync(arg1) {
+  return window['go']['app']['App']['PauseSync'](arg1);
+}
+
 export function PreviewImportFile(arg1) {
   return window['go']['app']['App']['PreviewImportFile'](arg1);
 }
 
+export function RedisAnalyzeKeys(arg1, arg2, arg3) {
+  return window['go']['app']['App']['RedisAnalyzeKeys'](arg1, arg2, arg3);
+}
+
 export function RedisConnect(arg1) {
   return window['go']['app']['App']['RedisConnect'](arg1);
 }
@@ -182,6 +230,10 @@ export function RedisFlushDB(arg1) {
   return window['go']['app']['App']['RedisFlushDB'](arg1);
 }
 
+export function RedisGetClusterNodes(arg1) {
+  return window['go']['app']['App']['RedisGetClusterNodes'](arg1);
+}
+
 export function RedisGetDatabases(arg1) {
   return window['go']['app']['App']['RedisGetDatabases'](arg1);
 }
@@ -202,14 +254,34 @@ export function RedisListSet(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['RedisListSet'](arg1, arg2, arg3, arg4);
 }
 
+export function RedisListSubscriptions(arg1) {
+  return window['go']['app']['App']['RedisListSubscriptions'](arg1);
+}
+
+export function RedisPublish(arg1, arg2, arg3) {
+  return window['go']['app']['App']['RedisPublish'](arg1, arg2, arg3);
+}
+
 export function RedisRenameKey(arg1, arg2, arg3) {
   return window['go']['app']['App']['RedisRenameKey'](arg1, arg2, arg3);
 }
 
+export function RedisScanHash(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['RedisScanHash'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function RedisScanKeys(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['RedisScanKeys'](arg1, arg2, arg3, arg4);
 }
 
+export function RedisScanSet(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['RedisScanSet'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function RedisScanZSet(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['RedisScanZSet'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function RedisSelectDB(arg1, arg2) {
   return window['go']['app']['App']['RedisSelectDB'](arg1, arg2);
 }
@@ -242,10 +314,18 @@ export function RedisStreamDelete(arg1, arg2, arg3) {
   return window['go']['app']['App']['RedisStreamDelete'](arg1, arg2, arg3);
 }
 
+export function RedisSubscribe(arg1, arg2, arg3) {
+  return window['go']['app']['App']['RedisSubscribe'](arg1, arg2, arg3);
+}
+
 export function RedisTestConnection(arg1) {
   return window['go']['app']['App']['RedisTestConnection'](arg1);
 }
 
+export function RedisUnsubscribe(arg1) {
+  return window['go']['app']['App']['RedisUnsubscribe'](arg1);
+}
+
 export function RedisZSetAdd(arg1, arg2, arg3) {
   return window['go']['app']['App']['RedisZSetAdd'](arg1, arg2, arg3);
 }
@@ -266,6 +346,34 @@ export function RenameView(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['RenameView'](arg1, arg2, arg3, arg4);
 }
 
+export function ResumeSync(arg1) {
+  return window['go']['app']['App']['ResumeSync'](arg1);
+}
+
+export function RollbackSession(arg1) {
+  return window['go']['app']['App']['RollbackSession'](arg1);
+}
+
+export function RunSyncJob(arg1) {
+  return window['go']['app']['App']['RunSyncJob'](arg1);
+}
+
+export function SaveSQLScript(arg1, arg2) {
+  return window['go']['app']['App']['SaveSQLScript'](arg1, arg2);
+}
+
+export function SaveSyncJob(arg1) {
+  return window['go']['app']['App']['SaveSyncJob'](arg1);
+}
+
+export function SchemaCompare(arg1) {
+  return window['go']['app']['App']['SchemaCompare'](arg1);
+}
+
+export function SessionQuery(arg1, arg2, arg3) {
+  return window['go']['app']['App']['SessionQuery'](arg1, arg2, arg3);
+}
+
 export function SetWindowTranslucency(arg1, arg2) {
   return window['go']['app']['App']['SetWindowTranslucency'](arg1, arg2);
 }
@@ -48,6 +48,26 @@ export namespace connection {
 		    return a;
 		}
 	}
+	export class TLSConfig {
+	    caPath?: string;
+	    certPath?: string;
+	    keyPath?: string;
+	    serverName?: string;
+	    skipVerify?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.caPath = source["caPath"];
+	        this.certPath = source["certPath"];
+	        this.keyPath = source["keyPath"];
+	        this.serverName = source["serverName"];
+	        this.skipVerify = source["skipVerify"];
+	    }
+	}
 	export class SSHConfig {
 	    host: string;
 	    port: number;
@@ -78,10 +98,15 @@ export namespace connection {
 	    database: string;
 	    useSSH: boolean;
 	    ssh: SSHConfig;
+	    useTLS?: boolean;
+	    tls?: TLSConfig;
 	    driver?: string;
 	    dsn?: string;
 	    timeout?: number;
 	    redisDB?: number;
+	    redisUser?: string;
+	    sentinelMaster?: string;
+	    sentinelPassword?: string;
 	    uri?: string;
 	    hosts?: string[];
 	    topology?: string;
@@ -110,10 +135,15 @@ export namespace connection {
 	        this.database = source["database"];
 	        this.useSSH = source["useSSH"];
 	        this.ssh = this.convertValues(source["ssh"], SSHConfig);
+	        this.useTLS = source["useTLS"];
+	        this.tls = this.convertValues(source["tls"], TLSConfig);
 	        this.driver = source["driver"];
 	        this.dsn = source["dsn"];
 	        this.timeout = source["timeout"];
 	        this.redisDB = source["redisDB"];
+	        this.redisUser = source["redisUser"];
+	        this.sentinelMaster = source["sentinelMaster"];
+	        this.sentinelPassword = source["sentinelPassword"];
 	        this.uri = source["uri"];
 	        this.hosts = source["hosts"];
 	        this.topology = source["topology"];
@@ -146,11 +176,39 @@ export namespace connection {
 		    return a;
 		}
 	}
+	export class ResultColumn {
+	    name: string;
+	    dbType?: string;
+	    scanType?: string;
+	    length?: number;
+	    precision?: number;
+	    scale?: number;
+	    nullable?: boolean;
+	    bsonType?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.dbType = source["dbType"];
+	        this.scanType = source["scanType"];
+	        this.length = source["length"];
+	        this.precision = source["precision"];
+	        this.scale = source["scale"];
+	        this.nullable = source["nullable"];
+	        this.bsonType = source["bsonType"];
+	    }
+	}
 	export class QueryResult {
 	    success: boolean;
 	    message: string;
 	    data: any;
 	    fields?: string[];
+	    columns?: ResultColumn[];
+	    queryId?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new QueryResult(source);
@@ -162,14 +220,80 @@ export namespace connection {
 	        this.message = source["message"];
 	        this.data = source["data"];
 	        this.fields = source["fields"];
+	        this.columns = this.convertValues(source["columns"], ResultColumn);
+	        this.queryId = source["queryId"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	
+	
+	
 
 }
 
 export namespace redis {
 	
+	export class RedisAnalysisOptions {
+	    pattern: string;
+	    delimiter: string;
+	    maxDepth: number;
+	    topN: number;
+	    scanCount: number;
+	    memorySamples: number;
+	    maxKeys: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RedisAnalysisOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pattern = source["pattern"];
+	        this.delimiter = source["delimiter"];
+	        this.maxDepth = source["maxDepth"];
+	        this.topN = source["topN"];
+	        this.scanCount = source["scanCount"];
+	        this.memorySamples = source["memorySamples"];
+	        this.maxKeys = source["maxKeys"];
+	    }
+	}
+	export class RedisSubscribeOptions {
+	    channels: string[];
+	    patterns: string[];
+	    keyEvents: string[];
+	    keyPattern: string;
+	    enableNotify: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RedisSubscribeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channels = source["channels"];
+	        this.patterns = source["patterns"];
+	        this.keyEvents = source["keyEvents"];
+	        this.keyPattern = source["keyPattern"];
+	        this.enableNotify = source["enableNotify"];
+	    }
+	}
 	export class ZSetMember {
 	    member: string;
 	    score: number;
@@ -189,6 +313,56 @@ export namespace redis {
 
 export namespace sync {
 	
+	export class ColumnTransform {
+	    column: string;
+	    type: string;
+	    value?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColumnTransform(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.column = source["column"];
+	        this.type = source["type"];
+	        this.value = source["value"];
+	    }
+	}
+	export class CompareOptions {
+	    floatEpsilon?: number;
+	    timePrecision?: string;
+	    whitespace?: string;
+	    ignoreCase?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.floatEpsilon = source["floatEpsilon"];
+	        this.timePrecision = source["timePrecision"];
+	        this.whitespace = source["whitespace"];
+	        this.ignoreCase = source["ignoreCase"];
+	    }
+	}
+	export class JobSchedule {
+	    type: string;
+	    cron?: string;
+	    intervalSeconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobSchedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.cron = source["cron"];
+	        this.intervalSeconds = source["intervalSeconds"];
+	    }
+	}
 	export class TableOptions {
 	    insert?: boolean;
 	    update?: boolean;
@@ -196,6 +370,13 @@ export namespace sync {
 	    selectedInsertPks?: string[];
 	    selectedUpdatePks?: string[];
 	    selectedDeletePks?: string[];
+	    matchIndex?: string;
+	    targetTable?: string;
+	    columnMap?: Record<string, string>;
+	    excludeColumns?: string[];
+	    transforms?: ColumnTransform[];
+	    where?: string;
+	    watermarkColumn?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new TableOptions(source);
@@ -209,7 +390,32 @@ export namespace sync {
 	        this.selectedInsertPks = source["selectedInsertPks"];
 	        this.selectedUpdatePks = source["selectedUpdatePks"];
 	        this.selectedDeletePks = source["selectedDeletePks"];
+	        this.matchIndex = source["matchIndex"];
+	        this.targetTable = source["targetTable"];
+	        this.columnMap = source["columnMap"];
+	        this.excludeColumns = source["excludeColumns"];
+	        this.transforms = this.convertValues(source["transforms"], ColumnTransform);
+	        this.where = source["where"];
+	        this.watermarkColumn = source["watermarkColumn"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class SyncConfig {
 	    sourceConfig: connection.ConnectionConfig;
@@ -220,6 +426,17 @@ export namespace sync {
 	    jobId?: string;
 	    autoAddColumns?: boolean;
 	    tableOptions?: Record<string, TableOptions>;
+	    compareMode?: string;
+	    compareOptions?: CompareOptions;
+	    dryRun?: boolean;
+	    scriptPath?: string;
+	    resumeFrom?: string;
+	    syncJobId?: string;
+	    fullRefresh?: boolean;
+	    workers?: number;
+	    batchSize?: number;
+	    maxRowsPerSecond?: number;
+	    batchPauseMs?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new SyncConfig(source);
@@ -235,6 +452,65 @@ export namespace sync {
 	        this.jobId = source["jobId"];
 	        this.autoAddColumns = source["autoAddColumns"];
 	        this.tableOptions = this.convertValues(source["tableOptions"], TableOptions, true);
+	        this.compareMode = source["compareMode"];
+	        this.compareOptions = this.convertValues(source["compareOptions"], CompareOptions);
+	        this.dryRun = source["dryRun"];
+	        this.scriptPath = source["scriptPath"];
+	        this.resumeFrom = source["resumeFrom"];
+	        this.syncJobId = source["syncJobId"];
+	        this.fullRefresh = source["fullRefresh"];
+	        this.workers = source["workers"];
+	        this.batchSize = source["batchSize"];
+	        this.maxRowsPerSecond = source["maxRowsPerSecond"];
+	        this.batchPauseMs = source["batchPauseMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SyncJob {
+	    id: string;
+	    name: string;
+	    config: SyncConfig;
+	    schedule: JobSchedule;
+	    enabled: boolean;
+	    createdAt: number;
+	    updatedAt: number;
+	    lastRunAt?: number;
+	    nextRunAt?: number;
+	    lastRunId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.config = this.convertValues(source["config"], SyncConfig);
+	        this.schedule = this.convertValues(source["schedule"], JobSchedule);
+	        this.enabled = source["enabled"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	        this.lastRunAt = source["lastRunAt"];
+	        this.nextRunAt = source["nextRunAt"];
+	        this.lastRunId = source["lastRunId"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -263,6 +539,9 @@ export namespace sync {
 	    rowsInserted: number;
 	    rowsUpdated: number;
 	    rowsDeleted: number;
+	    scriptPath?: string;
+	    resumable?: boolean;
+	    cancelled?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new SyncResult(source);
@@ -277,6 +556,9 @@ export namespace sync {
 	        this.rowsInserted = source["rowsInserted"];
 	        this.rowsUpdated = source["rowsUpdated"];
 	        this.rowsDeleted = source["rowsDeleted"];
+	        this.scriptPath = source["scriptPath"];
+	        this.resumable = source["resumable"];
+	        this.cancelled = source["cancelled"];
 	    }
 	}
 
@@ -31,12 +31,20 @@ type App struct {
 	mu          sync.RWMutex              // Mutex for cache access
 	updateMu    sync.Mutex
 	updateState updateState
+
+	queryMu        sync.Mutex
+	runningQueries map[string]*runningQuery // 正在执行的查询，key 为 queryID
+
+	txMu       sync.Mutex
+	txSessions map[string]*txSession // 事务会话，key 为前端标签页 ID
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
 	return &App{
-		dbCache: make(map[string]cachedDatabase),
+		dbCache:        make(map[string]cachedDatabase),
+		runningQueries: make(map[string]*runningQuery),
+		txSessions:     make(map[string]*txSession),
 	}
 }
 
@@ -46,6 +54,7 @@ func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
 	logger.Init()
 	applyMacWindowTranslucencyFix()
+	a.startSyncScheduler()
 	logger.Infof("应用启动完成")
 }
 
@@ -59,6 +68,9 @@ func (a *App) SetWindowTranslucency(opacity float64, blur float64) {
 // Shutdown is called when the app terminates
 func (a *App) Shutdown(ctx context.Context) {
 	logger.Infof("应用开始关闭，准备释放资源")
+	stopSyncScheduler()
+	a.cancelAllQueries()
+	a.rollbackAllTxSessions()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	for _, dbInst := range a.dbCache {
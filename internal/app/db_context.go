@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
 )
 
 func normalizeRunConfig(config connection.ConnectionConfig, dbName string) connection.ConnectionConfig {
@@ -13,17 +14,15 @@ func normalizeRunConfig(config connection.ConnectionConfig, dbName string) conne
 		return runConfig
 	}
 
-	switch strings.ToLower(strings.TrimSpace(config.Type)) {
-	case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "highgo", "vastbase", "sqlserver", "mongodb", "tdengine":
-		// 这些类型的 dbName 表示"数据库"，需要写入连接配置以选择目标库。
-		runConfig.Database = name
-	case "dameng":
-		// 达梦使用 schema 参数，沿用现有行为：dbName 表示 schema。
-		runConfig.Database = name
-	default:
-		// oracle: dbName 表示 schema/owner，不能覆盖 config.Database（服务名）
-		// sqlite: 无需设置 Database
+	dbType := strings.ToLower(strings.TrimSpace(config.Type))
+	if dbType == "custom" {
 		// custom: 语义不明确，避免污染缓存 key
+		return runConfig
+	}
+	// 达梦的 dbName 表示 schema，同样写入连接配置；oracle 的 dbName 表示 schema/owner，
+	// 不能覆盖 config.Database（服务名）；sqlite 无需设置 Database
+	if db.Capabilities(dbType).DatabaseInConfig {
+		runConfig.Database = name
 	}
 
 	return runConfig
@@ -44,15 +43,10 @@ func normalizeSchemaAndTable(config connection.ConnectionConfig, dbName string,
 		}
 	}
 
-	switch strings.ToLower(strings.TrimSpace(config.Type)) {
-	case "postgres", "kingbase", "highgo", "vastbase":
-		// PG/金仓/瀚高/海量：dbName 在 UI 里是"数据库"，schema 需从 tableName 或使用默认 public。
-		return "public", rawTable
-	case "sqlserver":
-		// SQL Server：dbName 表示数据库，schema 默认 dbo
-		return "dbo", rawTable
-	default:
-		// MySQL：dbName 表示数据库；Oracle/达梦：dbName 表示 schema/owner。
-		return rawDB, rawTable
+	// PG 系与 SQL Server 的 dbName 在 UI 里是"数据库"，schema 需从 tableName 或使用驱动声明的默认 schema（public/dbo）；
+	// MySQL 的 dbName 表示数据库，Oracle/达梦的 dbName 表示 schema/owner。
+	if schema := db.Capabilities(config.Type).DefaultSchema; schema != "" {
+		return schema, rawTable
 	}
+	return rawDB, rawTable
 }
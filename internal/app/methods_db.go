@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
 	"GoNavi-Wails/internal/logger"
 	"GoNavi-Wails/internal/utils"
 )
@@ -75,25 +76,25 @@ func (a *App) CreateDatabase(config connection.ConnectionConfig, dbName string)
 	runConfig := config
 	runConfig.Database = ""
 
+	dbType := db.ResolveDialect(runConfig)
+	caps := db.Capabilities(dbType)
+	if !caps.SupportsCreateDatabase {
+		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持创建数据库", dbType)}
+	}
+	var query string
+	switch caps.Family {
+	case db.FamilyMySQL:
+		query = fmt.Sprintf("CREATE DATABASE %s CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", db.QuoteIdent(dbType, dbName))
+	case db.FamilyTDengine:
+		query = fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", db.QuoteIdent(dbType, dbName))
+	default:
+		query = fmt.Sprintf("CREATE DATABASE %s", db.QuoteIdent(dbType, dbName))
+	}
+
 	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
 	}
-
-	escapedDbName := strings.ReplaceAll(dbName, "`", "``")
-	query := fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", escapedDbName)
-	dbType := strings.ToLower(strings.TrimSpace(runConfig.Type))
-	if dbType == "postgres" || dbType == "kingbase" || dbType == "highgo" || dbType == "vastbase" {
-		escapedDbName = strings.ReplaceAll(dbName, `"`, `""`)
-		query = fmt.Sprintf("CREATE DATABASE \"%s\"", escapedDbName)
-	} else if dbType == "tdengine" {
-		query = fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", quoteIdentByType(dbType, dbName))
-	} else if dbType == "mariadb" {
-		// MariaDB uses same syntax as MySQL
-	} else if dbType == "sphinx" {
-		return connection.QueryResult{Success: false, Message: "Sphinx 暂不支持创建数据库"}
-	}
-
 	_, err = dbInst.Exec(query)
 	if err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
@@ -102,27 +103,6 @@ func (a *App) CreateDatabase(config connection.ConnectionConfig, dbName string)
 	return connection.QueryResult{Success: true, Message: "Database created successfully"}
 }
 
-func resolveDDLDBType(config connection.ConnectionConfig) string {
-	dbType := strings.ToLower(strings.TrimSpace(config.Type))
-	if dbType != "custom" {
-		return dbType
-	}
-
-	driver := strings.ToLower(strings.TrimSpace(config.Driver))
-	switch driver {
-	case "postgresql":
-		return "postgres"
-	case "dm":
-		return "dameng"
-	case "sqlite3":
-		return "sqlite"
-	case "sphinxql":
-		return "sphinx"
-	default:
-		return driver
-	}
-}
-
 func normalizeSchemaAndTableByType(dbType string, dbName string, tableName string) (string, string) {
 	rawTable := strings.TrimSpace(tableName)
 	rawDB := strings.TrimSpace(dbName)
@@ -138,32 +118,28 @@ func normalizeSchemaAndTableByType(dbType string, dbName string, tableName strin
 		}
 	}
 
-	switch dbType {
-	case "postgres", "kingbase", "highgo", "vastbase":
-		return "public", rawTable
-	default:
-		return rawDB, rawTable
+	// SQL Server 的元数据接口以数据库名定位，仅 PG 系需要补默认 schema
+	if caps := db.Capabilities(dbType); caps.Family == db.FamilyPostgres && caps.DefaultSchema != "" {
+		return caps.DefaultSchema, rawTable
 	}
+	return rawDB, rawTable
 }
 
 func quoteTableIdentByType(dbType string, schema string, table string) string {
 	s := strings.TrimSpace(schema)
 	t := strings.TrimSpace(table)
 	if s == "" {
-		return quoteIdentByType(dbType, t)
+		return db.QuoteIdent(dbType, t)
 	}
-	return fmt.Sprintf("%s.%s", quoteIdentByType(dbType, s), quoteIdentByType(dbType, t))
+	return fmt.Sprintf("%s.%s", db.QuoteIdent(dbType, s), db.QuoteIdent(dbType, t))
 }
 
 func buildRunConfigForDDL(config connection.ConnectionConfig, dbType string, dbName string) connection.ConnectionConfig {
 	runConfig := normalizeRunConfig(config, dbName)
 	if strings.EqualFold(strings.TrimSpace(config.Type), "custom") {
 		// custom 连接的 dbName 语义依赖 driver，尽量在常见驱动上对齐内置类型行为。
-		switch dbType {
-		case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "vastbase", "dameng":
-			if strings.TrimSpace(dbName) != "" {
-				runConfig.Database = strings.TrimSpace(dbName)
-			}
+		if db.Capabilities(dbType).DatabaseInConfig && strings.TrimSpace(dbName) != "" {
+			runConfig.Database = strings.TrimSpace(dbName)
 		}
 	}
 	return runConfig
@@ -179,11 +155,12 @@ func (a *App) RenameDatabase(config connection.ConnectionConfig, oldName string,
 		return connection.QueryResult{Success: false, Message: "新旧数据库名称不能相同"}
 	}
 
-	dbType := resolveDDLDBType(config)
-	switch dbType {
-	case "mysql", "mariadb", "sphinx":
+	dbType := db.ResolveDialect(config)
+	caps := db.Capabilities(dbType)
+	switch {
+	case caps.Family == db.FamilyMySQL:
 		return connection.QueryResult{Success: false, Message: "MySQL/MariaDB/Sphinx 不支持直接重命名数据库，请新建库后迁移数据"}
-	case "postgres", "kingbase", "highgo", "vastbase":
+	case caps.SupportsRenameDatabase:
 		if strings.EqualFold(strings.TrimSpace(config.Database), oldName) {
 			return connection.QueryResult{Success: false, Message: "当前连接正在使用目标数据库，请先连接到其他数据库后再重命名"}
 		}
@@ -195,7 +172,7 @@ func (a *App) RenameDatabase(config connection.ConnectionConfig, oldName string,
 		if err != nil {
 			return connection.QueryResult{Success: false, Message: err.Error()}
 		}
-		sql := fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", quoteIdentByType(dbType, oldName), quoteIdentByType(dbType, newName))
+		sql := fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", db.QuoteIdent(dbType, oldName), db.QuoteIdent(dbType, newName))
 		if _, err := dbInst.Exec(sql); err != nil {
 			return connection.QueryResult{Success: false, Message: err.Error()}
 		}
@@ -211,28 +188,24 @@ func (a *App) DropDatabase(config connection.ConnectionConfig, dbName string) co
 		return connection.QueryResult{Success: false, Message: "数据库名称不能为空"}
 	}
 
-	dbType := resolveDDLDBType(config)
-	var (
-		runConfig connection.ConnectionConfig
-		sql       string
-	)
-	switch dbType {
-	case "mysql", "mariadb", "tdengine":
-		runConfig = config
-		runConfig.Database = ""
-		sql = fmt.Sprintf("DROP DATABASE %s", quoteIdentByType(dbType, dbName))
-	case "postgres", "kingbase", "highgo", "vastbase":
+	dbType := db.ResolveDialect(config)
+	caps := db.Capabilities(dbType)
+	if !caps.SupportsDropDatabase {
+		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持删除数据库", dbType)}
+	}
+	runConfig := config
+	if caps.Family == db.FamilyPostgres {
+		// PG 系不能删除当前连接所在的库，需要连接到其他库执行
 		if strings.EqualFold(strings.TrimSpace(config.Database), dbName) {
 			return connection.QueryResult{Success: false, Message: "当前连接正在使用目标数据库，请先连接到其他数据库后再删除"}
 		}
-		runConfig = config
 		if strings.TrimSpace(runConfig.Database) == "" {
 			runConfig.Database = "postgres"
 		}
-		sql = fmt.Sprintf("DROP DATABASE %s", quoteIdentByType(dbType, dbName))
-	default:
-		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持删除数据库", dbType)}
+	} else {
+		runConfig.Database = ""
 	}
+	sql := fmt.Sprintf("DROP DATABASE %s", db.QuoteIdent(dbType, dbName))
 
 	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
@@ -257,10 +230,8 @@ func (a *App) RenameTable(config connection.ConnectionConfig, dbName string, old
 		return connection.QueryResult{Success: false, Message: "新表名不能包含 schema 或数据库前缀"}
 	}
 
-	dbType := resolveDDLDBType(config)
-	switch dbType {
-	case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "sqlite", "oracle", "dameng", "highgo", "vastbase", "sqlserver":
-	default:
+	dbType := db.ResolveDialect(config)
+	if !db.Capabilities(dbType).SupportsRenameTable {
 		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持重命名表", dbType)}
 	}
 
@@ -269,14 +240,14 @@ func (a *App) RenameTable(config connection.ConnectionConfig, dbName string, old
 		return connection.QueryResult{Success: false, Message: "旧表名不能为空"}
 	}
 	oldQualifiedTable := quoteTableIdentByType(dbType, schemaName, pureOldTableName)
-	newTableQuoted := quoteIdentByType(dbType, newTableName)
+	newTableQuoted := db.QuoteIdent(dbType, newTableName)
 
 	var sql string
-	switch dbType {
-	case "mysql", "mariadb", "sphinx":
+	switch db.Capabilities(dbType).Family {
+	case db.FamilyMySQL:
 		newQualifiedTable := quoteTableIdentByType(dbType, schemaName, newTableName)
 		sql = fmt.Sprintf("RENAME TABLE %s TO %s", oldQualifiedTable, newQualifiedTable)
-	case "sqlserver":
+	case db.FamilySQLServer:
 		// SQL Server 使用 sp_rename，参数为 'schema.oldname', 'newname'
 		oldFullName := schemaName + "." + pureOldTableName
 		escapedOld := strings.ReplaceAll(oldFullName, "'", "''")
@@ -303,10 +274,8 @@ func (a *App) DropTable(config connection.ConnectionConfig, dbName string, table
 		return connection.QueryResult{Success: false, Message: "表名不能为空"}
 	}
 
-	dbType := resolveDDLDBType(config)
-	switch dbType {
-	case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "sqlite", "oracle", "dameng", "highgo", "vastbase", "sqlserver", "tdengine":
-	default:
+	dbType := db.ResolveDialect(config)
+	if !db.Capabilities(dbType).SupportsDropTable {
 		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持删除表", dbType)}
 	}
 
@@ -335,7 +304,7 @@ func (a *App) MySQLConnect(config connection.ConnectionConfig) connection.QueryR
 
 func (a *App) MySQLQuery(config connection.ConnectionConfig, dbName string, query string) connection.QueryResult {
 	config.Type = "mysql"
-	return a.DBQuery(config, dbName, query)
+	return a.DBQuery(config, dbName, query, "")
 }
 
 func (a *App) MySQLGetDatabases(config connection.ConnectionConfig) connection.QueryResult {
@@ -353,7 +322,8 @@ func (a *App) MySQLShowCreateTable(config connection.ConnectionConfig, dbName st
 	return a.DBShowCreateTable(config, dbName, tableName)
 }
 
-func (a *App) DBQuery(config connection.ConnectionConfig, dbName string, query string) connection.QueryResult {
+// DBQuery 执行单条语句。queryID 为空时自动生成，执行期间可通过 CancelQuery(queryID) 中止。
+func (a *App) DBQuery(config connection.ConnectionConfig, dbName string, query string, queryID string) connection.QueryResult {
 	runConfig := normalizeRunConfig(config, dbName)
 
 	dbInst, err := a.getDatabase(runConfig)
@@ -370,42 +340,74 @@ func (a *App) DBQuery(config connection.ConnectionConfig, dbName string, query s
 	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
 	defer cancel()
 
-	lowerQuery := strings.TrimSpace(strings.ToLower(query))
-	isReadQuery := strings.HasPrefix(lowerQuery, "select") || strings.HasPrefix(lowerQuery, "show") || strings.HasPrefix(lowerQuery, "describe") || strings.HasPrefix(lowerQuery, "explain")
-	// MongoDB JSON 命令中的 find/count/aggregate 也属于读查询
-	if !isReadQuery && strings.ToLower(strings.TrimSpace(runConfig.Type)) == "mongodb" && strings.HasPrefix(strings.TrimSpace(query), "{") {
-		isReadQuery = true
-	}
-	if isReadQuery {
-		var data []map[string]interface{}
-		var columns []string
-		if q, ok := dbInst.(interface {
-			QueryContext(context.Context, string) ([]map[string]interface{}, []string, error)
-		}); ok {
-			data, columns, err = q.QueryContext(ctx, query)
-		} else {
-			data, columns, err = dbInst.Query(query)
+	// 只有调用方提供 queryID 时才可能被中止，此时才需要固定会话并查询会话 ID
+	cancellable := strings.TrimSpace(queryID) != ""
+	queryID, running, err := a.registerQuery(queryID, cancel, formatConnSummary(runConfig))
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer a.finishQuery(queryID, running)
+	var session sessionRunner
+	if cancellable {
+		session = a.attachQuerySession(ctx, running, dbInst)
+	}
+
+	failed := func(action string, err error) connection.QueryResult {
+		if running.isCancelled() {
+			logger.Infof("DBQuery 已被取消：%s SQL片段=%q", formatConnSummary(runConfig), sqlSnippet(query))
+			return connection.QueryResult{Success: false, Message: "查询已取消", QueryID: queryID}
 		}
-		if err != nil {
-			logger.Error(err, "DBQuery 查询失败：%s SQL片段=%q", formatConnSummary(runConfig), sqlSnippet(query))
-			return connection.QueryResult{Success: false, Message: err.Error()}
+		logger.Error(err, "DBQuery %s失败：%s SQL片段=%q", action, formatConnSummary(runConfig), sqlSnippet(query))
+		return connection.QueryResult{Success: false, Message: err.Error(), QueryID: queryID}
+	}
+
+	out, err := runStatement(ctx, dbInst, session, db.ResolveDialect(runConfig), query)
+	if err != nil {
+		if out.isRead {
+			return failed("查询", err)
 		}
-		return connection.QueryResult{Success: true, Data: data, Fields: columns}
-	} else {
-		var affected int64
-		if e, ok := dbInst.(interface {
-			ExecContext(context.Context, string) (int64, error)
-		}); ok {
-			affected, err = e.ExecContext(ctx, query)
+		return failed("执行", err)
+	}
+	if out.isRead {
+		return connection.QueryResult{Success: true, Data: out.data, Fields: out.columns, Columns: out.meta, QueryID: queryID}
+	}
+	return connection.QueryResult{Success: true, Data: map[string]int64{"affectedRows": out.affected}, QueryID: queryID}
+}
+
+// statementOutput 是单条语句的执行结果：读语句返回结果集，写语句返回影响行数。
+type statementOutput struct {
+	isRead   bool
+	data     []map[string]interface{}
+	columns  []string
+	meta     []connection.ResultColumn
+	affected int64
+}
+
+// runStatement 执行单条语句；session 非空时在固定连接上执行，否则优先使用驱动的 Context 方法。
+// dbType 应为 db.ResolveDialect 解析后的方言，用于读写分类。
+func runStatement(ctx context.Context, dbInst db.Database, session sessionRunner, dbType string, query string) (statementOutput, error) {
+	out := statementOutput{isRead: isReadStatement(dbType, query)}
+	var err error
+	if out.isRead {
+		if session != nil {
+			out.data, out.meta, err = session.QueryWithMeta(ctx, query)
 		} else {
-			affected, err = dbInst.Exec(query)
-		}
-		if err != nil {
-			logger.Error(err, "DBQuery 执行失败：%s SQL片段=%q", formatConnSummary(runConfig), sqlSnippet(query))
-			return connection.QueryResult{Success: false, Message: err.Error()}
+			out.data, out.meta, err = db.QueryWithMeta(ctx, dbInst, query)
 		}
-		return connection.QueryResult{Success: true, Data: map[string]int64{"affectedRows": affected}}
+		out.columns = db.ResultColumnNames(out.meta)
+		return out, err
+	}
+
+	if session != nil {
+		out.affected, err = session.ExecContext(ctx, query)
+	} else if e, ok := dbInst.(interface {
+		ExecContext(context.Context, string) (int64, error)
+	}); ok {
+		out.affected, err = e.ExecContext(ctx, query)
+	} else {
+		out.affected, err = dbInst.Exec(query)
 	}
+	return out, err
 }
 
 func sqlSnippet(query string) string {
@@ -554,10 +556,8 @@ func (a *App) DropView(config connection.ConnectionConfig, dbName string, viewNa
 		return connection.QueryResult{Success: false, Message: "视图名称不能为空"}
 	}
 
-	dbType := resolveDDLDBType(config)
-	switch dbType {
-	case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "sqlite", "oracle", "dameng", "highgo", "vastbase", "sqlserver":
-	default:
+	dbType := db.ResolveDialect(config)
+	if !db.Capabilities(dbType).SupportsViews {
 		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持删除视图", dbType)}
 	}
 
@@ -589,10 +589,8 @@ func (a *App) DropFunction(config connection.ConnectionConfig, dbName string, ro
 		routineType = "FUNCTION"
 	}
 
-	dbType := resolveDDLDBType(config)
-	switch dbType {
-	case "mysql", "mariadb", "sphinx", "postgres", "kingbase", "oracle", "dameng", "highgo", "vastbase", "sqlserver":
-	default:
+	dbType := db.ResolveDialect(config)
+	if !db.Capabilities(dbType).SupportsRoutines {
 		return connection.QueryResult{Success: false, Message: fmt.Sprintf("当前数据源(%s)暂不支持删除函数/存储过程", dbType)}
 	}
 
@@ -632,22 +630,22 @@ func (a *App) RenameView(config connection.ConnectionConfig, dbName string, oldN
 		return connection.QueryResult{Success: false, Message: "新视图名不能包含 schema 或数据库前缀"}
 	}
 
-	dbType := resolveDDLDBType(config)
+	dbType := db.ResolveDialect(config)
 	schemaName, pureOldName := normalizeSchemaAndTableByType(dbType, dbName, oldName)
 	if pureOldName == "" {
 		return connection.QueryResult{Success: false, Message: "旧视图名不能为空"}
 	}
 	oldQualified := quoteTableIdentByType(dbType, schemaName, pureOldName)
-	newQuoted := quoteIdentByType(dbType, newName)
+	newQuoted := db.QuoteIdent(dbType, newName)
 
 	var sql string
-	switch dbType {
-	case "mysql", "mariadb", "sphinx":
+	switch db.Capabilities(dbType).Family {
+	case db.FamilyMySQL:
 		newQualified := quoteTableIdentByType(dbType, schemaName, newName)
 		sql = fmt.Sprintf("RENAME TABLE %s TO %s", oldQualified, newQualified)
-	case "postgres", "kingbase", "highgo", "vastbase":
+	case db.FamilyPostgres:
 		sql = fmt.Sprintf("ALTER VIEW %s RENAME TO %s", oldQualified, newQuoted)
-	case "sqlserver":
+	case db.FamilySQLServer:
 		oldFullName := schemaName + "." + pureOldName
 		escapedOld := strings.ReplaceAll(oldFullName, "'", "''")
 		escapedNew := strings.ReplaceAll(newName, "'", "''")
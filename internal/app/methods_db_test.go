@@ -0,0 +1,66 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestNormalizeSchemaAndTableByType(t *testing.T) {
+	cases := []struct {
+		dbType     string
+		dbName     string
+		table      string
+		wantSchema string
+		wantTable  string
+	}{
+		{"postgres", "appdb", "users", "public", "users"},
+		{"kingbase", "appdb", " users ", "public", "users"},
+		{"postgres", "appdb", "audit.users", "audit", "users"},
+		{"sqlserver", "appdb", "users", "appdb", "users"},
+		{"sqlserver", "appdb", "sales.orders", "sales", "orders"},
+		{"mysql", "appdb", "users", "appdb", "users"},
+		{"oracle", "SCOTT", "EMP", "SCOTT", "EMP"},
+		{"postgres", "appdb", "", "appdb", ""},
+		{"postgres", "appdb", ".users", "public", ".users"},
+	}
+	for _, tc := range cases {
+		schema, table := normalizeSchemaAndTableByType(tc.dbType, tc.dbName, tc.table)
+		if schema != tc.wantSchema || table != tc.wantTable {
+			t.Fatalf("normalizeSchemaAndTableByType(%q, %q, %q) = (%q, %q)，期望 (%q, %q)",
+				tc.dbType, tc.dbName, tc.table, schema, table, tc.wantSchema, tc.wantTable)
+		}
+	}
+}
+
+func TestDDLFollowsCapabilities(t *testing.T) {
+	a := NewApp()
+	config := connection.ConnectionConfig{Type: "sqlite", Host: filepath.Join(t.TempDir(), "ddl.db")}
+	t.Cleanup(func() {
+		for _, cached := range a.dbCache {
+			cached.inst.Close()
+		}
+	})
+	if res := a.DBQuery(config, "", "CREATE VIEW v AS SELECT 1 AS n", ""); !res.Success {
+		t.Fatalf("建视图失败：%s", res.Message)
+	}
+
+	unsupported := []struct {
+		name string
+		res  connection.QueryResult
+	}{
+		{"创建数据库", a.CreateDatabase(config, "other")},
+		{"删除数据库", a.DropDatabase(config, "other")},
+		{"删除函数/存储过程", a.DropFunction(config, "", "f", "FUNCTION")},
+	}
+	for _, tc := range unsupported {
+		if tc.res.Success || !strings.Contains(tc.res.Message, "暂不支持"+tc.name) {
+			t.Fatalf("sqlite 不支持%s，实际=%+v", tc.name, tc.res)
+		}
+	}
+	if res := a.DropView(config, "", "v"); !res.Success {
+		t.Fatalf("sqlite 应支持删除视图：%s", res.Message)
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"bufio"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -390,7 +391,7 @@ func (a *App) ImportDataWithProgress(config connection.ConnectionConfig, dbName,
 
 	quotedCols := make([]string, len(columns))
 	for i, c := range columns {
-		quotedCols[i] = quoteIdentByType(runConfig.Type, c)
+		quotedCols[i] = db.QuoteIdent(runConfig.Type, c)
 	}
 
 	for idx, row := range rows {
@@ -402,7 +403,7 @@ func (a *App) ImportDataWithProgress(config connection.ConnectionConfig, dbName,
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-			quoteQualifiedIdentByType(runConfig.Type, tableName),
+			db.QuoteQualifiedIdent(runConfig.Type, tableName),
 			strings.Join(quotedCols, ", "),
 			strings.Join(values, ", "))
 
@@ -473,43 +474,27 @@ func (a *App) ExportTable(config connection.ConnectionConfig, dbName string, tab
 
 	format = strings.ToLower(format)
 	if format == "sql" {
-		f, err := os.Create(filename)
+		err := writeSQLExportFile(filename, func(w *bufio.Writer) error {
+			if err := writeSQLHeader(w, runConfig, dbName); err != nil {
+				return err
+			}
+			if err := dumpTableSQL(w, dbInst, runConfig, dbName, tableName, true, true); err != nil {
+				return err
+			}
+			return writeSQLFooter(w, runConfig)
+		})
 		if err != nil {
 			return connection.QueryResult{Success: false, Message: err.Error()}
 		}
-		defer f.Close()
-
-		w := bufio.NewWriterSize(f, 1024*1024)
-		defer w.Flush()
-
-		if err := writeSQLHeader(w, runConfig, dbName); err != nil {
-			return connection.QueryResult{Success: false, Message: err.Error()}
-		}
-		if err := dumpTableSQL(w, dbInst, runConfig, dbName, tableName, true, true); err != nil {
-			return connection.QueryResult{Success: false, Message: err.Error()}
-		}
-		if err := writeSQLFooter(w, runConfig); err != nil {
-			return connection.QueryResult{Success: false, Message: err.Error()}
-		}
 
 		return connection.QueryResult{Success: true, Message: "Export successful"}
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(runConfig.Type, tableName))
-
-	data, columns, err := dbInst.Query(query)
-	if err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
-	}
+	query := fmt.Sprintf("SELECT * FROM %s", db.QuoteQualifiedIdent(runConfig.Type, tableName))
 
-	f, err := os.Create(filename)
-	if err != nil {
+	if err := exportQueryToFile(dbInst, query, filename, format); err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	defer f.Close()
-	if err := writeRowsToFile(f, data, columns, format); err != nil {
-		return connection.QueryResult{Success: false, Message: "Write error: " + err.Error()}
-	}
 
 	return connection.QueryResult{Success: true, Message: "Export successful"}
 }
@@ -571,24 +556,18 @@ func (a *App) exportTablesSQL(config connection.ConnectionConfig, dbName string,
 	}
 	sort.Strings(tables)
 
-	f, err := os.Create(filename)
-	if err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
-	}
-	defer f.Close()
-
-	w := bufio.NewWriterSize(f, 1024*1024)
-	defer w.Flush()
-
-	if err := writeSQLHeader(w, runConfig, dbName); err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
-	}
-	for _, t := range tables {
-		if err := dumpTableSQL(w, dbInst, runConfig, dbName, t, includeSchema, includeData); err != nil {
-			return connection.QueryResult{Success: false, Message: err.Error()}
+	err = writeSQLExportFile(filename, func(w *bufio.Writer) error {
+		if err := writeSQLHeader(w, runConfig, dbName); err != nil {
+			return err
 		}
-	}
-	if err := writeSQLFooter(w, runConfig); err != nil {
+		for _, t := range tables {
+			if err := dumpTableSQL(w, dbInst, runConfig, dbName, t, includeSchema, includeData); err != nil {
+				return err
+			}
+		}
+		return writeSQLFooter(w, runConfig)
+	})
+	if err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
@@ -625,72 +604,24 @@ func (a *App) ExportDatabaseSQL(config connection.ConnectionConfig, dbName strin
 	}
 	sort.Strings(tables)
 
-	f, err := os.Create(filename)
-	if err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
-	}
-	defer f.Close()
-
-	w := bufio.NewWriterSize(f, 1024*1024)
-	defer w.Flush()
-
-	if err := writeSQLHeader(w, runConfig, dbName); err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
-	}
-	for _, t := range tables {
-		if err := dumpTableSQL(w, dbInst, runConfig, dbName, t, true, includeData); err != nil {
-			return connection.QueryResult{Success: false, Message: err.Error()}
+	err = writeSQLExportFile(filename, func(w *bufio.Writer) error {
+		if err := writeSQLHeader(w, runConfig, dbName); err != nil {
+			return err
 		}
-	}
-	if err := writeSQLFooter(w, runConfig); err != nil {
+		for _, t := range tables {
+			if err := dumpTableSQL(w, dbInst, runConfig, dbName, t, true, includeData); err != nil {
+				return err
+			}
+		}
+		return writeSQLFooter(w, runConfig)
+	})
+	if err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
 	return connection.QueryResult{Success: true, Message: "Export successful"}
 }
 
-func quoteIdentByType(dbType string, ident string) string {
-	if ident == "" {
-		return ident
-	}
-
-	switch dbType {
-	case "mysql", "mariadb", "sphinx", "tdengine":
-		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
-	case "sqlserver":
-		escaped := strings.ReplaceAll(ident, "]", "]]")
-		return "[" + escaped + "]"
-	default:
-		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
-	}
-}
-
-func quoteQualifiedIdentByType(dbType string, ident string) string {
-	raw := strings.TrimSpace(ident)
-	if raw == "" {
-		return raw
-	}
-
-	parts := strings.Split(raw, ".")
-	if len(parts) <= 1 {
-		return quoteIdentByType(dbType, raw)
-	}
-
-	quotedParts := make([]string, 0, len(parts))
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
-		}
-		quotedParts = append(quotedParts, quoteIdentByType(dbType, part))
-	}
-
-	if len(quotedParts) == 0 {
-		return quoteIdentByType(dbType, raw)
-	}
-	return strings.Join(quotedParts, ".")
-}
-
 func writeSQLHeader(w *bufio.Writer, config connection.ConnectionConfig, dbName string) error {
 	now := time.Now().Format("2006-01-02 15:04:05")
 	if _, err := w.WriteString(fmt.Sprintf("-- GoNavi SQL Export\n-- Time: %s\n", now)); err != nil {
@@ -703,7 +634,7 @@ func writeSQLHeader(w *bufio.Writer, config connection.ConnectionConfig, dbName
 	}
 
 	if strings.ToLower(strings.TrimSpace(config.Type)) == "mysql" && strings.TrimSpace(dbName) != "" {
-		if _, err := w.WriteString(fmt.Sprintf("USE %s;\n\n", quoteIdentByType("mysql", dbName))); err != nil {
+		if _, err := w.WriteString(fmt.Sprintf("USE %s;\n\n", db.QuoteIdent("mysql", dbName))); err != nil {
 			return err
 		}
 		if _, err := w.WriteString("SET FOREIGN_KEY_CHECKS=0;\n\n"); err != nil {
@@ -829,32 +760,37 @@ func dumpTableSQL(w *bufio.Writer, dbInst db.Database, config connection.Connect
 	}
 
 	qualified := qualifyTable(schemaName, pureTableName)
-	selectSQL := fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.Type, qualified))
-	data, columns, err := dbInst.Query(selectSQL)
+	quotedTable := db.QuoteQualifiedIdent(config.Type, qualified)
+	selectSQL := fmt.Sprintf("SELECT * FROM %s", quotedTable)
+
+	rowCount := 0
+	var quotedCols []string
+	err := db.StreamQuery(context.Background(), dbInst, selectSQL, exportStreamBatchSize, func(batch db.RowBatch) error {
+		if quotedCols == nil {
+			quotedCols = make([]string, 0, len(batch.Columns))
+			for _, c := range batch.Columns {
+				quotedCols = append(quotedCols, db.QuoteIdent(config.Type, c))
+			}
+		}
+		for _, row := range batch.Rows {
+			values := make([]string, 0, len(batch.Columns))
+			for _, c := range batch.Columns {
+				values = append(values, formatSQLValue(config.Type, row[c]))
+			}
+			if _, err := w.WriteString(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);\n", quotedTable, strings.Join(quotedCols, ", "), strings.Join(values, ", "))); err != nil {
+				return err
+			}
+			rowCount++
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	if len(data) == 0 {
+	if rowCount == 0 {
 		if _, err := w.WriteString("-- (0 rows)\n"); err != nil {
 			return err
 		}
-		return nil
-	}
-
-	quotedCols := make([]string, 0, len(columns))
-	for _, c := range columns {
-		quotedCols = append(quotedCols, quoteIdentByType(config.Type, c))
-	}
-	quotedTable := quoteQualifiedIdentByType(config.Type, qualified)
-
-	for _, row := range data {
-		values := make([]string, 0, len(columns))
-		for _, c := range columns {
-			values = append(values, formatSQLValue(config.Type, row[c]))
-		}
-		if _, err := w.WriteString(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);\n", quotedTable, strings.Join(quotedCols, ", "), strings.Join(values, ", "))); err != nil {
-			return err
-		}
 	}
 
 	return nil
@@ -886,6 +822,25 @@ func (a *App) ExportData(data []map[string]interface{}, columns []string, defaul
 	return connection.QueryResult{Success: true, Message: "Export successful"}
 }
 
+// SaveSQLScript saves generated SQL (e.g. a schema migration script) to a user-chosen file.
+func (a *App) SaveSQLScript(script string, defaultName string) connection.QueryResult {
+	if defaultName == "" {
+		defaultName = "script"
+	}
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Save SQL Script",
+		DefaultFilename: fmt.Sprintf("%s.sql", defaultName),
+	})
+	if err != nil || filename == "" {
+		return connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+
+	if err := os.WriteFile(filename, []byte(script), 0o644); err != nil {
+		return connection.QueryResult{Success: false, Message: "Write error: " + err.Error()}
+	}
+	return connection.QueryResult{Success: true, Message: "Saved", Data: filename}
+}
+
 // ExportQuery exports by executing the provided SELECT query on backend side.
 // This avoids frontend IPC payload limits when exporting very large/long-text columns (e.g. base64).
 func (a *App) ExportQuery(config connection.ConnectionConfig, dbName string, query string, defaultName string, format string) connection.QueryResult {
@@ -918,72 +873,186 @@ func (a *App) ExportQuery(config connection.ConnectionConfig, dbName string, que
 		return connection.QueryResult{Success: false, Message: "Only SELECT/WITH queries are supported"}
 	}
 
-	data, columns, err := dbInst.Query(query)
-	if err != nil {
+	if err := exportQueryToFile(dbInst, query, filename, format); err != nil {
 		return connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
+	return connection.QueryResult{Success: true, Message: "Export successful"}
+}
+
+// exportStreamBatchSize 控制导出时每批从数据库读取的行数。
+const exportStreamBatchSize = 2000
+
+// exportQueryToFile 流式导出查询结果到 filename，失败时删除已写入的部分文件。
+func exportQueryToFile(dbInst db.Database, query string, filename string, format string) error {
 	f, err := os.Create(filename)
 	if err != nil {
-		return connection.QueryResult{Success: false, Message: err.Error()}
+		return err
 	}
-	defer f.Close()
+	err = streamQueryToFile(dbInst, query, f, format)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("Write error: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(filename)
+	}
+	return err
+}
 
-	if err := writeRowsToFile(f, data, columns, format); err != nil {
-		return connection.QueryResult{Success: false, Message: "Write error: " + err.Error()}
+// writeSQLExportFile 通过缓冲写入 SQL 导出文件，失败时删除已写入的部分文件。
+func writeSQLExportFile(filename string, write func(w *bufio.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(f, 1024*1024)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
 	}
+	return err
+}
 
-	return connection.QueryResult{Success: true, Message: "Export successful"}
+// streamQueryToFile 流式执行查询并逐批写入文件，避免大表导出时把全部数据载入内存。
+// 查询错误原样返回，写入错误带 "Write error: " 前缀，与导出前的提示保持一致。
+func streamQueryToFile(dbInst db.Database, query string, f *os.File, format string) error {
+	var writer *rowFileWriter
+	err := db.StreamQuery(context.Background(), dbInst, query, exportStreamBatchSize, func(batch db.RowBatch) error {
+		if writer == nil {
+			w, err := newRowFileWriter(f, batch.Columns, format)
+			if err != nil {
+				return fmt.Errorf("Write error: %w", err)
+			}
+			writer = w
+		}
+		if err := writer.WriteRows(batch.Rows); err != nil {
+			return fmt.Errorf("Write error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if writer != nil && writer.xlsx != nil {
+			writer.xlsx.Close()
+		}
+		return err
+	}
+	if writer == nil {
+		return nil
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("Write error: %w", err)
+	}
+	return nil
 }
 
 func writeRowsToFile(f *os.File, data []map[string]interface{}, columns []string, format string) error {
-	format = strings.ToLower(strings.TrimSpace(format))
-	if f == nil {
-		return fmt.Errorf("file required")
+	writer, err := newRowFileWriter(f, columns, format)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRows(data); err != nil {
+		return err
 	}
+	return writer.Close()
+}
 
-	// xlsx 使用 excelize 写入真正的 Excel 格式
-	if format == "xlsx" {
-		return writeRowsToXlsx(f.Name(), data, columns)
+// rowFileWriter 按格式增量写入行数据，可多次调用 WriteRows，最后必须调用 Close。
+type rowFileWriter struct {
+	f       *os.File
+	format  string
+	columns []string
+
+	csvWriter      *csv.Writer
+	jsonEncoder    *json.Encoder
+	isJsonFirstRow bool
+
+	xlsx       *excelize.File
+	xlsxStream *excelize.StreamWriter
+	xlsxRow    int
+}
+
+func newRowFileWriter(f *os.File, columns []string, format string) (*rowFileWriter, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if f == nil {
+		return nil, fmt.Errorf("file required")
 	}
 
-	var csvWriter *csv.Writer
-	var jsonEncoder *json.Encoder
-	isJsonFirstRow := true
+	w := &rowFileWriter{f: f, format: format, columns: columns, isJsonFirstRow: true}
 
 	switch format {
+	case "xlsx":
+		// xlsx 使用 excelize 流式写入真正的 Excel 格式
+		w.xlsx = excelize.NewFile()
+		sw, err := w.xlsx.NewStreamWriter("Sheet1")
+		if err != nil {
+			w.xlsx.Close()
+			return nil, err
+		}
+		w.xlsxStream = sw
+		header := make([]interface{}, len(columns))
+		for i, col := range columns {
+			header[i] = col
+		}
+		if err := sw.SetRow("A1", header); err != nil {
+			w.xlsx.Close()
+			return nil, err
+		}
+		w.xlsxRow = 1
 	case "csv":
 		if _, err := f.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
-			return err
+			return nil, err
 		}
-		csvWriter = csv.NewWriter(f)
-		if err := csvWriter.Write(columns); err != nil {
-			return err
+		w.csvWriter = csv.NewWriter(f)
+		if err := w.csvWriter.Write(columns); err != nil {
+			return nil, err
 		}
 	case "json":
 		if _, err := f.WriteString("[\n"); err != nil {
-			return err
+			return nil, err
 		}
-		jsonEncoder = json.NewEncoder(f)
-		jsonEncoder.SetIndent("  ", "  ")
+		w.jsonEncoder = json.NewEncoder(f)
+		w.jsonEncoder.SetIndent("  ", "  ")
 	case "md":
 		if _, err := fmt.Fprintf(f, "| %s |\n", strings.Join(columns, " | ")); err != nil {
-			return err
+			return nil, err
 		}
 		seps := make([]string, len(columns))
 		for i := range seps {
 			seps[i] = "---"
 		}
 		if _, err := fmt.Fprintf(f, "| %s |\n", strings.Join(seps, " | ")); err != nil {
-			return err
+			return nil, err
 		}
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 
+	return w, nil
+}
+
+func (w *rowFileWriter) WriteRows(data []map[string]interface{}) error {
 	for _, rowMap := range data {
-		record := make([]string, len(columns))
-		for i, col := range columns {
+		if w.format == "xlsx" {
+			w.xlsxRow++
+			cells := make([]interface{}, len(w.columns))
+			for i, col := range w.columns {
+				cells[i] = formatExportCellText(rowMap[col])
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, w.xlsxRow)
+			if err := w.xlsxStream.SetRow(cell, cells); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record := make([]string, len(w.columns))
+		for i, col := range w.columns {
 			val := rowMap[col]
 			if val == nil {
 				record[i] = "NULL"
@@ -991,48 +1060,57 @@ func writeRowsToFile(f *os.File, data []map[string]interface{}, columns []string
 			}
 
 			s := formatExportCellText(val)
-			if format == "md" {
+			if w.format == "md" {
 				s = strings.ReplaceAll(s, "|", "\\|")
 				s = strings.ReplaceAll(s, "\n", "<br>")
 			}
 			record[i] = s
 		}
 
-		switch format {
+		switch w.format {
 		case "csv":
-			if err := csvWriter.Write(record); err != nil {
+			if err := w.csvWriter.Write(record); err != nil {
 				return err
 			}
 		case "json":
-			if !isJsonFirstRow {
-				if _, err := f.WriteString(",\n"); err != nil {
+			if !w.isJsonFirstRow {
+				if _, err := w.f.WriteString(",\n"); err != nil {
 					return err
 				}
 			}
-			if err := jsonEncoder.Encode(rowMap); err != nil {
+			if err := w.jsonEncoder.Encode(rowMap); err != nil {
 				return err
 			}
-			isJsonFirstRow = false
+			w.isJsonFirstRow = false
 		case "md":
-			if _, err := fmt.Fprintf(f, "| %s |\n", strings.Join(record, " | ")); err != nil {
+			if _, err := fmt.Fprintf(w.f, "| %s |\n", strings.Join(record, " | ")); err != nil {
 				return err
 			}
 		}
 	}
 
-	if format == "csv" {
-		csvWriter.Flush()
-		if err := csvWriter.Error(); err != nil {
-			return err
-		}
+	if w.format == "csv" {
+		w.csvWriter.Flush()
+		return w.csvWriter.Error()
 	}
+	return nil
+}
 
-	if format == "json" {
-		if _, err := f.WriteString("\n]"); err != nil {
+func (w *rowFileWriter) Close() error {
+	switch w.format {
+	case "csv":
+		w.csvWriter.Flush()
+		return w.csvWriter.Error()
+	case "json":
+		_, err := w.f.WriteString("\n]")
+		return err
+	case "xlsx":
+		defer w.xlsx.Close()
+		if err := w.xlsxStream.Flush(); err != nil {
 			return err
 		}
+		return w.xlsx.SaveAs(w.f.Name())
 	}
-
 	return nil
 }
 
@@ -1053,32 +1131,3 @@ func formatExportCellText(val interface{}) string {
 		return fmt.Sprintf("%v", val)
 	}
 }
-
-// writeRowsToXlsx 使用 excelize 写入真正的 xlsx 格式文件
-func writeRowsToXlsx(filename string, data []map[string]interface{}, columns []string) error {
-	xlsx := excelize.NewFile()
-	defer xlsx.Close()
-
-	sheet := "Sheet1"
-
-	// 写入表头
-	for i, col := range columns {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		xlsx.SetCellValue(sheet, cell, col)
-	}
-
-	// 写入数据行
-	for rowIdx, rowMap := range data {
-		for colIdx, col := range columns {
-			cell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
-			val := rowMap[col]
-			if val == nil {
-				xlsx.SetCellValue(sheet, cell, "NULL")
-			} else {
-				xlsx.SetCellValue(sheet, cell, formatExportCellText(val))
-			}
-		}
-	}
-
-	return xlsx.SaveAs(filename)
-}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/db"
+)
+
+type fakeExportDB struct {
+	db.Database
+	rows    []map[string]interface{}
+	columns []string
+	err     error
+}
+
+func (f *fakeExportDB) Query(query string) ([]map[string]interface{}, []string, error) {
+	return f.rows, f.columns, f.err
+}
+
+func TestExportQueryToFile_WritesCSV(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "out.csv")
+	inst := &fakeExportDB{
+		rows:    []map[string]interface{}{{"id": 1, "name": "a"}, {"id": 2, "name": nil}},
+		columns: []string{"id", "name"},
+	}
+
+	if err := exportQueryToFile(inst, "SELECT 1", filename, "csv"); err != nil {
+		t.Fatalf("导出失败：%v", err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("读取导出文件失败：%v", err)
+	}
+	want := "\ufeffid,name\n1,a\n2,NULL\n"
+	if string(content) != want {
+		t.Fatalf("导出内容不正确：%q", content)
+	}
+}
+
+func TestExportQueryToFile_QueryErrorRemovesFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "out.csv")
+	inst := &fakeExportDB{err: errors.New("table not found")}
+
+	err := exportQueryToFile(inst, "SELECT 1", filename, "csv")
+	if err == nil || err.Error() != "table not found" {
+		t.Fatalf("查询错误应原样返回，实际=%v", err)
+	}
+	if _, statErr := os.Stat(filename); !os.IsNotExist(statErr) {
+		t.Fatalf("导出失败后不应保留文件：%v", statErr)
+	}
+}
+
+func TestExportQueryToFile_WriteErrorKeepsPrefix(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	inst := &fakeExportDB{rows: []map[string]interface{}{{"id": 1}}, columns: []string{"id"}}
+
+	err := exportQueryToFile(inst, "SELECT 1", filename, "txt")
+	if err == nil || !strings.HasPrefix(err.Error(), "Write error: ") {
+		t.Fatalf("写入错误应带 Write error 前缀，实际=%v", err)
+	}
+	if _, statErr := os.Stat(filename); !os.IsNotExist(statErr) {
+		t.Fatalf("导出失败后不应保留文件：%v", statErr)
+	}
+}
@@ -1,17 +1,24 @@
 package app
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"sync"
 
 	"GoNavi-Wails/internal/connection"
 	"GoNavi-Wails/internal/logger"
 	"GoNavi-Wails/internal/redis"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// redisAnalysisProgressEvent 在内存分析每扫描完一批键后触发。
+const redisAnalysisProgressEvent = "redis:analysis-progress"
+
 // Redis client cache
 var (
 	redisCache   = make(map[string]redis.RedisClient)
@@ -76,6 +83,23 @@ func formatRedisConnSummary(config connection.ConnectionConfig) string {
 	b.WriteString(string(rune(config.Port + '0')))
 	b.WriteString(" DB=")
 	b.WriteString(string(rune(config.RedisDB + '0')))
+	if strings.EqualFold(config.Topology, "cluster") {
+		b.WriteString(" 集群节点=")
+		b.WriteString(strings.Join(config.Hosts, ","))
+	}
+	if strings.EqualFold(config.Topology, "sentinel") {
+		b.WriteString(" Sentinel主节点=")
+		b.WriteString(config.SentinelMaster)
+		b.WriteString(" 哨兵=")
+		b.WriteString(strings.Join(config.Hosts, ","))
+	}
+	if config.RedisUser != "" {
+		b.WriteString(" 用户=")
+		b.WriteString(config.RedisUser)
+	}
+	if config.UseTLS {
+		b.WriteString(" TLS=开启")
+	}
 
 	if config.UseSSH {
 		b.WriteString(" SSH=")
@@ -140,6 +164,57 @@ func (a *App) RedisGetValue(config connection.ConnectionConfig, key string) conn
 	return connection.QueryResult{Success: true, Data: value}
 }
 
+// RedisScanHash returns one HSCAN page of a hash, filtered by pattern on the server
+func (a *App) RedisScanHash(config connection.ConnectionConfig, key, pattern string, cursor uint64, count int64) connection.QueryResult {
+	config.Type = "redis"
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	page, err := client.ScanHash(key, pattern, cursor, count)
+	if err != nil {
+		logger.Error(err, "RedisScanHash 扫描失败：key=%s pattern=%s", key, pattern)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	return connection.QueryResult{Success: true, Data: page}
+}
+
+// RedisScanSet returns one SSCAN page of a set, filtered by pattern on the server
+func (a *App) RedisScanSet(config connection.ConnectionConfig, key, pattern string, cursor uint64, count int64) connection.QueryResult {
+	config.Type = "redis"
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	page, err := client.ScanSet(key, pattern, cursor, count)
+	if err != nil {
+		logger.Error(err, "RedisScanSet 扫描失败：key=%s pattern=%s", key, pattern)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	return connection.QueryResult{Success: true, Data: page}
+}
+
+// RedisScanZSet returns one ZSCAN page of a sorted set, filtered by pattern on the server
+func (a *App) RedisScanZSet(config connection.ConnectionConfig, key, pattern string, cursor uint64, count int64) connection.QueryResult {
+	config.Type = "redis"
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	page, err := client.ScanZSet(key, pattern, cursor, count)
+	if err != nil {
+		logger.Error(err, "RedisScanZSet 扫描失败：key=%s pattern=%s", key, pattern)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	return connection.QueryResult{Success: true, Data: page}
+}
+
 // RedisSetString sets a string value
 func (a *App) RedisSetString(config connection.ConnectionConfig, key, value string, ttl int64) connection.QueryResult {
 	config.Type = "redis"
@@ -288,6 +363,23 @@ func (a *App) RedisGetServerInfo(config connection.ConnectionConfig) connection.
 	return connection.QueryResult{Success: true, Data: info}
 }
 
+// RedisGetClusterNodes returns the nodes of a Redis Cluster connection
+func (a *App) RedisGetClusterNodes(config connection.ConnectionConfig) connection.QueryResult {
+	config.Type = "redis"
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	nodes, err := client.GetClusterNodes()
+	if err != nil {
+		logger.Error(err, "RedisGetClusterNodes 获取失败")
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	return connection.QueryResult{Success: true, Data: nodes}
+}
+
 // RedisGetDatabases returns information about all databases
 func (a *App) RedisGetDatabases(config connection.ConnectionConfig) connection.QueryResult {
 	config.Type = "redis"
@@ -500,8 +592,50 @@ func (a *App) RedisFlushDB(config connection.ConnectionConfig) connection.QueryR
 	return connection.QueryResult{Success: true, Message: "清空成功"}
 }
 
-// CloseAllRedisClients closes all cached Redis clients (called on shutdown)
+// RedisAnalyzeKeys scans the current database and aggregates memory usage by key prefix.
+// analysisID is generated when empty; the analysis can be stopped with CancelQuery(analysisID),
+// which returns the partial report.
+func (a *App) RedisAnalyzeKeys(config connection.ConnectionConfig, opts redis.RedisAnalysisOptions, analysisID string) connection.QueryResult {
+	config.Type = "redis"
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	analysisID, running, err := a.registerQuery(analysisID, cancel, formatRedisConnSummary(config))
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer a.finishQuery(analysisID, running)
+
+	logger.Infof("RedisAnalyzeKeys 开始：%s pattern=%s 分隔符=%q", formatRedisConnSummary(config), opts.Pattern, opts.Delimiter)
+	report, err := redis.AnalyzeKeys(ctx, client, opts, func(progress redis.RedisAnalysisProgress) {
+		runtime.EventsEmit(a.ctx, redisAnalysisProgressEvent, map[string]any{
+			"analysisId": analysisID,
+			"scanned":    progress.Scanned,
+			"total":      progress.Total,
+			"bytes":      progress.Bytes,
+		})
+	})
+	if err != nil {
+		logger.Error(err, "RedisAnalyzeKeys 分析失败：%s", formatRedisConnSummary(config))
+		return connection.QueryResult{Success: false, Message: err.Error(), QueryID: analysisID}
+	}
+
+	message := fmt.Sprintf("共分析 %d 个键，占用 %d 字节，耗时 %d 毫秒", report.TotalKeys, report.TotalBytes, report.DurationMs)
+	if report.Cancelled {
+		message = "分析已取消：" + message
+	}
+	logger.Infof("RedisAnalyzeKeys 完成：%s %s", formatRedisConnSummary(config), message)
+	return connection.QueryResult{Success: true, Message: message, Data: report, QueryID: analysisID}
+}
+
+// CloseAllRedisClients closes all subscriptions and cached Redis clients (called on shutdown)
 func CloseAllRedisClients() {
+	closeAllRedisSubscriptions()
+
 	redisCacheMu.Lock()
 	defer redisCacheMu.Unlock()
 
@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/logger"
+	"GoNavi-Wails/internal/redis"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// redisPubSubMessageEvent 批量推送订阅会话收到的消息。
+	redisPubSubMessageEvent = "redis:pubsub-message"
+	// redisPubSubClosedEvent 在订阅会话结束时触发，message 非空表示异常断开。
+	redisPubSubClosedEvent = "redis:pubsub-closed"
+
+	redisPubSubFlushInterval = 200 * time.Millisecond
+	// redisPubSubMaxBatch 限制每个推送周期的消息数，超出部分只计数，避免高频频道拖垮前端。
+	redisPubSubMaxBatch = 1000
+)
+
+// redisSubscriptionSession 是一个订阅会话，独占一个 Redis 客户端，
+// 不复用连接缓存，避免 SelectDB 或缓存重建时中断订阅。
+type redisSubscriptionSession struct {
+	id        string
+	connKey   string
+	summary   string
+	client    redis.RedisClient
+	sub       *redis.RedisSubscription
+	cancel    context.CancelFunc
+	startedAt time.Time
+	received  atomic.Int64
+
+	mu      sync.Mutex
+	batch   []redis.RedisMessage
+	dropped int
+}
+
+// RedisSubscriptionInfo describes a running subscription session
+type RedisSubscriptionInfo struct {
+	ID        string   `json:"id"`
+	Summary   string   `json:"summary"`
+	Channels  []string `json:"channels"`
+	Patterns  []string `json:"patterns"`
+	StartedAt int64    `json:"startedAt"`
+	Received  int64    `json:"received"`
+}
+
+var (
+	redisSubscriptions   = make(map[string]*redisSubscriptionSession)
+	redisSubscriptionsMu sync.Mutex
+)
+
+// redisSubscriptionConnKey 标识订阅所属的连接，不区分数据库。
+func redisSubscriptionConnKey(config connection.ConnectionConfig) string {
+	config.Type = "redis"
+	config.RedisDB = 0
+	return getRedisClientCacheKey(config)
+}
+
+func (s *redisSubscriptionSession) info() RedisSubscriptionInfo {
+	return RedisSubscriptionInfo{
+		ID:        s.id,
+		Summary:   s.summary,
+		Channels:  s.sub.Channels,
+		Patterns:  s.sub.Patterns,
+		StartedAt: s.startedAt.UnixMilli(),
+		Received:  s.received.Load(),
+	}
+}
+
+func (s *redisSubscriptionSession) push(msg redis.RedisMessage) {
+	s.received.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.batch) < redisPubSubMaxBatch {
+		s.batch = append(s.batch, msg)
+	} else {
+		s.dropped++
+	}
+}
+
+func (s *redisSubscriptionSession) takeBatch() ([]redis.RedisMessage, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch, dropped := s.batch, s.dropped
+	s.batch, s.dropped = nil, 0
+	return batch, dropped
+}
+
+// runRedisSubscription 接收消息并按周期推送给前端，直到会话被取消或连接断开。
+func (a *App) runRedisSubscription(ctx context.Context, s *redisSubscriptionSession) {
+	done := make(chan error, 1)
+	go func() {
+		done <- s.sub.Receive(ctx, s.push)
+	}()
+
+	flush := func() {
+		batch, dropped := s.takeBatch()
+		if len(batch) == 0 && dropped == 0 {
+			return
+		}
+		runtime.EventsEmit(a.ctx, redisPubSubMessageEvent, map[string]any{
+			"subscriptionId": s.id,
+			"messages":       batch,
+			"dropped":        dropped,
+		})
+	}
+
+	ticker := time.NewTicker(redisPubSubFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			flush()
+		case err := <-done:
+			flush()
+			reason := ""
+			if err != nil && ctx.Err() == nil {
+				reason = err.Error()
+				logger.Error(err, "Redis 订阅异常结束：id=%s %s", s.id, s.summary)
+			}
+			stopRedisSubscription(s.id)
+			runtime.EventsEmit(a.ctx, redisPubSubClosedEvent, map[string]any{
+				"subscriptionId": s.id,
+				"message":        reason,
+			})
+			return
+		}
+	}
+}
+
+// stopRedisSubscription 取消并关闭订阅会话，会话不存在时返回 false。
+func stopRedisSubscription(id string) bool {
+	redisSubscriptionsMu.Lock()
+	s, ok := redisSubscriptions[id]
+	delete(redisSubscriptions, id)
+	redisSubscriptionsMu.Unlock()
+	if !ok {
+		return false
+	}
+	s.cancel()
+	s.sub.Close()
+	s.client.Close()
+	logger.Infof("已关闭 Redis 订阅：id=%s 共收到 %d 条消息", id, s.received.Load())
+	return true
+}
+
+// closeAllRedisSubscriptions 关闭全部订阅会话（应用关闭时调用）
+func closeAllRedisSubscriptions() {
+	redisSubscriptionsMu.Lock()
+	ids := make([]string, 0, len(redisSubscriptions))
+	for id := range redisSubscriptions {
+		ids = append(ids, id)
+	}
+	redisSubscriptionsMu.Unlock()
+	for _, id := range ids {
+		stopRedisSubscription(id)
+	}
+}
+
+// RedisSubscribe starts a subscription session; messages are pushed as redis:pubsub-message events
+func (a *App) RedisSubscribe(config connection.ConnectionConfig, opts redis.RedisSubscribeOptions, subscriptionID string) connection.QueryResult {
+	config.Type = "redis"
+	subscriptionID = strings.TrimSpace(subscriptionID)
+	if subscriptionID == "" {
+		subscriptionID = fmt.Sprintf("redis-sub-%d", time.Now().UnixNano())
+	}
+	redisSubscriptionsMu.Lock()
+	_, exists := redisSubscriptions[subscriptionID]
+	redisSubscriptionsMu.Unlock()
+	if exists {
+		return connection.QueryResult{Success: false, Message: fmt.Sprintf("订阅 ID 已存在：%s", subscriptionID)}
+	}
+
+	client := redis.NewRedisClient()
+	if err := client.Connect(config); err != nil {
+		logger.Error(err, "RedisSubscribe 连接失败：%s", formatRedisConnSummary(config))
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	sub, err := client.Subscribe(opts)
+	if err != nil {
+		client.Close()
+		logger.Error(err, "RedisSubscribe 订阅失败：%s", formatRedisConnSummary(config))
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &redisSubscriptionSession{
+		id:        subscriptionID,
+		connKey:   redisSubscriptionConnKey(config),
+		summary:   formatRedisConnSummary(config),
+		client:    client,
+		sub:       sub,
+		cancel:    cancel,
+		startedAt: time.Now(),
+	}
+	redisSubscriptionsMu.Lock()
+	if _, exists := redisSubscriptions[subscriptionID]; exists {
+		redisSubscriptionsMu.Unlock()
+		cancel()
+		sub.Close()
+		client.Close()
+		return connection.QueryResult{Success: false, Message: fmt.Sprintf("订阅 ID 已存在：%s", subscriptionID)}
+	}
+	redisSubscriptions[subscriptionID] = session
+	redisSubscriptionsMu.Unlock()
+
+	go a.runRedisSubscription(ctx, session)
+	logger.Infof("Redis 订阅已开始：id=%s %s 频道=%v 模式=%v", subscriptionID, session.summary, sub.Channels, sub.Patterns)
+
+	message := "订阅成功"
+	if sub.Warning != "" {
+		message = sub.Warning
+	}
+	return connection.QueryResult{Success: true, Message: message, Data: session.info()}
+}
+
+// RedisUnsubscribe stops a subscription session
+func (a *App) RedisUnsubscribe(subscriptionID string) connection.QueryResult {
+	if !stopRedisSubscription(subscriptionID) {
+		return connection.QueryResult{Success: false, Message: fmt.Sprintf("订阅不存在或已结束：%s", subscriptionID)}
+	}
+	return connection.QueryResult{Success: true, Message: "已取消订阅"}
+}
+
+// RedisListSubscriptions returns the running subscription sessions of a connection
+func (a *App) RedisListSubscriptions(config connection.ConnectionConfig) connection.QueryResult {
+	connKey := redisSubscriptionConnKey(config)
+	redisSubscriptionsMu.Lock()
+	defer redisSubscriptionsMu.Unlock()
+	sessions := make([]RedisSubscriptionInfo, 0)
+	for _, s := range redisSubscriptions {
+		if s.connKey == connKey {
+			sessions = append(sessions, s.info())
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt < sessions[j].StartedAt })
+	return connection.QueryResult{Success: true, Data: sessions}
+}
+
+// RedisPublish publishes a message and returns the number of receiving clients
+func (a *App) RedisPublish(config connection.ConnectionConfig, channel, message string) connection.QueryResult {
+	config.Type = "redis"
+	if strings.TrimSpace(channel) == "" {
+		return connection.QueryResult{Success: false, Message: "频道不能为空"}
+	}
+	client, err := a.getRedisClient(config)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	receivers, err := client.Publish(channel, message)
+	if err != nil {
+		logger.Error(err, "RedisPublish 发布失败：channel=%s", channel)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	return connection.QueryResult{Success: true, Message: fmt.Sprintf("已发布，%d 个订阅者收到", receivers), Data: receivers}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"GoNavi-Wails/internal/logger"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// scriptProgressEvent 在脚本中每条语句执行完成后触发。
+const scriptProgressEvent = "script:progress"
+
+// DBExecuteScript 按方言拆分脚本并在同一会话中顺序执行，返回每条语句的执行结果。
+// scriptID 为空时自动生成，执行期间可通过 CancelQuery(scriptID) 中止；
+// continueOnError 为 false 时遇到错误即停止，剩余语句标记为跳过。
+func (a *App) DBExecuteScript(config connection.ConnectionConfig, dbName string, script string, scriptID string, continueOnError bool) connection.QueryResult {
+	runConfig := normalizeRunConfig(config, dbName)
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		logger.Error(err, "DBExecuteScript 获取连接失败：%s", formatConnSummary(runConfig))
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	dbType := db.ResolveDialect(runConfig)
+	statements := splitSQLScript(dbType, script)
+	if len(statements) == 0 {
+		return connection.QueryResult{Success: false, Message: "没有可执行的 SQL"}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scriptID, running, err := a.registerQuery(scriptID, cancel, formatConnSummary(runConfig))
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer a.finishQuery(scriptID, running)
+	// 整个脚本固定在同一会话上执行，保证 SET/USE/临时表等会话状态在语句间生效
+	session := a.attachQuerySession(ctx, running, dbInst)
+
+	results := make([]connection.ScriptStatementResult, 0, len(statements))
+	succeeded, failed, skipped := 0, 0, 0
+	stopped := false
+	for i, stmt := range statements {
+		entry := connection.ScriptStatementResult{Index: i + 1, SQL: stmt}
+		if stopped || running.isCancelled() {
+			entry.Skipped = true
+			entry.Message = "未执行"
+			skipped++
+			results = append(results, entry)
+			continue
+		}
+
+		stmtCtx, stmtCancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+		startedAt := time.Now()
+		out, err := runStatement(stmtCtx, dbInst, session, dbType, sanitizeSQLForPgLike(runConfig.Type, stmt))
+		stmtCancel()
+
+		entry.DurationMs = time.Since(startedAt).Milliseconds()
+		entry.IsQuery = out.isRead
+		if err != nil {
+			failed++
+			if running.isCancelled() {
+				entry.Message = "查询已取消"
+				stopped = true
+			} else {
+				entry.Message = err.Error()
+				logger.Error(err, "DBExecuteScript 第 %d 条语句执行失败：%s SQL片段=%q", i+1, formatConnSummary(runConfig), sqlSnippet(stmt))
+				stopped = !continueOnError
+			}
+		} else {
+			succeeded++
+			entry.Success = true
+			entry.Rows = out.data
+			entry.Fields = out.columns
+			entry.Columns = out.meta
+			entry.AffectedRows = out.affected
+		}
+		results = append(results, entry)
+		a.emitScriptProgress(scriptID, len(statements), entry)
+	}
+
+	message := fmt.Sprintf("共 %d 条语句，成功 %d 条，失败 %d 条，跳过 %d 条", len(statements), succeeded, failed, skipped)
+	if running.isCancelled() {
+		message = "脚本已取消：" + message
+	}
+	logger.Infof("DBExecuteScript 完成：%s %s", formatConnSummary(runConfig), message)
+	return connection.QueryResult{
+		Success: failed == 0 && skipped == 0,
+		Message: message,
+		Data:    results,
+		QueryID: scriptID,
+	}
+}
+
+func (a *App) emitScriptProgress(scriptID string, total int, entry connection.ScriptStatementResult) {
+	// 进度事件只携带摘要，结果集随最终返回值一起给出
+	entry.Rows = nil
+	entry.Fields = nil
+	entry.Columns = nil
+	runtime.EventsEmit(a.ctx, scriptProgressEvent, map[string]any{
+		"scriptId":  scriptID,
+		"current":   entry.Index,
+		"total":     total,
+		"percent":   entry.Index * 100 / total,
+		"statement": entry,
+	})
+}
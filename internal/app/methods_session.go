@@ -0,0 +1,403 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"GoNavi-Wails/internal/logger"
+	"GoNavi-Wails/internal/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultTxSessionIdleTimeout = 10 * time.Minute
+	txSessionBeginTimeout       = 30 * time.Second
+	// txSessionTimeoutEvent 在事务会话因空闲超时被自动回滚时触发。
+	txSessionTimeoutEvent = "session:timeout"
+)
+
+// txSession 是绑定到前端标签页的事务会话，所有语句都在同一个固定连接上执行。
+// mu 串行化会话内的语句执行、提交/回滚与空闲超时回滚。
+type txSession struct {
+	mu          sync.Mutex
+	id          string
+	tx          *db.TxSession
+	ctx         context.Context // 会话级上下文，语句上下文由此派生，会话结束时取消
+	cancel      context.CancelFunc
+	dbType      string
+	connType    string
+	summary     string
+	isolation   string
+	timeout     time.Duration
+	idleTimeout time.Duration
+	timer       *time.Timer
+	startedAt   time.Time
+	lastActive  time.Time
+	statements  int
+	dirty       bool
+	closed      bool
+}
+
+// txSessionInfo 描述一个事务会话的状态。
+type txSessionInfo struct {
+	SessionID          string `json:"sessionId"`
+	Connection         string `json:"connection"`
+	Isolation          string `json:"isolation"`
+	StartedAt          int64  `json:"startedAt"`    // Unix milli
+	LastActiveAt       int64  `json:"lastActiveAt"` // Unix milli
+	Statements         int    `json:"statements"`
+	Dirty              bool   `json:"dirty"` // 是否执行过可能修改数据的语句
+	IdleTimeoutSeconds int    `json:"idleTimeoutSeconds"`
+}
+
+func (s *txSession) info() txSessionInfo {
+	return txSessionInfo{
+		SessionID:          s.id,
+		Connection:         s.summary,
+		Isolation:          s.isolation,
+		StartedAt:          s.startedAt.UnixMilli(),
+		LastActiveAt:       s.lastActive.UnixMilli(),
+		Statements:         s.statements,
+		Dirty:              s.dirty,
+		IdleTimeoutSeconds: int(s.idleTimeout / time.Second),
+	}
+}
+
+// parseIsolationLevel 将前端传入的隔离级别名称转换为 database/sql 的隔离级别。
+func parseIsolationLevel(level string) (sql.IsolationLevel, string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(level))
+	normalized = strings.NewReplacer("_", " ", "-", " ").Replace(normalized)
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	switch normalized {
+	case "", "DEFAULT":
+		return sql.LevelDefault, "DEFAULT", nil
+	case "READ UNCOMMITTED":
+		return sql.LevelReadUncommitted, normalized, nil
+	case "READ COMMITTED":
+		return sql.LevelReadCommitted, normalized, nil
+	case "REPEATABLE READ":
+		return sql.LevelRepeatableRead, normalized, nil
+	case "SNAPSHOT":
+		return sql.LevelSnapshot, normalized, nil
+	case "SERIALIZABLE":
+		return sql.LevelSerializable, normalized, nil
+	default:
+		return sql.LevelDefault, "", fmt.Errorf("不支持的隔离级别：%s", level)
+	}
+}
+
+// BeginSession 为前端标签页开启事务会话。sessionID 通常为标签页 ID；
+// isolationLevel 为空时使用数据库默认隔离级别；idleTimeoutSeconds<=0 时默认 10 分钟无操作自动回滚。
+func (a *App) BeginSession(config connection.ConnectionConfig, dbName string, sessionID string, isolationLevel string, idleTimeoutSeconds int) connection.QueryResult {
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" {
+		return connection.QueryResult{Success: false, Message: "会话 ID 不能为空"}
+	}
+	isolation, isolationName, err := parseIsolationLevel(isolationLevel)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	a.txMu.Lock()
+	_, exists := a.txSessions[sessionID]
+	a.txMu.Unlock()
+	if exists {
+		return connection.QueryResult{Success: false, Message: "当前标签页已有进行中的事务，请先提交或回滚"}
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		logger.Error(err, "BeginSession 获取连接失败：%s", formatConnSummary(runConfig))
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	opener, ok := dbInst.(db.TxSessionOpener)
+	if !ok {
+		return connection.QueryResult{Success: false, Message: db.ErrTxSessionUnsupported.Error()}
+	}
+
+	ctx, cancel := utils.ContextWithTimeout(txSessionBeginTimeout)
+	defer cancel()
+	tx, err := opener.BeginTxSession(ctx, isolation)
+	if err != nil {
+		logger.Error(err, "BeginSession 开启事务失败：%s 隔离级别=%s", formatConnSummary(runConfig), isolationName)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	idleTimeout := defaultTxSessionIdleTimeout
+	if idleTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(idleTimeoutSeconds) * time.Second
+	}
+	now := time.Now()
+	sessCtx, sessCancel := context.WithCancel(context.Background())
+	sess := &txSession{
+		id:          sessionID,
+		tx:          tx,
+		ctx:         sessCtx,
+		cancel:      sessCancel,
+		dbType:      db.ResolveDialect(runConfig),
+		connType:    runConfig.Type,
+		summary:     formatConnSummary(runConfig),
+		isolation:   isolationName,
+		timeout:     time.Duration(timeoutSeconds) * time.Second,
+		idleTimeout: idleTimeout,
+		startedAt:   now,
+		lastActive:  now,
+	}
+
+	sess.timer = time.AfterFunc(idleTimeout, func() { a.expireTxSession(sess) })
+
+	a.txMu.Lock()
+	if _, exists := a.txSessions[sessionID]; exists {
+		a.txMu.Unlock()
+		sess.timer.Stop()
+		sessCancel()
+		_ = tx.Rollback()
+		return connection.QueryResult{Success: false, Message: "当前标签页已有进行中的事务，请先提交或回滚"}
+	}
+	a.txSessions[sessionID] = sess
+	a.txMu.Unlock()
+
+	logger.Infof("事务会话已开启：%s 会话=%s 隔离级别=%s", sess.summary, sessionID, isolationName)
+	return connection.QueryResult{Success: true, Message: "事务已开启", Data: sess.info()}
+}
+
+// SessionQuery 在事务会话中执行单条语句，执行期间可通过 CancelQuery(queryID) 中止。
+func (a *App) SessionQuery(sessionID string, query string, queryID string) connection.QueryResult {
+	sess, ok := a.lookupTxSession(sessionID)
+	if !ok {
+		return connection.QueryResult{Success: false, Message: "事务会话不存在或已结束"}
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return connection.QueryResult{Success: false, Message: "事务会话不存在或已结束"}
+	}
+
+	// 语句上下文派生自会话而不是带超时的独立上下文：超时与取消都经 abort 优先在服务端中止，
+	// 只有驱动无法服务端中止时才取消本地上下文，避免驱动因上下文取消把事务连接标记为失效
+	ctx, cancel := context.WithCancel(sess.ctx)
+	defer cancel()
+	queryID, running, err := a.registerQuery(queryID, cancel, sess.summary)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer a.finishQuery(queryID, running)
+	a.attachQueryKiller(running, sess.tx)
+	var timedOut atomic.Bool
+	timeoutTimer := time.AfterFunc(sess.timeout, func() {
+		timedOut.Store(true)
+		running.abort()
+	})
+	defer timeoutTimer.Stop()
+
+	query = sanitizeSQLForPgLike(sess.connType, query)
+	out, err := runStatement(ctx, nil, sess.tx, sess.dbType, query)
+	sess.lastActive = time.Now()
+	sess.statements++
+	if statementMayWrite(sess.dbType, query) {
+		sess.dirty = true
+	}
+	if err != nil {
+		if isTxConnLost(err) {
+			a.closeLostTxSession(sess)
+			logger.Error(err, "SessionQuery 事务连接已断开：%s 会话=%s", sess.summary, sess.id)
+			return connection.QueryResult{Success: false, Message: "事务连接已断开，事务已回滚：" + err.Error(), QueryID: queryID}
+		}
+		if timedOut.Load() {
+			return connection.QueryResult{Success: false, Message: fmt.Sprintf("执行超过 %s，已中止", sess.timeout), QueryID: queryID}
+		}
+		if running.isCancelled() {
+			return connection.QueryResult{Success: false, Message: "查询已取消", QueryID: queryID}
+		}
+		logger.Error(err, "SessionQuery 执行失败：%s 会话=%s SQL片段=%q", sess.summary, sess.id, sqlSnippet(query))
+		return connection.QueryResult{Success: false, Message: err.Error(), QueryID: queryID}
+	}
+	if out.isRead {
+		return connection.QueryResult{Success: true, Data: out.data, Fields: out.columns, Columns: out.meta, QueryID: queryID}
+	}
+	return connection.QueryResult{Success: true, Data: map[string]int64{"affectedRows": out.affected}, QueryID: queryID}
+}
+
+// isTxConnLost 判断错误是否表示事务所在连接已失效，此时事务已无法继续。
+func isTxConnLost(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, sql.ErrTxDone)
+}
+
+// closeLostTxSession 关闭连接已失效的会话，调用方需持有 sess.mu。
+func (a *App) closeLostTxSession(sess *txSession) {
+	sess.closed = true
+	sess.timer.Stop()
+	sess.cancel()
+	a.removeTxSession(sess)
+	_ = sess.tx.Rollback()
+}
+
+// CommitSession 提交事务会话并归还连接。
+func (a *App) CommitSession(sessionID string) connection.QueryResult {
+	return a.endTxSession(sessionID, true)
+}
+
+// RollbackSession 回滚事务会话并归还连接。
+func (a *App) RollbackSession(sessionID string) connection.QueryResult {
+	return a.endTxSession(sessionID, false)
+}
+
+// ListSessions 返回所有进行中的事务会话。
+func (a *App) ListSessions() connection.QueryResult {
+	infos := make([]txSessionInfo, 0)
+	for _, sess := range a.snapshotTxSessions() {
+		sess.mu.Lock()
+		if !sess.closed {
+			infos = append(infos, sess.info())
+		}
+		sess.mu.Unlock()
+	}
+	return connection.QueryResult{Success: true, Data: infos}
+}
+
+func (a *App) endTxSession(sessionID string, commit bool) connection.QueryResult {
+	sess, ok := a.lookupTxSession(sessionID)
+	if !ok {
+		return connection.QueryResult{Success: false, Message: "事务会话不存在或已结束"}
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return connection.QueryResult{Success: false, Message: "事务会话不存在或已结束"}
+	}
+	sess.closed = true
+	sess.timer.Stop()
+	defer sess.cancel()
+	a.removeTxSession(sess)
+
+	action := "回滚"
+	var err error
+	if commit {
+		action = "提交"
+		err = sess.tx.Commit()
+	} else {
+		err = sess.tx.Rollback()
+	}
+	if err != nil {
+		logger.Error(err, "事务会话%s失败：%s 会话=%s", action, sess.summary, sess.id)
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	logger.Infof("事务会话已%s：%s 会话=%s 语句数=%d", action, sess.summary, sess.id, sess.statements)
+	return connection.QueryResult{Success: true, Message: fmt.Sprintf("事务已%s", action)}
+}
+
+// expireTxSession 在空闲超时后自动回滚；期间有新操作时按剩余时间重新计时。
+func (a *App) expireTxSession(sess *txSession) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return
+	}
+	if remaining := sess.idleTimeout - time.Since(sess.lastActive); remaining > 0 {
+		sess.timer.Reset(remaining)
+		return
+	}
+
+	sess.closed = true
+	sess.cancel()
+	a.removeTxSession(sess)
+	if err := sess.tx.Rollback(); err != nil {
+		logger.Error(err, "事务会话空闲超时回滚失败：%s 会话=%s", sess.summary, sess.id)
+	} else {
+		logger.Warnf("事务会话空闲超过 %s，已自动回滚：%s 会话=%s 未提交修改=%t", sess.idleTimeout, sess.summary, sess.id, sess.dirty)
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, txSessionTimeoutEvent, sess.info())
+	}
+}
+
+// BeforeClose 在窗口关闭前检查未提交的事务会话，用户取消时阻止关闭。
+func (a *App) BeforeClose(ctx context.Context) (prevent bool) {
+	dirty := 0
+	for _, sess := range a.snapshotTxSessions() {
+		sess.mu.Lock()
+		if !sess.closed && sess.dirty {
+			dirty++
+		}
+		sess.mu.Unlock()
+	}
+	if dirty == 0 {
+		return false
+	}
+
+	choice, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "存在未提交的事务",
+		Message:       fmt.Sprintf("有 %d 个事务会话包含未提交的修改，退出将全部回滚。确定要退出吗？", dirty),
+		Buttons:       []string{"退出", "取消"},
+		DefaultButton: "取消",
+		CancelButton:  "取消",
+	})
+	if err != nil {
+		logger.Error(err, "显示退出确认对话框失败")
+		return false
+	}
+	return choice != "退出" && choice != "Yes" && choice != "Ok"
+}
+
+// rollbackAllTxSessions 在应用关闭时回滚所有未结束的事务会话。
+func (a *App) rollbackAllTxSessions() {
+	for _, sess := range a.snapshotTxSessions() {
+		sess.mu.Lock()
+		if !sess.closed {
+			sess.closed = true
+			sess.timer.Stop()
+			sess.cancel()
+			if sess.dirty {
+				logger.Warnf("应用关闭，回滚未提交的事务会话：%s 会话=%s 语句数=%d", sess.summary, sess.id, sess.statements)
+			}
+			if err := sess.tx.Rollback(); err != nil {
+				logger.Error(err, "回滚事务会话失败：%s 会话=%s", sess.summary, sess.id)
+			}
+		}
+		sess.mu.Unlock()
+		a.removeTxSession(sess)
+	}
+}
+
+func (a *App) lookupTxSession(sessionID string) (*txSession, bool) {
+	a.txMu.Lock()
+	defer a.txMu.Unlock()
+	sess, ok := a.txSessions[strings.TrimSpace(sessionID)]
+	return sess, ok
+}
+
+func (a *App) removeTxSession(sess *txSession) {
+	a.txMu.Lock()
+	defer a.txMu.Unlock()
+	if current, ok := a.txSessions[sess.id]; ok && current == sess {
+		delete(a.txSessions, sess.id)
+	}
+}
+
+func (a *App) snapshotTxSessions() []*txSession {
+	a.txMu.Lock()
+	defer a.txMu.Unlock()
+	sessions := make([]*txSession, 0, len(a.txSessions))
+	for _, sess := range a.txSessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
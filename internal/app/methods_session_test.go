@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	cases := []struct {
+		in   string
+		want sql.IsolationLevel
+		name string
+	}{
+		{"", sql.LevelDefault, "DEFAULT"},
+		{"read committed", sql.LevelReadCommitted, "READ COMMITTED"},
+		{"REPEATABLE_READ", sql.LevelRepeatableRead, "REPEATABLE READ"},
+		{" read-uncommitted ", sql.LevelReadUncommitted, "READ UNCOMMITTED"},
+		{"Serializable", sql.LevelSerializable, "SERIALIZABLE"},
+	}
+	for _, tc := range cases {
+		level, name, err := parseIsolationLevel(tc.in)
+		if err != nil {
+			t.Fatalf("parseIsolationLevel(%q) 返回错误：%v", tc.in, err)
+		}
+		if level != tc.want || name != tc.name {
+			t.Fatalf("parseIsolationLevel(%q) = (%v, %q)，期望 (%v, %q)", tc.in, level, name, tc.want, tc.name)
+		}
+	}
+
+	if _, _, err := parseIsolationLevel("chaos"); err == nil {
+		t.Fatalf("不支持的隔离级别应返回错误")
+	}
+}
+
+// newSessionTestApp 创建一个带 SQLite 测试库（含空表 t）的 App。
+func newSessionTestApp(t *testing.T) (*App, connection.ConnectionConfig) {
+	t.Helper()
+	a := NewApp()
+	config := connection.ConnectionConfig{Type: "sqlite", Host: filepath.Join(t.TempDir(), "session.db"), Timeout: 1}
+	if res := a.DBQuery(config, "", "CREATE TABLE t (id INTEGER)", ""); !res.Success {
+		t.Fatalf("建表失败：%s", res.Message)
+	}
+	t.Cleanup(func() {
+		a.rollbackAllTxSessions()
+		for _, cached := range a.dbCache {
+			cached.inst.Close()
+		}
+	})
+	return a, config
+}
+
+func countRows(t *testing.T, a *App, config connection.ConnectionConfig) int {
+	t.Helper()
+	res := a.DBQuery(config, "", "SELECT COUNT(*) AS n FROM t", "")
+	if !res.Success {
+		t.Fatalf("统计行数失败：%s", res.Message)
+	}
+	rows := res.Data.([]map[string]interface{})
+	var count int
+	if _, err := fmt.Sscan(fmt.Sprint(rows[0]["n"]), &count); err != nil {
+		t.Fatalf("无法解析行数：%v", rows[0]["n"])
+	}
+	return count
+}
+
+func mustSessionQuery(t *testing.T, a *App, sessionID, query string) connection.QueryResult {
+	t.Helper()
+	res := a.SessionQuery(sessionID, query, "")
+	if !res.Success {
+		t.Fatalf("SessionQuery(%q) 失败：%s", query, res.Message)
+	}
+	return res
+}
+
+func TestTxSession_CommitAndRollback(t *testing.T) {
+	a, config := newSessionTestApp(t)
+
+	if res := a.BeginSession(config, "", "tab-1", "", 0); !res.Success {
+		t.Fatalf("BeginSession 失败：%s", res.Message)
+	}
+	if res := a.BeginSession(config, "", "tab-1", "", 0); res.Success {
+		t.Fatalf("同一标签页重复开启事务应失败")
+	}
+	mustSessionQuery(t, a, "tab-1", "INSERT INTO t VALUES (1)")
+	res := mustSessionQuery(t, a, "tab-1", "SELECT COUNT(*) AS n FROM t")
+	if rows := res.Data.([]map[string]interface{}); fmt.Sprint(rows[0]["n"]) != "1" {
+		t.Fatalf("事务内应能读到未提交的插入，实际=%v", rows[0]["n"])
+	}
+	if res := a.RollbackSession("tab-1"); !res.Success {
+		t.Fatalf("RollbackSession 失败：%s", res.Message)
+	}
+	if n := countRows(t, a, config); n != 0 {
+		t.Fatalf("回滚后应为 0 行，实际=%d", n)
+	}
+	if res := a.SessionQuery("tab-1", "SELECT 1", ""); res.Success {
+		t.Fatalf("已结束的会话不应再执行语句")
+	}
+
+	if res := a.BeginSession(config, "", "tab-1", "", 0); !res.Success {
+		t.Fatalf("BeginSession 失败：%s", res.Message)
+	}
+	mustSessionQuery(t, a, "tab-1", "INSERT INTO t VALUES (2)")
+	if res := a.CommitSession("tab-1"); !res.Success {
+		t.Fatalf("CommitSession 失败：%s", res.Message)
+	}
+	if n := countRows(t, a, config); n != 1 {
+		t.Fatalf("提交后应为 1 行，实际=%d", n)
+	}
+	if res := a.CommitSession("tab-1"); res.Success {
+		t.Fatalf("重复提交应失败")
+	}
+}
+
+func TestTxSession_TimeoutKeepsTransaction(t *testing.T) {
+	a, config := newSessionTestApp(t)
+
+	if res := a.BeginSession(config, "", "tab-1", "", 0); !res.Success {
+		t.Fatalf("BeginSession 失败：%s", res.Message)
+	}
+	mustSessionQuery(t, a, "tab-1", "INSERT INTO t VALUES (1)")
+
+	endless := "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c"
+	res := a.SessionQuery("tab-1", endless, "")
+	if res.Success || !strings.Contains(res.Message, "已中止") {
+		t.Fatalf("超时语句应返回超时中止，实际=%+v", res)
+	}
+
+	// 语句超时只中止该语句，事务与其中未提交的修改仍然有效
+	res = mustSessionQuery(t, a, "tab-1", "SELECT COUNT(*) AS n FROM t")
+	if rows := res.Data.([]map[string]interface{}); fmt.Sprint(rows[0]["n"]) != "1" {
+		t.Fatalf("超时后事务内的修改应保留，实际=%v", rows[0]["n"])
+	}
+}
+
+func TestTxSession_IdleExpiryRollsBack(t *testing.T) {
+	a, config := newSessionTestApp(t)
+
+	if res := a.BeginSession(config, "", "tab-1", "", 0); !res.Success {
+		t.Fatalf("BeginSession 失败：%s", res.Message)
+	}
+	mustSessionQuery(t, a, "tab-1", "INSERT INTO t VALUES (1)")
+	sess, ok := a.lookupTxSession("tab-1")
+	if !ok {
+		t.Fatalf("会话应存在")
+	}
+
+	// 有新操作时按剩余时间重新计时，不回滚
+	a.expireTxSession(sess)
+	if _, ok := a.lookupTxSession("tab-1"); !ok {
+		t.Fatalf("未到空闲超时不应回滚")
+	}
+
+	sess.mu.Lock()
+	sess.lastActive = time.Now().Add(-2 * sess.idleTimeout)
+	sess.mu.Unlock()
+	a.expireTxSession(sess)
+	if _, ok := a.lookupTxSession("tab-1"); ok {
+		t.Fatalf("空闲超时后会话应被移除")
+	}
+	if n := countRows(t, a, config); n != 0 {
+		t.Fatalf("空闲超时应回滚未提交的修改，实际=%d 行", n)
+	}
+}
+
+func TestTxSession_CloseRollsBackDirtySessions(t *testing.T) {
+	a, config := newSessionTestApp(t)
+
+	if res := a.BeginSession(config, "", "tab-1", "", 0); !res.Success {
+		t.Fatalf("BeginSession 失败：%s", res.Message)
+	}
+	mustSessionQuery(t, a, "tab-1", "SELECT 1")
+	// 只有只读语句时不需要确认，直接允许关闭
+	if a.BeforeClose(context.Background()) {
+		t.Fatalf("没有未提交修改时不应阻止关闭")
+	}
+
+	mustSessionQuery(t, a, "tab-1", "INSERT INTO t VALUES (1)")
+	var info txSessionInfo
+	for _, s := range a.ListSessions().Data.([]txSessionInfo) {
+		info = s
+	}
+	if !info.Dirty || info.Statements != 2 {
+		t.Fatalf("会话状态不正确：%+v", info)
+	}
+
+	a.rollbackAllTxSessions()
+	if len(a.ListSessions().Data.([]txSessionInfo)) != 0 {
+		t.Fatalf("关闭时应结束所有会话")
+	}
+	if n := countRows(t, a, config); n != 0 {
+		t.Fatalf("关闭时应回滚未提交的修改，实际=%d 行", n)
+	}
+}
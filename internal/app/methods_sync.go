@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/logger"
 	"GoNavi-Wails/internal/sync"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -19,6 +20,18 @@ func (a *App) DataSync(config sync.SyncConfig) sync.SyncResult {
 		config.JobID = jobID
 	}
 
+	// Dry run writes the statements to a script; ask where to save it if the caller didn't say.
+	if config.DryRun && strings.TrimSpace(config.ScriptPath) == "" {
+		filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Save Sync Script",
+			DefaultFilename: fmt.Sprintf("sync_%s.sql", time.Now().Format("20060102_150405")),
+		})
+		if err != nil || filename == "" {
+			return sync.SyncResult{Success: false, Message: "已取消"}
+		}
+		config.ScriptPath = filename
+	}
+
 	reporter := sync.Reporter{
 		OnLog: func(event sync.SyncLogEvent) {
 			runtime.EventsEmit(a.ctx, sync.EventSyncLog, event)
@@ -39,11 +52,38 @@ func (a *App) DataSync(config sync.SyncConfig) sync.SyncResult {
 	runtime.EventsEmit(a.ctx, sync.EventSyncDone, map[string]any{
 		"jobId":  jobID,
 		"result": res,
+		"status": sync.ResultStatus(res),
 	})
 
 	return res
 }
 
+// CancelSync cancels a running sync (DataSync or a sync job run) by its job/run id.
+// Batches already written are kept; the run stops at the next batch boundary.
+func (a *App) CancelSync(jobID string) connection.QueryResult {
+	if err := sync.CancelSync(jobID); err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	logger.Infof("已请求取消同步：任务=%s", jobID)
+	return connection.QueryResult{Success: true, Message: "正在取消"}
+}
+
+// PauseSync pauses a running sync before its next batch is written.
+func (a *App) PauseSync(jobID string) connection.QueryResult {
+	if err := sync.PauseSync(jobID); err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return connection.QueryResult{Success: true, Message: "已暂停"}
+}
+
+// ResumeSync resumes a paused sync.
+func (a *App) ResumeSync(jobID string) connection.QueryResult {
+	if err := sync.ResumeSync(jobID); err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return connection.QueryResult{Success: true, Message: "已恢复"}
+}
+
 // DataSyncAnalyze analyzes differences between source and target for the given tables (dry-run).
 func (a *App) DataSyncAnalyze(config sync.SyncConfig) connection.QueryResult {
 	jobID := strings.TrimSpace(config.JobID)
@@ -97,3 +137,28 @@ func (a *App) DataSyncPreview(config sync.SyncConfig, tableName string, limit in
 	}
 	return connection.QueryResult{Success: true, Message: "OK", Data: preview}
 }
+
+// SchemaCompare compares table structures and returns an ordered migration script for the target.
+func (a *App) SchemaCompare(config sync.SyncConfig) connection.QueryResult {
+	jobID := strings.TrimSpace(config.JobID)
+	if jobID == "" {
+		jobID = fmt.Sprintf("schema-%d", time.Now().UnixNano())
+		config.JobID = jobID
+	}
+
+	reporter := sync.Reporter{
+		OnLog: func(event sync.SyncLogEvent) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncLog, event)
+		},
+		OnProgress: func(event sync.SyncProgressEvent) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncProgress, event)
+		},
+	}
+
+	engine := sync.NewSyncEngine(reporter)
+	res := engine.CompareSchema(config)
+	if !res.Success {
+		return connection.QueryResult{Success: false, Message: res.Message, Data: res}
+	}
+	return connection.QueryResult{Success: true, Message: res.Message, Data: res}
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	stdsync "sync"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/logger"
+	"GoNavi-Wails/internal/sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Sync job scheduler, created on startup and stopped on shutdown.
+var (
+	syncScheduler   *sync.Scheduler
+	syncSchedulerMu stdsync.Mutex
+)
+
+// startSyncScheduler loads the saved sync jobs and starts running the scheduled ones.
+func (a *App) startSyncScheduler() {
+	store, err := sync.OpenJobStore(sync.DefaultJobStoreDir())
+	if err != nil {
+		logger.Error(err, "加载同步任务失败，定时同步不可用")
+		return
+	}
+
+	reporter := sync.Reporter{
+		OnLog: func(event sync.SyncLogEvent) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncLog, event)
+		},
+		OnProgress: func(event sync.SyncProgressEvent) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncProgress, event)
+		},
+	}
+	hooks := sync.JobHooks{
+		OnStart: func(job sync.SyncJob, runID string) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncStart, map[string]any{
+				"jobId":     runID,
+				"total":     len(job.Config.Tables),
+				"type":      "job",
+				"syncJobId": job.ID,
+			})
+		},
+		OnDone: func(job sync.SyncJob, run sync.SyncRunRecord) {
+			runtime.EventsEmit(a.ctx, sync.EventSyncDone, map[string]any{
+				"jobId":     run.ID,
+				"result":    run,
+				"status":    run.Status(),
+				"type":      "job",
+				"syncJobId": job.ID,
+			})
+		},
+	}
+
+	scheduler := sync.NewScheduler(store, reporter, hooks)
+	scheduler.Start()
+
+	syncSchedulerMu.Lock()
+	syncScheduler = scheduler
+	syncSchedulerMu.Unlock()
+	logger.Infof("同步任务调度已启动：任务数=%d", len(store.ListJobs()))
+}
+
+func stopSyncScheduler() {
+	syncSchedulerMu.Lock()
+	scheduler := syncScheduler
+	syncScheduler = nil
+	syncSchedulerMu.Unlock()
+	if scheduler != nil {
+		scheduler.Stop()
+	}
+}
+
+func currentSyncScheduler() (*sync.Scheduler, connection.QueryResult, bool) {
+	syncSchedulerMu.Lock()
+	defer syncSchedulerMu.Unlock()
+	if syncScheduler == nil {
+		return nil, connection.QueryResult{Success: false, Message: "同步任务存储不可用，请查看日志"}, false
+	}
+	return syncScheduler, connection.QueryResult{}, true
+}
+
+// ListSyncJobs returns all saved sync jobs.
+func (a *App) ListSyncJobs() connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	return connection.QueryResult{Success: true, Message: "OK", Data: scheduler.Store().ListJobs()}
+}
+
+// SaveSyncJob creates (empty id) or updates a sync job and reschedules it.
+func (a *App) SaveSyncJob(job sync.SyncJob) connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	saved, err := scheduler.Store().SaveJob(job)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	scheduler.Reload()
+	logger.Infof("已保存同步任务：任务=%s 调度=%s 启用=%v", saved.Name, saved.Schedule.Type, saved.Enabled)
+	return connection.QueryResult{Success: true, Message: "已保存", Data: saved}
+}
+
+// DeleteSyncJob deletes a sync job together with its run history.
+func (a *App) DeleteSyncJob(id string) connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	if err := scheduler.Store().DeleteJob(id); err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	scheduler.Reload()
+	return connection.QueryResult{Success: true, Message: "已删除"}
+}
+
+// RunSyncJob runs a saved job right away and returns its run record.
+func (a *App) RunSyncJob(id string) connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	run, err := scheduler.RunJob(id, sync.TriggerManual)
+	if err != nil {
+		return connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return connection.QueryResult{Success: run.Success, Message: run.Message, Data: run}
+}
+
+// ListSyncJobRuns returns run history, newest first. An empty jobID lists runs of all jobs.
+func (a *App) ListSyncJobRuns(jobID string, limit int) connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	return connection.QueryResult{Success: true, Message: "OK", Data: scheduler.Store().ListRuns(jobID, limit)}
+}
+
+// GetRunningSyncJobs returns the ids of jobs that are currently running, mapped to their run ids.
+func (a *App) GetRunningSyncJobs() connection.QueryResult {
+	scheduler, fail, ok := currentSyncScheduler()
+	if !ok {
+		return fail
+	}
+	return connection.QueryResult{Success: true, Message: "OK", Data: scheduler.Running()}
+}
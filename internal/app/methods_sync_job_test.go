@@ -0,0 +1,87 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/sync"
+)
+
+func TestSyncJobMethods(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	a := NewApp()
+	t.Cleanup(func() {
+		for _, cached := range a.dbCache {
+			cached.inst.Close()
+		}
+	})
+
+	// 调度器未启动时返回失败
+	stopSyncScheduler()
+	if res := a.ListSyncJobs(); res.Success || !strings.Contains(res.Message, "同步任务存储不可用") {
+		t.Fatalf("调度器不可用时应返回失败，实际=%+v", res)
+	}
+
+	source := connection.ConnectionConfig{Type: "sqlite", Host: filepath.Join(t.TempDir(), "source.db")}
+	target := connection.ConnectionConfig{Type: "sqlite", Host: filepath.Join(t.TempDir(), "target.db")}
+	for _, stmt := range []string{"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)", "INSERT INTO items VALUES (1, 'a'), (2, 'b')"} {
+		if res := a.DBQuery(source, "", stmt, ""); !res.Success {
+			t.Fatalf("准备源表失败：%s", res.Message)
+		}
+	}
+	if res := a.DBQuery(target, "", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)", ""); !res.Success {
+		t.Fatalf("准备目标表失败：%s", res.Message)
+	}
+
+	store, err := sync.OpenJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenJobStore 返回错误：%v", err)
+	}
+	syncSchedulerMu.Lock()
+	syncScheduler = sync.NewScheduler(store, sync.Reporter{}, sync.JobHooks{})
+	syncSchedulerMu.Unlock()
+	t.Cleanup(stopSyncScheduler)
+
+	if res := a.SaveSyncJob(sync.SyncJob{Name: " "}); res.Success {
+		t.Fatalf("任务名称为空时不应保存")
+	}
+	res := a.SaveSyncJob(sync.SyncJob{Name: "items", Config: sync.SyncConfig{
+		SourceConfig: source,
+		TargetConfig: target,
+		Tables:       []string{"items"},
+		Content:      "data",
+		Mode:         "insert_update",
+	}})
+	if !res.Success {
+		t.Fatalf("保存任务失败：%s", res.Message)
+	}
+	job := res.Data.(sync.SyncJob)
+	if jobs := a.ListSyncJobs().Data.([]sync.SyncJob); len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Fatalf("任务列表不正确：%+v", jobs)
+	}
+
+	res = a.RunSyncJob(job.ID)
+	run, ok := res.Data.(sync.SyncRunRecord)
+	if !res.Success || !ok || run.RowsInserted != 2 || run.Trigger != sync.TriggerManual {
+		t.Fatalf("运行任务结果不正确：%+v", res)
+	}
+	if res := a.RunSyncJob("missing"); res.Success {
+		t.Fatalf("任务不存在时应返回失败")
+	}
+	if runs := a.ListSyncJobRuns(job.ID, 10).Data.([]sync.SyncRunRecord); len(runs) != 1 || runs[0].ID != run.ID {
+		t.Fatalf("运行记录不正确：%+v", runs)
+	}
+	if running := a.GetRunningSyncJobs().Data.(map[string]string); len(running) != 0 {
+		t.Fatalf("没有运行中的任务，实际=%v", running)
+	}
+
+	if res := a.DeleteSyncJob(job.ID); !res.Success {
+		t.Fatalf("删除任务失败：%s", res.Message)
+	}
+	if jobs := a.ListSyncJobs().Data.([]sync.SyncJob); len(jobs) != 0 {
+		t.Fatalf("删除后任务列表应为空：%+v", jobs)
+	}
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"GoNavi-Wails/internal/logger"
+	"GoNavi-Wails/internal/utils"
+)
+
+const queryKillTimeout = 5 * time.Second
+
+// queryKiller 能在服务端中止正在执行的语句，由 db.QuerySession 与 db.TxSession 实现。
+type queryKiller interface {
+	Kill(ctx context.Context) error
+	SessionID() string
+}
+
+// sessionRunner 在固定连接上执行语句，由 db.QuerySession 与 db.TxSession 实现。
+type sessionRunner interface {
+	QueryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error)
+	ExecContext(ctx context.Context, query string) (int64, error)
+}
+
+// runningQuery 记录一条正在执行的查询，供 CancelQuery 中止。
+// 服务端中止不持有 mu 执行；中止期间查询结束时由 abort 归还会话，避免连接被归还后误杀复用该连接的其他查询。
+type runningQuery struct {
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	killer    queryKiller
+	release   func() error // 查询结束时归还固定连接；事务会话的连接由会话自身管理，为空
+	summary   string
+	finished  bool
+	cancelled bool
+	killing   bool
+}
+
+func (q *runningQuery) isCancelled() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.cancelled
+}
+
+// registerQuery 登记查询；queryID 为空时自动生成。
+func (a *App) registerQuery(queryID string, cancel context.CancelFunc, summary string) (string, *runningQuery, error) {
+	queryID = strings.TrimSpace(queryID)
+	if queryID == "" {
+		queryID = fmt.Sprintf("query-%d", time.Now().UnixNano())
+	}
+
+	a.queryMu.Lock()
+	defer a.queryMu.Unlock()
+	if _, exists := a.runningQueries[queryID]; exists {
+		return "", nil, fmt.Errorf("查询 ID 已存在：%s", queryID)
+	}
+	rq := &runningQuery{cancel: cancel, summary: summary}
+	a.runningQueries[queryID] = rq
+	return queryID, rq, nil
+}
+
+// attachQuerySession 为查询打开固定会话；驱动不支持时返回 nil，由调用方退回普通执行路径。
+func (a *App) attachQuerySession(ctx context.Context, rq *runningQuery, dbInst db.Database) sessionRunner {
+	opener, ok := dbInst.(db.QuerySessionOpener)
+	if !ok {
+		return nil
+	}
+	session, err := opener.OpenQuerySession(ctx)
+	if err != nil {
+		if !errors.Is(err, db.ErrQuerySessionUnsupported) {
+			logger.Warnf("打开查询会话失败，将无法服务端中止：%s 原因=%v", rq.summary, err)
+		}
+		return nil
+	}
+	rq.mu.Lock()
+	rq.killer = session
+	rq.release = session.Close
+	rq.mu.Unlock()
+	return session
+}
+
+// attachQueryKiller 关联事务会话，使 CancelQuery 能在服务端中止事务中的语句。
+func (a *App) attachQueryKiller(rq *runningQuery, killer queryKiller) {
+	rq.mu.Lock()
+	rq.killer = killer
+	rq.mu.Unlock()
+}
+
+// finishQuery 注销查询并归还固定会话。
+func (a *App) finishQuery(queryID string, rq *runningQuery) {
+	a.queryMu.Lock()
+	delete(a.runningQueries, queryID)
+	a.queryMu.Unlock()
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	rq.finished = true
+	rq.killer = nil
+	if !rq.killing {
+		rq.releaseSession()
+	}
+}
+
+// releaseSession 归还固定会话，调用方需持有 mu。
+func (q *runningQuery) releaseSession() {
+	if q.release == nil {
+		return
+	}
+	if err := q.release(); err != nil {
+		logger.Warnf("归还查询会话失败：%s 原因=%v", q.summary, err)
+	}
+	q.release = nil
+}
+
+// abort 优先在服务端中止语句，此时保留本地上下文，让驱动正常收到中止错误以保持连接可用；
+// 无法服务端中止时再取消本地上下文。
+func (q *runningQuery) abort() {
+	q.mu.Lock()
+	if q.finished || q.killing {
+		q.mu.Unlock()
+		return
+	}
+	q.cancelled = true
+	killer := q.killer
+	q.killing = killer != nil
+	q.mu.Unlock()
+
+	if killer != nil {
+		ctx, cancel := utils.ContextWithTimeout(queryKillTimeout)
+		err := killer.Kill(ctx)
+		cancel()
+
+		q.mu.Lock()
+		q.killing = false
+		if q.finished {
+			q.releaseSession()
+		}
+		q.mu.Unlock()
+
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, db.ErrQuerySessionUnsupported) {
+			logger.Warnf("服务端中止查询失败：%s 会话=%s 原因=%v", q.summary, killer.SessionID(), err)
+		}
+	}
+	q.cancel()
+}
+
+// CancelQuery 中止正在执行的查询。
+func (a *App) CancelQuery(queryID string) connection.QueryResult {
+	queryID = strings.TrimSpace(queryID)
+	if queryID == "" {
+		return connection.QueryResult{Success: false, Message: "查询 ID 不能为空"}
+	}
+
+	a.queryMu.Lock()
+	rq, ok := a.runningQueries[queryID]
+	a.queryMu.Unlock()
+	if !ok {
+		return connection.QueryResult{Success: false, Message: "查询不存在或已结束"}
+	}
+
+	rq.abort()
+	logger.Infof("已取消查询：%s queryID=%s", rq.summary, queryID)
+	return connection.QueryResult{Success: true, Message: "查询已取消", QueryID: queryID}
+}
+
+// cancelAllQueries 在应用关闭时中止所有仍在执行的查询。
+func (a *App) cancelAllQueries() {
+	a.queryMu.Lock()
+	pending := make([]*runningQuery, 0, len(a.runningQueries))
+	for _, rq := range a.runningQueries {
+		pending = append(pending, rq)
+	}
+	a.queryMu.Unlock()
+
+	for _, rq := range pending {
+		rq.abort()
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCancelQuery_CancelsRegisteredContext(t *testing.T) {
+	a := NewApp()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queryID, rq, err := a.registerQuery("", cancel, "test")
+	if err != nil {
+		t.Fatalf("registerQuery 返回错误：%v", err)
+	}
+	if queryID == "" {
+		t.Fatalf("queryID 为空时应自动生成")
+	}
+	if _, _, err := a.registerQuery(queryID, cancel, "test"); err == nil {
+		t.Fatalf("重复的 queryID 应返回错误")
+	}
+
+	res := a.CancelQuery(queryID)
+	if !res.Success {
+		t.Fatalf("CancelQuery 失败：%s", res.Message)
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("CancelQuery 后上下文应已取消")
+	}
+	if !rq.isCancelled() {
+		t.Fatalf("CancelQuery 后查询应标记为已取消")
+	}
+
+	a.finishQuery(queryID, rq)
+	if res := a.CancelQuery(queryID); res.Success {
+		t.Fatalf("已结束的查询不应再被取消")
+	}
+}
+
+type blockingKiller struct {
+	started chan struct{}
+	proceed chan struct{}
+}
+
+func (k *blockingKiller) Kill(ctx context.Context) error {
+	close(k.started)
+	<-k.proceed
+	return nil
+}
+
+func (k *blockingKiller) SessionID() string { return "1" }
+
+func TestAbort_KillRunsOutsideLockAndDefersRelease(t *testing.T) {
+	a := NewApp()
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queryID, rq, err := a.registerQuery("q1", cancel, "test")
+	if err != nil {
+		t.Fatalf("registerQuery 返回错误：%v", err)
+	}
+	killer := &blockingKiller{started: make(chan struct{}), proceed: make(chan struct{})}
+	released := make(chan struct{}, 1)
+	rq.killer = killer
+	rq.release = func() error {
+		released <- struct{}{}
+		return nil
+	}
+
+	aborted := make(chan struct{})
+	go func() {
+		rq.abort()
+		close(aborted)
+	}()
+	<-killer.started
+
+	// 服务端中止期间，再次取消与查询结束都不应被阻塞
+	rq.abort()
+	a.finishQuery(queryID, rq)
+	select {
+	case <-released:
+		t.Fatalf("服务端中止完成前不应归还会话")
+	default:
+	}
+
+	close(killer.proceed)
+	<-aborted
+	select {
+	case <-released:
+	default:
+		t.Fatalf("服务端中止完成后应归还会话")
+	}
+	if !rq.isCancelled() {
+		t.Fatalf("abort 后查询应标记为已取消")
+	}
+}
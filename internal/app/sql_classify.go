@@ -0,0 +1,255 @@
+package app
+
+import (
+	"strings"
+
+	"GoNavi-Wails/internal/db"
+)
+
+type sqlTokenKind int
+
+const (
+	sqlTokenWord sqlTokenKind = iota
+	sqlTokenSymbol
+	sqlTokenLiteral
+)
+
+// sqlToken 是轻量词法分析的结果：关键字/标识符统一转为大写，字符串与引号标识符作为整体字面量。
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	depth int // 所在的括号层级
+}
+
+// lexSQL 去掉注释并切分出单词、符号与字面量，只用于语句分类，不做完整语法分析。
+func lexSQL(dbType string, query string) []sqlToken {
+	mysqlLike := isMySQLLikeType(dbType)
+	pgLike := isPgLikeType(dbType)
+
+	tokens := make([]sqlToken, 0, 16)
+	depth := 0
+	for i := 0; i < len(query); {
+		ch := query[i]
+		next := byte(0)
+		if i+1 < len(query) {
+			next = query[i+1]
+		}
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '-' && next == '-', ch == '#' && mysqlLike:
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case ch == '/' && next == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += 2 + end + 2
+		case ch == '\'' || ch == '"' || (ch == '`' && mysqlLike):
+			start, backslash := i, mysqlLike && ch != '`'
+			// PG 的 E'...' 转义字符串中反斜杠可转义引号，前缀 E 与字符串合并为一个字面量
+			if ch == '\'' && pgLike && len(tokens) > 0 && (query[i-1] == 'E' || query[i-1] == 'e') {
+				if last := tokens[len(tokens)-1]; last.kind == sqlTokenWord && last.text == "E" {
+					tokens = tokens[:len(tokens)-1]
+					start, backslash = i-1, true
+				}
+			}
+			end := skipQuotedSQL(query, i, ch, backslash)
+			tokens = append(tokens, sqlToken{kind: sqlTokenLiteral, text: query[start:end], depth: depth})
+			i = end
+		case ch == '[' && dbType == "sqlserver":
+			end := skipQuotedSQL(query, i, ']', false)
+			tokens = append(tokens, sqlToken{kind: sqlTokenLiteral, text: query[i:end], depth: depth})
+			i = end
+		case ch == '$' && pgLike && parseDollarTag(query[i:]) != "":
+			tag := parseDollarTag(query[i:])
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				end = len(query)
+			} else {
+				end = i + len(tag) + end + len(tag)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenLiteral, text: query[i:end], depth: depth})
+			i = end
+		case isSQLWordStart(ch):
+			end := i + 1
+			for end < len(query) && isSQLWordChar(query[end]) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenWord, text: strings.ToUpper(query[i:end]), depth: depth})
+			i = end
+		case ch == '(':
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: "(", depth: depth})
+			depth++
+			i++
+		case ch == ')':
+			if depth > 0 {
+				depth--
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: ")", depth: depth})
+			i++
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: string(ch), depth: depth})
+			i++
+		}
+	}
+	return tokens
+}
+
+func skipQuotedSQL(query string, start int, closing byte, backslash bool) int {
+	i := start + 1
+	for i < len(query) {
+		c := query[i]
+		if backslash && c == '\\' && i+1 < len(query) {
+			i += 2
+			continue
+		}
+		if c == closing {
+			if i+1 < len(query) && query[i+1] == closing {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return len(query)
+}
+
+// isReadStatement 判断语句是否需要按查询方式执行以拿到结果集。
+//
+// 识别规则：
+//   - 去除前导注释、括号与分号后，SELECT/SHOW/DESCRIBE/DESC/EXPLAIN/VALUES/TABLE/PRAGMA 视为读语句
+//   - WITH 语句取 CTE 列表之后的主语句判断
+//   - INSERT/UPDATE/DELETE/MERGE 带 RETURNING（PG 系、SQLite、MariaDB）或 OUTPUT（SQL Server，非 OUTPUT INTO）时返回结果集
+//   - MySQL 系 CALL、SQL Server EXEC/EXECUTE 可能返回结果集，也按查询执行
+//   - MongoDB JSON 命令统一按查询执行
+func isReadStatement(dbType string, query string) bool {
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+	if dbType == "mongodb" {
+		// MongoDB JSON 命令中的 find/count/aggregate 也属于读查询
+		if strings.HasPrefix(strings.TrimSpace(query), "{") {
+			return true
+		}
+	}
+
+	tokens := lexSQL(dbType, query)
+	start := 0
+	for start < len(tokens) && tokens[start].kind == sqlTokenSymbol && (tokens[start].text == "(" || tokens[start].text == ";") {
+		start++
+	}
+	if start >= len(tokens) || tokens[start].kind != sqlTokenWord {
+		return false
+	}
+	depth := tokens[start].depth
+
+	switch tokens[start].text {
+	case "SELECT", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "VALUES", "TABLE", "PRAGMA":
+		return true
+	case "WITH":
+		main := findMainStatementAfterCTE(tokens, start+1, depth)
+		if main < 0 {
+			return false
+		}
+		switch tokens[main].text {
+		case "SELECT", "VALUES", "TABLE":
+			return true
+		case "INSERT", "UPDATE", "DELETE", "MERGE":
+			return dmlReturnsRows(dbType, tokens, main, depth)
+		}
+		return false
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "REPLACE":
+		return dmlReturnsRows(dbType, tokens, start, depth)
+	case "CALL":
+		return isMySQLLikeType(dbType)
+	case "EXEC", "EXECUTE":
+		return dbType == "sqlserver"
+	}
+	return false
+}
+
+// findMainStatementAfterCTE 跳过 WITH [RECURSIVE] name [(cols)] AS [NOT] [MATERIALIZED] (...) [, ...] 后返回主语句关键字位置。
+func findMainStatementAfterCTE(tokens []sqlToken, from int, depth int) int {
+	for i := from; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.depth != depth || tok.kind != sqlTokenWord {
+			continue
+		}
+		switch tok.text {
+		case "SELECT", "VALUES", "TABLE", "INSERT", "UPDATE", "DELETE", "MERGE":
+			return i
+		}
+	}
+	return -1
+}
+
+// dmlReturnsRows 判断 DML 是否带有返回结果集的 RETURNING/OUTPUT 子句。
+func dmlReturnsRows(dbType string, tokens []sqlToken, from int, depth int) bool {
+	supportsReturning := isPgLikeType(dbType) || dbType == "sqlite" || dbType == "mariadb"
+	for i := from + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.depth != depth || tok.kind != sqlTokenWord {
+			continue
+		}
+		switch tok.text {
+		case "RETURNING":
+			if supportsReturning {
+				return true
+			}
+		case "OUTPUT":
+			if dbType == "sqlserver" {
+				return !sqlServerOutputInto(tokens, i, depth)
+			}
+		}
+	}
+	return false
+}
+
+// sqlServerOutputInto 判断 OUTPUT 子句是否写入表变量（OUTPUT ... INTO @t），此时不会返回结果集。
+func sqlServerOutputInto(tokens []sqlToken, outputAt int, depth int) bool {
+	for i := outputAt + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.depth != depth || tok.kind != sqlTokenWord {
+			continue
+		}
+		switch tok.text {
+		case "INTO":
+			return true
+		case "VALUES", "SELECT", "DEFAULT", "FROM", "WHERE", "EXEC", "EXECUTE":
+			return false
+		}
+	}
+	return false
+}
+
+func isMySQLLikeType(dbType string) bool {
+	return db.Capabilities(dbType).Family == db.FamilyMySQL
+}
+
+func isPgLikeType(dbType string) bool {
+	return db.Capabilities(dbType).Family == db.FamilyPostgres
+}
+
+// statementMayWrite 判断语句是否可能修改数据或持有写锁，用于标记事务会话存在未提交的修改。
+// 判断偏保守：SELECT ... FOR UPDATE、带数据修改 CTE 的查询也视为写语句。
+func statementMayWrite(dbType string, query string) bool {
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+	if !isReadStatement(dbType, query) {
+		return len(lexSQL(dbType, query)) > 0
+	}
+	for _, tok := range lexSQL(dbType, query) {
+		if tok.kind != sqlTokenWord {
+			continue
+		}
+		switch tok.text {
+		case "INSERT", "UPDATE", "DELETE", "MERGE", "CALL", "EXEC", "EXECUTE", "LOCK":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package app
+
+import "testing"
+
+func TestIsReadStatement(t *testing.T) {
+	cases := []struct {
+		dbType string
+		query  string
+		want   bool
+	}{
+		{"mysql", "SELECT 1", true},
+		{"mysql", "  -- 注释\n/* 块注释 */ select * from t", true},
+		{"mysql", "# 注释\nSHOW TABLES", true},
+		{"mysql", "(SELECT 1) UNION (SELECT 2)", true},
+		{"mysql", "desc t", true},
+		{"mysql", "WITH cte AS (SELECT 1 AS id) SELECT * FROM cte", true},
+		{"mysql", "WITH cte AS (SELECT 1 AS id) UPDATE t JOIN cte ON t.id = cte.id SET t.v = 1", false},
+		{"mysql", "UPDATE t SET v = 'SELECT'", false},
+		{"mysql", "CALL p()", true},
+		{"mysql", "INSERT INTO t VALUES (1) RETURNING id", false},
+		{"mariadb", "INSERT INTO t VALUES (1) RETURNING id", true},
+		{"postgres", "VALUES (1), (2)", true},
+		{"postgres", "TABLE t", true},
+		{"postgres", "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r WHERE n < 3) SELECT * FROM r", true},
+		{"postgres", "WITH moved AS (DELETE FROM a RETURNING *) INSERT INTO b SELECT * FROM moved", false},
+		{"postgres", "WITH moved AS (DELETE FROM a RETURNING *) INSERT INTO b SELECT * FROM moved RETURNING id", true},
+		{"postgres", "INSERT INTO t(v) VALUES ('returning') RETURNING id", true},
+		{"postgres", "UPDATE t SET v = $$ RETURNING $$", false},
+		{"postgres", "DELETE FROM t", false},
+		{"sqlite", "PRAGMA table_info(t)", true},
+		{"sqlite", "DELETE FROM t RETURNING *", true},
+		{"sqlserver", ";WITH c AS (SELECT 1 AS id) SELECT * FROM c", true},
+		{"sqlserver", "INSERT INTO t (v) OUTPUT inserted.id VALUES (1)", true},
+		{"sqlserver", "INSERT INTO t (v) OUTPUT inserted.id INTO @ids VALUES (1)", false},
+		{"sqlserver", "EXEC sp_help", true},
+		{"oracle", "INSERT INTO t VALUES (1) RETURNING id INTO :id", false},
+		{"mongodb", `{"find":"users"}`, true},
+		{"mysql", "", false},
+		{"mysql", "-- 只有注释", false},
+	}
+
+	for _, tc := range cases {
+		if got := isReadStatement(tc.dbType, tc.query); got != tc.want {
+			t.Fatalf("isReadStatement(%q, %q)=%v，期望=%v", tc.dbType, tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestStatementMayWrite(t *testing.T) {
+	cases := []struct {
+		dbType string
+		query  string
+		want   bool
+	}{
+		{"mysql", "SELECT * FROM t", false},
+		{"mysql", "SELECT * FROM t FOR UPDATE", true},
+		{"mysql", "UPDATE t SET a = 1", true},
+		{"postgres", "WITH d AS (DELETE FROM t RETURNING id) SELECT * FROM d", true},
+		{"postgres", "INSERT INTO t(a) VALUES (1) RETURNING id", true},
+		{"mysql", "CALL proc()", true},
+		{"mysql", "SET autocommit = 0", true},
+		{"mysql", "-- only a comment", false},
+		// E'' 中的 \' 不结束字符串，之后的 DELETE 仍应识别
+		{"postgres", `WITH c AS (SELECT E'\'') DELETE FROM t`, true},
+		{"postgres", `SELECT e'it\'s', 'x' FROM t`, false},
+		{"kingbase", `WITH c AS (SELECT E'\'') DELETE FROM t`, true},
+	}
+	for _, tc := range cases {
+		if got := statementMayWrite(tc.dbType, tc.query); got != tc.want {
+			t.Fatalf("statementMayWrite(%q, %q)=%v，期望=%v", tc.dbType, tc.query, got, tc.want)
+		}
+	}
+}
@@ -3,11 +3,13 @@ package app
 import (
 	"strings"
 	"unicode"
+
+	"GoNavi-Wails/internal/db"
 )
 
 func sanitizeSQLForPgLike(dbType string, query string) string {
-	switch strings.ToLower(strings.TrimSpace(dbType)) {
-	case "postgres", "kingbase", "highgo", "vastbase":
+	switch db.Capabilities(dbType).Family {
+	case db.FamilyPostgres:
 		// 有些情况下会出现多层重复引用（例如 """"schema"""" 或 ""schema"""），单次修复不一定收敛。
 		// 这里做有限次数的迭代，直到输出不再变化。
 		out := query
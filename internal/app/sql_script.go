@@ -0,0 +1,367 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"GoNavi-Wails/internal/db"
+)
+
+var sqlServerGoLinePattern = regexp.MustCompile(`(?im)^[ \t]*go[ \t]*$`)
+
+// splitSQLScript 按方言把脚本拆分为可逐条执行的语句。
+//
+// 支持的语法：
+//   - 字符串、引号标识符（MySQL 反引号、SQL Server 方括号）与行/块注释内的分号不拆分
+//   - PG 系 $$ / $tag$ 块（与 sql_sanitize.go 共用 parseDollarTag）
+//   - BEGIN…END / CASE…END 复合语句内部的分号不拆分
+//   - MySQL/MariaDB 的 DELIMITER 指令
+//   - Oracle/达梦 PL/SQL 块，支持单独一行的 "/" 作为结束符
+//   - SQL Server 脚本中出现 GO 行时按批次拆分
+func splitSQLScript(dbType string, script string) []string {
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+	sp := &sqlScriptSplitter{
+		text:      strings.ReplaceAll(script, "\r\n", "\n"),
+		delimiter: ";",
+	}
+	switch db.Capabilities(dbType).Family {
+	case db.FamilyMySQL:
+		sp.mysqlLike = true
+	case db.FamilyPostgres:
+		sp.pgLike = true
+	case db.FamilyOracle, db.FamilyDameng:
+		sp.oracleLike = true
+	case db.FamilySQLServer:
+		sp.sqlServer = true
+		sp.goBatches = sqlServerGoLinePattern.MatchString(sp.text)
+	}
+	sp.split()
+	return sp.statements
+}
+
+type sqlScriptSplitter struct {
+	text       string
+	statements []string
+
+	mysqlLike  bool
+	pgLike     bool
+	oracleLike bool
+	sqlServer  bool
+	goBatches  bool
+
+	delimiter string
+
+	cur          strings.Builder
+	hasCode      bool // 当前语句是否包含注释以外的内容
+	depth        int  // BEGIN/CASE 嵌套层数
+	sawBlock     bool // 当前语句是否出现过 BEGIN 块
+	plsql        bool // 当前语句是 Oracle/达梦 PL/SQL 块
+	requireSlash bool // PL/SQL 包/类型定义只能由 "/" 行或脚本结尾结束
+	lastWord     string
+}
+
+func (sp *sqlScriptSplitter) push(includeTail string) {
+	sp.cur.WriteString(includeTail)
+	stmt := strings.TrimSpace(sp.cur.String())
+	if stmt != "" && sp.hasCode {
+		sp.statements = append(sp.statements, stmt)
+	}
+	sp.cur.Reset()
+	sp.hasCode = false
+	sp.depth = 0
+	sp.sawBlock = false
+	sp.plsql = false
+	sp.requireSlash = false
+	sp.lastWord = ""
+}
+
+func (sp *sqlScriptSplitter) split() {
+	text := sp.text
+	for i := 0; i < len(text); {
+		if i == 0 || text[i-1] == '\n' {
+			if next, ok := sp.handleLineDirective(i); ok {
+				i = next
+				continue
+			}
+		}
+
+		ch := text[i]
+		next := byte(0)
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+
+		switch {
+		case ch == '-' && next == '-', ch == '#' && sp.mysqlLike:
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			sp.cur.WriteString(text[i : i+end])
+			i += end
+			continue
+		case ch == '/' && next == '*':
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				end = len(text)
+			} else {
+				end = i + 2 + end + 2
+			}
+			sp.cur.WriteString(text[i:end])
+			i = end
+			continue
+		case ch == '\'':
+			i = sp.consumeQuoted(i, '\'', sp.mysqlLike || sp.isPgEscapeString(i))
+			continue
+		case ch == '"':
+			i = sp.consumeQuoted(i, '"', sp.mysqlLike)
+			continue
+		case ch == '`' && sp.mysqlLike:
+			i = sp.consumeQuoted(i, '`', false)
+			continue
+		case ch == '[' && sp.sqlServer:
+			i = sp.consumeQuoted(i, ']', false)
+			continue
+		case ch == '$' && sp.pgLike:
+			if tag := parseDollarTag(text[i:]); tag != "" {
+				end := strings.Index(text[i+len(tag):], tag)
+				if end < 0 {
+					end = len(text)
+				} else {
+					end = i + len(tag) + end + len(tag)
+				}
+				sp.hasCode = true
+				sp.cur.WriteString(text[i:end])
+				i = end
+				continue
+			}
+		}
+
+		if strings.HasPrefix(text[i:], sp.delimiter) {
+			if sp.delimiter != ";" {
+				sp.push("")
+				i += len(sp.delimiter)
+				continue
+			}
+			if sp.splitsAtSemicolon() {
+				tail := ""
+				if sp.plsql {
+					// PL/SQL 块的 END 后必须保留分号
+					tail = ";"
+				}
+				sp.push(tail)
+				i++
+				continue
+			}
+			sp.cur.WriteByte(ch)
+			i++
+			continue
+		}
+
+		if isSQLWordStart(ch) && (i == 0 || !isSQLWordChar(text[i-1])) {
+			end := i + 1
+			for end < len(text) && isSQLWordChar(text[end]) {
+				// 自定义分隔符（如 $$）可能紧贴在单词后面
+				if sp.delimiter != ";" && strings.HasPrefix(text[end:], sp.delimiter) {
+					break
+				}
+				end++
+			}
+			word := text[i:end]
+			sp.handleKeyword(strings.ToUpper(word), end)
+			sp.hasCode = true
+			sp.cur.WriteString(word)
+			i = end
+			continue
+		}
+
+		if ch != ' ' && ch != '\t' && ch != '\n' && ch != '\r' {
+			sp.hasCode = true
+		}
+		sp.cur.WriteByte(ch)
+		i++
+	}
+	sp.push("")
+}
+
+// handleLineDirective 处理以行为单位的客户端指令（DELIMITER、"/"、GO），返回下一行起始位置。
+func (sp *sqlScriptSplitter) handleLineDirective(start int) (int, bool) {
+	end := strings.IndexByte(sp.text[start:], '\n')
+	next := len(sp.text)
+	if end >= 0 {
+		end += start
+		next = end + 1
+	} else {
+		end = len(sp.text)
+	}
+	line := strings.TrimSpace(sp.text[start:end])
+
+	switch {
+	case sp.mysqlLike && len(line) > len("DELIMITER") && strings.EqualFold(line[:len("DELIMITER")], "DELIMITER") &&
+		(line[len("DELIMITER")] == ' ' || line[len("DELIMITER")] == '\t'):
+		delimiter := strings.TrimSpace(line[len("DELIMITER"):])
+		if delimiter == "" {
+			return 0, false
+		}
+		sp.push("")
+		sp.delimiter = delimiter
+		return next, true
+	case sp.oracleLike && line == "/":
+		sp.push("")
+		return next, true
+	case sp.goBatches && strings.EqualFold(line, "GO"):
+		sp.push("")
+		return next, true
+	}
+	return 0, false
+}
+
+func (sp *sqlScriptSplitter) splitsAtSemicolon() bool {
+	if sp.goBatches {
+		return false
+	}
+	if sp.depth > 0 {
+		return false
+	}
+	if sp.plsql {
+		return sp.sawBlock && !sp.requireSlash
+	}
+	return true
+}
+
+func (sp *sqlScriptSplitter) handleKeyword(word string, end int) {
+	defer func() { sp.lastWord = word }()
+	if sp.oracleLike && !sp.hasCode {
+		switch word {
+		case "DECLARE", "BEGIN":
+			sp.plsql = true
+		case "CREATE":
+			sp.detectPLSQLObject(end)
+		}
+	}
+
+	switch word {
+	case "BEGIN":
+		following := strings.ToUpper(nextSQLWord(sp.text, end))
+		switch following {
+		case "", "TRANSACTION", "TRAN", "WORK", "DISTRIBUTED", "ISOLATION", "DEFERRED", "IMMEDIATE", "EXCLUSIVE":
+			return
+		}
+		sp.depth++
+		sp.sawBlock = true
+	case "CASE":
+		// END CASE 的 CASE 不开启新的块，对应的层数已由 END 减去
+		if sp.lastWord != "END" {
+			sp.depth++
+		}
+	case "END":
+		switch strings.ToUpper(nextSQLWord(sp.text, end)) {
+		case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+			return
+		}
+		if sp.depth > 0 {
+			sp.depth--
+		}
+	}
+}
+
+// detectPLSQLObject 识别 CREATE [OR REPLACE] [EDITIONABLE] PROCEDURE/FUNCTION/TRIGGER/PACKAGE/TYPE。
+func (sp *sqlScriptSplitter) detectPLSQLObject(pos int) {
+	for n := 0; n < 4; n++ {
+		word, end := readSQLWord(sp.text, pos)
+		switch strings.ToUpper(word) {
+		case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+			pos = end
+			continue
+		case "PROCEDURE", "FUNCTION", "TRIGGER":
+			sp.plsql = true
+		case "PACKAGE", "TYPE":
+			sp.plsql = true
+			sp.requireSlash = true
+		}
+		return
+	}
+}
+
+// isPgEscapeString 判断 pos 处的单引号是否为 PG 的 E'...' 转义字符串。
+func (sp *sqlScriptSplitter) isPgEscapeString(pos int) bool {
+	if !sp.pgLike || pos == 0 {
+		return false
+	}
+	prefix := sp.text[pos-1]
+	if prefix != 'E' && prefix != 'e' {
+		return false
+	}
+	return pos == 1 || !isSQLWordChar(sp.text[pos-2])
+}
+
+// consumeQuoted 复制引号内的内容并返回结束位置；doubled 引号视为转义，backslash 为 true 时反斜杠也转义。
+func (sp *sqlScriptSplitter) consumeQuoted(start int, closing byte, backslash bool) int {
+	text := sp.text
+	i := start + 1
+	for i < len(text) {
+		c := text[i]
+		if backslash && c == '\\' && i+1 < len(text) {
+			i += 2
+			continue
+		}
+		if c == closing {
+			if i+1 < len(text) && text[i+1] == closing {
+				i += 2
+				continue
+			}
+			i++
+			break
+		}
+		i++
+	}
+	sp.hasCode = true
+	sp.cur.WriteString(text[start:i])
+	return i
+}
+
+func nextSQLWord(text string, pos int) string {
+	word, _ := readSQLWord(text, pos)
+	return word
+}
+
+// readSQLWord 跳过空白与注释后读取一个单词；紧跟分号、括号等符号时返回空串。
+func readSQLWord(text string, pos int) (string, int) {
+	for pos < len(text) {
+		c := text[pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			pos++
+			continue
+		}
+		if strings.HasPrefix(text[pos:], "--") {
+			end := strings.IndexByte(text[pos:], '\n')
+			if end < 0 {
+				return "", len(text)
+			}
+			pos += end + 1
+			continue
+		}
+		if strings.HasPrefix(text[pos:], "/*") {
+			end := strings.Index(text[pos+2:], "*/")
+			if end < 0 {
+				return "", len(text)
+			}
+			pos += 2 + end + 2
+			continue
+		}
+		break
+	}
+	start := pos
+	for pos < len(text) && isSQLWordChar(text[pos]) {
+		pos++
+	}
+	return text[start:pos], pos
+}
+
+func isSQLWordStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isSQLWordChar(c byte) bool {
+	return isSQLWordStart(c) || (c >= '0' && c <= '9') || c == '$'
+}
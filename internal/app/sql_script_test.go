@@ -0,0 +1,108 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSQLScript_QuotesAndComments(t *testing.T) {
+	script := "SELECT 'a;b' AS x; -- 注释;\nSELECT \"c;d\" /* ; */ FROM t;\n\n-- 仅注释\n"
+	got := splitSQLScript("postgres", script)
+	want := []string{
+		"SELECT 'a;b' AS x",
+		"-- 注释;\nSELECT \"c;d\" /* ; */ FROM t",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestSplitSQLScript_PostgresDollarQuote(t *testing.T) {
+	script := "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;\nBEGIN;\nSELECT f();\nCOMMIT;"
+	got := splitSQLScript("postgres", script)
+	if len(got) != 4 {
+		t.Fatalf("期望拆分为 4 条语句，实际=%d：%q", len(got), got)
+	}
+	if got[1] != "BEGIN" {
+		t.Fatalf("BEGIN; 应视为事务语句单独拆分，实际=%q", got[1])
+	}
+}
+
+func TestSplitSQLScript_MySQLDelimiter(t *testing.T) {
+	script := "DROP PROCEDURE IF EXISTS p;\nDELIMITER $$\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND$$\nDELIMITER ;\nCALL p();"
+	got := splitSQLScript("mysql", script)
+	want := []string{
+		"DROP PROCEDURE IF EXISTS p",
+		"CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND",
+		"CALL p()",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestSplitSQLScript_BeginEndWithoutDelimiter(t *testing.T) {
+	script := "CREATE TRIGGER trg AFTER INSERT ON t BEGIN UPDATE c SET n = CASE WHEN n > 0 THEN n + 1 ELSE 1 END; END;\nSELECT 1;"
+	got := splitSQLScript("sqlite", script)
+	if len(got) != 2 {
+		t.Fatalf("期望拆分为 2 条语句，实际=%d：%q", len(got), got)
+	}
+}
+
+func TestSplitSQLScript_OraclePLSQL(t *testing.T) {
+	script := "CREATE OR REPLACE PROCEDURE p IS\n  v NUMBER;\nBEGIN\n  IF v > 0 THEN\n    v := 1;\n  END IF;\nEND;\n/\nSELECT 1 FROM dual;\nDECLARE x NUMBER; BEGIN x := 1; END;"
+	got := splitSQLScript("oracle", script)
+	want := []string{
+		"CREATE OR REPLACE PROCEDURE p IS\n  v NUMBER;\nBEGIN\n  IF v > 0 THEN\n    v := 1;\n  END IF;\nEND;",
+		"SELECT 1 FROM dual",
+		"DECLARE x NUMBER; BEGIN x := 1; END;",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestSplitSQLScript_SQLServerGoBatches(t *testing.T) {
+	script := "CREATE TABLE [a;b] (id int);\nINSERT INTO [a;b] VALUES (1);\nGO\nSELECT * FROM [a;b];\ngo\n"
+	got := splitSQLScript("sqlserver", script)
+	if len(got) != 2 {
+		t.Fatalf("期望按 GO 拆分为 2 个批次，实际=%d：%q", len(got), got)
+	}
+}
+
+func TestSplitSQLScript_EndCaseWithoutDelimiter(t *testing.T) {
+	mysqlScript := "CREATE PROCEDURE p(v INT) BEGIN CASE v WHEN 1 THEN SELECT 1; ELSE SELECT 2; END CASE; END;\nSELECT 3;\nSELECT 4;"
+	got := splitSQLScript("mysql", mysqlScript)
+	want := []string{
+		"CREATE PROCEDURE p(v INT) BEGIN CASE v WHEN 1 THEN SELECT 1; ELSE SELECT 2; END CASE; END",
+		"SELECT 3",
+		"SELECT 4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MySQL END CASE 拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+
+	oracleScript := "BEGIN\n  CASE x WHEN 1 THEN NULL; ELSE NULL; END CASE;\nEND;\nSELECT 1 FROM dual;\nSELECT CASE WHEN 1 = 1 THEN 'a' END FROM dual;"
+	got = splitSQLScript("oracle", oracleScript)
+	want = []string{
+		"BEGIN\n  CASE x WHEN 1 THEN NULL; ELSE NULL; END CASE;\nEND;",
+		"SELECT 1 FROM dual",
+		"SELECT CASE WHEN 1 = 1 THEN 'a' END FROM dual",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Oracle END CASE 拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestSplitSQLScript_PostgresEscapeString(t *testing.T) {
+	script := "SELECT E'it\\'s; ok', e'\\\\';\nSELECT 'plain\\';\nSELECT 2;"
+	got := splitSQLScript("postgres", script)
+	want := []string{
+		"SELECT E'it\\'s; ok', e'\\\\'",
+		"SELECT 'plain\\'",
+		"SELECT 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("拆分结果不符合预期\nwant: %q\ngot:  %q", want, got)
+	}
+}
@@ -9,6 +9,15 @@ type SSHConfig struct {
 	KeyPath  string `json:"keyPath"`
 }
 
+// TLSConfig holds TLS connection details
+type TLSConfig struct {
+	CAPath     string `json:"caPath,omitempty"`     // CA certificate (PEM), empty uses the system pool
+	CertPath   string `json:"certPath,omitempty"`   // Client certificate (PEM) for mutual TLS
+	KeyPath    string `json:"keyPath,omitempty"`    // Client private key (PEM)
+	ServerName string `json:"serverName,omitempty"` // Server name to verify, defaults to the host
+	SkipVerify bool   `json:"skipVerify,omitempty"` // Skip server certificate verification
+}
+
 // ConnectionConfig holds database connection details including SSH
 type ConnectionConfig struct {
 	Type                 string    `json:"type"`
@@ -20,13 +29,18 @@ type ConnectionConfig struct {
 	Database             string    `json:"database"`
 	UseSSH               bool      `json:"useSSH"`
 	SSH                  SSHConfig `json:"ssh"`
+	UseTLS               bool      `json:"useTLS,omitempty"`
+	TLS                  TLSConfig `json:"tls,omitempty"`
 	Driver               string    `json:"driver,omitempty"`               // For custom connection
 	DSN                  string    `json:"dsn,omitempty"`                  // For custom connection
 	Timeout              int       `json:"timeout,omitempty"`              // Connection timeout in seconds (default: 30)
 	RedisDB              int       `json:"redisDB,omitempty"`              // Redis database index (0-15)
+	RedisUser            string    `json:"redisUser,omitempty"`            // Redis ACL username (Redis 6+)
+	SentinelMaster       string    `json:"sentinelMaster,omitempty"`       // Redis Sentinel master name
+	SentinelPassword     string    `json:"sentinelPassword,omitempty"`     // Redis Sentinel password
 	URI                  string    `json:"uri,omitempty"`                  // Connection URI for copy/paste
 	Hosts                []string  `json:"hosts,omitempty"`                // Multi-host addresses: host:port
-	Topology             string    `json:"topology,omitempty"`             // single | replica
+	Topology             string    `json:"topology,omitempty"`             // single | replica | cluster | sentinel
 	MySQLReplicaUser     string    `json:"mysqlReplicaUser,omitempty"`     // MySQL replica auth user
 	MySQLReplicaPassword string    `json:"mysqlReplicaPassword,omitempty"` // MySQL replica auth password
 	ReplicaSet           string    `json:"replicaSet,omitempty"`           // MongoDB replica set name
@@ -40,10 +54,39 @@ type ConnectionConfig struct {
 
 // QueryResult is the standard response format for Wails methods
 type QueryResult struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
-	Fields  []string    `json:"fields,omitempty"`
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    interface{}    `json:"data"`
+	Fields  []string       `json:"fields,omitempty"`
+	Columns []ResultColumn `json:"columns,omitempty"`
+	QueryID string         `json:"queryId,omitempty"`
+}
+
+// ResultColumn describes one column of a query result set
+type ResultColumn struct {
+	Name      string `json:"name"`
+	DBType    string `json:"dbType,omitempty"`   // 数据库类型名，如 VARCHAR、DECIMAL
+	ScanType  string `json:"scanType,omitempty"` // 驱动扫描时使用的 Go 类型
+	Length    *int64 `json:"length,omitempty"`
+	Precision *int64 `json:"precision,omitempty"`
+	Scale     *int64 `json:"scale,omitempty"`
+	Nullable  *bool  `json:"nullable,omitempty"`
+	BsonType  string `json:"bsonType,omitempty"` // MongoDB 字段的 BSON 类型，混合类型时为 mixed
+}
+
+// ScriptStatementResult is the per-statement entry returned by DBExecuteScript
+type ScriptStatementResult struct {
+	Index        int                      `json:"index"`
+	SQL          string                   `json:"sql"`
+	Success      bool                     `json:"success"`
+	Skipped      bool                     `json:"skipped,omitempty"`
+	Message      string                   `json:"message,omitempty"`
+	IsQuery      bool                     `json:"isQuery"`
+	Rows         []map[string]interface{} `json:"rows,omitempty"`
+	Fields       []string                 `json:"fields,omitempty"`
+	Columns      []ResultColumn           `json:"columns,omitempty"`
+	AffectedRows int64                    `json:"affectedRows"`
+	DurationMs   int64                    `json:"durationMs"`
 }
 
 // ColumnDefinition represents a table column
@@ -83,6 +126,12 @@ type TriggerDefinition struct {
 	Statement string `json:"statement"`
 }
 
+// TableOptionDefinition represents a table-level option (engine, collation, comment)
+type TableOptionDefinition struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 // ColumnDefinitionWithTable represents a column with its table name (for search/autocomplete)
 type ColumnDefinitionWithTable struct {
 	TableName string `json:"tableName"`
@@ -11,6 +11,16 @@ import (
 	"GoNavi-Wails/internal/utils"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "custom",
+		New:  func() Database { return &CustomDB{} },
+		Capabilities: DriverCapabilities{
+			SupportsTransactions: true,
+		},
+	})
+}
+
 type CustomDB struct {
 	conn        *sql.DB
 	driver      string
@@ -72,6 +82,28 @@ func (c *CustomDB) QueryContext(ctx context.Context, query string) ([]map[string
 	return scanRows(rows)
 }
 
+func (c *CustomDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if c.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (c *CustomDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := openPinnedSession(ctx, c.conn)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (c *CustomDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if c.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -248,11 +280,16 @@ func (c *CustomDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDe
 }
 
 func (c *CustomDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return c.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (c *CustomDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if c.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := c.conn.Begin()
+	tx, err := c.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -320,7 +357,7 @@ func (c *CustomDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -353,7 +390,7 @@ func (c *CustomDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -377,7 +414,7 @@ func (c *CustomDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
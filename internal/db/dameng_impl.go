@@ -18,6 +18,26 @@ import (
 	_ "gitee.com/chunanyong/dm"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "dameng",
+		Aliases: []string{"dm"},
+		New:     func() Database { return &DamengDB{} },
+		Capabilities: DriverCapabilities{
+			Family:               FamilyDameng,
+			DefaultPort:          5236,
+			SupportsTransactions: true,
+			SupportsForeignKeys:  true,
+			SupportsTriggers:     true,
+			SupportsRenameTable:  true,
+			SupportsDropTable:    true,
+			SupportsViews:        true,
+			SupportsRoutines:     true,
+			DatabaseInConfig:     true,
+		},
+	})
+}
+
 type DamengDB struct {
 	conn        *sql.DB
 	pingTimeout time.Duration
@@ -139,6 +159,28 @@ func (d *DamengDB) QueryContext(ctx context.Context, query string) ([]map[string
 	return scanRows(rows)
 }
 
+func (d *DamengDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if d.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := d.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (d *DamengDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := openPinnedSession(ctx, d.conn)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (d *DamengDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if d.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -248,38 +290,11 @@ func (d *DamengDB) GetCreateStatement(dbName, tableName string) (string, error)
 }
 
 func (d *DamengDB) GetColumns(dbName, tableName string) ([]connection.ColumnDefinition, error) {
-	query := fmt.Sprintf(`SELECT column_name, data_type, nullable, data_default 
-		FROM all_tab_columns 
-		WHERE owner = '%s' AND table_name = '%s'`,
-		strings.ToUpper(dbName), strings.ToUpper(tableName))
-
-	if dbName == "" {
-		query = fmt.Sprintf(`SELECT column_name, data_type, nullable, data_default 
-			FROM user_tab_columns 
-			WHERE table_name = '%s'`, strings.ToUpper(tableName))
-	}
-
-	data, _, err := d.Query(query)
+	data, _, err := d.Query(oracleColumnsQuery(dbName, tableName))
 	if err != nil {
 		return nil, err
 	}
-
-	var columns []connection.ColumnDefinition
-	for _, row := range data {
-		col := connection.ColumnDefinition{
-			Name:     fmt.Sprintf("%v", row["COLUMN_NAME"]),
-			Type:     fmt.Sprintf("%v", row["DATA_TYPE"]),
-			Nullable: fmt.Sprintf("%v", row["NULLABLE"]),
-		}
-
-		if row["DATA_DEFAULT"] != nil {
-			def := fmt.Sprintf("%v", row["DATA_DEFAULT"])
-			col.Default = &def
-		}
-
-		columns = append(columns, col)
-	}
-	return columns, nil
+	return oracleColumnsFromRows(data), nil
 }
 
 func (d *DamengDB) GetIndexes(dbName, tableName string) ([]connection.IndexDefinition, error) {
@@ -373,11 +388,16 @@ func (d *DamengDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDe
 }
 
 func (d *DamengDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return d.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (d *DamengDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if d.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := d.conn.Begin()
+	tx, err := d.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -421,7 +441,7 @@ func (d *DamengDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -454,7 +474,7 @@ func (d *DamengDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -478,7 +498,7 @@ func (d *DamengDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
@@ -2,7 +2,7 @@ package db
 
 import (
 	"GoNavi-Wails/internal/connection"
-	"fmt"
+	"context"
 )
 
 type Database interface {
@@ -25,42 +25,115 @@ type BatchApplier interface {
 	ApplyChanges(tableName string, changes connection.ChangeSet) error
 }
 
-// Factory
-func NewDatabase(dbType string) (Database, error) {
-	switch dbType {
-	case "mysql":
-		return &MySQLDB{}, nil
-	case "postgres":
-		return &PostgresDB{}, nil
-	case "sqlite":
-		return &SQLiteDB{}, nil
-	case "oracle":
-		return &OracleDB{}, nil
-	case "dameng":
-		return &DamengDB{}, nil
-	case "kingbase":
-		return &KingbaseDB{}, nil
-	case "mongodb":
-		return &MongoDB{}, nil
-	case "sqlserver":
-		return &SqlServerDB{}, nil
-	case "highgo":
-		return &HighGoDB{}, nil
-	case "mariadb":
-		return &MariaDB{}, nil
-	case "sphinx":
-		return &SphinxDB{}, nil
-	case "vastbase":
-		return &VastbaseDB{}, nil
-	case "tdengine":
-		return &TDengineDB{}, nil
-	case "custom":
-		return &CustomDB{}, nil
-	default:
-		// Default to MySQL for backward compatibility if empty
-		if dbType == "" {
-			return &MySQLDB{}, nil
+// BatchApplierContext 可选接口：在事务中应用一批变更，ctx 取消时中止并回滚该批。
+type BatchApplierContext interface {
+	ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error
+}
+
+// ApplyChanges 优先使用驱动的 ApplyChangesContext；驱动不支持时回退为 ApplyChanges，此时 ctx 仅在开始前检查。
+func ApplyChanges(ctx context.Context, applier BatchApplier, tableName string, changes connection.ChangeSet) error {
+	if ctxApplier, ok := applier.(BatchApplierContext); ok {
+		return ctxApplier.ApplyChangesContext(ctx, tableName, changes)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return applier.ApplyChanges(tableName, changes)
+}
+
+// TableOptionsProvider 由能读取表级选项（存储引擎、排序规则、表注释等）的驱动实现，供结构对比使用。
+type TableOptionsProvider interface {
+	GetTableOptions(dbName, tableName string) ([]connection.TableOptionDefinition, error)
+}
+
+// RowBatch is one chunk of a streamed result set.
+type RowBatch struct {
+	Columns []string
+	Meta    []connection.ResultColumn // 与 Columns 一一对应的列元数据
+	Rows    []map[string]interface{}
+}
+
+// RowBatchHandler consumes streamed batches; returning an error stops the stream.
+type RowBatchHandler func(batch RowBatch) error
+
+// StreamQuerier 可选接口：按批次流式读取结果集，避免大表一次性全部加载到内存。
+type StreamQuerier interface {
+	QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error
+}
+
+// StreamQuery 优先使用驱动的流式查询；驱动不支持时回退为 Query 后按批次切分。
+func StreamQuery(ctx context.Context, inst Database, query string, batchSize int, handle RowBatchHandler) error {
+	if streamer, ok := inst.(StreamQuerier); ok {
+		return streamer.QueryStream(ctx, query, batchSize, handle)
+	}
+
+	var (
+		data    []map[string]interface{}
+		columns []string
+		err     error
+	)
+	if q, ok := inst.(interface {
+		QueryContext(context.Context, string) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, columns, err = q.QueryContext(ctx, query)
+	} else {
+		data, columns, err = inst.Query(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+	meta := resultColumnsFromNames(columns)
+	if len(data) == 0 {
+		return handle(RowBatch{Columns: columns, Meta: meta, Rows: data})
+	}
+	for start := 0; start < len(data); start += batchSize {
+		end := start + batchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := handle(RowBatch{Columns: columns, Meta: meta, Rows: data[start:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetaQuerier 可选接口：驱动自行提供列元数据（如 MongoDB 的 BSON 类型）。
+type MetaQuerier interface {
+	QueryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error)
+}
+
+// QueryWithMeta 执行查询并返回列元数据。SQL 驱动通过流式读取拿到 ColumnType 信息，
+// 不支持的驱动只返回列名。
+func QueryWithMeta(ctx context.Context, inst Database, query string) ([]map[string]interface{}, []connection.ResultColumn, error) {
+	if q, ok := inst.(MetaQuerier); ok {
+		return q.QueryWithMeta(ctx, query)
+	}
+
+	data := make([]map[string]interface{}, 0)
+	var meta []connection.ResultColumn
+	err := StreamQuery(ctx, inst, query, 0, func(batch RowBatch) error {
+		if meta == nil {
+			meta = batch.Meta
 		}
-		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+		data = append(data, batch.Rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, meta, nil
+}
+
+// ResultColumnNames 返回元数据中的列名列表。
+func ResultColumnNames(meta []connection.ResultColumn) []string {
+	names := make([]string, len(meta))
+	for i, col := range meta {
+		names[i] = col.Name
 	}
+	return names
 }
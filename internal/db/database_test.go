@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type fakeQueryDB struct {
+	Database
+	rows    []map[string]interface{}
+	columns []string
+}
+
+func (f *fakeQueryDB) Query(query string) ([]map[string]interface{}, []string, error) {
+	return f.rows, f.columns, nil
+}
+
+func TestStreamQuery_FallbackSplitsBatches(t *testing.T) {
+	rows := make([]map[string]interface{}, 0, 5)
+	for i := 0; i < 5; i++ {
+		rows = append(rows, map[string]interface{}{"id": i})
+	}
+	inst := &fakeQueryDB{rows: rows, columns: []string{"id"}}
+
+	var sizes []int
+	err := StreamQuery(context.Background(), inst, "SELECT 1", 2, func(batch RowBatch) error {
+		if len(batch.Columns) != 1 || batch.Columns[0] != "id" {
+			t.Fatalf("列信息不正确：%v", batch.Columns)
+		}
+		if len(batch.Meta) != 1 || batch.Meta[0].Name != "id" {
+			t.Fatalf("列元数据不正确：%+v", batch.Meta)
+		}
+		sizes = append(sizes, len(batch.Rows))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamQuery 返回错误：%v", err)
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Fatalf("批次大小期望为 [2 2 1]，实际=%v", sizes)
+	}
+}
+
+func TestStreamQuery_EmptyResultStillReportsColumns(t *testing.T) {
+	inst := &fakeQueryDB{columns: []string{"id", "name"}}
+
+	calls := 0
+	err := StreamQuery(context.Background(), inst, "SELECT 1", 10, func(batch RowBatch) error {
+		calls++
+		if len(batch.Rows) != 0 || len(batch.Columns) != 2 {
+			t.Fatalf("空结果期望返回 0 行 2 列，实际 rows=%d columns=%v", len(batch.Rows), batch.Columns)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamQuery 返回错误：%v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("空结果期望回调 1 次，实际=%d", calls)
+	}
+}
+
+// countRowsDriver 返回 "SELECT <n>" 指定行数的单列结果集，用于测试 streamRows。
+type countRowsDriver struct{}
+
+type countRowsConn struct{}
+
+type countRowsStmt struct{ n int }
+
+type countRows struct{ n, next int }
+
+func (countRowsDriver) Open(name string) (driver.Conn, error) { return countRowsConn{}, nil }
+
+func (countRowsConn) Prepare(query string) (driver.Stmt, error) {
+	var n int
+	if _, err := fmt.Sscanf(query, "SELECT %d", &n); err != nil {
+		return nil, err
+	}
+	return &countRowsStmt{n: n}, nil
+}
+func (countRowsConn) Close() error              { return nil }
+func (countRowsConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+func (s *countRowsStmt) Close() error  { return nil }
+func (s *countRowsStmt) NumInput() int { return 0 }
+func (s *countRowsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+func (s *countRowsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &countRows{n: s.n}, nil
+}
+
+func (r *countRows) Columns() []string { return []string{"id"} }
+func (r *countRows) Close() error      { return nil }
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.next >= r.n {
+		return io.EOF
+	}
+	dest[0] = int64(r.next)
+	r.next++
+	return nil
+}
+
+func init() {
+	sql.Register("gonavi-count-rows", countRowsDriver{})
+}
+
+func TestScanRowsWithMeta_ExactBatchMultipleKeepsMeta(t *testing.T) {
+	conn, err := sql.Open("gonavi-count-rows", "")
+	if err != nil {
+		t.Fatalf("打开测试驱动失败：%v", err)
+	}
+	defer conn.Close()
+
+	for _, n := range []int{0, 999, defaultStreamBatchSize, 2 * defaultStreamBatchSize} {
+		rows, err := conn.Query(fmt.Sprintf("SELECT %d", n))
+		if err != nil {
+			t.Fatalf("查询失败：%v", err)
+		}
+		data, meta, err := scanRowsWithMeta(rows)
+		rows.Close()
+		if err != nil {
+			t.Fatalf("%d 行：scanRowsWithMeta 返回错误：%v", n, err)
+		}
+		if len(data) != n {
+			t.Fatalf("%d 行：读取行数不正确，实际=%d", n, len(data))
+		}
+		if len(meta) != 1 || meta[0].Name != "id" {
+			t.Fatalf("%d 行：列元数据丢失：%+v", n, meta)
+		}
+	}
+}
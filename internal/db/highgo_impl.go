@@ -18,6 +18,31 @@ import (
 	_ "github.com/highgo/pq-sm3" // HighGo uses dedicated SM3-capable driver
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "highgo",
+		New:  func() Database { return &HighGoDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyPostgres,
+			DefaultPort:            5866,
+			SupportsSchemas:        true,
+			DefaultSchema:          "public",
+			SupportsTransactions:   true,
+			SupportsForeignKeys:    true,
+			SupportsTriggers:       true,
+			SupportsRenameDatabase: true,
+			SupportsServerCancel:   true,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsRenameTable:    true,
+			SupportsDropTable:      true,
+			SupportsViews:          true,
+			SupportsRoutines:       true,
+			DatabaseInConfig:       true,
+		},
+	})
+}
+
 // HighGoDB implements Database interface for HighGo (瀚高) database
 // HighGo is a PostgreSQL-compatible database, so we reuse PostgreSQL driver
 type HighGoDB struct {
@@ -134,6 +159,32 @@ func (h *HighGoDB) QueryContext(ctx context.Context, query string) ([]map[string
 	return scanRows(rows)
 }
 
+func (h *HighGoDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if h.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := h.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (h *HighGoDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, h.conn, "SELECT pg_backend_pid()", "SELECT pg_cancel_backend(%s)")
+}
+
+func (h *HighGoDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := h.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (h *HighGoDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if h.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -514,11 +565,16 @@ ORDER BY table_schema, table_name, ordinal_position`
 }
 
 func (h *HighGoDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return h.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (h *HighGoDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if h.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := h.conn.Begin()
+	tx, err := h.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -562,7 +618,7 @@ func (h *HighGoDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -595,7 +651,7 @@ func (h *HighGoDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -619,7 +675,7 @@ func (h *HighGoDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
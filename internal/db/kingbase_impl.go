@@ -17,6 +17,31 @@ import (
 	_ "gitea.com/kingbase/gokb" // Registers "kingbase" driver
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "kingbase",
+		New:  func() Database { return &KingbaseDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyPostgres,
+			DefaultPort:            54321,
+			SupportsSchemas:        true,
+			DefaultSchema:          "public",
+			SupportsTransactions:   true,
+			SupportsForeignKeys:    true,
+			SupportsTriggers:       true,
+			SupportsRenameDatabase: true,
+			SupportsServerCancel:   true,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsRenameTable:    true,
+			SupportsDropTable:      true,
+			SupportsViews:          true,
+			SupportsRoutines:       true,
+			DatabaseInConfig:       true,
+		},
+	})
+}
+
 type KingbaseDB struct {
 	conn        *sql.DB
 	pingTimeout time.Duration
@@ -168,6 +193,32 @@ func (k *KingbaseDB) QueryContext(ctx context.Context, query string) ([]map[stri
 	return scanRows(rows)
 }
 
+func (k *KingbaseDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if k.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := k.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (k *KingbaseDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, k.conn, "SELECT pg_backend_pid()", "SELECT pg_cancel_backend(%s)")
+}
+
+func (k *KingbaseDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := k.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (k *KingbaseDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if k.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -597,11 +648,16 @@ func (k *KingbaseDB) GetTriggers(dbName, tableName string) ([]connection.Trigger
 }
 
 func (k *KingbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return k.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (k *KingbaseDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if k.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := k.conn.Begin()
+	tx, err := k.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -645,7 +701,7 @@ func (k *KingbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -678,7 +734,7 @@ func (k *KingbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -702,7 +758,7 @@ func (k *KingbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
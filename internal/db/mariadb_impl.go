@@ -15,6 +15,30 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "mariadb",
+		New:  func() Database { return &MariaDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyMySQL,
+			DefaultPort:            defaultMySQLPort,
+			SupportsTransactions:   true,
+			SupportsForeignKeys:    true,
+			SupportsTriggers:       true,
+			SupportsServerCancel:   true,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsTruncate:       true,
+			SupportsRenameTable:    true,
+			SupportsDropTable:      true,
+			SupportsViews:          true,
+			SupportsRoutines:       true,
+			DatabaseInConfig:       true,
+			IdentQuote:             QuoteBacktick,
+		},
+	})
+}
+
 // MariaDB implements Database interface for MariaDB
 // MariaDB is MySQL-compatible, so we reuse the MySQL driver
 type MariaDB struct {
@@ -92,6 +116,32 @@ func (m *MariaDB) QueryContext(ctx context.Context, query string) ([]map[string]
 	return scanRows(rows)
 }
 
+func (m *MariaDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if m.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := m.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (m *MariaDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, m.conn, "SELECT CONNECTION_ID()", "KILL QUERY %s")
+}
+
+func (m *MariaDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := m.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (m *MariaDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if m.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -302,12 +352,46 @@ func (m *MariaDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDef
 	return triggers, nil
 }
 
+// GetTableOptions 读取存储引擎、排序规则与表注释。
+func (m *MariaDB) GetTableOptions(dbName, tableName string) ([]connection.TableOptionDefinition, error) {
+	esc := func(s string) string { return strings.ReplaceAll(s, "'", "''") }
+	schemaExpr := "DATABASE()"
+	if dbName != "" {
+		schemaExpr = fmt.Sprintf("'%s'", esc(dbName))
+	}
+	query := fmt.Sprintf(`SELECT ENGINE, TABLE_COLLATION, TABLE_COMMENT
+              FROM information_schema.TABLES
+              WHERE TABLE_SCHEMA = %s AND TABLE_NAME = '%s'`, schemaExpr, esc(tableName))
+
+	data, _, err := m.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("table not found: %s", tableName)
+	}
+
+	row := data[0]
+	var options []connection.TableOptionDefinition
+	for _, item := range []struct{ name, column string }{{"ENGINE", "ENGINE"}, {"COLLATE", "TABLE_COLLATION"}, {"COMMENT", "TABLE_COMMENT"}} {
+		if v, ok := row[item.column]; ok && v != nil {
+			options = append(options, connection.TableOptionDefinition{Name: item.name, Value: fmt.Sprintf("%v", v)})
+		}
+	}
+	return options, nil
+}
+
 func (m *MariaDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return m.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (m *MariaDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if m.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := m.conn.Begin()
+	tx, err := m.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -325,7 +409,7 @@ func (m *MariaDB) ApplyChanges(tableName string, changes connection.ChangeSet) e
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM `%s` WHERE %s", tableName, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -355,7 +439,7 @@ func (m *MariaDB) ApplyChanges(tableName string, changes connection.ChangeSet) e
 		}
 
 		query := fmt.Sprintf("UPDATE `%s` SET %s WHERE %s", tableName, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -377,7 +461,7 @@ func (m *MariaDB) ApplyChanges(tableName string, changes connection.ChangeSet) e
 		}
 
 		query := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)", tableName, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
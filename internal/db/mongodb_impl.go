@@ -20,6 +20,18 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "mongodb",
+		New:  func() Database { return &MongoDB{} },
+		Capabilities: DriverCapabilities{
+			Family:           FamilyMongoDB,
+			DefaultPort:      defaultMongoPort,
+			DatabaseInConfig: true,
+		},
+	})
+}
+
 type MongoDB struct {
 	client      *mongo.Client
 	database    string
@@ -630,6 +642,11 @@ func (m *MongoDB) QueryContext(ctx context.Context, query string) ([]map[string]
 	return m.queryWithContext(ctx, query)
 }
 
+// QueryWithMeta 与 QueryContext 相同，额外返回每个字段的 BSON 类型。
+func (m *MongoDB) QueryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error) {
+	return m.queryWithMeta(ctx, query)
+}
+
 // sqlToMongoFind 将前端生成的简单 SQL 转换为 MongoDB find 命令 JSON。
 // 支持：SELECT * FROM "coll" LIMIT n OFFSET m / SELECT COUNT(*) as total FROM "coll"
 func sqlToMongoFind(sql string) (string, bool) {
@@ -723,6 +740,14 @@ func extractCollectionFromSQL(sql string) string {
 }
 
 func (m *MongoDB) queryWithContext(ctx context.Context, query string) ([]map[string]interface{}, []string, error) {
+	data, meta, err := m.queryWithMeta(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, ResultColumnNames(meta), nil
+}
+
+func (m *MongoDB) queryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error) {
 	if m.client == nil {
 		return nil, nil, fmt.Errorf("connection not open")
 	}
@@ -766,41 +791,42 @@ func (m *MongoDB) queryWithContext(ctx context.Context, query string) ([]map[str
 	// Handle COUNT result (e.g. delete/update returns "n")
 	if n, ok := result["n"]; ok {
 		if _, hasCursor := result["cursor"]; !hasCursor {
-			return []map[string]interface{}{{"total": n}}, []string{"total"}, nil
+			return []map[string]interface{}{{"total": n}}, []connection.ResultColumn{mongoResultColumn("total", bsonTypeName(n))}, nil
 		}
 	}
 
 	// Convert result to standard format
 	data := []map[string]interface{}{{"result": result}}
-	columns := []string{"result"}
+	meta := []connection.ResultColumn{mongoResultColumn("result", "object")}
 
 	// If result contains cursor with documents, extract them
 	if cursor, ok := result["cursor"].(bson.M); ok {
 		if batch, ok := cursor["firstBatch"].(bson.A); ok {
 			data = make([]map[string]interface{}, 0, len(batch))
-			columnSet := make(map[string]bool)
+			fieldTypes := make(map[string]string)
 			for _, doc := range batch {
 				if docMap, ok := doc.(bson.M); ok {
 					row := make(map[string]interface{})
 					for k, v := range docMap {
 						row[k] = v
-						columnSet[k] = true
+						mergeBsonFieldType(fieldTypes, k, v)
 					}
 					data = append(data, row)
 				}
 			}
-			columns = make([]string, 0, len(columnSet))
-			for k := range columnSet {
+			columns := make([]string, 0, len(fieldTypes))
+			for k := range fieldTypes {
 				columns = append(columns, k)
 			}
+			meta = buildMongoResultColumns(columns, fieldTypes)
 		}
 	}
 
-	return data, columns, nil
+	return data, meta, nil
 }
 
 // execFind 使用原生 Collection.Find() 执行查询，正确处理游标迭代
-func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interface{}, []string, error) {
+func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interface{}, []connection.ResultColumn, error) {
 	var collName string
 	var filter interface{}
 	var limit int64
@@ -854,7 +880,7 @@ func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interf
 	defer cursor.Close(ctx)
 
 	var data []map[string]interface{}
-	columnSet := make(map[string]bool)
+	fieldTypes := make(map[string]string)
 
 	for cursor.Next(ctx) {
 		var doc bson.M
@@ -864,7 +890,7 @@ func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interf
 		row := make(map[string]interface{})
 		for k, v := range doc {
 			row[k] = convertBsonValue(v)
-			columnSet[k] = true
+			mergeBsonFieldType(fieldTypes, k, v)
 		}
 		data = append(data, row)
 	}
@@ -873,8 +899,8 @@ func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interf
 		return nil, nil, err
 	}
 
-	columns := make([]string, 0, len(columnSet))
-	for k := range columnSet {
+	columns := make([]string, 0, len(fieldTypes))
+	for k := range fieldTypes {
 		columns = append(columns, k)
 	}
 	sort.Strings(columns)
@@ -888,11 +914,11 @@ func (m *MongoDB) execFind(ctx context.Context, cmd bson.D) ([]map[string]interf
 		}
 	}
 
-	return data, columns, nil
+	return data, buildMongoResultColumns(columns, fieldTypes), nil
 }
 
 // execCount 使用原生 Collection.CountDocuments() 执行计数
-func (m *MongoDB) execCount(ctx context.Context, cmd bson.D) ([]map[string]interface{}, []string, error) {
+func (m *MongoDB) execCount(ctx context.Context, cmd bson.D) ([]map[string]interface{}, []connection.ResultColumn, error) {
 	var collName string
 	var filter interface{}
 
@@ -918,7 +944,80 @@ func (m *MongoDB) execCount(ctx context.Context, cmd bson.D) ([]map[string]inter
 		return nil, nil, err
 	}
 
-	return []map[string]interface{}{{"total": n}}, []string{"total"}, nil
+	return []map[string]interface{}{{"total": n}}, []connection.ResultColumn{mongoResultColumn("total", "long")}, nil
+}
+
+func mongoResultColumn(name string, bsonType string) connection.ResultColumn {
+	return connection.ResultColumn{Name: name, DBType: bsonType, BsonType: bsonType}
+}
+
+func buildMongoResultColumns(columns []string, fieldTypes map[string]string) []connection.ResultColumn {
+	meta := make([]connection.ResultColumn, len(columns))
+	for i, col := range columns {
+		meta[i] = mongoResultColumn(col, fieldTypes[col])
+	}
+	return meta
+}
+
+// mergeBsonFieldType 合并同一字段在多个文档中的类型；null 不影响已知类型，不同类型记为 mixed。
+func mergeBsonFieldType(fieldTypes map[string]string, field string, v interface{}) {
+	typeName := bsonTypeName(v)
+	prev, ok := fieldTypes[field]
+	switch {
+	case !ok || prev == "null":
+		fieldTypes[field] = typeName
+	case typeName == "null" || prev == typeName:
+	default:
+		fieldTypes[field] = "mixed"
+	}
+}
+
+// bsonTypeName 返回与 MongoDB $type 一致的 BSON 类型别名。
+func bsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil, bson.Null:
+		return "null"
+	case bson.ObjectID:
+		return "objectId"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int32:
+		return "int"
+	case int64, int:
+		return "long"
+	case float64:
+		return "double"
+	case bson.Decimal128:
+		return "decimal"
+	case bson.DateTime, time.Time:
+		return "date"
+	case bson.Timestamp:
+		return "timestamp"
+	case bson.Binary:
+		return "binData"
+	case bson.Regex:
+		return "regex"
+	case bson.A, []interface{}:
+		return "array"
+	case bson.M, bson.D, map[string]interface{}:
+		return "object"
+	case bson.JavaScript, bson.CodeWithScope:
+		return "javascript"
+	case bson.Symbol:
+		return "symbol"
+	case bson.MinKey:
+		return "minKey"
+	case bson.MaxKey:
+		return "maxKey"
+	case bson.Undefined:
+		return "undefined"
+	case bson.DBPointer:
+		return "dbPointer"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
 }
 
 // convertBsonValue 将 BSON 特殊类型转换为前端可读的 JSON 友好值
@@ -1085,11 +1184,16 @@ func (m *MongoDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDef
 
 // ApplyChanges implements batch changes for MongoDB
 func (m *MongoDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return m.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 应用一批变更，ctx 取消时中止尚未执行的操作
+func (m *MongoDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if m.client == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	collection := m.client.Database(m.database).Collection(tableName)
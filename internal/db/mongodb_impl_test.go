@@ -0,0 +1,24 @@
+package db
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMergeBsonFieldType(t *testing.T) {
+	fieldTypes := make(map[string]string)
+	mergeBsonFieldType(fieldTypes, "_id", bson.NewObjectID())
+	mergeBsonFieldType(fieldTypes, "age", nil)
+	mergeBsonFieldType(fieldTypes, "age", int32(18))
+	mergeBsonFieldType(fieldTypes, "age", nil)
+	mergeBsonFieldType(fieldTypes, "tag", "a")
+	mergeBsonFieldType(fieldTypes, "tag", bson.A{"b"})
+
+	want := map[string]string{"_id": "objectId", "age": "int", "tag": "mixed"}
+	for field, typeName := range want {
+		if fieldTypes[field] != typeName {
+			t.Fatalf("字段 %s 的 BSON 类型期望为 %s，实际=%s", field, typeName, fieldTypes[field])
+		}
+	}
+}
@@ -18,6 +18,26 @@ import (
 	_ "github.com/sijms/go-ora/v2"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "oracle",
+		Aliases: []string{"godror"},
+		New:     func() Database { return &OracleDB{} },
+		Capabilities: DriverCapabilities{
+			Family:               FamilyOracle,
+			DefaultPort:          1521,
+			SupportsTransactions: true,
+			SupportsForeignKeys:  true,
+			SupportsTriggers:     true,
+			SupportsRenameTable:  true,
+			SupportsDropTable:    true,
+			SupportsViews:        true,
+			SupportsRoutines:     true,
+			Pagination:           PaginationRowNum,
+		},
+	})
+}
+
 type OracleDB struct {
 	conn        *sql.DB
 	pingTimeout time.Duration
@@ -133,6 +153,28 @@ func (o *OracleDB) QueryContext(ctx context.Context, query string) ([]map[string
 	return scanRows(rows)
 }
 
+func (o *OracleDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if o.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := o.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (o *OracleDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := openPinnedSession(ctx, o.conn)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (o *OracleDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if o.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -236,39 +278,112 @@ func (o *OracleDB) GetCreateStatement(dbName, tableName string) (string, error)
 }
 
 func (o *OracleDB) GetColumns(dbName, tableName string) ([]connection.ColumnDefinition, error) {
-	query := fmt.Sprintf(`SELECT column_name, data_type, nullable, data_default 
-		FROM all_tab_columns 
-		WHERE owner = '%s' AND table_name = '%s' 
-		ORDER BY column_id`, strings.ToUpper(dbName), strings.ToUpper(tableName))
-
-	if dbName == "" {
-		query = fmt.Sprintf(`SELECT column_name, data_type, nullable, data_default 
-			FROM user_tab_columns 
-			WHERE table_name = '%s' 
-			ORDER BY column_id`, strings.ToUpper(tableName))
-	}
-
-	data, _, err := o.Query(query)
+	data, _, err := o.Query(oracleColumnsQuery(dbName, tableName))
 	if err != nil {
 		return nil, err
 	}
+	return oracleColumnsFromRows(data), nil
+}
+
+// oracleColumnsQuery 查询列定义及长度/精度、主键与注释；dbName 为空时查询当前用户的表。
+// 达梦兼容 Oracle 的数据字典视图，共用此查询。
+func oracleColumnsQuery(dbName, tableName string) string {
+	owner := "USER"
+	if strings.TrimSpace(dbName) != "" {
+		owner = fmt.Sprintf("'%s'", strings.ReplaceAll(strings.ToUpper(dbName), "'", "''"))
+	}
+	table := strings.ReplaceAll(strings.ToUpper(tableName), "'", "''")
+	return fmt.Sprintf(`SELECT c.column_name, c.data_type, c.data_length, c.data_precision, c.data_scale, c.char_length,
+		c.nullable, c.data_default, cc.comments,
+		CASE WHEN pk.column_name IS NOT NULL THEN 'PRI' ELSE '' END AS column_key
+		FROM all_tab_columns c
+		LEFT JOIN all_col_comments cc ON cc.owner = c.owner AND cc.table_name = c.table_name AND cc.column_name = c.column_name
+		LEFT JOIN (
+			SELECT acc.owner, acc.table_name, acc.column_name
+			FROM all_constraints ac
+			JOIN all_cons_columns acc ON acc.owner = ac.owner AND acc.constraint_name = ac.constraint_name
+			WHERE ac.constraint_type = 'P'
+		) pk ON pk.owner = c.owner AND pk.table_name = c.table_name AND pk.column_name = c.column_name
+		WHERE c.owner = %s AND c.table_name = '%s'
+		ORDER BY c.column_id`, owner, table)
+}
 
+func oracleColumnsFromRows(data []map[string]interface{}) []connection.ColumnDefinition {
 	var columns []connection.ColumnDefinition
 	for _, row := range data {
+		nullable := "YES"
+		if strings.EqualFold(strings.TrimSpace(fmt.Sprintf("%v", row["NULLABLE"])), "N") {
+			nullable = "NO"
+		}
 		col := connection.ColumnDefinition{
 			Name:     fmt.Sprintf("%v", row["COLUMN_NAME"]),
-			Type:     fmt.Sprintf("%v", row["DATA_TYPE"]),
-			Nullable: fmt.Sprintf("%v", row["NULLABLE"]),
+			Type:     oracleColumnType(row),
+			Nullable: nullable,
+		}
+		if v, ok := row["COLUMN_KEY"]; ok && v != nil {
+			col.Key = strings.TrimSpace(fmt.Sprintf("%v", v))
+		}
+		if v, ok := row["COMMENTS"]; ok && v != nil {
+			col.Comment = fmt.Sprintf("%v", v)
 		}
 
 		if row["DATA_DEFAULT"] != nil {
-			d := fmt.Sprintf("%v", row["DATA_DEFAULT"])
+			d := strings.TrimSpace(fmt.Sprintf("%v", row["DATA_DEFAULT"]))
 			col.Default = &d
 		}
 
 		columns = append(columns, col)
 	}
-	return columns, nil
+	return columns
+}
+
+// oracleColumnType 按数据字典中的长度/精度还原完整列类型，如 VARCHAR2(50)、NUMBER(10,2)。
+func oracleColumnType(row map[string]interface{}) string {
+	dataType := strings.TrimSpace(fmt.Sprintf("%v", row["DATA_TYPE"]))
+	intValue := func(key string) (int, bool) {
+		v, ok := row[key]
+		if !ok || v == nil {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%v", v)), 64)
+		if err != nil {
+			return 0, false
+		}
+		return int(n), true
+	}
+
+	switch strings.ToUpper(dataType) {
+	case "VARCHAR2", "NVARCHAR2", "VARCHAR", "CHAR", "NCHAR", "CHARACTER":
+		if n, ok := intValue("CHAR_LENGTH"); ok && n > 0 {
+			return fmt.Sprintf("%s(%d)", dataType, n)
+		}
+		if n, ok := intValue("DATA_LENGTH"); ok && n > 0 {
+			return fmt.Sprintf("%s(%d)", dataType, n)
+		}
+	case "RAW", "BINARY", "VARBINARY":
+		if n, ok := intValue("DATA_LENGTH"); ok && n > 0 {
+			return fmt.Sprintf("%s(%d)", dataType, n)
+		}
+	case "NUMBER", "DECIMAL", "NUMERIC", "DEC":
+		precision, hasPrecision := intValue("DATA_PRECISION")
+		scale, hasScale := intValue("DATA_SCALE")
+		if !hasPrecision || precision <= 0 {
+			if hasScale && scale == 0 {
+				// INTEGER 列在字典中表现为精度为空、小数位为 0
+				return dataType + "(38)"
+			}
+			return dataType
+		}
+		if !hasScale || scale == 0 {
+			return fmt.Sprintf("%s(%d)", dataType, precision)
+		}
+		return fmt.Sprintf("%s(%d,%d)", dataType, precision, scale)
+	case "FLOAT":
+		if p, ok := intValue("DATA_PRECISION"); ok && p > 0 {
+			return fmt.Sprintf("%s(%d)", dataType, p)
+		}
+	}
+	return dataType
 }
 
 func (o *OracleDB) GetIndexes(dbName, tableName string) ([]connection.IndexDefinition, error) {
@@ -363,11 +478,16 @@ func (o *OracleDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDe
 }
 
 func (o *OracleDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return o.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (o *OracleDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if o.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := o.conn.Begin()
+	tx, err := o.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -411,7 +531,7 @@ func (o *OracleDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -444,7 +564,7 @@ func (o *OracleDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -468,7 +588,7 @@ func (o *OracleDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
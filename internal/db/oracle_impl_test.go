@@ -0,0 +1,40 @@
+package db
+
+import "testing"
+
+func TestOracleColumnType(t *testing.T) {
+	cases := []struct {
+		row  map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"DATA_TYPE": "VARCHAR2", "DATA_LENGTH": "400", "CHAR_LENGTH": "100"}, "VARCHAR2(100)"},
+		{map[string]interface{}{"DATA_TYPE": "RAW", "DATA_LENGTH": int64(16)}, "RAW(16)"},
+		{map[string]interface{}{"DATA_TYPE": "NUMBER", "DATA_PRECISION": "10", "DATA_SCALE": "2"}, "NUMBER(10,2)"},
+		{map[string]interface{}{"DATA_TYPE": "NUMBER", "DATA_PRECISION": int64(10), "DATA_SCALE": int64(0)}, "NUMBER(10)"},
+		{map[string]interface{}{"DATA_TYPE": "NUMBER", "DATA_PRECISION": nil, "DATA_SCALE": "0"}, "NUMBER(38)"},
+		{map[string]interface{}{"DATA_TYPE": "NUMBER", "DATA_PRECISION": nil, "DATA_SCALE": nil}, "NUMBER"},
+		{map[string]interface{}{"DATA_TYPE": "TIMESTAMP(6)", "DATA_LENGTH": "11"}, "TIMESTAMP(6)"},
+		{map[string]interface{}{"DATA_TYPE": "CLOB", "DATA_LENGTH": "4000"}, "CLOB"},
+	}
+	for _, c := range cases {
+		if got := oracleColumnType(c.row); got != c.want {
+			t.Fatalf("oracleColumnType(%v) 期望 %s，实际 %s", c.row, c.want, got)
+		}
+	}
+}
+
+func TestOracleColumnsFromRows_NormalizesNullableAndKey(t *testing.T) {
+	cols := oracleColumnsFromRows([]map[string]interface{}{
+		{"COLUMN_NAME": "ID", "DATA_TYPE": "NUMBER", "DATA_PRECISION": "10", "NULLABLE": "N", "COLUMN_KEY": "PRI", "COMMENTS": "主键"},
+		{"COLUMN_NAME": "NAME", "DATA_TYPE": "VARCHAR2", "CHAR_LENGTH": "20", "NULLABLE": "Y", "COLUMN_KEY": "", "DATA_DEFAULT": "'x' "},
+	})
+	if len(cols) != 2 {
+		t.Fatalf("期望 2 个字段，实际 %d", len(cols))
+	}
+	if cols[0].Nullable != "NO" || cols[0].Key != "PRI" || cols[0].Comment != "主键" {
+		t.Fatalf("主键字段解析不正确：%+v", cols[0])
+	}
+	if cols[1].Nullable != "YES" || cols[1].Key != "" || cols[1].Default == nil || *cols[1].Default != "'x'" {
+		t.Fatalf("普通字段解析不正确：%+v", cols[1])
+	}
+}
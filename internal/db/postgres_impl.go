@@ -18,6 +18,32 @@ import (
 	_ "github.com/lib/pq"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "postgres",
+		Aliases: []string{"postgresql", "pgx"},
+		New:     func() Database { return &PostgresDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyPostgres,
+			DefaultPort:            5432,
+			SupportsSchemas:        true,
+			DefaultSchema:          "public",
+			SupportsTransactions:   true,
+			SupportsForeignKeys:    true,
+			SupportsTriggers:       true,
+			SupportsRenameDatabase: true,
+			SupportsServerCancel:   true,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsRenameTable:    true,
+			SupportsDropTable:      true,
+			SupportsViews:          true,
+			SupportsRoutines:       true,
+			DatabaseInConfig:       true,
+		},
+	})
+}
+
 
 type PostgresDB struct {
 	conn        *sql.DB
@@ -142,6 +168,32 @@ func (p *PostgresDB) QueryContext(ctx context.Context, query string) ([]map[stri
 	return scanRows(rows)
 }
 
+func (p *PostgresDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if p.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := p.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (p *PostgresDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, p.conn, "SELECT pg_backend_pid()", "SELECT pg_cancel_backend(%s)")
+}
+
+func (p *PostgresDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := p.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (p *PostgresDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if p.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -490,6 +542,42 @@ ORDER BY trigger_name, event_manipulation`, esc(table), esc(schema))
 	return triggers, nil
 }
 
+// GetTableOptions 读取表注释。
+func (p *PostgresDB) GetTableOptions(dbName, tableName string) ([]connection.TableOptionDefinition, error) {
+	schema := strings.TrimSpace(dbName)
+	if schema == "" {
+		schema = "public"
+	}
+	table := strings.TrimSpace(tableName)
+	if table == "" {
+		return nil, fmt.Errorf("table name required")
+	}
+
+	esc := func(s string) string { return strings.ReplaceAll(s, "'", "''") }
+
+	query := fmt.Sprintf(`
+SELECT obj_description(c.oid, 'pg_class') AS table_comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p')
+  AND n.nspname = '%s'
+  AND c.relname = '%s'`, esc(schema), esc(table))
+
+	data, _, err := p.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("table not found: %s", tableName)
+	}
+
+	comment := ""
+	if v, ok := data[0]["table_comment"]; ok && v != nil {
+		comment = fmt.Sprintf("%v", v)
+	}
+	return []connection.TableOptionDefinition{{Name: "COMMENT", Value: comment}}, nil
+}
+
 func (p *PostgresDB) GetAllColumns(dbName string) ([]connection.ColumnDefinitionWithTable, error) {
 	query := `
 SELECT table_schema, table_name, column_name, data_type
@@ -523,11 +611,16 @@ ORDER BY table_schema, table_name, ordinal_position`
 }
 
 func (p *PostgresDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return p.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (p *PostgresDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if p.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := p.conn.Begin()
+	tx, err := p.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -571,7 +664,7 @@ func (p *PostgresDB) ApplyChanges(tableName string, changes connection.ChangeSet
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -604,7 +697,7 @@ func (p *PostgresDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -628,7 +721,7 @@ func (p *PostgresDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
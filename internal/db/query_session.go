@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+// ErrQuerySessionUnsupported 表示驱动无法定位服务端会话，只能依赖上下文取消。
+var ErrQuerySessionUnsupported = errors.New("当前数据源不支持服务端中止查询")
+
+// QuerySessionOpener 由支持服务端中止查询的驱动实现。
+type QuerySessionOpener interface {
+	OpenQuerySession(ctx context.Context) (*QuerySession, error)
+}
+
+// QuerySession 将一次查询固定在单个物理连接上，并记录该连接的服务端会话 ID，
+// 以便查询执行期间从连接池的其他连接发起服务端中止。
+type QuerySession struct {
+	pool      *sql.DB
+	conn      *sql.Conn
+	sessionID string
+	killSQL   string
+}
+
+// openQuerySession 从连接池取出一个连接并查询其会话 ID；killFormat 中的 %s 会被替换为会话 ID。
+func openQuerySession(ctx context.Context, pool *sql.DB, sessionIDQuery string, killFormat string) (*QuerySession, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("connection not open")
+	}
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := conn.QueryRowContext(ctx, sessionIDQuery).Scan(&raw); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("获取会话 ID 失败：%w", err)
+	}
+	sessionID := strings.TrimSpace(fmt.Sprintf("%v", normalizeQueryValue(raw)))
+	if sessionID == "" || sessionID == "<nil>" {
+		_ = conn.Close()
+		return nil, fmt.Errorf("获取会话 ID 失败：返回值为空")
+	}
+
+	return &QuerySession{
+		pool:      pool,
+		conn:      conn,
+		sessionID: sessionID,
+		killSQL:   fmt.Sprintf(killFormat, sessionID),
+	}, nil
+}
+
+// openPinnedSession 只固定连接、不获取会话 ID，用于无法服务端中止查询的驱动。
+func openPinnedSession(ctx context.Context, pool *sql.DB) (*QuerySession, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("connection not open")
+	}
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &QuerySession{pool: pool, conn: conn}, nil
+}
+
+func (s *QuerySession) SessionID() string {
+	return s.sessionID
+}
+
+func (s *QuerySession) QueryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error) {
+	rows, err := s.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWithMeta(rows)
+}
+
+func (s *QuerySession) ExecContext(ctx context.Context, query string) (int64, error) {
+	res, err := s.conn.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Kill 通过连接池中的另一个连接中止本会话正在执行的语句。
+func (s *QuerySession) Kill(ctx context.Context) error {
+	if s.killSQL == "" {
+		return ErrQuerySessionUnsupported
+	}
+	_, err := s.pool.ExecContext(ctx, s.killSQL)
+	return err
+}
+
+// Close 将固定的连接归还连接池。
+func (s *QuerySession) Close() error {
+	return s.conn.Close()
+}
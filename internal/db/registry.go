@@ -0,0 +1,228 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+// 方言族：同一族的数据源共享 SQL 语法、系统视图与引用规则。
+const (
+	FamilyMySQL     = "mysql"
+	FamilyPostgres  = "postgres"
+	FamilySQLServer = "sqlserver"
+	FamilyOracle    = "oracle"
+	FamilyDameng    = "dameng"
+	FamilySQLite    = "sqlite"
+	FamilyTDengine  = "tdengine"
+	FamilyMongoDB   = "mongodb"
+)
+
+// IdentQuoteStyle 标识符引用方式。
+type IdentQuoteStyle string
+
+const (
+	QuoteDoubleQuote IdentQuoteStyle = "double"   // "ident"
+	QuoteBacktick    IdentQuoteStyle = "backtick" // `ident`
+	QuoteBracket     IdentQuoteStyle = "bracket"  // [ident]
+)
+
+// PaginationStyle 分页语法。
+type PaginationStyle string
+
+const (
+	PaginationLimitOffset PaginationStyle = "limit_offset" // LIMIT n OFFSET m
+	PaginationOffsetFetch PaginationStyle = "offset_fetch" // ORDER BY ... OFFSET m ROWS FETCH NEXT n ROWS ONLY
+	PaginationRowNum      PaginationStyle = "rownum"       // 子查询 + ROWNUM
+)
+
+// DriverCapabilities 描述数据源的方言与功能支持情况，供 App 与数据同步按能力分支，而不是逐个匹配类型名。
+type DriverCapabilities struct {
+	Type                   string          `json:"type"`
+	Family                 string          `json:"family"`
+	DefaultPort            int             `json:"defaultPort"`
+	SupportsSchemas        bool            `json:"supportsSchemas"`         // 数据库之下还有 schema 层级
+	DefaultSchema          string          `json:"defaultSchema,omitempty"` // 表名未带 schema 时使用
+	SupportsTransactions   bool            `json:"supportsTransactions"`
+	SupportsForeignKeys    bool            `json:"supportsForeignKeys"`
+	SupportsTriggers       bool            `json:"supportsTriggers"`
+	SupportsRenameDatabase bool            `json:"supportsRenameDatabase"`
+	SupportsServerCancel   bool            `json:"supportsServerCancel"` // 可从另一个连接中止正在执行的语句
+	SupportsCreateDatabase bool            `json:"supportsCreateDatabase"`
+	SupportsDropDatabase   bool            `json:"supportsDropDatabase"`
+	SupportsTruncate       bool            `json:"supportsTruncate"` // 全量覆盖清空整表时用 TRUNCATE TABLE 代替 DELETE
+	SupportsRenameTable    bool            `json:"supportsRenameTable"`
+	SupportsDropTable      bool            `json:"supportsDropTable"`
+	SupportsViews          bool            `json:"supportsViews"`
+	SupportsRoutines       bool            `json:"supportsRoutines"` // 函数与存储过程
+	DatabaseInConfig       bool            `json:"databaseInConfig"` // 切换数据库需写入连接配置的 Database
+	IdentQuote             IdentQuoteStyle `json:"identQuote"`
+	Pagination             PaginationStyle `json:"pagination"`
+}
+
+// DriverDescriptor 是驱动在注册表中的登记信息。
+type DriverDescriptor struct {
+	Type         string
+	Aliases      []string // custom 连接的 database/sql 驱动名，用于推断其方言
+	New          func() Database
+	Capabilities DriverCapabilities
+}
+
+var (
+	registryMu  sync.RWMutex
+	registry    = make(map[string]DriverDescriptor)
+	driverAlias = make(map[string]string)
+	genericCaps = DriverCapabilities{IdentQuote: QuoteDoubleQuote, Pagination: PaginationLimitOffset}
+)
+
+const defaultDriverType = "mysql"
+
+// RegisterDriver 登记驱动，通常在各驱动实现文件的 init 中调用；重复登记同一类型会 panic。
+func RegisterDriver(desc DriverDescriptor) {
+	key := normalizeDriverKey(desc.Type)
+	if key == "" || desc.New == nil {
+		panic("db: RegisterDriver 需要类型名与构造函数")
+	}
+	desc.Type = key
+	desc.Capabilities.Type = key
+	if desc.Capabilities.Family == "" {
+		desc.Capabilities.Family = key
+	}
+	if desc.Capabilities.IdentQuote == "" {
+		desc.Capabilities.IdentQuote = QuoteDoubleQuote
+	}
+	if desc.Capabilities.Pagination == "" {
+		desc.Capabilities.Pagination = PaginationLimitOffset
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[key]; exists {
+		panic(fmt.Sprintf("db: 驱动重复注册：%s", key))
+	}
+	registry[key] = desc
+	for _, alias := range desc.Aliases {
+		if alias = normalizeDriverKey(alias); alias != "" {
+			driverAlias[alias] = key
+		}
+	}
+}
+
+// LookupDriver 按类型名查找已注册的驱动。
+func LookupDriver(dbType string) (DriverDescriptor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	desc, ok := registry[normalizeDriverKey(dbType)]
+	return desc, ok
+}
+
+// RegisteredDrivers 返回全部已注册驱动的能力描述，按类型名排序。
+func RegisteredDrivers() []DriverCapabilities {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	result := make([]DriverCapabilities, 0, len(registry))
+	for _, desc := range registry {
+		result = append(result, desc.Capabilities)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+// NewDatabase 按类型名创建驱动实例。
+func NewDatabase(dbType string) (Database, error) {
+	key := normalizeDriverKey(dbType)
+	if key == "" {
+		// Default to MySQL for backward compatibility if empty
+		key = defaultDriverType
+	}
+	desc, ok := LookupDriver(key)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+	return desc.New(), nil
+}
+
+// ResolveDialect 返回连接实际使用的方言类型；custom 连接按驱动名推断，无法识别时原样返回驱动名。
+func ResolveDialect(config connection.ConnectionConfig) string {
+	dbType := normalizeDriverKey(config.Type)
+	if dbType != "custom" {
+		return dbType
+	}
+
+	driver := normalizeDriverKey(config.Driver)
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if key, ok := driverAlias[driver]; ok {
+		return key
+	}
+	return driver
+}
+
+// Capabilities 返回方言类型的能力描述；未注册的类型按通用 SQL 处理（双引号引用、LIMIT/OFFSET 分页、不声明任何可选能力）。
+func Capabilities(dbType string) DriverCapabilities {
+	if desc, ok := LookupDriver(dbType); ok {
+		return desc.Capabilities
+	}
+	caps := genericCaps
+	caps.Type = normalizeDriverKey(dbType)
+	caps.Family = caps.Type
+	return caps
+}
+
+// QuoteIdent 按方言规则引用单个标识符。
+func QuoteIdent(dbType string, ident string) string {
+	return Capabilities(dbType).QuoteIdent(ident)
+}
+
+// QuoteQualifiedIdent 按方言规则逐段引用 schema.table 形式的标识符。
+func QuoteQualifiedIdent(dbType string, ident string) string {
+	return Capabilities(dbType).QuoteQualifiedIdent(ident)
+}
+
+func (c DriverCapabilities) QuoteIdent(ident string) string {
+	if ident == "" {
+		return ident
+	}
+
+	switch c.IdentQuote {
+	case QuoteBacktick:
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	case QuoteBracket:
+		return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+	default:
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	}
+}
+
+func (c DriverCapabilities) QuoteQualifiedIdent(ident string) string {
+	raw := strings.TrimSpace(ident)
+	if raw == "" {
+		return raw
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) <= 1 {
+		return c.QuoteIdent(raw)
+	}
+
+	quotedParts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		quotedParts = append(quotedParts, c.QuoteIdent(part))
+	}
+
+	if len(quotedParts) == 0 {
+		return c.QuoteIdent(raw)
+	}
+	return strings.Join(quotedParts, ".")
+}
+
+func normalizeDriverKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
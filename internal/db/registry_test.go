@@ -0,0 +1,111 @@
+package db
+
+import (
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestNewDatabase_RegisteredTypes(t *testing.T) {
+	types := []string{"mysql", "mariadb", "sphinx", "postgres", "kingbase", "highgo", "vastbase", "sqlserver", "oracle", "dameng", "sqlite", "tdengine", "mongodb", "custom"}
+	for _, dbType := range types {
+		inst, err := NewDatabase(dbType)
+		if err != nil || inst == nil {
+			t.Fatalf("NewDatabase(%q) 失败：%v", dbType, err)
+		}
+	}
+
+	if inst, err := NewDatabase(""); err != nil {
+		t.Fatalf("空类型应回退为 MySQL：%v", err)
+	} else if _, ok := inst.(*MySQLDB); !ok {
+		t.Fatalf("空类型应回退为 MySQL，实际=%T", inst)
+	}
+	if _, err := NewDatabase("unknown"); err == nil {
+		t.Fatalf("未注册类型应返回错误")
+	}
+}
+
+func TestResolveDialect(t *testing.T) {
+	cases := []struct {
+		config connection.ConnectionConfig
+		want   string
+	}{
+		{connection.ConnectionConfig{Type: " MySQL "}, "mysql"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "postgresql"}, "postgres"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "dm"}, "dameng"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "sqlite3"}, "sqlite"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "sphinxql"}, "sphinx"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "kingbase"}, "kingbase"},
+		{connection.ConnectionConfig{Type: "custom", Driver: "odbc"}, "odbc"},
+	}
+	for _, tc := range cases {
+		if got := ResolveDialect(tc.config); got != tc.want {
+			t.Fatalf("ResolveDialect(%+v)=%q，期望=%q", tc.config, got, tc.want)
+		}
+	}
+}
+
+func TestQuoteQualifiedIdent(t *testing.T) {
+	cases := []struct {
+		dbType string
+		ident  string
+		want   string
+	}{
+		{"mysql", "db.t`x", "`db`.`t``x`"},
+		{"tdengine", "meters", "`meters`"},
+		{"sqlserver", "dbo.a]b", "[dbo].[a]]b]"},
+		{"postgres", `public."t"`, `"public"."""t"""`},
+		{"odbc", "t", `"t"`},
+		{"mysql", "  ", ""},
+	}
+	for _, tc := range cases {
+		if got := QuoteQualifiedIdent(tc.dbType, tc.ident); got != tc.want {
+			t.Fatalf("QuoteQualifiedIdent(%q, %q)=%q，期望=%q", tc.dbType, tc.ident, got, tc.want)
+		}
+	}
+}
+
+func TestCapabilities_Family(t *testing.T) {
+	for _, dbType := range []string{"postgres", "kingbase", "highgo", "vastbase"} {
+		caps := Capabilities(dbType)
+		if caps.Family != FamilyPostgres || caps.DefaultSchema != "public" {
+			t.Fatalf("%s 能力描述不正确：%+v", dbType, caps)
+		}
+	}
+	if caps := Capabilities("sphinx"); caps.Family != FamilyMySQL || caps.SupportsTransactions {
+		t.Fatalf("sphinx 能力描述不正确：%+v", caps)
+	}
+	if caps := Capabilities("unknown"); caps.IdentQuote != QuoteDoubleQuote || caps.SupportsTransactions {
+		t.Fatalf("未注册类型应使用通用能力描述：%+v", caps)
+	}
+}
+
+func TestCapabilities_DDLSupport(t *testing.T) {
+	cases := []struct {
+		dbType                       string
+		createDB, dropDB, truncate   bool
+		renameTable, dropTable, view bool
+		routines, databaseInConfig   bool
+	}{
+		{"mysql", true, true, true, true, true, true, true, true},
+		{"mariadb", true, true, true, true, true, true, true, true},
+		{"sphinx", false, false, false, true, true, true, true, true},
+		{"postgres", true, true, false, true, true, true, true, true},
+		{"sqlserver", false, false, false, true, true, true, true, true},
+		{"oracle", false, false, false, true, true, true, true, false},
+		{"sqlite", false, false, false, true, true, true, false, false},
+		{"tdengine", true, true, false, false, true, false, false, true},
+		{"mongodb", false, false, false, false, false, false, false, true},
+		{"unknown", false, false, false, false, false, false, false, false},
+	}
+	for _, tc := range cases {
+		c := Capabilities(tc.dbType)
+		got := []bool{c.SupportsCreateDatabase, c.SupportsDropDatabase, c.SupportsTruncate, c.SupportsRenameTable, c.SupportsDropTable, c.SupportsViews, c.SupportsRoutines, c.DatabaseInConfig}
+		want := []bool{tc.createDB, tc.dropDB, tc.truncate, tc.renameTable, tc.dropTable, tc.view, tc.routines, tc.databaseInConfig}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s 的 DDL 能力描述不正确：实际=%v，期望=%v", tc.dbType, got, want)
+			}
+		}
+	}
+}
@@ -2,8 +2,12 @@ package db
 
 import (
 	"database/sql"
+
+	"GoNavi-Wails/internal/connection"
 )
 
+const defaultStreamBatchSize = 1000
+
 func scanRows(rows *sql.Rows) ([]map[string]interface{}, []string, error) {
 	columns, err := rows.Columns()
 	if err != nil {
@@ -18,24 +22,10 @@ func scanRows(rows *sql.Rows) ([]map[string]interface{}, []string, error) {
 	resultData := make([]map[string]interface{}, 0)
 
 	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range columns {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
+		entry, ok := scanRowEntry(rows, columns, colTypes)
+		if !ok {
 			continue
 		}
-
-		entry := make(map[string]interface{}, len(columns))
-		for i, col := range columns {
-			dbTypeName := ""
-			if colTypes != nil && i < len(colTypes) && colTypes[i] != nil {
-				dbTypeName = colTypes[i].DatabaseTypeName()
-			}
-			entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
-		}
 		resultData = append(resultData, entry)
 	}
 
@@ -44,3 +34,119 @@ func scanRows(rows *sql.Rows) ([]map[string]interface{}, []string, error) {
 	}
 	return resultData, columns, nil
 }
+
+// scanRowsWithMeta 读取全部结果并返回带类型信息的列元数据。
+func scanRowsWithMeta(rows *sql.Rows) ([]map[string]interface{}, []connection.ResultColumn, error) {
+	data := make([]map[string]interface{}, 0)
+	var meta []connection.ResultColumn
+	err := streamRows(rows, defaultStreamBatchSize, func(batch RowBatch) error {
+		if meta == nil {
+			meta = batch.Meta
+		}
+		data = append(data, batch.Rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, meta, nil
+}
+
+// streamRows 按批次读取结果集并交给 handle 处理，内存占用只与 batchSize 相关。
+// handle 至少会被调用一次（空结果集时 Rows 为空），以便调用方拿到列信息。
+func streamRows(rows *sql.Rows, batchSize int, handle RowBatchHandler) error {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil || len(colTypes) != len(columns) {
+		colTypes = nil
+	}
+	meta := buildResultColumns(columns, colTypes)
+
+	delivered := false
+	batch := make([]map[string]interface{}, 0, batchSize)
+	for rows.Next() {
+		entry, ok := scanRowEntry(rows, columns, colTypes)
+		if !ok {
+			continue
+		}
+		batch = append(batch, entry)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := handle(RowBatch{Columns: columns, Meta: meta, Rows: batch}); err != nil {
+			return err
+		}
+		delivered = true
+		batch = make([]map[string]interface{}, 0, batchSize)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 || !delivered {
+		return handle(RowBatch{Columns: columns, Meta: meta, Rows: batch})
+	}
+	return nil
+}
+
+func scanRowEntry(rows *sql.Rows, columns []string, colTypes []*sql.ColumnType) (map[string]interface{}, bool) {
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, false
+	}
+
+	entry := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		dbTypeName := ""
+		if colTypes != nil && i < len(colTypes) && colTypes[i] != nil {
+			dbTypeName = colTypes[i].DatabaseTypeName()
+		}
+		entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
+	}
+	return entry, true
+}
+
+// buildResultColumns 从驱动的 ColumnType 提取列元数据；驱动未提供的信息保持为空。
+func buildResultColumns(columns []string, colTypes []*sql.ColumnType) []connection.ResultColumn {
+	meta := make([]connection.ResultColumn, len(columns))
+	for i, col := range columns {
+		meta[i].Name = col
+		if colTypes == nil || i >= len(colTypes) || colTypes[i] == nil {
+			continue
+		}
+		ct := colTypes[i]
+		meta[i].DBType = ct.DatabaseTypeName()
+		if scanType := ct.ScanType(); scanType != nil {
+			meta[i].ScanType = scanType.String()
+		}
+		if length, ok := ct.Length(); ok {
+			meta[i].Length = &length
+		}
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			meta[i].Precision = &precision
+			meta[i].Scale = &scale
+		}
+		if nullable, ok := ct.Nullable(); ok {
+			meta[i].Nullable = &nullable
+		}
+	}
+	return meta
+}
+
+// resultColumnsFromNames 在驱动无法提供类型信息时仅保留列名。
+func resultColumnsFromNames(columns []string) []connection.ResultColumn {
+	return buildResultColumns(columns, nil)
+}
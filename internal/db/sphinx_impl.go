@@ -1,6 +1,8 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -8,6 +10,24 @@ import (
 	"GoNavi-Wails/internal/logger"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "sphinx",
+		Aliases: []string{"sphinxql"},
+		New:     func() Database { return &SphinxDB{} },
+		Capabilities: DriverCapabilities{
+			Family:              FamilyMySQL,
+			DefaultPort:         9306,
+			SupportsRenameTable: true,
+			SupportsDropTable:   true,
+			SupportsViews:       true,
+			SupportsRoutines:    true,
+			DatabaseInConfig:    true,
+			IdentQuote:          QuoteBacktick,
+		},
+	})
+}
+
 const sphinxDefaultDatabaseName = "default"
 
 // SphinxDB 复用 MySQL 协议实现，并在数据库列表不可用时提供兜底。
@@ -46,6 +66,16 @@ func (s *SphinxDB) Connect(config connection.ConnectionConfig) error {
 	return s.MySQLDB.Connect(config)
 }
 
+// OpenQuerySession Sphinx 不支持 CONNECTION_ID()/KILL QUERY，查询中止仅依赖上下文取消。
+func (s *SphinxDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return nil, ErrQuerySessionUnsupported
+}
+
+// BeginTxSession Sphinx 不提供通用事务语义，不支持事务会话。
+func (s *SphinxDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	return nil, ErrTxSessionUnsupported
+}
+
 func (s *SphinxDB) resolveDatabaseName(dbName string) string {
 	name := strings.TrimSpace(dbName)
 	if name == "" {
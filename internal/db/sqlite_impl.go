@@ -13,6 +13,23 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "sqlite",
+		Aliases: []string{"sqlite3"},
+		New:     func() Database { return &SQLiteDB{} },
+		Capabilities: DriverCapabilities{
+			Family:               FamilySQLite,
+			SupportsTransactions: true,
+			SupportsForeignKeys:  true,
+			SupportsTriggers:     true,
+			SupportsRenameTable:  true,
+			SupportsDropTable:    true,
+			SupportsViews:        true,
+		},
+	})
+}
+
 type SQLiteDB struct {
 	conn        *sql.DB
 	pingTimeout time.Duration
@@ -68,6 +85,28 @@ func (s *SQLiteDB) QueryContext(ctx context.Context, query string) ([]map[string
 	return scanRows(rows)
 }
 
+func (s *SQLiteDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if s.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (s *SQLiteDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := openPinnedSession(ctx, s.conn)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (s *SQLiteDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if s.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -446,11 +485,16 @@ func (s *SQLiteDB) GetTriggers(dbName, tableName string) ([]connection.TriggerDe
 }
 
 func (s *SQLiteDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return s.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (s *SQLiteDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if s.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := s.conn.Begin()
+	tx, err := s.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -492,7 +536,7 @@ func (s *SQLiteDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -522,7 +566,7 @@ func (s *SQLiteDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -544,7 +588,7 @@ func (s *SQLiteDB) ApplyChanges(tableName string, changes connection.ChangeSet)
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+// cancellingValue 在驱动转换参数时取消 ctx，模拟批次应用到一半时被取消。
+type cancellingValue struct {
+	cancel context.CancelFunc
+	v      int64
+}
+
+func (c cancellingValue) Value() (driver.Value, error) {
+	c.cancel()
+	return c.v, nil
+}
+
+func newTestSQLite(t *testing.T) *SQLiteDB {
+	t.Helper()
+	s := &SQLiteDB{}
+	if err := s.Connect(connection.ConnectionConfig{Type: "sqlite", Host: filepath.Join(t.TempDir(), "apply.db")}); err != nil {
+		t.Fatalf("连接 SQLite 失败：%v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if _, err := s.Exec("CREATE TABLE t (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("建表失败：%v", err)
+	}
+	return s
+}
+
+func countSQLiteRows(t *testing.T, s *SQLiteDB) string {
+	t.Helper()
+	rows, _, err := s.Query("SELECT COUNT(*) AS n FROM t")
+	if err != nil {
+		t.Fatalf("统计行数失败：%v", err)
+	}
+	return fmt.Sprint(rows[0]["n"])
+}
+
+func TestSQLiteApplyChangesContext_CancelRollsBackBatch(t *testing.T) {
+	s := newTestSQLite(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := connection.ChangeSet{Inserts: []map[string]interface{}{
+		{"id": int64(1)},
+		{"id": cancellingValue{cancel: cancel, v: 2}},
+		{"id": int64(3)},
+	}}
+	err := s.ApplyChangesContext(ctx, "t", changes)
+	if err == nil {
+		t.Fatalf("应用过程中取消应返回错误")
+	}
+	if n := countSQLiteRows(t, s); n != "0" {
+		t.Fatalf("取消后整批应回滚，实际剩余 %s 行", n)
+	}
+
+	if err := s.ApplyChangesContext(context.Background(), "t", connection.ChangeSet{Inserts: changes.Inserts[:1]}); err != nil {
+		t.Fatalf("应用变更失败：%v", err)
+	}
+	if n := countSQLiteRows(t, s); n != "1" {
+		t.Fatalf("提交后应为 1 行，实际 %s 行", n)
+	}
+}
+
+func TestApplyChanges_CancelledContextWritesNothing(t *testing.T) {
+	s := newTestSQLite(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ApplyChanges(ctx, s, "t", connection.ChangeSet{Inserts: []map[string]interface{}{{"id": int64(1)}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("已取消的 ctx 应返回 context.Canceled，实际=%v", err)
+	}
+	if n := countSQLiteRows(t, s); n != "0" {
+		t.Fatalf("已取消时不应写入，实际 %s 行", n)
+	}
+}
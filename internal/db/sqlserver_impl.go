@@ -18,6 +18,31 @@ import (
 	_ "github.com/microsoft/go-mssqldb"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "sqlserver",
+		Aliases: []string{"mssql"},
+		New:     func() Database { return &SqlServerDB{} },
+		Capabilities: DriverCapabilities{
+			Family:               FamilySQLServer,
+			DefaultPort:          1433,
+			SupportsSchemas:      true,
+			DefaultSchema:        "dbo",
+			SupportsTransactions: true,
+			SupportsForeignKeys:  true,
+			SupportsTriggers:     true,
+			SupportsServerCancel: true,
+			SupportsRenameTable:  true,
+			SupportsDropTable:    true,
+			SupportsViews:        true,
+			SupportsRoutines:     true,
+			DatabaseInConfig:     true,
+			IdentQuote:           QuoteBracket,
+			Pagination:           PaginationOffsetFetch,
+		},
+	})
+}
+
 type SqlServerDB struct {
 	conn        *sql.DB
 	pingTimeout time.Duration
@@ -139,6 +164,32 @@ func (s *SqlServerDB) QueryContext(ctx context.Context, query string) ([]map[str
 	return scanRows(rows)
 }
 
+func (s *SqlServerDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if s.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (s *SqlServerDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, s.conn, "SELECT @@SPID", "KILL %s")
+}
+
+func (s *SqlServerDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := s.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (s *SqlServerDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if s.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -526,11 +577,16 @@ ORDER BY tr.name`,
 }
 
 func (s *SqlServerDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return s.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (s *SqlServerDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if s.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := s.conn.Begin()
+	tx, err := s.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -569,7 +625,7 @@ func (s *SqlServerDB) ApplyChanges(tableName string, changes connection.ChangeSe
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -602,12 +658,49 @@ func (s *SqlServerDB) ApplyChanges(tableName string, changes connection.ChangeSe
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
 
 	// 3. Inserts
+	// 插入行带有自增列的值时（如数据同步写入源表主键）需要临时开启 IDENTITY_INSERT。
+	// 该设置属于会话级别，提交或返回错误前都要关闭，避免影响连接池中后续使用该连接的语句。
+	identityInsert := false
+	if len(changes.Inserts) > 0 {
+		var identityCol string
+		err := tx.QueryRowContext(ctx, "SELECT name FROM sys.identity_columns WHERE object_id = OBJECT_ID(@p1)", sql.Named("p1", qualifiedTable)).Scan(&identityCol)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("query identity column error: %v", err)
+		}
+		if identityCol != "" {
+			for _, row := range changes.Inserts {
+				for k := range row {
+					if strings.EqualFold(strings.TrimSpace(k), identityCol) {
+						identityInsert = true
+						break
+					}
+				}
+				if identityInsert {
+					break
+				}
+			}
+		}
+		if identityInsert {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET IDENTITY_INSERT %s ON", qualifiedTable)); err != nil {
+				return fmt.Errorf("enable identity insert error: %v", err)
+			}
+		}
+	}
+	disableIdentityInsert := func() error {
+		if !identityInsert {
+			return nil
+		}
+		identityInsert = false
+		_, err := tx.ExecContext(ctx, fmt.Sprintf("SET IDENTITY_INSERT %s OFF", qualifiedTable))
+		return err
+	}
+
 	for _, row := range changes.Inserts {
 		var cols []string
 		var placeholders []string
@@ -626,10 +719,14 @@ func (s *SqlServerDB) ApplyChanges(tableName string, changes connection.ChangeSe
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			_ = disableIdentityInsert()
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
+	if err := disableIdentityInsert(); err != nil {
+		return fmt.Errorf("disable identity insert error: %v", err)
+	}
 
 	return tx.Commit()
 }
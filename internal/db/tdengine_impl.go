@@ -17,6 +17,23 @@ import (
 	_ "github.com/taosdata/driver-go/v3/taosWS"
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type:    "tdengine",
+		Aliases: []string{"taosws", "taossql", "taosrestful"},
+		New:     func() Database { return &TDengineDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyTDengine,
+			DefaultPort:            6041,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsDropTable:      true,
+			DatabaseInConfig:       true,
+			IdentQuote:             QuoteBacktick,
+		},
+	})
+}
+
 // TDengineDB implements Database interface for TDengine.
 // Uses taosWS driver via WebSocket (通常通过 taosAdapter 提供服务)。
 type TDengineDB struct {
@@ -126,6 +143,20 @@ func (t *TDengineDB) QueryContext(ctx context.Context, query string) ([]map[stri
 	return scanRows(rows)
 }
 
+func (t *TDengineDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if t.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := t.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
 func (t *TDengineDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if t.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
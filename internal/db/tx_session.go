@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+// ErrTxSessionUnsupported 表示驱动不支持跨调用的事务会话。
+var ErrTxSessionUnsupported = errors.New("当前数据源不支持事务会话")
+
+// TxSessionOpener 由支持跨调用事务会话的驱动实现。
+type TxSessionOpener interface {
+	BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error)
+}
+
+// TxSession 在固定连接上开启的事务，可跨多次调用执行语句，最后提交或回滚。
+type TxSession struct {
+	query *QuerySession
+	tx    *sql.Tx
+}
+
+// beginTxSession 在已固定的连接上开启事务；失败时归还连接。
+func beginTxSession(ctx context.Context, qs *QuerySession, isolation sql.IsolationLevel) (*TxSession, error) {
+	var opts *sql.TxOptions
+	if isolation != sql.LevelDefault {
+		opts = &sql.TxOptions{Isolation: isolation}
+	}
+	// 事务生命周期跨越多次调用，不能绑定到单次调用的 ctx 上，否则 ctx 结束时事务会被自动回滚
+	tx, err := qs.conn.BeginTx(context.WithoutCancel(ctx), opts)
+	if err != nil {
+		_ = qs.Close()
+		return nil, fmt.Errorf("开启事务失败：%w", err)
+	}
+	return &TxSession{query: qs, tx: tx}, nil
+}
+
+// SessionID 返回服务端会话 ID，驱动不支持时为空。
+func (s *TxSession) SessionID() string {
+	return s.query.SessionID()
+}
+
+func (s *TxSession) QueryWithMeta(ctx context.Context, query string) ([]map[string]interface{}, []connection.ResultColumn, error) {
+	rows, err := s.tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWithMeta(rows)
+}
+
+func (s *TxSession) ExecContext(ctx context.Context, query string) (int64, error) {
+	res, err := s.tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Kill 中止事务中正在执行的语句，事务本身保持打开。
+func (s *TxSession) Kill(ctx context.Context) error {
+	return s.query.Kill(ctx)
+}
+
+// Commit 提交事务并归还连接。
+func (s *TxSession) Commit() error {
+	err := s.tx.Commit()
+	_ = s.query.Close()
+	return err
+}
+
+// Rollback 回滚事务并归还连接。
+func (s *TxSession) Rollback() error {
+	err := s.tx.Rollback()
+	_ = s.query.Close()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
@@ -18,6 +18,31 @@ import (
 	_ "github.com/lib/pq" // Vastbase is PostgreSQL compatible
 )
 
+func init() {
+	RegisterDriver(DriverDescriptor{
+		Type: "vastbase",
+		New:  func() Database { return &VastbaseDB{} },
+		Capabilities: DriverCapabilities{
+			Family:                 FamilyPostgres,
+			DefaultPort:            5432,
+			SupportsSchemas:        true,
+			DefaultSchema:          "public",
+			SupportsTransactions:   true,
+			SupportsForeignKeys:    true,
+			SupportsTriggers:       true,
+			SupportsRenameDatabase: true,
+			SupportsServerCancel:   true,
+			SupportsCreateDatabase: true,
+			SupportsDropDatabase:   true,
+			SupportsRenameTable:    true,
+			SupportsDropTable:      true,
+			SupportsViews:          true,
+			SupportsRoutines:       true,
+			DatabaseInConfig:       true,
+		},
+	})
+}
+
 // VastbaseDB implements Database interface for Vastbase (海量) database
 // Vastbase is a PostgreSQL-compatible database, so we reuse PostgreSQL driver
 type VastbaseDB struct {
@@ -133,6 +158,32 @@ func (v *VastbaseDB) QueryContext(ctx context.Context, query string) ([]map[stri
 	return scanRows(rows)
 }
 
+func (v *VastbaseDB) QueryStream(ctx context.Context, query string, batchSize int, handle RowBatchHandler) error {
+	if v.conn == nil {
+		return fmt.Errorf("connection not open")
+	}
+
+	rows, err := v.conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return streamRows(rows, batchSize, handle)
+}
+
+func (v *VastbaseDB) OpenQuerySession(ctx context.Context) (*QuerySession, error) {
+	return openQuerySession(ctx, v.conn, "SELECT pg_backend_pid()", "SELECT pg_cancel_backend(%s)")
+}
+
+func (v *VastbaseDB) BeginTxSession(ctx context.Context, isolation sql.IsolationLevel) (*TxSession, error) {
+	qs, err := v.OpenQuerySession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return beginTxSession(ctx, qs, isolation)
+}
+
 func (v *VastbaseDB) Query(query string) ([]map[string]interface{}, []string, error) {
 	if v.conn == nil {
 		return nil, nil, fmt.Errorf("connection not open")
@@ -513,11 +564,16 @@ ORDER BY table_schema, table_name, ordinal_position`
 }
 
 func (v *VastbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet) error {
+	return v.ApplyChangesContext(context.Background(), tableName, changes)
+}
+
+// ApplyChangesContext 在事务中应用一批变更，ctx 取消时事务回滚
+func (v *VastbaseDB) ApplyChangesContext(ctx context.Context, tableName string, changes connection.ChangeSet) error {
 	if v.conn == nil {
 		return fmt.Errorf("connection not open")
 	}
 
-	tx, err := v.conn.Begin()
+	tx, err := v.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -561,7 +617,7 @@ func (v *VastbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 			continue
 		}
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", qualifiedTable, strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("delete error: %v", err)
 		}
 	}
@@ -594,7 +650,7 @@ func (v *VastbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qualifiedTable, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("update error: %v", err)
 		}
 	}
@@ -618,7 +674,7 @@ func (v *VastbaseDB) ApplyChanges(tableName string, changes connection.ChangeSet
 		}
 
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("insert error: %v", err)
 		}
 	}
@@ -8,6 +8,15 @@ type RedisValue struct {
 	TTL    int64       `json:"ttl"`    // TTL in seconds, -1 means no expiry, -2 means key doesn't exist
 	Value  interface{} `json:"value"`  // The actual value
 	Length int64       `json:"length"` // Length/size of the value
+	Cursor uint64      `json:"cursor"` // Non-zero when Value is only the first page of a hash, set or sorted set
+}
+
+// RedisCollectionPage represents one HSCAN/SSCAN/ZSCAN page of a collection
+type RedisCollectionPage struct {
+	Type   string      `json:"type"`   // hash, set, zset
+	Value  interface{} `json:"value"`  // map[string]string, []string or []ZSetMember
+	Cursor uint64      `json:"cursor"` // Cursor of the next page, 0 when the scan is complete
+	Length int64       `json:"length"` // Total length (HLEN/SCARD/ZCARD)
 }
 
 // RedisDBInfo represents information about a Redis database
@@ -16,6 +25,20 @@ type RedisDBInfo struct {
 	Keys  int64 `json:"keys"`  // Number of keys in this database
 }
 
+// RedisNodeInfo describes one node of a Redis Cluster
+type RedisNodeInfo struct {
+	ID         string            `json:"id"`
+	Addr       string            `json:"addr"`
+	Role       string            `json:"role"` // master, replica
+	MasterID   string            `json:"masterId,omitempty"`
+	Flags      string            `json:"flags"`
+	LinkState  string            `json:"linkState"`
+	Slots      []string          `json:"slots,omitempty"` // Slot ranges, e.g. 0-5460
+	Keys       int64             `json:"keys"`
+	UsedMemory string            `json:"usedMemory,omitempty"`
+	Info       map[string]string `json:"info,omitempty"`
+}
+
 // RedisKeyInfo represents information about a Redis key
 type RedisKeyInfo struct {
 	Key  string `json:"key"`
@@ -54,6 +77,7 @@ type RedisClient interface {
 
 	// Hash operations
 	GetHash(key string) (map[string]string, error)
+	ScanHash(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error)
 	SetHashField(key, field, value string) error
 	DeleteHashField(key string, fields ...string) error
 
@@ -64,11 +88,13 @@ type RedisClient interface {
 
 	// Set operations
 	GetSet(key string) ([]string, error)
+	ScanSet(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error)
 	SetAdd(key string, members ...string) error
 	SetRemove(key string, members ...string) error
 
 	// Sorted Set operations
 	GetZSet(key string, start, stop int64) ([]ZSetMember, error)
+	ScanZSet(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error)
 	ZSetAdd(key string, members ...ZSetMember) error
 	ZSetRemove(key string, members ...string) error
 
@@ -77,6 +103,13 @@ type RedisClient interface {
 	StreamAdd(key string, fields map[string]string, id string) (string, error)
 	StreamDelete(key string, ids ...string) (int64, error)
 
+	// Memory analysis
+	GetKeyStats(keys []RedisKeyInfo, samples int) ([]RedisKeyStat, error)
+
+	// Pub/Sub
+	Subscribe(opts RedisSubscribeOptions) (*RedisSubscription, error)
+	Publish(channel, message string) (int64, error)
+
 	// Command execution
 	ExecuteCommand(args []string) (interface{}, error)
 
@@ -86,6 +119,7 @@ type RedisClient interface {
 	SelectDB(index int) error
 	GetCurrentDB() int
 	FlushDB() error
+	GetClusterNodes() ([]RedisNodeInfo, error)
 }
 
 // ZSetMember represents a member in a sorted set
@@ -0,0 +1,394 @@
+package redis
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultAnalysisDelimiter = ":"
+	defaultAnalysisDepth     = 3
+	defaultAnalysisTopN      = 50
+	defaultAnalysisScanCount = 500
+
+	// maxPrefixChildren bounds the distinct children tracked per prefix (prefixes such as
+	// user:<id>: would otherwise grow one node per key); later ones are merged into otherPrefixName
+	maxPrefixChildren = 10000
+	// reportPrefixChildren is how many children per prefix the report keeps, by bytes
+	reportPrefixChildren = 100
+
+	noPrefixName    = "(无前缀)"
+	otherPrefixName = "(其他)"
+)
+
+// RedisAnalysisOptions configures AnalyzeKeys
+type RedisAnalysisOptions struct {
+	Pattern       string `json:"pattern"`       // SCAN MATCH pattern, default *
+	Delimiter     string `json:"delimiter"`     // Prefix delimiter, default ":"
+	MaxDepth      int    `json:"maxDepth"`      // Prefix tree depth, default 3
+	TopN          int    `json:"topN"`          // Number of biggest keys to report, default 50
+	ScanCount     int64  `json:"scanCount"`     // SCAN COUNT per batch, default 500
+	MemorySamples int    `json:"memorySamples"` // MEMORY USAGE SAMPLES for nested values, 0 uses the server default
+	MaxKeys       int64  `json:"maxKeys"`       // Stop after this many keys, 0 means the whole keyspace
+}
+
+// RedisKeyStat describes the memory usage of one key
+type RedisKeyStat struct {
+	Key      string `json:"key"`
+	Type     string `json:"type"`
+	TTL      int64  `json:"ttl"`
+	Bytes    int64  `json:"bytes"`    // MEMORY USAGE
+	Elements int64  `json:"elements"` // STRLEN/HLEN/LLEN/SCARD/ZCARD/XLEN
+}
+
+// RedisPrefixNode aggregates the keys sharing a prefix
+type RedisPrefixNode struct {
+	Name     string             `json:"name"`   // Last segment, e.g. session
+	Prefix   string             `json:"prefix"` // Full prefix, e.g. user:session:
+	Keys     int64              `json:"keys"`
+	Bytes    int64              `json:"bytes"`
+	Elements int64              `json:"elements"`
+	Children []*RedisPrefixNode `json:"children,omitempty"`
+
+	index map[string]*RedisPrefixNode
+}
+
+// RedisTTLBucket counts the keys whose TTL falls into a range
+type RedisTTLBucket struct {
+	Label string `json:"label"`
+	Keys  int64  `json:"keys"`
+	Bytes int64  `json:"bytes"`
+}
+
+// RedisTypeStat aggregates the keys of one type
+type RedisTypeStat struct {
+	Type  string `json:"type"`
+	Keys  int64  `json:"keys"`
+	Bytes int64  `json:"bytes"`
+}
+
+// RedisAnalysisProgress is reported after every scanned batch
+type RedisAnalysisProgress struct {
+	Scanned int64 `json:"scanned"`
+	Total   int64 `json:"total"` // Keys in the current database, 0 when unknown
+	Bytes   int64 `json:"bytes"`
+}
+
+// RedisAnalysisReport is the result of AnalyzeKeys
+type RedisAnalysisReport struct {
+	TotalKeys  int64              `json:"totalKeys"`
+	TotalBytes int64              `json:"totalBytes"`
+	Prefixes   []*RedisPrefixNode `json:"prefixes"`
+	TopKeys    []RedisKeyStat     `json:"topKeys"`
+	TTL        []RedisTTLBucket   `json:"ttl"`
+	Types      []RedisTypeStat    `json:"types"`
+	Cancelled  bool               `json:"cancelled,omitempty"`
+	Truncated  bool               `json:"truncated,omitempty"` // Stopped at MaxKeys
+	DurationMs int64              `json:"durationMs"`
+}
+
+// GetKeyStats returns MEMORY USAGE and element counts for keys returned by ScanKeys.
+// Keys that expired in the meantime are left out.
+func (r *RedisClientImpl) GetKeyStats(keys []RedisKeyInfo, samples int) ([]RedisKeyStat, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pipe := r.client.Pipeline()
+	memory := make([]*redis.IntCmd, len(keys))
+	elements := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		if samples > 0 {
+			memory[i] = pipe.MemoryUsage(ctx, key.Key, samples)
+		} else {
+			memory[i] = pipe.MemoryUsage(ctx, key.Key)
+		}
+		switch key.Type {
+		case "string":
+			elements[i] = pipe.StrLen(ctx, key.Key)
+		case "hash":
+			elements[i] = pipe.HLen(ctx, key.Key)
+		case "list":
+			elements[i] = pipe.LLen(ctx, key.Key)
+		case "set":
+			elements[i] = pipe.SCard(ctx, key.Key)
+		case "zset":
+			elements[i] = pipe.ZCard(ctx, key.Key)
+		case "stream":
+			elements[i] = pipe.XLen(ctx, key.Key)
+		}
+	}
+	// Errors are checked per command below
+	_, _ = pipe.Exec(ctx)
+
+	stats := make([]RedisKeyStat, 0, len(keys))
+	for i, key := range keys {
+		bytes, err := memory[i].Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("MEMORY USAGE 执行失败: %w", err)
+		}
+		stat := RedisKeyStat{Key: key.Key, Type: key.Type, TTL: key.TTL, Bytes: bytes}
+		if elements[i] != nil {
+			stat.Elements = elements[i].Val()
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// AnalyzeKeys scans the keyspace of the current database with ScanKeys and aggregates memory
+// usage by key prefix. Cancelling ctx stops the scan after the current batch and returns the
+// partial report with Cancelled set.
+func AnalyzeKeys(ctx context.Context, client RedisClient, opts RedisAnalysisOptions, onProgress func(RedisAnalysisProgress)) (*RedisAnalysisReport, error) {
+	opts = normalizeAnalysisOptions(opts)
+	startedAt := time.Now()
+
+	var total int64
+	if dbs, err := client.GetDatabases(); err == nil {
+		for _, info := range dbs {
+			if info.Index == client.GetCurrentDB() {
+				total = info.Keys
+			}
+		}
+	}
+
+	agg := newKeyAggregator(opts)
+	var cursor uint64
+	for {
+		if ctx.Err() != nil {
+			agg.report.Cancelled = true
+			break
+		}
+		page, err := client.ScanKeys(opts.Pattern, cursor, opts.ScanCount)
+		if err != nil {
+			return nil, fmt.Errorf("扫描键失败: %w", err)
+		}
+		keys := page.Keys
+		if opts.MaxKeys > 0 && agg.report.TotalKeys+int64(len(keys)) > opts.MaxKeys {
+			keys = keys[:opts.MaxKeys-agg.report.TotalKeys]
+			agg.report.Truncated = true
+		}
+		if len(keys) > 0 {
+			stats, err := client.GetKeyStats(keys, opts.MemorySamples)
+			if err != nil {
+				return nil, err
+			}
+			for _, stat := range stats {
+				agg.add(stat)
+			}
+		}
+		if onProgress != nil {
+			onProgress(RedisAnalysisProgress{Scanned: agg.report.TotalKeys, Total: total, Bytes: agg.report.TotalBytes})
+		}
+		cursor = page.Cursor
+		if cursor == 0 || agg.report.Truncated {
+			break
+		}
+	}
+
+	report := agg.finish()
+	report.DurationMs = time.Since(startedAt).Milliseconds()
+	return report, nil
+}
+
+func normalizeAnalysisOptions(opts RedisAnalysisOptions) RedisAnalysisOptions {
+	if strings.TrimSpace(opts.Pattern) == "" {
+		opts.Pattern = "*"
+	}
+	if opts.Delimiter == "" {
+		opts.Delimiter = defaultAnalysisDelimiter
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultAnalysisDepth
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = defaultAnalysisTopN
+	}
+	if opts.ScanCount <= 0 {
+		opts.ScanCount = defaultAnalysisScanCount
+	}
+	return opts
+}
+
+// keyAggregator accumulates key stats into a prefix tree, a top-N heap and TTL/type buckets
+type keyAggregator struct {
+	opts   RedisAnalysisOptions
+	root   *RedisPrefixNode
+	top    keyStatHeap
+	ttl    []RedisTTLBucket
+	types  map[string]*RedisTypeStat
+	report *RedisAnalysisReport
+}
+
+// ttlBucketBounds are the upper bounds (seconds) of the TTL buckets after the no-expiry bucket
+var ttlBucketBounds = []struct {
+	label string
+	max   int64
+}{
+	{"1 小时内", 3600},
+	{"1 小时 - 1 天", 86400},
+	{"1 - 7 天", 7 * 86400},
+	{"7 天以上", 1<<63 - 1},
+}
+
+func newKeyAggregator(opts RedisAnalysisOptions) *keyAggregator {
+	ttl := []RedisTTLBucket{{Label: "永不过期"}}
+	for _, b := range ttlBucketBounds {
+		ttl = append(ttl, RedisTTLBucket{Label: b.label})
+	}
+	return &keyAggregator{
+		opts:   opts,
+		root:   &RedisPrefixNode{},
+		ttl:    ttl,
+		types:  make(map[string]*RedisTypeStat),
+		report: &RedisAnalysisReport{},
+	}
+}
+
+func (g *keyAggregator) add(stat RedisKeyStat) {
+	g.report.TotalKeys++
+	g.report.TotalBytes += stat.Bytes
+
+	node := g.root
+	segments := strings.Split(stat.Key, g.opts.Delimiter)
+	// The last segment is the key name itself, not a prefix
+	depth := len(segments) - 1
+	if depth > g.opts.MaxDepth {
+		depth = g.opts.MaxDepth
+	}
+	if depth == 0 {
+		node = node.child(noPrefixName, "")
+		node.addStat(stat)
+	}
+	prefix := ""
+	for i := 0; i < depth; i++ {
+		prefix += segments[i] + g.opts.Delimiter
+		node = node.child(segments[i], prefix)
+		node.addStat(stat)
+	}
+
+	heap.Push(&g.top, stat)
+	if g.top.Len() > g.opts.TopN {
+		heap.Pop(&g.top)
+	}
+
+	bucket := 0
+	if stat.TTL >= 0 {
+		for i, b := range ttlBucketBounds {
+			if stat.TTL <= b.max {
+				bucket = i + 1
+				break
+			}
+		}
+	}
+	g.ttl[bucket].Keys++
+	g.ttl[bucket].Bytes += stat.Bytes
+
+	t, ok := g.types[stat.Type]
+	if !ok {
+		t = &RedisTypeStat{Type: stat.Type}
+		g.types[stat.Type] = t
+	}
+	t.Keys++
+	t.Bytes += stat.Bytes
+}
+
+func (g *keyAggregator) finish() *RedisAnalysisReport {
+	report := g.report
+	report.Prefixes = g.root.sortedChildren()
+
+	report.TopKeys = make([]RedisKeyStat, g.top.Len())
+	for i := len(report.TopKeys) - 1; i >= 0; i-- {
+		report.TopKeys[i] = heap.Pop(&g.top).(RedisKeyStat)
+	}
+
+	report.TTL = g.ttl
+	report.Types = make([]RedisTypeStat, 0, len(g.types))
+	for _, t := range g.types {
+		report.Types = append(report.Types, *t)
+	}
+	sort.Slice(report.Types, func(i, j int) bool { return report.Types[i].Bytes > report.Types[j].Bytes })
+	return report
+}
+
+// child returns the child for segment, creating it, or the shared other node once the node
+// already tracks maxPrefixChildren children
+func (n *RedisPrefixNode) child(name, prefix string) *RedisPrefixNode {
+	if n.index == nil {
+		n.index = make(map[string]*RedisPrefixNode)
+	}
+	if c, ok := n.index[name]; ok {
+		return c
+	}
+	if len(n.index) >= maxPrefixChildren {
+		name, prefix = otherPrefixName, n.Prefix
+		if c, ok := n.index[name]; ok {
+			return c
+		}
+	}
+	c := &RedisPrefixNode{Name: name, Prefix: prefix}
+	n.index[name] = c
+	return c
+}
+
+func (n *RedisPrefixNode) addStat(stat RedisKeyStat) {
+	n.Keys++
+	n.Bytes += stat.Bytes
+	n.Elements += stat.Elements
+}
+
+// sortedChildren returns the children by bytes, keeping the biggest reportPrefixChildren and
+// merging the rest into one node
+func (n *RedisPrefixNode) sortedChildren() []*RedisPrefixNode {
+	children := make([]*RedisPrefixNode, 0, len(n.index))
+	for _, c := range n.index {
+		children = append(children, c)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].Bytes != children[j].Bytes {
+			return children[i].Bytes > children[j].Bytes
+		}
+		return children[i].Prefix < children[j].Prefix
+	})
+	if len(children) > reportPrefixChildren {
+		rest := &RedisPrefixNode{Prefix: n.Prefix}
+		for _, c := range children[reportPrefixChildren:] {
+			rest.Keys += c.Keys
+			rest.Bytes += c.Bytes
+			rest.Elements += c.Elements
+		}
+		rest.Name = fmt.Sprintf("%s %d 个前缀", otherPrefixName, len(children)-reportPrefixChildren)
+		children = append(children[:reportPrefixChildren], rest)
+	}
+	for _, c := range children {
+		c.Children = c.sortedChildren()
+		c.index = nil
+	}
+	return children
+}
+
+// keyStatHeap is a min-heap by bytes, used to keep the biggest keys
+type keyStatHeap []RedisKeyStat
+
+func (h keyStatHeap) Len() int            { return len(h) }
+func (h keyStatHeap) Less(i, j int) bool  { return h[i].Bytes < h[j].Bytes }
+func (h keyStatHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *keyStatHeap) Push(x interface{}) { *h = append(*h, x.(RedisKeyStat)) }
+func (h *keyStatHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,198 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeAnalysisClient 按页返回键，并从 stats 中查出键的内存占用；stats 中没有的键视为已过期。
+type fakeAnalysisClient struct {
+	RedisClient
+	pages []RedisScanResult
+	stats map[string]RedisKeyStat
+	scans int
+}
+
+func (f *fakeAnalysisClient) GetDatabases() ([]RedisDBInfo, error) {
+	return []RedisDBInfo{{Index: 0, Keys: 100}, {Index: 1, Keys: 7}}, nil
+}
+
+func (f *fakeAnalysisClient) GetCurrentDB() int { return 0 }
+
+func (f *fakeAnalysisClient) ScanKeys(pattern string, cursor uint64, count int64) (*RedisScanResult, error) {
+	page := f.pages[f.scans]
+	f.scans++
+	return &page, nil
+}
+
+func (f *fakeAnalysisClient) GetKeyStats(keys []RedisKeyInfo, samples int) ([]RedisKeyStat, error) {
+	stats := make([]RedisKeyStat, 0, len(keys))
+	for _, key := range keys {
+		if stat, ok := f.stats[key.Key]; ok {
+			stats = append(stats, stat)
+		}
+	}
+	return stats, nil
+}
+
+func newFakeAnalysisClient() *fakeAnalysisClient {
+	keyInfos := func(keys ...string) []RedisKeyInfo {
+		infos := make([]RedisKeyInfo, len(keys))
+		for i, key := range keys {
+			infos[i] = RedisKeyInfo{Key: key}
+		}
+		return infos
+	}
+	stats := []RedisKeyStat{
+		{Key: "user:1:name", Type: "string", TTL: -1, Bytes: 100},
+		{Key: "user:1:tags", Type: "set", TTL: 60, Bytes: 300, Elements: 5},
+		{Key: "user:2:name", Type: "string", TTL: 7200, Bytes: 50},
+		{Key: "order:9", Type: "hash", TTL: 90000, Bytes: 1000, Elements: 10},
+		{Key: "plain", Type: "string", TTL: -1, Bytes: 10},
+		{Key: "a:b:c:d:e", Type: "string", TTL: 700000, Bytes: 5},
+	}
+	f := &fakeAnalysisClient{
+		pages: []RedisScanResult{
+			{Keys: keyInfos("user:1:name", "user:1:tags", "gone"), Cursor: 5},
+			{Keys: keyInfos("user:2:name", "order:9", "plain", "a:b:c:d:e"), Cursor: 0},
+		},
+		stats: make(map[string]RedisKeyStat),
+	}
+	for _, stat := range stats {
+		f.stats[stat.Key] = stat
+	}
+	return f
+}
+
+func prefixSummary(nodes []*RedisPrefixNode) []string {
+	result := make([]string, len(nodes))
+	for i, n := range nodes {
+		result[i] = fmt.Sprintf("%s=%d/%d", n.Prefix, n.Keys, n.Bytes)
+	}
+	return result
+}
+
+func TestAnalyzeKeys_AggregatesByPrefix(t *testing.T) {
+	client := newFakeAnalysisClient()
+	var progress []RedisAnalysisProgress
+	report, err := AnalyzeKeys(context.Background(), client, RedisAnalysisOptions{TopN: 3}, func(p RedisAnalysisProgress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeKeys 返回错误：%v", err)
+	}
+	if report.TotalKeys != 6 || report.TotalBytes != 1465 || report.Cancelled || report.Truncated {
+		t.Fatalf("汇总不正确：%+v", report)
+	}
+	want := []RedisAnalysisProgress{{Scanned: 2, Total: 100, Bytes: 400}, {Scanned: 6, Total: 100, Bytes: 1465}}
+	if !reflect.DeepEqual(progress, want) {
+		t.Fatalf("进度期望 %v，实际 %v", want, progress)
+	}
+
+	// 按字节数降序；无分隔符的键归入无前缀节点，层级以 MaxDepth 为上限
+	if got := prefixSummary(report.Prefixes); !reflect.DeepEqual(got, []string{"order:=1/1000", "user:=3/450", "=1/10", "a:=1/5"}) {
+		t.Fatalf("顶层前缀不正确：%v", got)
+	}
+	if report.Prefixes[2].Name != noPrefixName {
+		t.Fatalf("无前缀节点名称不正确：%q", report.Prefixes[2].Name)
+	}
+	user := report.Prefixes[1]
+	if got := prefixSummary(user.Children); !reflect.DeepEqual(got, []string{"user:1:=2/400", "user:2:=1/50"}) {
+		t.Fatalf("user: 的子前缀不正确：%v", got)
+	}
+	if user.Elements != 5 || user.Children[0].Name != "1" || len(user.Children[0].Children) != 0 {
+		t.Fatalf("user:1: 节点不正确：%+v", user.Children[0])
+	}
+	node := report.Prefixes[3]
+	for _, prefix := range []string{"a:b:", "a:b:c:"} {
+		if len(node.Children) != 1 || node.Children[0].Prefix != prefix {
+			t.Fatalf("期望子前缀 %s，实际 %v", prefix, prefixSummary(node.Children))
+		}
+		node = node.Children[0]
+	}
+	if len(node.Children) != 0 {
+		t.Fatalf("超过 MaxDepth 的层级不应展开：%v", prefixSummary(node.Children))
+	}
+
+	topKeys := make([]string, len(report.TopKeys))
+	for i, stat := range report.TopKeys {
+		topKeys[i] = stat.Key
+	}
+	if !reflect.DeepEqual(topKeys, []string{"order:9", "user:1:tags", "user:1:name"}) {
+		t.Fatalf("最大键期望按字节数降序取前 3 个，实际 %v", topKeys)
+	}
+
+	wantTTL := []RedisTTLBucket{
+		{Label: "永不过期", Keys: 2, Bytes: 110},
+		{Label: "1 小时内", Keys: 1, Bytes: 300},
+		{Label: "1 小时 - 1 天", Keys: 1, Bytes: 50},
+		{Label: "1 - 7 天", Keys: 1, Bytes: 1000},
+		{Label: "7 天以上", Keys: 1, Bytes: 5},
+	}
+	if !reflect.DeepEqual(report.TTL, wantTTL) {
+		t.Fatalf("TTL 分布期望 %v，实际 %v", wantTTL, report.TTL)
+	}
+	wantTypes := []RedisTypeStat{{Type: "hash", Keys: 1, Bytes: 1000}, {Type: "set", Keys: 1, Bytes: 300}, {Type: "string", Keys: 4, Bytes: 165}}
+	if !reflect.DeepEqual(report.Types, wantTypes) {
+		t.Fatalf("类型分布期望 %v，实际 %v", wantTypes, report.Types)
+	}
+}
+
+func TestAnalyzeKeys_StopsAtMaxKeys(t *testing.T) {
+	client := newFakeAnalysisClient()
+	report, err := AnalyzeKeys(context.Background(), client, RedisAnalysisOptions{MaxKeys: 4}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeKeys 返回错误：%v", err)
+	}
+	if !report.Truncated || report.TotalKeys != 4 || report.TotalBytes != 1450 {
+		t.Fatalf("达到 MaxKeys 应截断，实际 keys=%d bytes=%d truncated=%v", report.TotalKeys, report.TotalBytes, report.Truncated)
+	}
+}
+
+func TestAnalyzeKeys_Cancelled(t *testing.T) {
+	client := newFakeAnalysisClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := AnalyzeKeys(ctx, client, RedisAnalysisOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeKeys 返回错误：%v", err)
+	}
+	if !report.Cancelled || report.TotalKeys != 0 || client.scans != 0 {
+		t.Fatalf("已取消时应直接返回部分报告，实际 cancelled=%v keys=%d scans=%d", report.Cancelled, report.TotalKeys, client.scans)
+	}
+}
+
+func TestPrefixNode_MergesExtraChildren(t *testing.T) {
+	agg := newKeyAggregator(normalizeAnalysisOptions(RedisAnalysisOptions{}))
+	extra := 50
+	for i := 0; i < reportPrefixChildren+extra; i++ {
+		agg.add(RedisKeyStat{Key: fmt.Sprintf("p%03d:k", i), Type: "string", TTL: -1, Bytes: int64(1000 - i)})
+	}
+	report := agg.finish()
+	if len(report.Prefixes) != reportPrefixChildren+1 {
+		t.Fatalf("期望保留 %d 个前缀加 1 个合并节点，实际 %d", reportPrefixChildren, len(report.Prefixes))
+	}
+	rest := report.Prefixes[reportPrefixChildren]
+	if rest.Name != fmt.Sprintf("%s %d 个前缀", otherPrefixName, extra) || rest.Keys != int64(extra) {
+		t.Fatalf("合并节点不正确：%+v", rest)
+	}
+	if report.Prefixes[0].Prefix != "p000:" || report.Prefixes[reportPrefixChildren-1].Prefix != fmt.Sprintf("p%03d:", reportPrefixChildren-1) {
+		t.Fatalf("应保留字节数最大的前缀：首个=%s 末个=%s", report.Prefixes[0].Prefix, report.Prefixes[reportPrefixChildren-1].Prefix)
+	}
+}
+
+func TestPrefixNode_ChildLimit(t *testing.T) {
+	root := &RedisPrefixNode{Prefix: "user:"}
+	for i := 0; i < maxPrefixChildren; i++ {
+		root.child(fmt.Sprint(i), fmt.Sprintf("user:%d:", i))
+	}
+	other := root.child("new", "user:new:")
+	if other.Name != otherPrefixName || other.Prefix != "user:" {
+		t.Fatalf("子节点超过上限后应归入其他节点：%+v", other)
+	}
+	if root.child("another", "user:another:") != other || root.child("0", "user:0:") == other {
+		t.Fatalf("超限后新前缀应共用其他节点，已有前缀不受影响")
+	}
+}
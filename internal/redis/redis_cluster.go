@@ -0,0 +1,331 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	clusterSlotCount = 16384
+
+	// A cluster SCAN cursor packs the node cursor and the master index (masters sorted by
+	// address) as nodeCursor<<10 | nodeIndex. Cursor 0 means start, or all masters done.
+	clusterScanNodeBits = 10
+	clusterScanNodeMask = 1<<clusterScanNodeBits - 1
+)
+
+// connectCluster connects to a Redis Cluster. With SSH enabled every node is dialed through
+// the SSH connection, so the node addresses announced by the cluster must be reachable from the SSH host.
+func (r *RedisClientImpl) connectCluster(config connection.ConnectionConfig) error {
+	opts, err := clusterOptions(config, r.tlsConfig)
+	if err != nil {
+		return err
+	}
+	if config.UseSSH {
+		logger.Infof("Redis 集群通过 SSH 隧道连接：SSH=%s:%d", config.SSH.Host, config.SSH.Port)
+	}
+
+	client := redis.NewClusterClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout(config))
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return fmt.Errorf("Redis 集群连接失败: %w", err)
+	}
+
+	r.client = client
+	r.cluster = client
+	r.currentDB = 0
+	logger.Infof("Redis 集群连接成功：种子节点=%s", strings.Join(opts.Addrs, ","))
+	return nil
+}
+
+// clusterOptions builds the cluster client options from the seed nodes
+func clusterOptions(config connection.ConnectionConfig, tlsConfig *tls.Config) (*redis.ClusterOptions, error) {
+	if config.RedisDB != 0 {
+		return nil, fmt.Errorf("Redis 集群只有 db0，不支持切换数据库")
+	}
+	seeds := seedAddrs(config, defaultRedisPort)
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("Redis 集群至少需要一个节点地址")
+	}
+	timeout := redisTimeout(config)
+	opts := &redis.ClusterOptions{
+		Addrs:        seeds,
+		Username:     config.RedisUser,
+		Password:     config.Password,
+		TLSConfig:    tlsConfig,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+	if config.UseSSH {
+		opts.Dialer = sshDialer(config, tlsConfig)
+	}
+	return opts, nil
+}
+
+// clusterMasters returns the master nodes sorted by address, the order SCAN cursors rely on
+func (r *RedisClientImpl) clusterMasters(ctx context.Context) ([]*redis.Client, error) {
+	var mu sync.Mutex
+	masters := make([]*redis.Client, 0)
+	err := r.cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		mu.Lock()
+		masters = append(masters, client)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(masters, func(i, j int) bool {
+		return masters[i].Options().Addr < masters[j].Options().Addr
+	})
+	return masters, nil
+}
+
+// scanCluster scans the masters one after another until count keys are collected
+// or a node has not been fully scanned yet
+func (r *RedisClientImpl) scanCluster(ctx context.Context, pattern string, cursor uint64, count int64) ([]string, uint64, error) {
+	masters, err := r.clusterMasters(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	nodeIndex := int(cursor & clusterScanNodeMask)
+	nodeCursor := cursor >> clusterScanNodeBits
+
+	keys := make([]string, 0, count)
+	for nodeIndex < len(masters) && int64(len(keys)) < count {
+		node := masters[nodeIndex]
+		batch, next, err := node.Scan(ctx, nodeCursor, pattern, count-int64(len(keys))).Result()
+		if err != nil {
+			return nil, 0, fmt.Errorf("扫描节点 %s 失败: %w", node.Options().Addr, err)
+		}
+		keys = append(keys, batch...)
+		if next != 0 {
+			nodeCursor = next
+			break
+		}
+		nodeIndex++
+		nodeCursor = 0
+	}
+	if nodeIndex >= len(masters) {
+		return keys, 0, nil
+	}
+	if nodeCursor > (^uint64(0))>>clusterScanNodeBits {
+		return nil, 0, fmt.Errorf("节点 %s 的 SCAN 游标过大", masters[nodeIndex].Options().Addr)
+	}
+	return keys, nodeCursor<<clusterScanNodeBits | uint64(nodeIndex), nil
+}
+
+// deleteClusterKeys deletes keys grouped by slot to avoid CROSSSLOT errors
+func (r *RedisClientImpl) deleteClusterKeys(ctx context.Context, keys []string) (int64, error) {
+	var deleted int64
+	for _, group := range groupKeysBySlot(keys) {
+		n, err := r.client.Del(ctx, group...).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// groupKeysBySlot groups keys by cluster slot, ordered by slot, keeping the key order within a slot
+func groupKeysBySlot(keys []string) [][]string {
+	groups := make(map[int][]string)
+	for _, key := range keys {
+		slot := keyHashSlot(key)
+		groups[slot] = append(groups[slot], key)
+	}
+	slots := make([]int, 0, len(groups))
+	for slot := range groups {
+		slots = append(slots, slot)
+	}
+	sort.Ints(slots)
+
+	result := make([][]string, 0, len(slots))
+	for _, slot := range slots {
+		result = append(result, groups[slot])
+	}
+	return result
+}
+
+// renameClusterKey renames within a slot with RENAME. Across slots it copies the key with
+// DUMP/RESTORE and then deletes the old key, which is not atomic.
+func (r *RedisClientImpl) renameClusterKey(ctx context.Context, oldKey, newKey string) error {
+	if keyHashSlot(oldKey) == keyHashSlot(newKey) {
+		return r.client.Rename(ctx, oldKey, newKey).Err()
+	}
+	dump, err := r.client.Dump(ctx, oldKey).Result()
+	if err == redis.Nil {
+		return fmt.Errorf("键 %s 不存在", oldKey)
+	}
+	if err != nil {
+		return err
+	}
+	ttl, err := r.client.PTTL(ctx, oldKey).Result()
+	if err != nil {
+		return err
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := r.client.RestoreReplace(ctx, newKey, ttl, dump).Err(); err != nil {
+		return fmt.Errorf("写入新键失败: %w", err)
+	}
+	return r.client.Del(ctx, oldKey).Err()
+}
+
+// clusterNodeInfos reads the INFO of every node, replicas included, indexed by address
+func (r *RedisClientImpl) clusterNodeInfos(ctx context.Context) (map[string]map[string]string, error) {
+	var mu sync.Mutex
+	infos := make(map[string]map[string]string)
+	err := r.cluster.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		text, err := client.Info(ctx).Result()
+		if err != nil {
+			return fmt.Errorf("读取节点 %s 的信息失败: %w", client.Options().Addr, err)
+		}
+		info := make(map[string]string)
+		parseInfo(text, "", info)
+		mu.Lock()
+		infos[client.Options().Addr] = info
+		mu.Unlock()
+		return nil
+	})
+	return infos, err
+}
+
+// clusterServerInfo returns the CLUSTER INFO fields and the INFO of every node keyed as "addr/field"
+func (r *RedisClientImpl) clusterServerInfo(ctx context.Context) (map[string]string, error) {
+	text, err := r.cluster.ClusterInfo(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	parseInfo(text, "", result)
+
+	infos, err := r.clusterNodeInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for addr, info := range infos {
+		for field, value := range info {
+			result[addr+"/"+field] = value
+		}
+	}
+	return result, nil
+}
+
+// GetClusterNodes returns the nodes of a Redis Cluster with their slots and key counts
+func (r *RedisClientImpl) GetClusterNodes() ([]RedisNodeInfo, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	if r.cluster == nil {
+		return nil, fmt.Errorf("当前连接不是集群模式")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	text, err := r.cluster.ClusterNodes(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	nodes := parseClusterNodes(text)
+	infos, err := r.clusterNodeInfos(ctx)
+	if err != nil {
+		logger.Warnf("读取 Redis 集群节点信息失败：%v", err)
+	}
+	for i := range nodes {
+		info, ok := infos[nodes[i].Addr]
+		if !ok {
+			continue
+		}
+		nodes[i].Info = info
+		nodes[i].UsedMemory = info["used_memory_human"]
+		nodes[i].Keys = parseKeyspace(info)[0]
+	}
+	return nodes, nil
+}
+
+// parseClusterNodes parses CLUSTER NODES output, masters first, then by address.
+// Line format: <id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <epoch> <link-state> <slot>...
+func parseClusterNodes(text string) []RedisNodeInfo {
+	nodes := make([]RedisNodeInfo, 0)
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+		addr := fields[1]
+		if i := strings.IndexAny(addr, "@,"); i >= 0 {
+			addr = addr[:i]
+		}
+		node := RedisNodeInfo{
+			ID:        fields[0],
+			Addr:      addr,
+			Flags:     fields[2],
+			LinkState: fields[7],
+			Role:      "replica",
+		}
+		for _, flag := range strings.Split(fields[2], ",") {
+			if flag == "master" {
+				node.Role = "master"
+			}
+		}
+		if fields[3] != "-" {
+			node.MasterID = fields[3]
+		}
+		for _, slot := range fields[8:] {
+			// Skip slots being migrated, e.g. [slot->-node]
+			if !strings.HasPrefix(slot, "[") {
+				node.Slots = append(node.Slots, slot)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Role != nodes[j].Role {
+			return nodes[i].Role == "master"
+		}
+		return nodes[i].Addr < nodes[j].Addr
+	})
+	return nodes
+}
+
+// keyHashSlot returns the cluster slot of a key, hashing only a non-empty {hashtag} if present
+func keyHashSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key)) % clusterSlotCount
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) variant used by Redis Cluster
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"crypto/tls"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestCRC16(t *testing.T) {
+	// Redis Cluster 规范给出的校验值
+	if got := crc16("123456789"); got != 0x31C3 {
+		t.Fatalf("crc16(123456789) 期望 0x31C3，实际 0x%04X", got)
+	}
+	if got := crc16(""); got != 0 {
+		t.Fatalf("crc16 空串期望 0，实际 0x%04X", got)
+	}
+}
+
+func TestKeyHashSlot(t *testing.T) {
+	cases := []struct {
+		key  string
+		want int
+	}{
+		{"foo", 12182},
+		{"bar", 5061},
+		{"123456789", 0x31C3},
+		// 只对第一个非空 {} 内的内容计算
+		{"{user1000}.following", keyHashSlot("user1000")},
+		{"{user1000}.followers", keyHashSlot("user1000")},
+		{"foo{bar}{zap}", keyHashSlot("bar")},
+		{"foo{{bar}}zap", keyHashSlot("{bar")},
+		// 空 {} 或未闭合时对整个键计算
+		{"foo{}{bar}", int(crc16("foo{}{bar}")) % clusterSlotCount},
+		{"foo{bar", int(crc16("foo{bar")) % clusterSlotCount},
+	}
+	for _, c := range cases {
+		if got := keyHashSlot(c.key); got != c.want {
+			t.Fatalf("keyHashSlot(%q) 期望 %d，实际 %d", c.key, c.want, got)
+		}
+	}
+}
+
+func TestGroupKeysBySlot(t *testing.T) {
+	keys := []string{"foo", "{user}:b", "bar", "{user}:a", "foo"}
+	got := groupKeysBySlot(keys)
+
+	// bar=5061 < {user}=5474 < foo=12182
+	want := [][]string{{"bar"}, {"{user}:b", "{user}:a"}, {"foo", "foo"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("按槽位分组期望 %v，实际 %v", want, got)
+	}
+	if len(groupKeysBySlot(nil)) != 0 {
+		t.Fatalf("空键列表应返回空分组")
+	}
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	text := "07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004,host-4 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected\n" +
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 [5461->-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]\n" +
+		"67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922\n" +
+		"\n"
+	nodes := parseClusterNodes(text)
+	if len(nodes) != 3 {
+		t.Fatalf("期望 3 个节点，实际 %d", len(nodes))
+	}
+	if nodes[0].Addr != "127.0.0.1:30001" || nodes[0].Role != "master" || nodes[0].MasterID != "" {
+		t.Fatalf("第一个节点应为地址最小的主节点：%+v", nodes[0])
+	}
+	if !reflect.DeepEqual(nodes[0].Slots, []string{"0-5460"}) {
+		t.Fatalf("迁移中的槽位应被跳过，实际 %v", nodes[0].Slots)
+	}
+	if nodes[1].Addr != "127.0.0.1:30002" || nodes[1].Role != "master" {
+		t.Fatalf("第二个节点应为主节点 30002：%+v", nodes[1])
+	}
+	replica := nodes[2]
+	if replica.Addr != "127.0.0.1:30004" || replica.Role != "replica" || replica.MasterID != "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca" || replica.LinkState != "connected" {
+		t.Fatalf("从节点解析不正确：%+v", replica)
+	}
+}
+
+func TestClusterOptions(t *testing.T) {
+	tlsConfig := &tls.Config{}
+	config := connection.ConnectionConfig{
+		Topology:  "cluster",
+		Hosts:     []string{"n1:7000", "n2"},
+		RedisUser: "app",
+		Password:  "secret",
+	}
+	opts, err := clusterOptions(config, tlsConfig)
+	if err != nil {
+		t.Fatalf("clusterOptions 返回错误：%v", err)
+	}
+	if !reflect.DeepEqual(opts.Addrs, []string{"n1:7000", "n2:6379"}) || opts.Username != "app" || opts.Password != "secret" {
+		t.Fatalf("集群连接参数不正确：%+v", opts)
+	}
+	if opts.TLSConfig != tlsConfig || opts.Dialer != nil {
+		t.Fatalf("TLS 或拨号器不正确：%+v", opts)
+	}
+
+	config.RedisDB = 1
+	if _, err := clusterOptions(config, nil); err == nil || !strings.Contains(err.Error(), "db0") {
+		t.Fatalf("集群模式切换数据库应返回错误，实际=%v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// collectionPageSize is how many elements GetValue returns for a hash, set or sorted set before
+// switching to cursor paging, and the default page size of ScanHash/ScanSet/ScanZSet
+const collectionPageSize = 1000
+
+// maxCollectionScanRounds bounds the *SCAN calls per page, so a MATCH that filters out most
+// elements returns early with a non-zero cursor instead of walking the whole collection
+const maxCollectionScanRounds = 20
+
+type collectionScanFunc func(ctx context.Context, key string, cursor uint64, match string, count int64) *redis.ScanCmd
+
+// scanCollection repeats scan until count elements are collected or the scan completes.
+// HSCAN and ZSCAN reply with field/value pairs, so stride is 2 for them.
+func scanCollection(ctx context.Context, scan collectionScanFunc, key, pattern string, cursor uint64, count int64, stride int) ([]string, uint64, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if count <= 0 {
+		count = collectionPageSize
+	}
+	items := make([]string, 0, int(count)*stride)
+	for round := 0; round < maxCollectionScanRounds; round++ {
+		batch, next, err := scan(ctx, key, cursor, pattern, count).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, batch...)
+		cursor = next
+		if cursor == 0 || int64(len(items)/stride) >= count {
+			break
+		}
+	}
+	return items, cursor, nil
+}
+
+func (r *RedisClientImpl) hashPage(ctx context.Context, key, pattern string, cursor uint64, count int64) (map[string]string, uint64, error) {
+	items, next, err := scanCollection(ctx, r.client.HScan, key, pattern, cursor, count, 2)
+	if err != nil {
+		return nil, 0, err
+	}
+	fields := make(map[string]string, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		fields[items[i]] = items[i+1]
+	}
+	return fields, next, nil
+}
+
+func (r *RedisClientImpl) setPage(ctx context.Context, key, pattern string, cursor uint64, count int64) ([]string, uint64, error) {
+	items, next, err := scanCollection(ctx, r.client.SScan, key, pattern, cursor, count, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	// SSCAN may return an element more than once
+	seen := make(map[string]bool, len(items))
+	members := make([]string, 0, len(items))
+	for _, member := range items {
+		if !seen[member] {
+			seen[member] = true
+			members = append(members, member)
+		}
+	}
+	return members, next, nil
+}
+
+func (r *RedisClientImpl) zsetPage(ctx context.Context, key, pattern string, cursor uint64, count int64) ([]ZSetMember, uint64, error) {
+	items, next, err := scanCollection(ctx, r.client.ZScan, key, pattern, cursor, count, 2)
+	if err != nil {
+		return nil, 0, err
+	}
+	seen := make(map[string]bool, len(items)/2)
+	members := make([]ZSetMember, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		if seen[items[i]] {
+			continue
+		}
+		seen[items[i]] = true
+		score, err := strconv.ParseFloat(items[i+1], 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("解析成员 %s 的分数失败: %w", items[i], err)
+		}
+		members = append(members, ZSetMember{Member: items[i], Score: score})
+	}
+	return members, next, nil
+}
+
+// ScanHash returns one HSCAN page of a hash together with its HLEN
+func (r *RedisClientImpl) ScanHash(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	length, err := r.client.HLen(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	fields, next, err := r.hashPage(ctx, key, pattern, cursor, count)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCollectionPage{Type: "hash", Value: fields, Cursor: next, Length: length}, nil
+}
+
+// ScanSet returns one SSCAN page of a set together with its SCARD
+func (r *RedisClientImpl) ScanSet(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	length, err := r.client.SCard(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	members, next, err := r.setPage(ctx, key, pattern, cursor, count)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCollectionPage{Type: "set", Value: members, Cursor: next, Length: length}, nil
+}
+
+// ScanZSet returns one ZSCAN page of a sorted set together with its ZCARD
+func (r *RedisClientImpl) ScanZSet(key, pattern string, cursor uint64, count int64) (*RedisCollectionPage, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	length, err := r.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	members, next, err := r.zsetPage(ctx, key, pattern, cursor, count)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCollectionPage{Type: "zset", Value: members, Cursor: next, Length: length}, nil
+}
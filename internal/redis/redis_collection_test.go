@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanPage 是 fakeScan 按调用顺序返回的一页结果。
+type scanPage struct {
+	items []string
+	next  uint64
+}
+
+type scanCall struct {
+	cursor uint64
+	match  string
+	count  int64
+}
+
+// fakeScan 依次返回 pages，并记录每次调用的参数。
+type fakeScan struct {
+	pages []scanPage
+	calls []scanCall
+	err   error
+}
+
+func (f *fakeScan) scan(ctx context.Context, key string, cursor uint64, match string, count int64) *redis.ScanCmd {
+	f.calls = append(f.calls, scanCall{cursor, match, count})
+	if f.err != nil {
+		return redis.NewScanCmdResult(nil, 0, f.err)
+	}
+	page := f.pages[len(f.calls)-1]
+	return redis.NewScanCmdResult(page.items, page.next, nil)
+}
+
+func TestScanCollection_StopsWhenPageFilled(t *testing.T) {
+	f := &fakeScan{pages: []scanPage{
+		{[]string{"f1", "v1"}, 5},
+		{nil, 9},
+		{[]string{"f2", "v2", "f3", "v3"}, 12},
+		{[]string{"f4", "v4"}, 0},
+	}}
+	items, next, err := scanCollection(context.Background(), f.scan, "h", "", 0, 3, 2)
+	if err != nil {
+		t.Fatalf("scanCollection 返回错误：%v", err)
+	}
+	// HSCAN 按字段/值成对计数，凑满 3 个字段即返回下一页游标
+	if !reflect.DeepEqual(items, []string{"f1", "v1", "f2", "v2", "f3", "v3"}) || next != 12 {
+		t.Fatalf("期望 3 个字段与游标 12，实际 items=%v next=%d", items, next)
+	}
+	want := []scanCall{{0, "*", 3}, {5, "*", 3}, {9, "*", 3}}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("SCAN 调用参数期望 %v，实际 %v", want, f.calls)
+	}
+}
+
+func TestScanCollection_CompletesAtZeroCursor(t *testing.T) {
+	f := &fakeScan{pages: []scanPage{{[]string{"a"}, 7}, {[]string{"b"}, 0}}}
+	items, next, err := scanCollection(context.Background(), f.scan, "s", "a*", 7, 0, 1)
+	if err != nil {
+		t.Fatalf("scanCollection 返回错误：%v", err)
+	}
+	if !reflect.DeepEqual(items, []string{"a", "b"}) || next != 0 {
+		t.Fatalf("期望扫描完成并返回游标 0，实际 items=%v next=%d", items, next)
+	}
+	if f.calls[0].cursor != 7 || f.calls[0].match != "a*" || f.calls[0].count != collectionPageSize {
+		t.Fatalf("应从传入游标开始并使用默认页大小，实际 %+v", f.calls[0])
+	}
+}
+
+func TestScanCollection_BoundsRounds(t *testing.T) {
+	pages := make([]scanPage, maxCollectionScanRounds+1)
+	for i := range pages {
+		pages[i] = scanPage{nil, uint64(i + 1)}
+	}
+	f := &fakeScan{pages: pages}
+	items, next, err := scanCollection(context.Background(), f.scan, "z", "nomatch*", 0, 10, 2)
+	if err != nil {
+		t.Fatalf("scanCollection 返回错误：%v", err)
+	}
+	// MATCH 过滤掉大部分元素时在轮数上限处提前返回，游标非 0 表示还有后续
+	if len(items) != 0 || next != maxCollectionScanRounds || len(f.calls) != maxCollectionScanRounds {
+		t.Fatalf("期望 %d 轮后返回游标 %d，实际 items=%v next=%d calls=%d", maxCollectionScanRounds, maxCollectionScanRounds, items, next, len(f.calls))
+	}
+}
+
+func TestScanCollection_ReturnsError(t *testing.T) {
+	f := &fakeScan{err: errors.New("WRONGTYPE")}
+	if _, _, err := scanCollection(context.Background(), f.scan, "k", "", 0, 10, 1); err == nil || err.Error() != "WRONGTYPE" {
+		t.Fatalf("SCAN 错误应原样返回，实际=%v", err)
+	}
+}
@@ -2,9 +2,11 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"GoNavi-Wails/internal/connection"
@@ -16,10 +18,12 @@ import (
 
 // RedisClientImpl implements RedisClient using go-redis
 type RedisClientImpl struct {
-	client    *redis.Client
+	client    redis.UniversalClient
+	cluster   *redis.ClusterClient // 集群模式下与 client 相同，单机模式为 nil
 	config    connection.ConnectionConfig
 	currentDB int
 	forwarder *ssh.LocalForwarder
+	tlsConfig *tls.Config
 }
 
 // NewRedisClient creates a new Redis client instance
@@ -31,48 +35,37 @@ func NewRedisClient() RedisClient {
 func (r *RedisClientImpl) Connect(config connection.ConnectionConfig) error {
 	r.config = config
 	r.currentDB = config.RedisDB
+	r.cluster = nil
+	r.forwarder = nil
 
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return err
+	}
+	r.tlsConfig = tlsConfig
 
-	// Handle SSH tunnel if enabled
-	if config.UseSSH {
+	if isClusterTopology(config) {
+		return r.connectCluster(config)
+	}
+
+	// Handle SSH tunnel if enabled. Sentinel mode dials the sentinels and the
+	// discovered master through the SSH connection instead.
+	if config.UseSSH && !isSentinelTopology(config) {
 		forwarder, err := ssh.GetOrCreateLocalForwarder(config.SSH, config.Host, config.Port)
 		if err != nil {
 			return fmt.Errorf("创建 SSH 隧道失败: %w", err)
 		}
 		r.forwarder = forwarder
-		addr = forwarder.LocalAddr
-		logger.Infof("Redis 通过 SSH 隧道连接: %s -> %s:%d", addr, config.Host, config.Port)
+		logger.Infof("Redis 通过 SSH 隧道连接: %s -> %s:%d", forwarder.LocalAddr, config.Host, config.Port)
 	}
 
-	opts := &redis.Options{
-		Addr:         addr,
-		Password:     config.Password,
-		DB:           config.RedisDB,
-		DialTimeout:  time.Duration(config.Timeout) * time.Second,
-		ReadTimeout:  time.Duration(config.Timeout) * time.Second,
-		WriteTimeout: time.Duration(config.Timeout) * time.Second,
-	}
-
-	if opts.DialTimeout == 0 {
-		opts.DialTimeout = 30 * time.Second
-		opts.ReadTimeout = 30 * time.Second
-		opts.WriteTimeout = 30 * time.Second
-	}
-
-	r.client = redis.NewClient(opts)
-
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
-	defer cancel()
-
-	if err := r.client.Ping(ctx).Err(); err != nil {
-		r.client.Close()
-		r.client = nil
+	client, desc, err := r.newClient(config.RedisDB)
+	if err != nil {
 		return fmt.Errorf("Redis 连接失败: %w", err)
 	}
+	r.client = client
 
-	logger.Infof("Redis 连接成功: %s DB=%d", addr, config.RedisDB)
+	logger.Infof("Redis 连接成功: %s DB=%d", desc, config.RedisDB)
 	return nil
 }
 
@@ -81,6 +74,7 @@ func (r *RedisClientImpl) Close() error {
 	if r.client != nil {
 		err := r.client.Close()
 		r.client = nil
+		r.cluster = nil
 		return err
 	}
 	return nil
@@ -112,7 +106,14 @@ func (r *RedisClientImpl) ScanKeys(pattern string, cursor uint64, count int64) (
 		count = 100
 	}
 
-	keys, nextCursor, err := r.client.Scan(ctx, cursor, pattern, count).Result()
+	var keys []string
+	var nextCursor uint64
+	var err error
+	if r.cluster != nil {
+		keys, nextCursor, err = r.scanCluster(ctx, pattern, cursor, count)
+	} else {
+		keys, nextCursor, err = r.client.Scan(ctx, cursor, pattern, count).Result()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -218,6 +219,9 @@ func (r *RedisClientImpl) DeleteKeys(keys []string) (int64, error) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	if r.cluster != nil {
+		return r.deleteClusterKeys(ctx, keys)
+	}
 	return r.client.Del(ctx, keys...).Result()
 }
 
@@ -228,6 +232,9 @@ func (r *RedisClientImpl) RenameKey(oldKey, newKey string) error {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	if r.cluster != nil {
+		return r.renameClusterKey(ctx, oldKey, newKey)
+	}
 	return r.client.Rename(ctx, oldKey, newKey).Err()
 }
 
@@ -273,12 +280,24 @@ func (r *RedisClientImpl) GetValue(key string) (*RedisValue, error) {
 		result.Length = int64(len(val))
 
 	case "hash":
+		length, err := r.client.HLen(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		result.Length = length
+		// Large hashes only return the first HSCAN page
+		if length > collectionPageSize {
+			result.Value, result.Cursor, err = r.hashPage(ctx, key, "*", 0, collectionPageSize)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
 		val, err := r.client.HGetAll(ctx, key).Result()
 		if err != nil {
 			return nil, err
 		}
 		result.Value = val
-		result.Length = int64(len(val))
 
 	case "list":
 		length, err := r.client.LLen(ctx, key).Result()
@@ -302,25 +321,37 @@ func (r *RedisClientImpl) GetValue(key string) (*RedisValue, error) {
 		if err != nil {
 			return nil, err
 		}
-		// Get members using SMembers (limited by Redis server)
+		result.Length = length
+		// Large sets only return the first SSCAN page
+		if length > collectionPageSize {
+			result.Value, result.Cursor, err = r.setPage(ctx, key, "*", 0, collectionPageSize)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
 		members, err := r.client.SMembers(ctx, key).Result()
 		if err != nil {
 			return nil, err
 		}
 		result.Value = members
-		result.Length = length
 
 	case "zset":
 		length, err := r.client.ZCard(ctx, key).Result()
 		if err != nil {
 			return nil, err
 		}
-		// Get first 1000 members with scores
-		limit := int64(1000)
-		if length < limit {
-			limit = length
+		// Large sorted sets only return the first ZSCAN page, so that further
+		// pages can continue from its cursor
+		if length > collectionPageSize {
+			result.Value, result.Cursor, err = r.zsetPage(ctx, key, "*", 0, collectionPageSize)
+			if err != nil {
+				return nil, err
+			}
+			result.Length = length
+			break
 		}
-		val, err := r.client.ZRangeWithScores(ctx, key, 0, limit-1).Result()
+		val, err := r.client.ZRangeWithScores(ctx, key, 0, -1).Result()
 		if err != nil {
 			return nil, err
 		}
@@ -670,11 +701,17 @@ func formatCommandResult(result interface{}) interface{} {
 	}
 }
 
-// GetServerInfo returns server information
+// GetServerInfo returns server information. In cluster mode it returns the CLUSTER INFO
+// fields plus the INFO of every node, keyed as "addr/field".
 func (r *RedisClientImpl) GetServerInfo() (map[string]string, error) {
 	if r.client == nil {
 		return nil, fmt.Errorf("Redis 客户端未连接")
 	}
+	if r.cluster != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return r.clusterServerInfo(ctx)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -684,6 +721,12 @@ func (r *RedisClientImpl) GetServerInfo() (map[string]string, error) {
 	}
 
 	result := make(map[string]string)
+	parseInfo(info, "", result)
+	return result, nil
+}
+
+// parseInfo parses INFO-style "field:value" lines into result, prefixing each field
+func parseInfo(info string, prefix string, result map[string]string) {
 	lines := strings.Split(info, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -692,10 +735,32 @@ func (r *RedisClientImpl) GetServerInfo() (map[string]string, error) {
 		}
 		parts := strings.SplitN(line, ":", 2)
 		if len(parts) == 2 {
-			result[parts[0]] = parts[1]
+			result[prefix+parts[0]] = parts[1]
 		}
 	}
-	return result, nil
+}
+
+// parseKeyspace returns the key count of each database from parsed INFO keyspace fields
+func parseKeyspace(info map[string]string) map[int]int64 {
+	dbMap := make(map[int]int64)
+	for field, value := range info {
+		if !strings.HasPrefix(field, "db") {
+			continue
+		}
+		// Format: db0:keys=123,expires=0,avg_ttl=0
+		dbIndex, err := strconv.Atoi(strings.TrimPrefix(field, "db"))
+		if err != nil {
+			continue
+		}
+		for _, kv := range strings.Split(value, ",") {
+			if strings.HasPrefix(kv, "keys=") {
+				keys, _ := strconv.ParseInt(strings.TrimPrefix(kv, "keys="), 10, 64)
+				dbMap[dbIndex] = keys
+				break
+			}
+		}
+	}
+	return dbMap
 }
 
 // GetDatabases returns information about all databases
@@ -706,39 +771,35 @@ func (r *RedisClientImpl) GetDatabases() ([]RedisDBInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Get keyspace info
-	info, err := r.client.Info(ctx, "keyspace").Result()
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse keyspace info
-	dbMap := make(map[int]int64)
-	lines := strings.Split(info, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "db") {
-			// Format: db0:keys=123,expires=0,avg_ttl=0
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) != 2 {
-				continue
-			}
-			dbIndex, err := strconv.Atoi(strings.TrimPrefix(parts[0], "db"))
+	// Cluster mode only has db0, whose size is the sum over all masters
+	if r.cluster != nil {
+		var mu sync.Mutex
+		var total int64
+		err := r.cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			n, err := client.DBSize(ctx).Result()
 			if err != nil {
-				continue
-			}
-			// Parse keys count
-			kvPairs := strings.Split(parts[1], ",")
-			for _, kv := range kvPairs {
-				if strings.HasPrefix(kv, "keys=") {
-					keys, _ := strconv.ParseInt(strings.TrimPrefix(kv, "keys="), 10, 64)
-					dbMap[dbIndex] = keys
-					break
-				}
+				return err
 			}
+			mu.Lock()
+			total += n
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		return []RedisDBInfo{{Index: 0, Keys: total}}, nil
 	}
 
+	// Get keyspace info
+	text, err := r.client.Info(ctx, "keyspace").Result()
+	if err != nil {
+		return nil, err
+	}
+	info := make(map[string]string)
+	parseInfo(text, "", info)
+	dbMap := parseKeyspace(info)
+
 	// Return all 16 databases (0-15)
 	result := make([]RedisDBInfo, 16)
 	for i := 0; i < 16; i++ {
@@ -756,38 +817,18 @@ func (r *RedisClientImpl) SelectDB(index int) error {
 	if r.client == nil {
 		return fmt.Errorf("Redis 客户端未连接")
 	}
+	if r.cluster != nil {
+		if index == 0 {
+			return nil
+		}
+		return fmt.Errorf("集群模式不支持切换数据库")
+	}
 	if index < 0 || index > 15 {
 		return fmt.Errorf("数据库索引必须在 0-15 之间")
 	}
 
-	// Create new client with different DB
-	addr := fmt.Sprintf("%s:%d", r.config.Host, r.config.Port)
-	if r.forwarder != nil {
-		addr = r.forwarder.LocalAddr
-	}
-
-	opts := &redis.Options{
-		Addr:         addr,
-		Password:     r.config.Password,
-		DB:           index,
-		DialTimeout:  time.Duration(r.config.Timeout) * time.Second,
-		ReadTimeout:  time.Duration(r.config.Timeout) * time.Second,
-		WriteTimeout: time.Duration(r.config.Timeout) * time.Second,
-	}
-
-	if opts.DialTimeout == 0 {
-		opts.DialTimeout = 30 * time.Second
-		opts.ReadTimeout = 30 * time.Second
-		opts.WriteTimeout = 30 * time.Second
-	}
-
-	newClient := redis.NewClient(opts)
-
-	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
-	defer cancel()
-
-	if err := newClient.Ping(ctx).Err(); err != nil {
-		newClient.Close()
+	newClient, _, err := r.newClient(index)
+	if err != nil {
 		return fmt.Errorf("切换数据库失败: %w", err)
 	}
 
@@ -812,5 +853,10 @@ func (r *RedisClientImpl) FlushDB() error {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	if r.cluster != nil {
+		return r.cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.FlushDB(ctx).Err()
+		})
+	}
 	return r.client.FlushDB(ctx).Err()
 }
@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/ssh"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRedisPort    = 6379
+	defaultSentinelPort = 26379
+)
+
+func isClusterTopology(config connection.ConnectionConfig) bool {
+	return strings.EqualFold(strings.TrimSpace(config.Topology), "cluster")
+}
+
+func isSentinelTopology(config connection.ConnectionConfig) bool {
+	return strings.EqualFold(strings.TrimSpace(config.Topology), "sentinel")
+}
+
+// redisTimeout returns the dial/read/write timeout, 30 seconds by default
+func redisTimeout(config connection.ConnectionConfig) time.Duration {
+	if config.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(config.Timeout) * time.Second
+}
+
+// seedAddrs returns the cluster seed nodes or sentinel addresses, falling back to Host:Port when Hosts is empty
+func seedAddrs(config connection.ConnectionConfig, defaultPort int) []string {
+	candidates := config.Hosts
+	if len(candidates) == 0 {
+		candidates = []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
+	}
+	addrs := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, entry := range candidates {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil {
+			host, port = entry, strconv.Itoa(defaultPort)
+		}
+		addr := net.JoinHostPort(host, port)
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// buildTLSConfig builds the TLS configuration, nil when TLS is disabled
+func buildTLSConfig(config connection.ConnectionConfig) (*tls.Config, error) {
+	if !config.UseTLS {
+		return nil, nil
+	}
+	opts := config.TLS
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         strings.TrimSpace(opts.ServerName),
+		InsecureSkipVerify: opts.SkipVerify,
+	}
+
+	if caPath := strings.TrimSpace(opts.CAPath); caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书无效：%s", caPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPath, keyPath := strings.TrimSpace(opts.CertPath), strings.TrimSpace(opts.KeyPath)
+	if certPath != "" || keyPath != "" {
+		if certPath == "" || keyPath == "" {
+			return nil, fmt.Errorf("客户端证书与私钥需同时填写")
+		}
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// withServerName returns tlsConfig verifying host when no server name is configured.
+// Connections through an SSH tunnel dial a local or forwarded address, so the name
+// has to come from the original host.
+func withServerName(tlsConfig *tls.Config, host string) *tls.Config {
+	if tlsConfig == nil || tlsConfig.ServerName != "" {
+		return tlsConfig
+	}
+	clone := tlsConfig.Clone()
+	clone.ServerName = host
+	return clone
+}
+
+// sshDialer dials every address through the SSH connection, wrapping it in TLS when enabled.
+// Used for cluster and sentinel mode, where node addresses are only known after connecting.
+func sshDialer(config connection.ConnectionConfig, tlsConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	sshConfig := config.SSH
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := ssh.DialThroughSSH(sshConfig, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			return conn, nil
+		}
+		host, _, _ := net.SplitHostPort(addr)
+		tlsConn := tls.Client(conn, withServerName(tlsConfig, host))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS 握手失败: %w", err)
+		}
+		return tlsConn, nil
+	}
+}
+
+// sentinelOptions builds the sentinel client options for database db
+func (r *RedisClientImpl) sentinelOptions(db int) (*redis.FailoverOptions, error) {
+	config := r.config
+	masterName := strings.TrimSpace(config.SentinelMaster)
+	if masterName == "" {
+		return nil, fmt.Errorf("请填写 Sentinel 主节点名称")
+	}
+	sentinels := seedAddrs(config, defaultSentinelPort)
+	if len(sentinels) == 0 {
+		return nil, fmt.Errorf("Redis Sentinel 至少需要一个哨兵地址")
+	}
+	timeout := redisTimeout(config)
+	opts := &redis.FailoverOptions{
+		MasterName:       masterName,
+		SentinelAddrs:    sentinels,
+		SentinelPassword: config.SentinelPassword,
+		Username:         config.RedisUser,
+		Password:         config.Password,
+		DB:               db,
+		TLSConfig:        r.tlsConfig,
+		DialTimeout:      timeout,
+		ReadTimeout:      timeout,
+		WriteTimeout:     timeout,
+	}
+	if config.UseSSH {
+		opts.Dialer = sshDialer(config, r.tlsConfig)
+	}
+	return opts, nil
+}
+
+// singleOptions builds the single-node client options for database db, dialing the local
+// end of the SSH tunnel when one is open
+func (r *RedisClientImpl) singleOptions(db int) *redis.Options {
+	config := r.config
+	timeout := redisTimeout(config)
+	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	tlsConfig := r.tlsConfig
+	if r.forwarder != nil {
+		addr = r.forwarder.LocalAddr
+		tlsConfig = withServerName(tlsConfig, config.Host)
+	}
+	return &redis.Options{
+		Addr:         addr,
+		Username:     config.RedisUser,
+		Password:     config.Password,
+		DB:           db,
+		TLSConfig:    tlsConfig,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+}
+
+// newClient creates and pings a single-node or sentinel client for database db
+func (r *RedisClientImpl) newClient(db int) (redis.UniversalClient, string, error) {
+	var client redis.UniversalClient
+	var desc string
+	if isSentinelTopology(r.config) {
+		opts, err := r.sentinelOptions(db)
+		if err != nil {
+			return nil, "", err
+		}
+		client = redis.NewFailoverClient(opts)
+		desc = fmt.Sprintf("Sentinel 主节点=%s 哨兵=%s", opts.MasterName, strings.Join(opts.SentinelAddrs, ","))
+	} else {
+		opts := r.singleOptions(db)
+		client = redis.NewClient(opts)
+		desc = opts.Addr
+	}
+	if r.tlsConfig != nil {
+		desc += " TLS"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout(r.config))
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, "", err
+	}
+	return client, desc, nil
+}
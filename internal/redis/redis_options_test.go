@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/ssh"
+)
+
+func TestSeedAddrs(t *testing.T) {
+	cases := []struct {
+		config connection.ConnectionConfig
+		want   []string
+	}{
+		{connection.ConnectionConfig{Host: "10.0.0.1", Port: 7000}, []string{"10.0.0.1:7000"}},
+		{connection.ConnectionConfig{Host: "ignored", Port: 1, Hosts: []string{"a:7001", " b ", "", "a:7001", "::1", "[::1]:7002"}},
+			[]string{"a:7001", "b:26379", "[::1]:26379", "[::1]:7002"}},
+	}
+	for _, c := range cases {
+		if got := seedAddrs(c.config, defaultSentinelPort); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("seedAddrs(%v) 期望 %v，实际 %v", c.config.Hosts, c.want, got)
+		}
+	}
+}
+
+// writeTestCert 生成自签名证书与私钥，返回 PEM 文件路径。
+func writeTestCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败：%v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("生成证书失败：%v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("编码私钥失败：%v", err)
+	}
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("写入证书失败：%v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("写入私钥失败：%v", err)
+	}
+	return certPath, keyPath
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	if cfg, err := buildTLSConfig(connection.ConnectionConfig{TLS: connection.TLSConfig{ServerName: "x"}}); err != nil || cfg != nil {
+		t.Fatalf("未启用 TLS 时应返回 nil，实际=%v err=%v", cfg, err)
+	}
+
+	cfg, err := buildTLSConfig(connection.ConnectionConfig{UseTLS: true, TLS: connection.TLSConfig{ServerName: " redis.local ", SkipVerify: true}})
+	if err != nil {
+		t.Fatalf("buildTLSConfig 返回错误：%v", err)
+	}
+	if cfg.ServerName != "redis.local" || !cfg.InsecureSkipVerify || cfg.MinVersion != tls.VersionTLS12 || cfg.RootCAs != nil || len(cfg.Certificates) != 0 {
+		t.Fatalf("TLS 配置不正确：%+v", cfg)
+	}
+
+	certPath, keyPath := writeTestCert(t)
+	cfg, err = buildTLSConfig(connection.ConnectionConfig{UseTLS: true, TLS: connection.TLSConfig{CAPath: certPath, CertPath: certPath, KeyPath: keyPath}})
+	if err != nil {
+		t.Fatalf("加载证书返回错误：%v", err)
+	}
+	if cfg.RootCAs == nil || len(cfg.Certificates) != 1 {
+		t.Fatalf("应加载 CA 与客户端证书：%+v", cfg)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a pem"), 0o600); err != nil {
+		t.Fatalf("写入文件失败：%v", err)
+	}
+	errCases := []struct {
+		tls  connection.TLSConfig
+		want string
+	}{
+		{connection.TLSConfig{CAPath: filepath.Join(t.TempDir(), "missing.pem")}, "读取 CA 证书失败"},
+		{connection.TLSConfig{CAPath: invalid}, "CA 证书无效"},
+		{connection.TLSConfig{CertPath: certPath}, "客户端证书与私钥需同时填写"},
+		{connection.TLSConfig{CertPath: invalid, KeyPath: keyPath}, "加载客户端证书失败"},
+	}
+	for _, c := range errCases {
+		_, err := buildTLSConfig(connection.ConnectionConfig{UseTLS: true, TLS: c.tls})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("TLS 配置 %+v 期望错误包含 %q，实际=%v", c.tls, c.want, err)
+		}
+	}
+}
+
+func TestWithServerName(t *testing.T) {
+	if withServerName(nil, "redis.local") != nil {
+		t.Fatalf("未启用 TLS 时应返回 nil")
+	}
+	named := &tls.Config{ServerName: "configured"}
+	if withServerName(named, "redis.local") != named {
+		t.Fatalf("已配置 ServerName 时应原样返回")
+	}
+	base := &tls.Config{}
+	got := withServerName(base, "redis.local")
+	if got.ServerName != "redis.local" || base.ServerName != "" {
+		t.Fatalf("应在副本上设置 ServerName，实际 got=%q base=%q", got.ServerName, base.ServerName)
+	}
+}
+
+func TestSentinelOptions(t *testing.T) {
+	tlsConfig := &tls.Config{}
+	r := &RedisClientImpl{
+		config: connection.ConnectionConfig{
+			Topology:         "sentinel",
+			Hosts:            []string{"s1:26380", "s2"},
+			SentinelMaster:   " mymaster ",
+			SentinelPassword: "sentinel-pass",
+			RedisUser:        "app",
+			Password:         "secret",
+			Timeout:          5,
+		},
+		tlsConfig: tlsConfig,
+	}
+	opts, err := r.sentinelOptions(3)
+	if err != nil {
+		t.Fatalf("sentinelOptions 返回错误：%v", err)
+	}
+	if opts.MasterName != "mymaster" || !reflect.DeepEqual(opts.SentinelAddrs, []string{"s1:26380", "s2:26379"}) {
+		t.Fatalf("哨兵地址或主节点名不正确：%+v", opts)
+	}
+	if opts.SentinelPassword != "sentinel-pass" || opts.Username != "app" || opts.Password != "secret" || opts.DB != 3 {
+		t.Fatalf("认证信息或数据库不正确：%+v", opts)
+	}
+	if opts.TLSConfig != tlsConfig || opts.DialTimeout != 5*time.Second || opts.Dialer != nil {
+		t.Fatalf("TLS、超时或拨号器不正确：%+v", opts)
+	}
+
+	r.config.UseSSH = true
+	if opts, err = r.sentinelOptions(0); err != nil || opts.Dialer == nil {
+		t.Fatalf("启用 SSH 时应通过 SSH 拨号，err=%v", err)
+	}
+
+	r.config.SentinelMaster = " "
+	if _, err := r.sentinelOptions(0); err == nil || !strings.Contains(err.Error(), "主节点名称") {
+		t.Fatalf("缺少主节点名称应返回错误，实际=%v", err)
+	}
+}
+
+func TestSingleOptions(t *testing.T) {
+	r := &RedisClientImpl{
+		config:    connection.ConnectionConfig{Host: "redis.local", Port: 6380, RedisUser: "app", Password: "secret"},
+		tlsConfig: &tls.Config{},
+	}
+	opts := r.singleOptions(2)
+	if opts.Addr != "redis.local:6380" || opts.Username != "app" || opts.Password != "secret" || opts.DB != 2 {
+		t.Fatalf("单机连接参数不正确：%+v", opts)
+	}
+	if opts.TLSConfig != r.tlsConfig || opts.ReadTimeout != 30*time.Second {
+		t.Fatalf("TLS 或默认超时不正确：%+v", opts)
+	}
+
+	// 经 SSH 隧道时连接本地转发地址，并以原始主机名校验证书
+	r.forwarder = &ssh.LocalForwarder{LocalAddr: "127.0.0.1:50000"}
+	opts = r.singleOptions(0)
+	if opts.Addr != "127.0.0.1:50000" || opts.TLSConfig.ServerName != "redis.local" {
+		t.Fatalf("SSH 隧道连接参数不正确：addr=%s serverName=%q", opts.Addr, opts.TLSConfig.ServerName)
+	}
+}
@@ -0,0 +1,231 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyspaceEventClasses maps common notification events to their notify-keyspace-events class flag.
+// Events not listed here require all classes (A).
+var keyspaceEventClasses = map[string]byte{
+	"expired": 'x', "evicted": 'e', "new": 'n',
+	"set": '$', "setrange": '$', "append": '$', "incrby": '$', "incrbyfloat": '$',
+	"del": 'g', "expire": 'g', "persist": 'g', "rename_from": 'g', "rename_to": 'g', "copy_to": 'g', "restore": 'g',
+	"lpush": 'l', "rpush": 'l', "lpop": 'l', "rpop": 'l', "lset": 'l', "linsert": 'l', "ltrim": 'l',
+	"hset": 'h', "hdel": 'h', "hincrby": 'h', "hincrbyfloat": 'h',
+	"sadd": 's', "srem": 's', "spop": 's',
+	"zadd": 'z', "zrem": 'z', "zincr": 'z',
+	"xadd": 't', "xdel": 't', "xtrim": 't',
+}
+
+// keyspaceAllClasses lists the classes included in the A alias
+const keyspaceAllClasses = "g$lshzxetd"
+
+// RedisSubscribeOptions describes what a subscription listens to
+type RedisSubscribeOptions struct {
+	Channels     []string `json:"channels"`     // SUBSCRIBE channels
+	Patterns     []string `json:"patterns"`     // PSUBSCRIBE patterns
+	KeyEvents    []string `json:"keyEvents"`    // Keyevent notifications, e.g. expired, evicted, set; * for all
+	KeyPattern   string   `json:"keyPattern"`   // Keyspace notifications for keys matching this pattern
+	EnableNotify bool     `json:"enableNotify"` // Add the missing flags to notify-keyspace-events before subscribing
+}
+
+// RedisMessage is a message received by a subscription
+type RedisMessage struct {
+	Channel string `json:"channel"`
+	Pattern string `json:"pattern,omitempty"` // Matching pattern of a PSUBSCRIBE message
+	Payload string `json:"payload"`
+	Time    int64  `json:"time"` // Receive time in Unix milliseconds
+}
+
+// RedisSubscription is a SUBSCRIBE/PSUBSCRIBE session. It keeps its own connection,
+// which go-redis pings and re-subscribes after a reconnect.
+type RedisSubscription struct {
+	Channels []string // Subscribed channels
+	Patterns []string // Subscribed patterns, including keyspace notification patterns
+	Warning  string   // Set when keyspace notifications are requested but not enabled on the server
+
+	pubsub *redis.PubSub
+}
+
+// Receive passes every message to onMessage until ctx is cancelled or the subscription is closed
+func (s *RedisSubscription) Receive(ctx context.Context, onMessage func(RedisMessage)) error {
+	ch := s.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("订阅连接已关闭")
+			}
+			onMessage(RedisMessage{
+				Channel: msg.Channel,
+				Pattern: msg.Pattern,
+				Payload: msg.Payload,
+				Time:    time.Now().UnixMilli(),
+			})
+		}
+	}
+}
+
+// Close unsubscribes and releases the connection
+func (s *RedisSubscription) Close() error {
+	return s.pubsub.Close()
+}
+
+// keyspaceEventFlags returns the notify-keyspace-events flags needed for the keyevent
+// notifications of events and, when keyspace is set, the keyspace notifications
+func keyspaceEventFlags(events []string, keyspace bool) string {
+	var flags []byte
+	if keyspace {
+		flags = append(flags, 'K')
+	}
+	if len(events) > 0 {
+		flags = append(flags, 'E')
+	}
+	classes := ""
+	for _, event := range events {
+		class, ok := keyspaceEventClasses[strings.ToLower(event)]
+		if !ok {
+			classes = "A"
+			break
+		}
+		if strings.IndexByte(classes, class) < 0 {
+			classes += string(class)
+		}
+	}
+	if classes == "" {
+		classes = "A"
+	}
+	return string(flags) + classes
+}
+
+// missingKeyspaceFlags returns the flags of required that current does not enable
+func missingKeyspaceFlags(current, required string) string {
+	all := strings.IndexByte(current, 'A') >= 0
+	var missing []byte
+	for i := 0; i < len(required); i++ {
+		flag := required[i]
+		if strings.IndexByte(current, flag) >= 0 || (all && strings.IndexByte(keyspaceAllClasses, flag) >= 0) {
+			continue
+		}
+		missing = append(missing, flag)
+	}
+	return string(missing)
+}
+
+// keyspacePatterns returns the PSUBSCRIBE patterns for the keyspace notifications requested by opts in database db
+func keyspacePatterns(db int, opts RedisSubscribeOptions) []string {
+	patterns := make([]string, 0, len(opts.KeyEvents)+1)
+	for _, event := range opts.KeyEvents {
+		patterns = append(patterns, fmt.Sprintf("__keyevent@%d__:%s", db, event))
+	}
+	if opts.KeyPattern != "" {
+		patterns = append(patterns, fmt.Sprintf("__keyspace@%d__:%s", db, opts.KeyPattern))
+	}
+	return patterns
+}
+
+func trimNonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// ensureKeyspaceEvents checks notify-keyspace-events for required and adds the missing
+// flags when enable is set. It returns a warning when notifications stay disabled.
+func (r *RedisClientImpl) ensureKeyspaceEvents(ctx context.Context, required string, enable bool) (string, error) {
+	config, err := r.client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		if enable {
+			return "", fmt.Errorf("读取 notify-keyspace-events 失败: %w", err)
+		}
+		// Managed services often disable CONFIG; subscribe anyway
+		return fmt.Sprintf("无法读取 notify-keyspace-events（%v），若服务端未开启键空间通知将收不到事件", err), nil
+	}
+	current := config["notify-keyspace-events"]
+	missing := missingKeyspaceFlags(current, required)
+	if missing == "" {
+		return "", nil
+	}
+	if !enable {
+		return fmt.Sprintf("服务端 notify-keyspace-events=%q，缺少 %q，将收不到对应的键空间通知", current, missing), nil
+	}
+	if err := r.client.ConfigSet(ctx, "notify-keyspace-events", current+missing).Err(); err != nil {
+		return "", fmt.Errorf("开启键空间通知失败: %w", err)
+	}
+	return "", nil
+}
+
+// Subscribe starts a subscription on a dedicated connection. Keyspace notifications are
+// published per node, so they are not supported in cluster mode.
+func (r *RedisClientImpl) Subscribe(opts RedisSubscribeOptions) (*RedisSubscription, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("Redis 客户端未连接")
+	}
+	opts.Channels = trimNonEmpty(opts.Channels)
+	opts.Patterns = trimNonEmpty(opts.Patterns)
+	opts.KeyEvents = trimNonEmpty(opts.KeyEvents)
+	opts.KeyPattern = strings.TrimSpace(opts.KeyPattern)
+	keyspace := len(opts.KeyEvents) > 0 || opts.KeyPattern != ""
+	if len(opts.Channels) == 0 && len(opts.Patterns) == 0 && !keyspace {
+		return nil, fmt.Errorf("请至少填写一个频道、模式或键空间事件")
+	}
+	if keyspace && r.cluster != nil {
+		return nil, fmt.Errorf("集群模式下键空间通知由各节点单独发布，暂不支持订阅")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout(r.config))
+	defer cancel()
+
+	sub := &RedisSubscription{Channels: opts.Channels, Patterns: opts.Patterns}
+	if keyspace {
+		required := keyspaceEventFlags(opts.KeyEvents, opts.KeyPattern != "")
+		warning, err := r.ensureKeyspaceEvents(ctx, required, opts.EnableNotify)
+		if err != nil {
+			return nil, err
+		}
+		sub.Warning = warning
+		sub.Patterns = append(sub.Patterns, keyspacePatterns(r.currentDB, opts)...)
+	}
+
+	pubsub := r.client.Subscribe(ctx)
+	if len(sub.Channels) > 0 {
+		if err := pubsub.Subscribe(ctx, sub.Channels...); err != nil {
+			pubsub.Close()
+			return nil, err
+		}
+	}
+	if len(sub.Patterns) > 0 {
+		if err := pubsub.PSubscribe(ctx, sub.Patterns...); err != nil {
+			pubsub.Close()
+			return nil, err
+		}
+	}
+	// Wait for the first confirmation so ACL or connection errors surface here
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	sub.pubsub = pubsub
+	return sub, nil
+}
+
+// Publish posts a message to a channel and returns the number of receiving clients
+func (r *RedisClientImpl) Publish(channel, message string) (int64, error) {
+	if r.client == nil {
+		return 0, fmt.Errorf("Redis 客户端未连接")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return r.client.Publish(ctx, channel, message).Result()
+}
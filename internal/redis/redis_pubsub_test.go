@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyspaceEventFlags(t *testing.T) {
+	cases := []struct {
+		events   []string
+		keyspace bool
+		want     string
+	}{
+		{[]string{"expired", "evicted", "set"}, false, "Exe$"},
+		{[]string{"EXPIRED", "expire", "del"}, false, "Exg"},
+		{[]string{"hset"}, true, "KEh"},
+		{nil, true, "KA"},
+		// 未收录的事件或 * 需要全部类别
+		{[]string{"expired", "unknown"}, false, "EA"},
+		{[]string{"*"}, false, "EA"},
+	}
+	for _, c := range cases {
+		if got := keyspaceEventFlags(c.events, c.keyspace); got != c.want {
+			t.Fatalf("keyspaceEventFlags(%v, %v) 期望 %q，实际 %q", c.events, c.keyspace, c.want, got)
+		}
+	}
+}
+
+func TestMissingKeyspaceFlags(t *testing.T) {
+	cases := []struct {
+		current, required, want string
+	}{
+		{"", "Exe$", "Exe$"},
+		{"Ex", "Exe$", "e$"},
+		{"KEx", "KEx", ""},
+		// A 包含除 K、E、n、m 以外的全部类别
+		{"AE", "Exe$", ""},
+		{"Ex", "KExe$", "Ke$"},
+		{"AKE", "KEn", "n"},
+	}
+	for _, c := range cases {
+		if got := missingKeyspaceFlags(c.current, c.required); got != c.want {
+			t.Fatalf("missingKeyspaceFlags(%q, %q) 期望 %q，实际 %q", c.current, c.required, c.want, got)
+		}
+	}
+}
+
+func TestKeyspacePatterns(t *testing.T) {
+	got := keyspacePatterns(3, RedisSubscribeOptions{KeyEvents: []string{"expired", "*"}, KeyPattern: "user:*"})
+	want := []string{"__keyevent@3__:expired", "__keyevent@3__:*", "__keyspace@3__:user:*"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("keyspacePatterns 期望 %v，实际 %v", want, got)
+	}
+	if got := keyspacePatterns(0, RedisSubscribeOptions{Channels: []string{"news"}}); len(got) != 0 {
+		t.Fatalf("未请求键空间通知时不应生成模式，实际 %v", got)
+	}
+}
+
+func TestTrimNonEmpty(t *testing.T) {
+	got := trimNonEmpty([]string{" a ", "", "  ", "b"})
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("trimNonEmpty 期望 [a b]，实际 %v", got)
+	}
+}
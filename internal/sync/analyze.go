@@ -3,20 +3,26 @@ package sync
 import (
 	"GoNavi-Wails/internal/db"
 	"GoNavi-Wails/internal/logger"
+	"context"
 	"fmt"
 	"strings"
 )
 
 type TableDiffSummary struct {
-	Table     string `json:"table"`
-	PKColumn  string `json:"pkColumn,omitempty"`
-	CanSync   bool   `json:"canSync"`
-	Inserts   int    `json:"inserts"`
-	Updates   int    `json:"updates"`
-	Deletes   int    `json:"deletes"`
-	Same      int    `json:"same"`
-	Message   string `json:"message,omitempty"`
-	HasSchema bool   `json:"hasSchema,omitempty"`
+	Table         string   `json:"table"`
+	PKColumn      string   `json:"pkColumn,omitempty"`      // 匹配键列，复合键以逗号分隔
+	KeyColumns    []string `json:"keyColumns,omitempty"`    // 匹配键列
+	MatchIndex    string   `json:"matchIndex,omitempty"`    // 使用唯一索引匹配时的索引名
+	UniqueIndexes []string `json:"uniqueIndexes,omitempty"` // 可选作匹配键的唯一索引
+	CanSync       bool     `json:"canSync"`
+	Inserts       int      `json:"inserts"`
+	Updates       int      `json:"updates"`
+	Deletes       int      `json:"deletes"`
+	Same          int      `json:"same"`
+	Message       string   `json:"message,omitempty"`
+	HasSchema     bool     `json:"hasSchema,omitempty"`
+	ChunksTotal   int      `json:"chunksTotal,omitempty"`   // 校验和对比时的分块数
+	ChunksSkipped int      `json:"chunksSkipped,omitempty"` // 校验和一致、未逐行对比的分块数
 }
 
 type SyncAnalyzeResult struct {
@@ -46,6 +52,7 @@ func (s *SyncEngine) Analyze(config SyncConfig) SyncAnalyzeResult {
 	}
 
 	totalTables := len(config.Tables)
+	var totalRows int64
 	s.progress(config.JobID, 0, totalTables, "", "差异分析开始")
 
 	sourceDB, err := db.NewDatabase(config.SourceConfig.Type)
@@ -88,10 +95,17 @@ func (s *SyncEngine) Analyze(config SyncConfig) SyncAnalyzeResult {
 				HasSchema: syncSchema,
 			}
 
+			mapping, err := newTableMapping(tableName, config.TableOptions[tableName])
+			if err != nil {
+				summary.Message = err.Error()
+				result.Tables = append(result.Tables, summary)
+				return
+			}
+
 			sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
-			targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
+			targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, mapping.targetTable)
 			sourceQueryTable := qualifiedNameForQuery(config.SourceConfig.Type, sourceSchema, sourceTable, tableName)
-			targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
+			targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, mapping.targetTable)
 
 			cols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
 			if err != nil {
@@ -107,84 +121,61 @@ func (s *SyncEngine) Analyze(config SyncConfig) SyncAnalyzeResult {
 				return
 			}
 
-			pkCols := make([]string, 0, 2)
-			for _, c := range cols {
-				if c.Key == "PRI" || c.Key == "PK" {
-					pkCols = append(pkCols, c.Name)
-				}
-			}
-			if len(pkCols) == 0 {
-				summary.Message = "无主键，不支持数据对比/同步"
+			sourceKey, uniqueIndexes, err := resolveMatchKey(sourceDB, sourceSchema, sourceTable, cols, config.TableOptions[tableName].MatchIndex)
+			summary.UniqueIndexes = uniqueIndexes
+			if err != nil {
+				summary.Message = err.Error() + "，不支持数据对比/同步"
 				result.Tables = append(result.Tables, summary)
 				return
 			}
-			if len(pkCols) > 1 {
-				summary.Message = fmt.Sprintf("复合主键（%s），暂不支持数据对比/同步", strings.Join(pkCols, ","))
+			key, err := mapping.mapKey(sourceKey)
+			if err != nil {
+				summary.Message = err.Error()
 				result.Tables = append(result.Tables, summary)
 				return
 			}
-			summary.PKColumn = pkCols[0]
+			summary.PKColumn = key.label()
+			summary.KeyColumns = key.columns
+			summary.MatchIndex = key.index
 
-			// Query data for diff
-			sourceRows, _, err := sourceDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.SourceConfig.Type, sourceQueryTable)))
+			source := mapping.sourceSide(sourceDB, db.ResolveDialect(config.SourceConfig), sourceQueryTable, sourceKey)
+			filter, err := resolveTableFilter(context.Background(), config, source, tableName, config.TableOptions[tableName], normalizeSyncMode(config.Mode) != "full_overwrite")
 			if err != nil {
-				summary.Message = "读取源表失败: " + err.Error()
+				summary.Message = err.Error()
 				result.Tables = append(result.Tables, summary)
 				return
 			}
-			targetRows, _, err := targetDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.TargetConfig.Type, targetQueryTable)))
+			source, target := filter.sides(source, tableSide{inst: targetDB, dialect: db.ResolveDialect(config.TargetConfig), queryTable: targetQueryTable}, mapping)
+			source.columnTypes = mapping.columnTypes(cols)
+			target.columnTypes = loadColumnTypes(targetDB, targetSchema, targetTable)
+			var tableRows int64
+			compareStats, err := diffTable(context.Background(), config, source, target, key, cols, nil, func(diff chunkDiff) error {
+				summary.Inserts += len(diff.inserts)
+				summary.Updates += len(diff.updates)
+				summary.Deletes += len(diff.deletes)
+				summary.Same += diff.same
+				if diff.sourceRows > 0 {
+					tableRows += int64(diff.sourceRows)
+					totalRows += int64(diff.sourceRows)
+					s.rowProgress(config.JobID, i, totalTables, tableName, fmt.Sprintf("分析表(%d/%d)，已读取 %d 行", i+1, totalTables, tableRows), tableRows, totalRows)
+				}
+				return nil
+			})
 			if err != nil {
-				summary.Message = "读取目标表失败: " + err.Error()
+				summary.Message = err.Error()
 				result.Tables = append(result.Tables, summary)
 				return
 			}
-
-			pkCol := summary.PKColumn
-			targetMap := make(map[string]map[string]interface{}, len(targetRows))
-			for _, row := range targetRows {
-				if row[pkCol] == nil {
-					continue
-				}
-				pkVal := strings.TrimSpace(fmt.Sprintf("%v", row[pkCol]))
-				if pkVal == "" || pkVal == "<nil>" {
-					continue
-				}
-				targetMap[pkVal] = row
+			summary.ChunksTotal = compareStats.chunks
+			summary.ChunksSkipped = compareStats.skipped
+			if compareStats.fallback != nil {
+				summary.Message = "校验和对比不可用，已逐行对比: " + compareStats.fallback.Error()
 			}
-
-			sourcePKSet := make(map[string]struct{}, len(sourceRows))
-			for _, sRow := range sourceRows {
-				if sRow[pkCol] == nil {
-					continue
-				}
-				pkVal := strings.TrimSpace(fmt.Sprintf("%v", sRow[pkCol]))
-				if pkVal == "" || pkVal == "<nil>" {
-					continue
-				}
-				sourcePKSet[pkVal] = struct{}{}
-
-				if tRow, exists := targetMap[pkVal]; exists {
-					changed := false
-					for k, v := range sRow {
-						if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", tRow[k]) {
-							changed = true
-							break
-						}
-					}
-					if changed {
-						summary.Updates++
-					} else {
-						summary.Same++
-					}
-				} else {
-					summary.Inserts++
-				}
-			}
-
-			for pkVal := range targetMap {
-				if _, ok := sourcePKSet[pkVal]; !ok {
-					summary.Deletes++
+			if desc := filter.describe(); desc != "" {
+				if source.lookupTarget {
+					desc += "，不检测删除"
 				}
+				summary.Message = strings.TrimPrefix(summary.Message+"；"+desc, "；")
 			}
 
 			summary.CanSync = true
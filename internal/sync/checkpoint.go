@@ -0,0 +1,320 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	stdsync "sync"
+	"time"
+)
+
+// SyncCheckpoint 记录一次同步任务的进度：已完成的表，以及未完成表已提交到的键位置。
+// 任务失败或中断后，新任务通过 SyncConfig.ResumeFrom 指定原任务的 JobID 从断点继续。
+type SyncCheckpoint struct {
+	JobID       string                     `json:"jobId"`
+	Fingerprint string                     `json:"fingerprint"` // 源、目标、表清单、模式与表映射的摘要，变化后断点失效
+	Completed   []string                   `json:"completed"`
+	Tables      map[string]TableCheckpoint `json:"tables,omitempty"`
+	UpdatedAt   int64                      `json:"updatedAt"` // Unix milli
+}
+
+// TableCheckpoint 是单表的断点：键不大于 After 的数据已提交到目标库。
+type TableCheckpoint struct {
+	KeyColumns []string          `json:"keyColumns"`
+	After      []CheckpointValue `json:"after,omitempty"`
+	Rows       int64             `json:"rows,omitempty"`    // 已处理的源表行数
+	Cleared    bool              `json:"cleared,omitempty"` // 全量覆盖模式已清空目标表
+}
+
+// CheckpointValue 保存键值及其类型，恢复后生成的键范围条件与原值一致。
+type CheckpointValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// CheckpointDir 返回断点文件所在目录。
+func CheckpointDir() string {
+	return filepath.Join(DefaultJobStoreDir(), "checkpoints")
+}
+
+var checkpointFileUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+func checkpointPath(jobID string) string {
+	return filepath.Join(CheckpointDir(), checkpointFileUnsafe.ReplaceAllString(jobID, "_")+".json")
+}
+
+// LoadCheckpoint 读取指定任务的断点，不存在时返回 nil。
+func LoadCheckpoint(jobID string) (*SyncCheckpoint, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(checkpointPath(jobID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp SyncCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("断点文件已损坏: %w", err)
+	}
+	return &cp, nil
+}
+
+// DeleteCheckpoint 删除指定任务的断点。
+func DeleteCheckpoint(jobID string) error {
+	err := os.Remove(checkpointPath(jobID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// checkpointFingerprint 计算决定断点是否可复用的配置摘要。
+func checkpointFingerprint(config SyncConfig) string {
+	parts := []string{
+		connFingerprint(config.SourceConfig),
+		connFingerprint(config.TargetConfig),
+		strings.Join(config.Tables, ","),
+		normalizeSyncMode(config.Mode),
+		strings.ToLower(strings.TrimSpace(config.Content)),
+	}
+	// 表映射与过滤条件决定读取的行和写入的目标表、字段，变化后已写入的数据与断点不再对应
+	for _, table := range config.Tables {
+		opts := config.TableOptions[table]
+		if opts.TargetTable == "" && len(opts.ColumnMap) == 0 && len(opts.ExcludeColumns) == 0 && len(opts.Transforms) == 0 && opts.Where == "" && opts.WatermarkColumn == "" {
+			continue
+		}
+		mapping, _ := json.Marshal([]interface{}{opts.TargetTable, opts.ColumnMap, opts.ExcludeColumns, opts.Transforms, opts.Where, opts.WatermarkColumn})
+		parts = append(parts, table+"="+string(mapping))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// connFingerprint 标识一个连接的库（类型、地址、端口与库名）。
+func connFingerprint(cfg connection.ConnectionConfig) string {
+	return fmt.Sprintf("%s|%s|%d|%s", strings.ToLower(cfg.Type), cfg.Host, cfg.Port, cfg.Database)
+}
+
+// checkpointer 在同步过程中维护并落盘断点，nil 表示不记录断点（如演练模式）。多张表并行同步时共用同一个实例。
+type checkpointer struct {
+	mu   stdsync.Mutex
+	path string
+	cp   SyncCheckpoint
+	done map[string]bool
+}
+
+// openCheckpointer 为本次任务创建断点记录。指定 ResumeFrom 时接管原任务的断点（配置不一致则忽略），
+// 返回值中的 resumed 表示是否确实从断点继续。
+func openCheckpointer(config SyncConfig) (c *checkpointer, resumed bool, err error) {
+	c = &checkpointer{
+		path: checkpointPath(config.JobID),
+		cp: SyncCheckpoint{
+			JobID:       config.JobID,
+			Fingerprint: checkpointFingerprint(config),
+			Tables:      make(map[string]TableCheckpoint),
+		},
+		done: make(map[string]bool),
+	}
+
+	if from := strings.TrimSpace(config.ResumeFrom); from != "" {
+		prev, err := LoadCheckpoint(from)
+		if err != nil {
+			return nil, false, fmt.Errorf("读取断点失败: %w", err)
+		}
+		if prev == nil {
+			return nil, false, fmt.Errorf("任务 %s 没有可用的断点", from)
+		}
+		if prev.Fingerprint != c.cp.Fingerprint {
+			return nil, false, fmt.Errorf("任务 %s 的源、目标、表清单、同步模式或表映射已变化，无法从断点继续", from)
+		}
+		c.cp.Completed = prev.Completed
+		for _, table := range prev.Completed {
+			c.done[table] = true
+		}
+		for table, tcp := range prev.Tables {
+			c.cp.Tables[table] = tcp
+		}
+		if from != config.JobID {
+			_ = DeleteCheckpoint(from)
+		}
+		resumed = true
+	}
+
+	if err := os.MkdirAll(CheckpointDir(), 0o700); err != nil {
+		return nil, false, fmt.Errorf("创建断点目录失败: %w", err)
+	}
+	return c, resumed, c.flush()
+}
+
+func (c *checkpointer) completed(table string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[table]
+}
+
+// resumePoint 返回表的断点；键列与断点记录不一致时（如更换了匹配索引）从头开始。
+func (c *checkpointer) resumePoint(table string, key matchKey) (TableCheckpoint, []interface{}) {
+	if c == nil {
+		return TableCheckpoint{}, nil
+	}
+	c.mu.Lock()
+	tcp, ok := c.cp.Tables[table]
+	c.mu.Unlock()
+	if !ok || !sameStrings(tcp.KeyColumns, key.columns) {
+		return TableCheckpoint{}, nil
+	}
+	after, err := decodeCheckpointValues(tcp.After)
+	if err != nil || len(after) != len(key.columns) {
+		return TableCheckpoint{Cleared: tcp.Cleared}, nil
+	}
+	return tcp, after
+}
+
+// advance 记录表中键不大于 after 的数据已提交。
+func (c *checkpointer) advance(table string, key matchKey, after []interface{}, rows int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tcp := c.cp.Tables[table]
+	tcp.KeyColumns = key.columns
+	tcp.After = encodeCheckpointValues(after)
+	tcp.Rows = rows
+	c.cp.Tables[table] = tcp
+	c.save()
+}
+
+// markCleared 记录全量覆盖模式已清空目标表，续传时不再重复清空。
+func (c *checkpointer) markCleared(table string, key matchKey) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tcp := c.cp.Tables[table]
+	tcp.KeyColumns = key.columns
+	tcp.Cleared = true
+	c.cp.Tables[table] = tcp
+	c.save()
+}
+
+func (c *checkpointer) complete(table string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done[table] {
+		return
+	}
+	c.done[table] = true
+	c.cp.Completed = append(c.cp.Completed, table)
+	delete(c.cp.Tables, table)
+	c.save()
+}
+
+// finish 在任务结束时调用：全部表完成则删除断点，否则保留供续传，返回是否保留。
+func (c *checkpointer) finish(tables []string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		if !c.done[table] {
+			return true
+		}
+	}
+	if err := DeleteCheckpoint(c.cp.JobID); err != nil {
+		logger.Error(err, "删除同步断点失败：任务=%s", c.cp.JobID)
+	}
+	return false
+}
+
+func (c *checkpointer) save() {
+	if err := c.flush(); err != nil {
+		logger.Error(err, "保存同步断点失败：任务=%s", c.cp.JobID)
+	}
+}
+
+func (c *checkpointer) flush() error {
+	c.cp.UpdatedAt = time.Now().UnixMilli()
+	return writeJSONFile(c.path, c.cp)
+}
+
+func encodeCheckpointValues(values []interface{}) []CheckpointValue {
+	out := make([]CheckpointValue, len(values))
+	for i, v := range values {
+		switch val := v.(type) {
+		case nil:
+			out[i] = CheckpointValue{Type: "null"}
+		case bool:
+			out[i] = CheckpointValue{Type: "bool", Value: strconv.FormatBool(val)}
+		case int, int8, int16, int32, int64:
+			out[i] = CheckpointValue{Type: "int", Value: fmt.Sprintf("%d", val)}
+		case uint, uint8, uint16, uint32, uint64:
+			out[i] = CheckpointValue{Type: "uint", Value: fmt.Sprintf("%d", val)}
+		case float32:
+			out[i] = CheckpointValue{Type: "float", Value: strconv.FormatFloat(float64(val), 'g', -1, 32)}
+		case float64:
+			out[i] = CheckpointValue{Type: "float", Value: strconv.FormatFloat(val, 'g', -1, 64)}
+		case time.Time:
+			out[i] = CheckpointValue{Type: "time", Value: val.Format(time.RFC3339Nano)}
+		case []byte:
+			out[i] = CheckpointValue{Type: "bytes", Value: hex.EncodeToString(val)}
+		case string:
+			out[i] = CheckpointValue{Type: "string", Value: val}
+		default:
+			out[i] = CheckpointValue{Type: "string", Value: fmt.Sprintf("%v", val)}
+		}
+	}
+	return out
+}
+
+func decodeCheckpointValues(values []CheckpointValue) ([]interface{}, error) {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		var err error
+		switch v.Type {
+		case "null":
+			out[i] = nil
+		case "bool":
+			out[i], err = strconv.ParseBool(v.Value)
+		case "int":
+			out[i], err = strconv.ParseInt(v.Value, 10, 64)
+		case "uint":
+			out[i], err = strconv.ParseUint(v.Value, 10, 64)
+		case "float":
+			out[i], err = strconv.ParseFloat(v.Value, 64)
+		case "time":
+			out[i], err = time.Parse(time.RFC3339Nano, v.Value)
+		case "bytes":
+			out[i], err = hex.DecodeString(v.Value)
+		case "string":
+			out[i] = v.Value
+		default:
+			err = fmt.Errorf("未知的键值类型 %q", v.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
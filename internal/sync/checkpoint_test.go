@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestCheckpointValuesRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 8, 30, 0, 123456789, time.FixedZone("CST", 8*3600))
+	cases := []struct {
+		in   interface{}
+		typ  string
+		want interface{}
+	}{
+		{nil, "null", nil},
+		{true, "bool", true},
+		{int(-7), "int", int64(-7)},
+		{int32(12), "int", int64(12)},
+		{int64(9007199254740993), "int", int64(9007199254740993)},
+		{uint64(18446744073709551615), "uint", uint64(18446744073709551615)},
+		{float32(1.5), "float", float64(1.5)},
+		{0.1, "float", 0.1},
+		{ts, "time", ts},
+		{[]byte{0x00, 0xff, 0x10}, "bytes", []byte{0x00, 0xff, 0x10}},
+		{[]byte{}, "bytes", []byte{}},
+		{"O'Neil 中文", "string", "O'Neil 中文"},
+		{json.Number("12.50"), "string", "12.50"},
+	}
+
+	in := make([]interface{}, len(cases))
+	for i, tc := range cases {
+		in[i] = tc.in
+	}
+	encoded := encodeCheckpointValues(in)
+	for i, tc := range cases {
+		if encoded[i].Type != tc.typ {
+			t.Fatalf("%#v 的编码类型=%q，期望=%q", tc.in, encoded[i].Type, tc.typ)
+		}
+	}
+
+	// 断点经 JSON 落盘后再读回
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		t.Fatalf("序列化断点失败：%v", err)
+	}
+	var loaded []CheckpointValue
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("反序列化断点失败：%v", err)
+	}
+	decoded, err := decodeCheckpointValues(loaded)
+	if err != nil {
+		t.Fatalf("decodeCheckpointValues 返回错误：%v", err)
+	}
+	for i, tc := range cases {
+		got := decoded[i]
+		if want, ok := tc.want.(time.Time); ok {
+			if gotTime, ok := got.(time.Time); !ok || !gotTime.Equal(want) {
+				t.Fatalf("时间键值往返后不一致：实际=%v，期望=%v", got, want)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%#v 往返后=%#v，期望=%#v", tc.in, got, tc.want)
+		}
+		// 恢复后生成的键范围条件须与原值一致
+		if a, b := sqlLiteral("postgres", tc.in), sqlLiteral("postgres", got); a != b {
+			t.Fatalf("%#v 往返后的字面量不一致：%s != %s", tc.in, a, b)
+		}
+	}
+}
+
+func TestDecodeCheckpointValuesRejectsInvalid(t *testing.T) {
+	invalid := [][]CheckpointValue{
+		{{Type: "int", Value: "abc"}},
+		{{Type: "time", Value: "2024-03-01"}},
+		{{Type: "bytes", Value: "zz"}},
+		{{Type: "decimal", Value: "1"}},
+	}
+	for _, values := range invalid {
+		if _, err := decodeCheckpointValues(values); err == nil {
+			t.Fatalf("%+v 应返回错误", values)
+		}
+	}
+}
+
+func TestCheckpointerResume(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	config := SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "mysql", Host: "src", Port: 3306, Database: "app"},
+		TargetConfig: connection.ConnectionConfig{Type: "postgres", Host: "dst", Port: 5432, Database: "app"},
+		Tables:       []string{"orders", "users"},
+		JobID:        "job-1",
+	}
+	key := matchKey{columns: []string{"tenant", "id"}}
+
+	cp, resumed, err := openCheckpointer(config)
+	if err != nil || resumed {
+		t.Fatalf("openCheckpointer 返回 (%v, %v)", resumed, err)
+	}
+	cp.complete("users")
+	cp.advance("orders", key, []interface{}{"t1", int64(99)}, 500)
+	if !cp.finish(config.Tables) {
+		t.Fatalf("仍有未完成的表时应保留断点")
+	}
+
+	config.JobID = "job-2"
+	config.ResumeFrom = "job-1"
+	cp2, resumed, err := openCheckpointer(config)
+	if err != nil || !resumed {
+		t.Fatalf("从断点继续失败：(%v, %v)", resumed, err)
+	}
+	if !cp2.completed("users") || cp2.completed("orders") {
+		t.Fatalf("已完成的表记录不正确：%v", cp2.cp.Completed)
+	}
+	tcp, after := cp2.resumePoint("orders", key)
+	if tcp.Rows != 500 || !reflect.DeepEqual(after, []interface{}{"t1", int64(99)}) {
+		t.Fatalf("断点位置不正确：rows=%d after=%v", tcp.Rows, after)
+	}
+	if _, after := cp2.resumePoint("orders", matchKey{columns: []string{"id"}}); after != nil {
+		t.Fatalf("键列变化后应从头开始，实际 after=%v", after)
+	}
+	if prev, _ := LoadCheckpoint("job-1"); prev != nil {
+		t.Fatalf("接管后应删除原任务的断点")
+	}
+
+	cp2.complete("orders")
+	if cp2.finish(config.Tables) {
+		t.Fatalf("全部表完成后不应保留断点")
+	}
+	if prev, _ := LoadCheckpoint("job-2"); prev != nil {
+		t.Fatalf("全部表完成后应删除断点文件")
+	}
+
+	config.JobID = "job-3"
+	config.ResumeFrom = "job-2"
+	if _, _, err := openCheckpointer(config); err == nil {
+		t.Fatalf("断点不存在时应返回错误")
+	}
+
+	// 表清单变化后断点失效
+	config.ResumeFrom = ""
+	if _, _, err := openCheckpointer(config); err != nil {
+		t.Fatalf("openCheckpointer 返回错误：%v", err)
+	}
+	config.JobID = "job-4"
+	config.ResumeFrom = "job-3"
+	config.Tables = []string{"orders"}
+	if _, _, err := openCheckpointer(config); err == nil {
+		t.Fatalf("配置变化后不应从断点继续")
+	}
+}
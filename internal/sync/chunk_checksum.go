@@ -0,0 +1,231 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func normalizeCompareMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "checksum":
+		return "checksum"
+	default:
+		return "row"
+	}
+}
+
+// checksumSupported 判断两端能否比较分块校验和：校验和基于数据库内置哈希与各自的文本表示，
+// 只有同一方言族的两端结果才可比。
+func checksumSupported(sourceDialect string, targetDialect string) bool {
+	sourceFamily := db.Capabilities(sourceDialect).Family
+	if sourceFamily != db.Capabilities(targetDialect).Family {
+		return false
+	}
+	switch sourceFamily {
+	case db.FamilyMySQL, db.FamilyPostgres, db.FamilyOracle, db.FamilySQLServer:
+		return true
+	default:
+		return false
+	}
+}
+
+// chunkCompareStats 汇总一次对比的分块情况。
+type chunkCompareStats struct {
+	checksum bool  // 是否使用了校验和对比
+	chunks   int   // 分块总数（仅校验和模式统计）
+	skipped  int   // 校验和一致而跳过逐行对比的分块数
+	fallback error // 校验和计算失败后改为逐行对比的原因
+
+	unordered bool // 两端对键的排序可能不一致，改为按键查找对比
+}
+
+// diffTable 按 config.CompareMode 对比单表：checksum 模式且两端方言支持时先比较分块校验和，
+// 仅对校验和不一致的分块逐行对比；否则逐行对比全表。源表只读取部分行时按键查找目标行，
+// 两端对键的排序可能不一致时（见 orderedKeyMerge）改为两端互相按键查找。
+// 逐行对比按两端字段类型与 config.CompareOptions 比较取值。
+func diffTable(ctx context.Context, config SyncConfig, source tableSide, target tableSide, key matchKey, cols []connection.ColumnDefinition, start []interface{}, handle chunkDiffHandler) (chunkCompareStats, error) {
+	cmp := newValueComparer(config.CompareOptions, source, target)
+	if source.lookupTarget {
+		return chunkCompareStats{}, diffTableByLookup(ctx, source, target, key, start, syncBatchSize(config), cmp, handle)
+	}
+	if !orderedKeyMerge(source, target, key, cmp) {
+		return chunkCompareStats{unordered: true}, diffTableUnordered(ctx, source, target, key, start, syncBatchSize(config), cmp, handle)
+	}
+	// 配置了字段映射或取值转换时两端字段不再一一对应，只能逐行对比
+	if normalizeCompareMode(config.CompareMode) != "checksum" || !checksumSupported(source.dialect, target.dialect) || source.mapRow != nil {
+		return chunkCompareStats{}, diffTableByKey(ctx, source, target, key, start, syncBatchSize(config), cmp, handle)
+	}
+	columns := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if name := strings.TrimSpace(col.Name); name != "" {
+			columns = append(columns, col.Name)
+		}
+	}
+	return diffTableByChecksum(ctx, source, target, key, columns, start, syncBatchSize(config), cmp, handle)
+}
+
+// diffTableByChecksum 先只读取源表键列确定分块边界，再分别在两端计算每个键范围的行数与校验和，
+// 一致的分块直接计为相同行，不一致的分块才读取整行对比。
+// 任一分块的校验和计算失败（如列类型无法参与拼接）时，该分块及后续分块改为逐行对比。
+func diffTableByChecksum(ctx context.Context, source tableSide, target tableSide, key matchKey, columns []string, start []interface{}, chunkSize int, cmp *valueComparer, handle chunkDiffHandler) (chunkCompareStats, error) {
+	stats := chunkCompareStats{checksum: true}
+	after := start
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		keys, err := fetchKeyPage(ctx, source, key, after, chunkSize)
+		if err != nil {
+			return stats, fmt.Errorf("读取源表失败: %w", err)
+		}
+		lastChunk := len(keys) < chunkSize
+		var upper []interface{}
+		if !lastChunk {
+			upper = key.values(keys[len(keys)-1])
+		}
+		stats.chunks++
+
+		matched := false
+		sourceRows := 0
+		if stats.fallback == nil {
+			matched, sourceRows, err = compareRangeChecksum(ctx, source, target, key, columns, after, upper)
+			if err != nil {
+				stats.fallback = err
+			}
+		}
+
+		if matched {
+			stats.skipped++
+			if err := handle(chunkDiff{same: sourceRows, sourceRows: sourceRows, rangeDone: true, rangeEnd: upper}); err != nil {
+				return stats, err
+			}
+		} else {
+			srcRows, err := fetchKeysetPage(ctx, source, key, after, upper, 0)
+			if err != nil {
+				return stats, fmt.Errorf("读取源表失败: %w", err)
+			}
+			if err := diffKeyRange(ctx, target, key, srcRows, after, upper, chunkSize, cmp, handle); err != nil {
+				return stats, err
+			}
+		}
+
+		if lastChunk {
+			return stats, nil
+		}
+		after = upper
+	}
+}
+
+// compareRangeChecksum 比较两端键范围 (after, upper] 的行数与校验和，返回是否一致及源表行数。
+func compareRangeChecksum(ctx context.Context, source tableSide, target tableSide, key matchKey, columns []string, after []interface{}, upper []interface{}) (bool, int, error) {
+	sourceCount, sourceSum, err := rangeChecksum(ctx, source, key, columns, after, upper)
+	if err != nil {
+		return false, 0, fmt.Errorf("计算源表校验和失败: %w", err)
+	}
+	targetCount, targetSum, err := rangeChecksum(ctx, target, key, columns, after, upper)
+	if err != nil {
+		return false, 0, fmt.Errorf("计算目标表校验和失败: %w", err)
+	}
+	return sourceCount == targetCount && sourceSum == targetSum, sourceCount, nil
+}
+
+func rangeChecksum(ctx context.Context, side tableSide, key matchKey, columns []string, after []interface{}, upper []interface{}) (int, string, error) {
+	caps := db.Capabilities(side.dialect)
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = caps.QuoteIdent(col)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) AS chunk_rows, %s AS chunk_sum FROM %s", checksumAggregate(caps.Family, quoted), caps.QuoteQualifiedIdent(side.queryTable))
+	if conds := side.conditions(key, after, upper); len(conds) > 0 {
+		query += " WHERE (" + strings.Join(conds, ") AND (") + ")"
+	}
+
+	var row map[string]interface{}
+	err := db.StreamQuery(ctx, side.inst, query, 1, func(batch db.RowBatch) error {
+		if len(batch.Rows) > 0 && row == nil {
+			row = batch.Rows[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if row == nil {
+		return 0, "", fmt.Errorf("校验和查询未返回结果")
+	}
+
+	countText := scalarText(lookupColumn(row, "chunk_rows"))
+	count, err := strconv.Atoi(countText)
+	if err != nil {
+		// 部分驱动以浮点或十进制文本返回 COUNT
+		f, ferr := strconv.ParseFloat(countText, 64)
+		if ferr != nil {
+			return 0, "", fmt.Errorf("无法解析行数 %q", countText)
+		}
+		count = int(f)
+	}
+	return count, scalarText(lookupColumn(row, "chunk_sum")), nil
+}
+
+// checksumAggregate 生成对一组行求校验和的聚合表达式：每行取哈希的前 32 位求和，与行顺序无关。
+// 行的文本由各列值拼接而成，并追加各列是否为 NULL 的标记，以区分 NULL 与空串。
+func checksumAggregate(family string, quoted []string) string {
+	switch family {
+	case db.FamilyPostgres:
+		return fmt.Sprintf("COALESCE(SUM(('x' || SUBSTR(MD5(ROW(%s)::text), 1, 8))::bit(32)::bigint), 0)", strings.Join(quoted, ", "))
+	case db.FamilyOracle:
+		parts := make([]string, 0, len(quoted)+1)
+		markers := make([]string, len(quoted))
+		for i, col := range quoted {
+			parts = append(parts, col)
+			markers[i] = fmt.Sprintf("NVL2(%s, '0', '1')", col)
+		}
+		parts = append(parts, strings.Join(markers, " || "))
+		rowText := strings.Join(parts, " || '#' || ")
+		return fmt.Sprintf("NVL(SUM(TO_NUMBER(SUBSTR(RAWTOHEX(STANDARD_HASH(%s, 'MD5')), 1, 8), 'XXXXXXXX')), 0)", rowText)
+	case db.FamilySQLServer:
+		args := make([]string, 0, len(quoted)*2+1)
+		markers := make([]string, len(quoted))
+		for i, col := range quoted {
+			args = append(args, col, "'#'")
+			markers[i] = fmt.Sprintf("CASE WHEN %s IS NULL THEN '1' ELSE '0' END", col)
+		}
+		args = append(args, markers...)
+		return fmt.Sprintf("COALESCE(SUM(CAST(CAST(SUBSTRING(HASHBYTES('MD5', CONCAT(%s)), 1, 4) AS INT) AS BIGINT)), 0)", strings.Join(args, ", "))
+	default:
+		markers := make([]string, len(quoted))
+		for i, col := range quoted {
+			markers[i] = fmt.Sprintf("ISNULL(%s)", col)
+		}
+		return fmt.Sprintf("COALESCE(SUM(CRC32(CONCAT_WS('#', %s, CONCAT(%s)))), 0)", strings.Join(quoted, ", "), strings.Join(markers, ", "))
+	}
+}
+
+// lookupColumn 按列名取值，忽略大小写（Oracle 会把未加引号的别名转为大写）。
+func lookupColumn(row map[string]interface{}, name string) interface{} {
+	if v, ok := row[name]; ok {
+		return v
+	}
+	for k, v := range row {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func scalarText(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return strings.TrimSpace(string(val))
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%v", val))
+	}
+}
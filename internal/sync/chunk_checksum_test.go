@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/db"
+)
+
+func TestChecksumSupported(t *testing.T) {
+	cases := []struct {
+		source string
+		target string
+		want   bool
+	}{
+		{"mysql", "mariadb", true},
+		{"postgres", "kingbase", true},
+		{"oracle", "oracle", true},
+		{"sqlserver", "sqlserver", true},
+		{"mysql", "postgres", false},
+		{"oracle", "dameng", false},
+		{"dameng", "dameng", false},
+		{"sqlite", "sqlite", false},
+		{"tdengine", "tdengine", false},
+	}
+	for _, tc := range cases {
+		if got := checksumSupported(tc.source, tc.target); got != tc.want {
+			t.Fatalf("checksumSupported(%q, %q)=%v，期望=%v", tc.source, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestChecksumAggregate(t *testing.T) {
+	quoted := []string{"a", "b"}
+	cases := []struct {
+		family string
+		want   string
+	}{
+		{db.FamilyMySQL, "COALESCE(SUM(CRC32(CONCAT_WS('#', a, b, CONCAT(ISNULL(a), ISNULL(b))))), 0)"},
+		{db.FamilyPostgres, "COALESCE(SUM(('x' || SUBSTR(MD5(ROW(a, b)::text), 1, 8))::bit(32)::bigint), 0)"},
+		{db.FamilyOracle, "NVL(SUM(TO_NUMBER(SUBSTR(RAWTOHEX(STANDARD_HASH(a || '#' || b || '#' || NVL2(a, '0', '1') || NVL2(b, '0', '1'), 'MD5')), 1, 8), 'XXXXXXXX')), 0)"},
+		{db.FamilySQLServer, "COALESCE(SUM(CAST(CAST(SUBSTRING(HASHBYTES('MD5', CONCAT(a, '#', b, '#', CASE WHEN a IS NULL THEN '1' ELSE '0' END, CASE WHEN b IS NULL THEN '1' ELSE '0' END)), 1, 4) AS INT) AS BIGINT)), 0)"},
+	}
+	for _, tc := range cases {
+		if got := checksumAggregate(tc.family, quoted); got != tc.want {
+			t.Fatalf("%s 的校验和表达式：\n实际=%s\n期望=%s", tc.family, got, tc.want)
+		}
+	}
+}
+
+type checksumResponse struct {
+	rows int
+	sum  string
+	err  error
+}
+
+// fakeChecksumDB 把校验和查询按 responses 依次应答，其余查询交给真实的 SQLite 库。
+type fakeChecksumDB struct {
+	db.Database
+	responses []checksumResponse
+	checksums int
+}
+
+func (f *fakeChecksumDB) Query(query string) ([]map[string]interface{}, []string, error) {
+	if !strings.Contains(query, "chunk_sum") {
+		return f.Database.Query(query)
+	}
+	if f.checksums >= len(f.responses) {
+		return nil, nil, errors.New("意外的校验和查询")
+	}
+	resp := f.responses[f.checksums]
+	f.checksums++
+	if resp.err != nil {
+		return nil, nil, resp.err
+	}
+	return []map[string]interface{}{{"chunk_rows": int64(resp.rows), "chunk_sum": resp.sum}}, []string{"chunk_rows", "chunk_sum"}, nil
+}
+
+func TestDiffTableByChecksum(t *testing.T) {
+	const schema = "CREATE TABLE t (id INTEGER PRIMARY KEY, v TEXT)"
+	newSides := func(t *testing.T, sourceResp []checksumResponse, targetResp []checksumResponse) (tableSide, tableSide, *fakeChecksumDB) {
+		source := &fakeChecksumDB{Database: openTestSQLite(t, "source.db", schema, "INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')"), responses: sourceResp}
+		target := &fakeChecksumDB{Database: openTestSQLite(t, "target.db", schema, "INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'x'), (5, 'e')"), responses: targetResp}
+		return tableSide{inst: source, dialect: "sqlite", queryTable: "t"}, tableSide{inst: target, dialect: "sqlite", queryTable: "t"}, target
+	}
+	key := matchKey{columns: []string{"id"}}
+	collect := func(updates *[]string, same *int, ends *[][]interface{}) chunkDiffHandler {
+		return func(diff chunkDiff) error {
+			for _, change := range diff.updates {
+				*updates = append(*updates, change.key)
+			}
+			*same += diff.same
+			if diff.rangeDone {
+				*ends = append(*ends, diff.rangeEnd)
+			}
+			return nil
+		}
+	}
+
+	t.Run("一致的分块跳过逐行对比", func(t *testing.T) {
+		// 分块 (,2] (2,4] (4,]：第二块校验和不一致
+		source, target, _ := newSides(t,
+			[]checksumResponse{{rows: 2, sum: "s1"}, {rows: 2, sum: "s2"}, {rows: 1, sum: "s3"}},
+			[]checksumResponse{{rows: 2, sum: "s1"}, {rows: 2, sum: "t2"}, {rows: 1, sum: "s3"}},
+		)
+		var updates []string
+		var ends [][]interface{}
+		same := 0
+		stats, err := diffTableByChecksum(context.Background(), source, target, key, []string{"id", "v"}, nil, 2, newValueComparer(CompareOptions{}, source, target), collect(&updates, &same, &ends))
+		if err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		if !stats.checksum || stats.chunks != 3 || stats.skipped != 2 || stats.fallback != nil {
+			t.Fatalf("分块统计不正确：%+v", stats)
+		}
+		if !reflect.DeepEqual(updates, []string{"4"}) || same != 4 {
+			t.Fatalf("对比结果不正确：updates=%v same=%d", updates, same)
+		}
+		if len(ends) != 3 || ends[2] != nil {
+			t.Fatalf("每个分块都应标记完成，最后一块没有上界：%v", ends)
+		}
+	})
+
+	t.Run("校验和失败后改为逐行对比", func(t *testing.T) {
+		source, target, fakeTarget := newSides(t,
+			[]checksumResponse{{rows: 2, sum: "s1"}, {rows: 2, sum: "s2"}},
+			[]checksumResponse{{rows: 2, sum: "s1"}, {err: errors.New("列类型无法拼接")}},
+		)
+		var updates []string
+		var ends [][]interface{}
+		same := 0
+		stats, err := diffTableByChecksum(context.Background(), source, target, key, []string{"id", "v"}, nil, 2, newValueComparer(CompareOptions{}, source, target), collect(&updates, &same, &ends))
+		if err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		if stats.fallback == nil || !strings.Contains(stats.fallback.Error(), "计算目标表校验和失败") {
+			t.Fatalf("应记录校验和失败原因：%+v", stats)
+		}
+		if stats.chunks != 3 || stats.skipped != 1 {
+			t.Fatalf("分块统计不正确：%+v", stats)
+		}
+		if fakeTarget.checksums != 2 {
+			t.Fatalf("失败后的分块不应再计算校验和，实际查询 %d 次", fakeTarget.checksums)
+		}
+		if !reflect.DeepEqual(updates, []string{"4"}) || same != 4 {
+			t.Fatalf("逐行对比结果不正确：updates=%v same=%d", updates, same)
+		}
+	})
+}
+
+func TestDiffTableChecksumGating(t *testing.T) {
+	const schema = "CREATE TABLE t (id INTEGER PRIMARY KEY, v TEXT)"
+	source := tableSide{inst: openTestSQLite(t, "source.db", schema, "INSERT INTO t VALUES (1, 'a')"), dialect: "sqlite", queryTable: "t"}
+	target := tableSide{inst: openTestSQLite(t, "target.db", schema), dialect: "sqlite", queryTable: "t"}
+	config := SyncConfig{CompareMode: "checksum"}
+	key := matchKey{columns: []string{"id"}}
+	inserts := 0
+	handle := func(diff chunkDiff) error {
+		inserts += len(diff.inserts)
+		return nil
+	}
+
+	// SQLite 不支持校验和，逐行对比
+	stats, err := diffTable(context.Background(), config, source, target, key, nil, nil, handle)
+	if err != nil || stats.checksum || inserts != 1 {
+		t.Fatalf("不支持校验和的方言应逐行对比：stats=%+v inserts=%d err=%v", stats, inserts, err)
+	}
+
+	// 配置了字段映射时两端字段不再一一对应，即使方言支持也逐行对比
+	source.mapRow = func(row map[string]interface{}) (map[string]interface{}, error) { return row, nil }
+	source.dialect, target.dialect = "mysql", "mysql"
+	inserts = 0
+	stats, err = diffTable(context.Background(), config, source, target, key, nil, nil, handle)
+	if err != nil || stats.checksum || inserts != 1 {
+		t.Fatalf("字段映射时应逐行对比：stats=%+v inserts=%d err=%v", stats, inserts, err)
+	}
+}
@@ -0,0 +1,382 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/db"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// syncChunkSize 控制分块对比时每块读取的源表行数，以及目标表在同一键范围内每页读取的行数。
+const syncChunkSize = 2000
+
+// tableSide 描述参与对比的一端。
+type tableSide struct {
+	inst       db.Database
+	dialect    string
+	queryTable string
+
+	// 源表配置了字段映射时，keyColumns 为该端的键列名（匹配键使用目标字段名），
+	// mapRow 把读出的行转换为目标字段名与取值。
+	keyColumns []string
+	mapRow     func(row map[string]interface{}) (map[string]interface{}, error)
+
+	// filter 是读取该端时附加的过滤条件（WHERE 片段）。
+	// lookupTarget 表示源表只读取了部分行（增量水位，或过滤条件无法同样作用于目标表），
+	// 此时目标表按键查找对应行，不检测删除。
+	filter       string
+	lookupTarget bool
+
+	// columnTypes 为该端的字段类型（小写目标字段名 -> 类型），用于按类型比较取值；为空时按取值本身比较。
+	columnTypes map[string]string
+}
+
+// queryKey 返回在该端生成 SQL 时使用的键。
+func (side tableSide) queryKey(key matchKey) matchKey {
+	if len(side.keyColumns) != len(key.columns) {
+		return key
+	}
+	return matchKey{columns: side.keyColumns, index: key.index}
+}
+
+// conditions 返回读取该端键范围 (after, upTo] 时的全部过滤条件。
+func (side tableSide) conditions(key matchKey, after []interface{}, upTo []interface{}) []string {
+	conds := keyRangeConditions(side.dialect, side.queryKey(key), after, upTo)
+	if side.filter != "" {
+		conds = append(conds, side.filter)
+	}
+	return conds
+}
+
+// rowChange 是一行键相同但内容不同的数据。
+type rowChange struct {
+	key     string
+	source  map[string]interface{}
+	target  map[string]interface{}
+	changed []string
+}
+
+// chunkDiff 是一次回调携带的对比结果，同一键范围的结果可能分多次回调给出。
+type chunkDiff struct {
+	inserts    []map[string]interface{} // 仅源表存在的行
+	updates    []rowChange
+	deletes    []map[string]interface{} // 仅目标表存在的行
+	same       int
+	sourceRows int // 本次回调新读取的源表行数
+
+	// rangeDone 表示这是某个键范围的最后一次回调，处理完后键不大于 rangeEnd 的数据都已处理；
+	// 最后一个范围没有上界，rangeEnd 为空。
+	rangeDone bool
+	rangeEnd  []interface{}
+}
+
+type chunkDiffHandler func(diff chunkDiff) error
+
+// diffTableByKey 按匹配键分块对比源表与目标表：源表按键有序做 keyset 分页，
+// 每块再分页读取目标表在同一键范围 (上一块末键, 本块末键] 内的行做哈希匹配，
+// 内存占用只与块大小相关，差异按块回调，调用方可边对比边应用。
+//
+// 键范围依赖两端对键列的排序一致，diffTable 只在 orderedKeyMerge 成立时使用本函数。
+// start 不为空时只对比键大于 start 的部分，用于从断点继续。
+func diffTableByKey(ctx context.Context, source tableSide, target tableSide, key matchKey, start []interface{}, chunkSize int, cmp *valueComparer, handle chunkDiffHandler) error {
+	if chunkSize <= 0 {
+		chunkSize = syncChunkSize
+	}
+
+	after := start
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		srcRows, err := fetchKeysetPage(ctx, source, key, after, nil, chunkSize)
+		if err != nil {
+			return fmt.Errorf("读取源表失败: %w", err)
+		}
+		lastChunk := len(srcRows) < chunkSize
+		var upper []interface{}
+		if !lastChunk {
+			upper = key.values(srcRows[len(srcRows)-1])
+		}
+
+		if err := diffKeyRange(ctx, target, key, srcRows, after, upper, chunkSize, cmp, handle); err != nil {
+			return err
+		}
+
+		if lastChunk {
+			return nil
+		}
+		after = upper
+	}
+}
+
+// diffKeyRange 对比键范围 (after, upper] 内已读出的源表行与目标表行，upper 为空表示不设上界。
+// 目标表在该范围内分页读取，每页回调一次；仅源表存在的行在最后一页一并给出。
+func diffKeyRange(ctx context.Context, target tableSide, key matchKey, srcRows []map[string]interface{}, after []interface{}, upper []interface{}, pageSize int, cmp *valueComparer, handle chunkDiffHandler) error {
+	pending := make(map[string]map[string]interface{}, len(srcRows))
+	order := make([]string, 0, len(srcRows))
+	for _, row := range srcRows {
+		k, ok := cmp.rowKey(key, row)
+		if !ok {
+			continue
+		}
+		if _, dup := pending[k]; !dup {
+			order = append(order, k)
+		}
+		pending[k] = row
+	}
+
+	sourceRows := len(srcRows)
+	targetAfter := after
+	for {
+		tRows, err := fetchKeysetPage(ctx, target, key, targetAfter, upper, pageSize)
+		if err != nil {
+			return fmt.Errorf("读取目标表失败: %w", err)
+		}
+
+		diff := chunkDiff{sourceRows: sourceRows}
+		sourceRows = 0
+		for _, tRow := range tRows {
+			k, ok := cmp.rowKey(key, tRow)
+			if !ok {
+				continue
+			}
+			sRow, exists := pending[k]
+			if !exists {
+				diff.deletes = append(diff.deletes, tRow)
+				continue
+			}
+			delete(pending, k)
+			if changed := cmp.changedColumns(sRow, tRow); len(changed) > 0 {
+				display, _ := key.rowKey(sRow)
+				diff.updates = append(diff.updates, rowChange{key: display, source: sRow, target: tRow, changed: changed})
+			} else {
+				diff.same++
+			}
+		}
+
+		lastPage := len(tRows) < pageSize
+		if lastPage {
+			for _, k := range order {
+				if row, ok := pending[k]; ok {
+					diff.inserts = append(diff.inserts, row)
+				}
+			}
+			diff.rangeDone = true
+			diff.rangeEnd = upper
+		}
+		if err := handle(diff); err != nil {
+			return err
+		}
+		if lastPage {
+			return nil
+		}
+		targetAfter = key.values(tRows[len(tRows)-1])
+	}
+}
+
+// orderedKeyMerge 判断能否按键范围归并对比两端：同一方言族的两端对键列排序一致；
+// 跨方言族时只有数值键可靠，字符键受排序规则（大小写、字节序）影响，时间键受时区影响，
+// 按一端的键范围读取另一端会漏读或错位，把相同的行误判为删除与插入。
+func orderedKeyMerge(source tableSide, target tableSide, key matchKey, cmp *valueComparer) bool {
+	if db.Capabilities(source.dialect).Family == db.Capabilities(target.dialect).Family {
+		return true
+	}
+	for _, col := range key.columns {
+		switch cmp.kinds[strings.ToLower(col)] {
+		case cmpExact, cmpFloat:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// diffTableUnordered 在两端对键的排序可能不一致时对比：先按源表键序分块到目标表查找插入与更新，
+// 再按目标表键序分块到源表查找删除，两步都只依赖各自一端的排序。
+func diffTableUnordered(ctx context.Context, source tableSide, target tableSide, key matchKey, start []interface{}, chunkSize int, cmp *valueComparer, handle chunkDiffHandler) error {
+	if err := diffTableByLookup(ctx, source, target, key, start, chunkSize, cmp, handle); err != nil {
+		return err
+	}
+	return diffTargetOnlyRows(ctx, source, target, key, chunkSize, cmp, handle)
+}
+
+// diffTargetOnlyRows 按键分页读取目标表，每页按键到源表查找，源表不存在的行作为删除回调。
+// 回调不标记 rangeDone，断点续传时删除检测总是对整个目标表重新进行。
+func diffTargetOnlyRows(ctx context.Context, source tableSide, target tableSide, key matchKey, chunkSize int, cmp *valueComparer, handle chunkDiffHandler) error {
+	if chunkSize <= 0 {
+		chunkSize = syncChunkSize
+	}
+
+	var after []interface{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tRows, err := fetchKeysetPage(ctx, target, key, after, nil, chunkSize)
+		if err != nil {
+			return fmt.Errorf("读取目标表失败: %w", err)
+		}
+		sRows, err := fetchRowsByKeys(ctx, source, key, tRows)
+		if err != nil {
+			return fmt.Errorf("读取源表失败: %w", err)
+		}
+		existing := make(map[string]bool, len(sRows))
+		for _, row := range sRows {
+			if k, ok := cmp.rowKey(key, row); ok {
+				existing[k] = true
+			}
+		}
+
+		var diff chunkDiff
+		for _, tRow := range tRows {
+			if k, ok := cmp.rowKey(key, tRow); ok && !existing[k] {
+				diff.deletes = append(diff.deletes, tRow)
+			}
+		}
+		if len(diff.deletes) > 0 {
+			if err := handle(diff); err != nil {
+				return err
+			}
+		}
+
+		if len(tRows) < chunkSize {
+			return nil
+		}
+		after = key.values(tRows[len(tRows)-1])
+	}
+}
+
+// fetchKeysetPage 读取键大于 after 且不大于 upTo 的一页数据，按键升序；after/upTo 为空表示不限制该侧。
+func fetchKeysetPage(ctx context.Context, side tableSide, key matchKey, after []interface{}, upTo []interface{}, limit int) ([]map[string]interface{}, error) {
+	rows, err := queryKeysetPage(ctx, side, buildKeysetQuery(side.dialect, side.queryTable, "*", side.queryKey(key), side.conditions(key, after, upTo), limit), limit)
+	if err != nil || side.mapRow == nil {
+		return rows, err
+	}
+	for i, row := range rows {
+		if rows[i], err = side.mapRow(row); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// fetchKeyPage 与 fetchKeysetPage 相同，但只读取键列，用于确定分块边界。
+func fetchKeyPage(ctx context.Context, side tableSide, key matchKey, after []interface{}, limit int) ([]map[string]interface{}, error) {
+	sideKey := side.queryKey(key)
+	caps := db.Capabilities(side.dialect)
+	quoted := make([]string, len(sideKey.columns))
+	for i, col := range sideKey.columns {
+		quoted[i] = caps.QuoteIdent(col)
+	}
+	rows, err := queryKeysetPage(ctx, side, buildKeysetQuery(side.dialect, side.queryTable, strings.Join(quoted, ", "), sideKey, side.conditions(key, after, nil), limit), limit)
+	if err != nil || len(side.keyColumns) == 0 {
+		return rows, err
+	}
+	// 键列改回匹配键使用的字段名
+	for i, row := range rows {
+		mapped := make(map[string]interface{}, len(key.columns))
+		for j, col := range key.columns {
+			mapped[col] = lookupMappedColumn(row, sideKey.columns[j])
+		}
+		rows[i] = mapped
+	}
+	return rows, nil
+}
+
+func queryKeysetPage(ctx context.Context, side tableSide, query string, limit int) ([]map[string]interface{}, error) {
+	batchSize := limit
+	if batchSize <= 0 {
+		batchSize = syncChunkSize
+	}
+	rows := make([]map[string]interface{}, 0, batchSize)
+	err := db.StreamQuery(ctx, side.inst, query, batchSize, func(batch db.RowBatch) error {
+		rows = append(rows, batch.Rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// buildKeysetQuery 生成满足 conds 且按键有序的分页查询，limit 不大于 0 时不分页。
+func buildKeysetQuery(dialect string, queryTable string, selectList string, key matchKey, conds []string, limit int) string {
+	caps := db.Capabilities(dialect)
+	quoted := make([]string, len(key.columns))
+	for i, col := range key.columns {
+		quoted[i] = caps.QuoteIdent(col)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList, caps.QuoteQualifiedIdent(queryTable))
+	if len(conds) > 0 {
+		query += " WHERE (" + strings.Join(conds, ") AND (") + ")"
+	}
+	query += " ORDER BY " + strings.Join(quoted, ", ")
+	if limit <= 0 {
+		return query
+	}
+
+	switch caps.Pagination {
+	case db.PaginationOffsetFetch:
+		return fmt.Sprintf("%s OFFSET 0 ROWS FETCH NEXT %d ROWS ONLY", query, limit)
+	case db.PaginationRowNum:
+		return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", query, limit)
+	default:
+		return fmt.Sprintf("%s LIMIT %d", query, limit)
+	}
+}
+
+// keyRangeConditions 生成键范围 (after, upTo] 的过滤条件。多列键的范围条件展开为 OR/AND 形式，
+// 以兼容不支持行值比较的方言（SQL Server、Oracle 等）。
+func keyRangeConditions(dialect string, key matchKey, after []interface{}, upTo []interface{}) []string {
+	caps := db.Capabilities(dialect)
+	quoted := make([]string, len(key.columns))
+	for i, col := range key.columns {
+		quoted[i] = caps.QuoteIdent(col)
+	}
+
+	conds := make([]string, 0, 3)
+	if key.index != "" {
+		// 唯一索引键允许 NULL，NULL 键无法参与范围分页与匹配
+		notNull := make([]string, len(quoted))
+		for i, col := range quoted {
+			notNull[i] = col + " IS NOT NULL"
+		}
+		conds = append(conds, strings.Join(notNull, " AND "))
+	}
+	if len(after) == len(quoted) {
+		conds = append(conds, keyCompareCondition(dialect, quoted, after, ">", false))
+	}
+	if len(upTo) == len(quoted) {
+		conds = append(conds, keyCompareCondition(dialect, quoted, upTo, "<", true))
+	}
+	return conds
+}
+
+// keyCompareCondition 生成 (c1, c2, ...) op (v1, v2, ...) 的展开形式，orEqual 为 true 时包含相等。
+func keyCompareCondition(dialect string, quoted []string, values []interface{}, op string, orEqual bool) string {
+	if len(quoted) == 1 {
+		if orEqual {
+			op += "="
+		}
+		return fmt.Sprintf("%s %s %s", quoted[0], op, sqlLiteral(dialect, values[0]))
+	}
+
+	branches := make([]string, 0, len(quoted)+1)
+	for i := range quoted {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", quoted[j], sqlLiteral(dialect, values[j])))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", quoted[i], op, sqlLiteral(dialect, values[i])))
+		branches = append(branches, strings.Join(parts, " AND "))
+	}
+	if orEqual {
+		parts := make([]string, len(quoted))
+		for i := range quoted {
+			parts[i] = fmt.Sprintf("%s = %s", quoted[i], sqlLiteral(dialect, values[i]))
+		}
+		branches = append(branches, strings.Join(parts, " AND "))
+	}
+	return "(" + strings.Join(branches, ") OR (") + ")"
+}
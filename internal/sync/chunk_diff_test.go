@@ -0,0 +1,282 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyRangeConditions(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		dialect string
+		key     matchKey
+		after   []interface{}
+		upTo    []interface{}
+		want    []string
+	}{
+		{
+			name:    "单列主键无范围",
+			dialect: "mysql",
+			key:     matchKey{columns: []string{"id"}},
+			want:    []string{},
+		},
+		{
+			name:    "单列主键",
+			dialect: "mysql",
+			key:     matchKey{columns: []string{"id"}},
+			after:   []interface{}{int64(10)},
+			upTo:    []interface{}{int64(20)},
+			want:    []string{"`id` > 10", "`id` <= 20"},
+		},
+		{
+			name:    "复合主键",
+			dialect: "postgres",
+			key:     matchKey{columns: []string{"tenant", "id"}},
+			after:   []interface{}{"a", int64(1)},
+			upTo:    []interface{}{"b", int64(5)},
+			want: []string{
+				`("tenant" > 'a') OR ("tenant" = 'a' AND "id" > 1)`,
+				`("tenant" < 'b') OR ("tenant" = 'b' AND "id" < 5) OR ("tenant" = 'b' AND "id" = 5)`,
+			},
+		},
+		{
+			name:    "SQL Server 复合键使用 N 前缀与 ISO 时间",
+			dialect: "sqlserver",
+			key:     matchKey{columns: []string{"code", "at"}},
+			after:   []interface{}{"x'y", ts},
+			want:    []string{`([code] > N'x''y') OR ([code] = N'x''y' AND [at] > '2024-03-01T08:30:00')`},
+		},
+		{
+			name:    "Oracle 时间键",
+			dialect: "oracle",
+			key:     matchKey{columns: []string{"AT"}},
+			upTo:    []interface{}{ts},
+			want:    []string{`"AT" <= TO_TIMESTAMP('2024-03-01 08:30:00.000000', 'YYYY-MM-DD HH24:MI:SS.FF6')`},
+		},
+		{
+			name:    "唯一索引键排除 NULL",
+			dialect: "mysql",
+			key:     matchKey{columns: []string{"a", "b"}, index: "uk_ab"},
+			after:   []interface{}{int64(1), int64(2)},
+			want: []string{
+				"`a` IS NOT NULL AND `b` IS NOT NULL",
+				"(`a` > 1) OR (`a` = 1 AND `b` > 2)",
+			},
+		},
+		{
+			name:    "唯一索引键无范围时仍排除 NULL",
+			dialect: "dameng",
+			key:     matchKey{columns: []string{"CODE"}, index: "UK_CODE"},
+			want:    []string{`"CODE" IS NOT NULL`},
+		},
+		{
+			name:    "键值个数与键列不一致时忽略范围",
+			dialect: "mysql",
+			key:     matchKey{columns: []string{"a", "b"}},
+			after:   []interface{}{int64(1)},
+			want:    []string{},
+		},
+	}
+	for _, tc := range cases {
+		got := keyRangeConditions(tc.dialect, tc.key, tc.after, tc.upTo)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s：\n实际=%q\n期望=%q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBuildKeysetQuery(t *testing.T) {
+	key := matchKey{columns: []string{"tenant", "id"}}
+	cases := []struct {
+		dialect string
+		table   string
+		conds   []string
+		limit   int
+		want    string
+	}{
+		{
+			dialect: "mysql",
+			table:   "shop.orders",
+			conds:   []string{"`id` > 1"},
+			limit:   100,
+			want:    "SELECT * FROM `shop`.`orders` WHERE (`id` > 1) ORDER BY `tenant`, `id` LIMIT 100",
+		},
+		{
+			dialect: "postgres",
+			table:   "public.orders",
+			conds:   []string{`"id" > 1`, "status = 1"},
+			limit:   100,
+			want:    `SELECT * FROM "public"."orders" WHERE ("id" > 1) AND (status = 1) ORDER BY "tenant", "id" LIMIT 100`,
+		},
+		{
+			dialect: "sqlserver",
+			table:   "dbo.orders",
+			limit:   50,
+			want:    "SELECT * FROM [dbo].[orders] ORDER BY [tenant], [id] OFFSET 0 ROWS FETCH NEXT 50 ROWS ONLY",
+		},
+		{
+			dialect: "oracle",
+			table:   "APP.ORDERS",
+			conds:   []string{`"id" > 1`},
+			limit:   50,
+			want:    `SELECT * FROM (SELECT * FROM "APP"."ORDERS" WHERE ("id" > 1) ORDER BY "tenant", "id") WHERE ROWNUM <= 50`,
+		},
+		{
+			dialect: "sqlite",
+			table:   "orders",
+			limit:   0,
+			want:    `SELECT * FROM "orders" ORDER BY "tenant", "id"`,
+		},
+	}
+	for _, tc := range cases {
+		if got := buildKeysetQuery(tc.dialect, tc.table, "*", key, tc.conds, tc.limit); got != tc.want {
+			t.Fatalf("buildKeysetQuery(%q)：\n实际=%s\n期望=%s", tc.dialect, got, tc.want)
+		}
+	}
+}
+
+func TestTableSideQueryKeyUsesMappedColumns(t *testing.T) {
+	key := matchKey{columns: []string{"id"}, index: "uk_id"}
+	side := tableSide{dialect: "mysql", keyColumns: []string{"src_id"}, filter: "deleted = 0"}
+
+	got := side.conditions(key, []interface{}{int64(7)}, nil)
+	want := []string{"`src_id` IS NOT NULL", "`src_id` > 7", "deleted = 0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("映射键列的过滤条件不正确：\n实际=%q\n期望=%q", got, want)
+	}
+
+	// 键列个数与匹配键不一致时沿用匹配键
+	side.keyColumns = []string{"a", "b"}
+	if got := side.queryKey(key); !reflect.DeepEqual(got, key) {
+		t.Fatalf("键列个数不一致时应沿用匹配键，实际=%+v", got)
+	}
+}
+
+// openTestSQLite 在临时目录创建 SQLite 库并执行 stmts，测试结束时关闭。
+func openTestSQLite(t *testing.T, name string, stmts ...string) db.Database {
+	t.Helper()
+	return openTestSQLiteAt(t, filepath.Join(t.TempDir(), name), stmts...)
+}
+
+func openTestSQLiteAt(t *testing.T, path string, stmts ...string) db.Database {
+	t.Helper()
+	inst, err := db.NewDatabase("sqlite")
+	if err != nil {
+		t.Fatalf("创建 SQLite 实例失败：%v", err)
+	}
+	if err := inst.Connect(connection.ConnectionConfig{Type: "sqlite", Host: path}); err != nil {
+		t.Fatalf("连接 SQLite 失败：%v", err)
+	}
+	t.Cleanup(func() { inst.Close() })
+	for _, stmt := range stmts {
+		if _, err := inst.Exec(stmt); err != nil {
+			t.Fatalf("执行 %q 失败：%v", stmt, err)
+		}
+	}
+	return inst
+}
+
+func TestOrderedKeyMerge(t *testing.T) {
+	cases := []struct {
+		name          string
+		sourceDialect string
+		targetDialect string
+		types         map[string]string
+		key           []string
+		want          bool
+	}{
+		{"同一方言族的字符键", "mysql", "mariadb", map[string]string{"code": "varchar(20)"}, []string{"code"}, true},
+		{"跨方言族的整数键", "mysql", "postgres", map[string]string{"id": "bigint"}, []string{"id"}, true},
+		{"跨方言族的定点数键", "oracle", "sqlserver", map[string]string{"id": "NUMBER(10,2)"}, []string{"id"}, true},
+		{"跨方言族的字符键", "mysql", "postgres", map[string]string{"code": "varchar(20)"}, []string{"code"}, false},
+		{"跨方言族的时间键", "postgres", "mysql", map[string]string{"at": "timestamp"}, []string{"at"}, false},
+		{"跨方言族的复合键含字符列", "mysql", "oracle", map[string]string{"id": "int", "code": "char(2)"}, []string{"id", "code"}, false},
+		{"跨方言族且没有类型信息", "mysql", "postgres", nil, []string{"id"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			source := tableSide{dialect: c.sourceDialect, columnTypes: c.types}
+			target := tableSide{dialect: c.targetDialect, columnTypes: c.types}
+			cmp := newValueComparer(CompareOptions{}, source, target)
+			if got := orderedKeyMerge(source, target, matchKey{columns: c.key}, cmp); got != c.want {
+				t.Fatalf("期望=%v，实际=%v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestDiffKeyRangeMatchesNormalizedKeys(t *testing.T) {
+	target := openTestSQLite(t, "target.db",
+		"CREATE TABLE t (id TEXT PRIMARY KEY, v INTEGER)",
+		"INSERT INTO t VALUES ('1.5', 1), ('2', 2)",
+	)
+	side := tableSide{inst: target, dialect: "sqlite", queryTable: "t"}
+	source := tableSide{dialect: "mysql", columnTypes: map[string]string{"id": "decimal(10,2)", "v": "int"}}
+	cmp := newValueComparer(CompareOptions{}, source, tableSide{dialect: "sqlite", columnTypes: map[string]string{"id": "numeric", "v": "integer"}})
+	srcRows := []map[string]interface{}{
+		{"id": "1.50", "v": int64(1)},
+		{"id": "2.00", "v": int64(3)},
+	}
+
+	var got chunkDiff
+	err := diffKeyRange(context.Background(), side, matchKey{columns: []string{"id"}}, srcRows, nil, nil, 10, cmp, func(diff chunkDiff) error {
+		got = diff
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	if len(got.inserts) != 0 || len(got.deletes) != 0 {
+		t.Fatalf("键值表示不同的同一行不应判为插入或删除：inserts=%v deletes=%v", got.inserts, got.deletes)
+	}
+	if got.same != 1 || len(got.updates) != 1 || got.updates[0].key != "2.00" {
+		t.Fatalf("期望 1 行相同、1 行更新（键为源表原值），实际 same=%d updates=%+v", got.same, got.updates)
+	}
+}
+
+func TestDiffTableUnordered(t *testing.T) {
+	source := openTestSQLite(t, "source.db",
+		"CREATE TABLE t (code TEXT PRIMARY KEY, v INTEGER)",
+		"INSERT INTO t VALUES ('a', 1), ('b', 2), ('c', 3)",
+	)
+	target := openTestSQLite(t, "target.db",
+		"CREATE TABLE t (code TEXT PRIMARY KEY, v INTEGER)",
+		"INSERT INTO t VALUES ('b', 2), ('c', 30), ('z', 9)",
+	)
+	sourceSide := tableSide{inst: source, dialect: "sqlite", queryTable: "t"}
+	targetSide := tableSide{inst: target, dialect: "sqlite", queryTable: "t"}
+	cmp := newValueComparer(CompareOptions{}, sourceSide, targetSide)
+
+	var inserts, deletes []string
+	var updates []string
+	same, sourceRows := 0, 0
+	err := diffTableUnordered(context.Background(), sourceSide, targetSide, matchKey{columns: []string{"code"}}, nil, 2, cmp, func(diff chunkDiff) error {
+		for _, row := range diff.inserts {
+			inserts = append(inserts, row["code"].(string))
+		}
+		for _, row := range diff.deletes {
+			deletes = append(deletes, row["code"].(string))
+		}
+		for _, change := range diff.updates {
+			updates = append(updates, change.key)
+		}
+		same += diff.same
+		sourceRows += diff.sourceRows
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	if !reflect.DeepEqual(inserts, []string{"a"}) || !reflect.DeepEqual(updates, []string{"c"}) || !reflect.DeepEqual(deletes, []string{"z"}) {
+		t.Fatalf("对比结果不正确：inserts=%v updates=%v deletes=%v", inserts, updates, deletes)
+	}
+	if same != 1 || sourceRows != 3 {
+		t.Fatalf("期望 same=1 sourceRows=3，实际 same=%d sourceRows=%d", same, sourceRows)
+	}
+}
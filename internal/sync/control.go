@@ -0,0 +1,179 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	stdsync "sync"
+)
+
+// ErrSyncCancelled 表示同步被 CancelSync 取消。
+var ErrSyncCancelled = errors.New("同步已取消")
+
+// 同步结束状态，随 sync:done 事件发送。
+const (
+	StatusSuccess   = "success"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// ResultStatus 返回同步结果对应的结束状态。
+func ResultStatus(res SyncResult) string {
+	return endStatus(res.Success, res.Cancelled)
+}
+
+// Status 返回运行记录对应的结束状态。
+func (r SyncRunRecord) Status() string {
+	return endStatus(r.Success, r.Cancelled)
+}
+
+func endStatus(success bool, cancelled bool) string {
+	switch {
+	case cancelled:
+		return StatusCancelled
+	case success:
+		return StatusSuccess
+	default:
+		return StatusFailed
+	}
+}
+
+// syncControl 控制一次正在运行的同步。暂停在批次之间的安全点生效；取消除在安全点生效外，
+// 还会中止正在应用的批次并回滚其事务。已提交的批次保留（断点照常记录），未提交的批次被放弃。
+type syncControl struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       stdsync.Mutex
+	paused   bool
+	resumed  chan struct{} // 暂停期间有效，恢复或取消时关闭
+	pauseSeq int           // 每次暂停加一，用于只通知一次
+	notified int
+}
+
+func newSyncControl() *syncControl {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &syncControl{ctx: ctx, cancel: cancel}
+}
+
+var (
+	syncControls   = make(map[string]*syncControl)
+	syncControlsMu stdsync.Mutex
+)
+
+// startSyncControl 为 jobID 登记控制器，release 在同步结束时调用。jobID 为空时不登记。
+func startSyncControl(jobID string) (*syncControl, func()) {
+	c := newSyncControl()
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return c, c.cancel
+	}
+	syncControlsMu.Lock()
+	syncControls[jobID] = c
+	syncControlsMu.Unlock()
+	return c, func() {
+		syncControlsMu.Lock()
+		if syncControls[jobID] == c {
+			delete(syncControls, jobID)
+		}
+		syncControlsMu.Unlock()
+		c.cancel()
+	}
+}
+
+func lookupSyncControl(jobID string) (*syncControl, error) {
+	syncControlsMu.Lock()
+	defer syncControlsMu.Unlock()
+	c, ok := syncControls[strings.TrimSpace(jobID)]
+	if !ok {
+		return nil, fmt.Errorf("同步任务不存在或已结束：%s", jobID)
+	}
+	return c, nil
+}
+
+// CancelSync 取消正在运行的同步。
+func CancelSync(jobID string) error {
+	c, err := lookupSyncControl(jobID)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.paused {
+		c.paused = false
+		close(c.resumed)
+	}
+	c.mu.Unlock()
+	c.cancel()
+	return nil
+}
+
+// PauseSync 暂停正在运行的同步。已开始应用的批次会先提交，尚未写入的批次在写入目标库前停下。
+func PauseSync(jobID string) error {
+	c, err := lookupSyncControl(jobID)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx.Err() != nil {
+		return ErrSyncCancelled
+	}
+	if !c.paused {
+		c.paused = true
+		c.resumed = make(chan struct{})
+		c.pauseSeq++
+	}
+	return nil
+}
+
+// ResumeSync 恢复已暂停的同步。
+func ResumeSync(jobID string) error {
+	c, err := lookupSyncControl(jobID)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		c.paused = false
+		close(c.resumed)
+	}
+	return nil
+}
+
+func (c *syncControl) cancelled() bool {
+	return c.ctx.Err() != nil
+}
+
+// wait 是安全点：已取消时返回 ErrSyncCancelled；暂停时阻塞到恢复或取消。
+// 每次暂停只有第一个到达安全点的调用方收到 onPause、onResume 通知。
+func (c *syncControl) wait(onPause func(), onResume func()) error {
+	if c.cancelled() {
+		return ErrSyncCancelled
+	}
+	c.mu.Lock()
+	if !c.paused {
+		c.mu.Unlock()
+		return nil
+	}
+	resumed := c.resumed
+	notify := c.notified != c.pauseSeq
+	c.notified = c.pauseSeq
+	c.mu.Unlock()
+
+	if notify && onPause != nil {
+		onPause()
+	}
+	select {
+	case <-resumed:
+	case <-c.ctx.Done():
+	}
+	if c.cancelled() {
+		return ErrSyncCancelled
+	}
+	if notify && onResume != nil {
+		onResume()
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncControlRegistry(t *testing.T) {
+	if err := PauseSync("missing"); err == nil || !strings.Contains(err.Error(), "同步任务不存在或已结束") {
+		t.Fatalf("任务不存在时应返回错误，实际=%v", err)
+	}
+
+	// jobID 为空时不登记，release 仍会取消
+	c, release := startSyncControl(" ")
+	if _, err := lookupSyncControl(""); err == nil {
+		t.Fatalf("jobID 为空时不应登记控制器")
+	}
+	release()
+	if !c.cancelled() {
+		t.Fatalf("release 后控制器应已取消")
+	}
+
+	c, release = startSyncControl("job-registry")
+	if found, err := lookupSyncControl(" job-registry "); err != nil || found != c {
+		t.Fatalf("应按去除空白后的 jobID 找到控制器：%v", err)
+	}
+	// 同一 jobID 重新登记后，旧的 release 不应移除新控制器
+	next, releaseNext := startSyncControl("job-registry")
+	release()
+	if found, err := lookupSyncControl("job-registry"); err != nil || found != next {
+		t.Fatalf("旧的 release 移除了新控制器：%v", err)
+	}
+	releaseNext()
+	if _, err := lookupSyncControl("job-registry"); err == nil {
+		t.Fatalf("release 后应移除控制器")
+	}
+}
+
+// waitResult 在后台调用 safePoint，返回接收结果的通道。
+func waitResult(run *syncRun) <-chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- run.safePoint("items") }()
+	return ch
+}
+
+func assertBlocked(t *testing.T, ch <-chan error) {
+	t.Helper()
+	select {
+	case err := <-ch:
+		t.Fatalf("暂停期间安全点不应返回，实际=%v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func receive(t *testing.T, ch <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("安全点未返回")
+		return nil
+	}
+}
+
+func TestSyncControlPauseResume(t *testing.T) {
+	control, release := startSyncControl("job-pause")
+	defer release()
+	res := &SyncResult{}
+	run := newSyncRun(NewSyncEngine(Reporter{}), control, SyncConfig{JobID: "job-pause"}, res, nil, nil, false, true, "insert_update")
+
+	if err := run.safePoint("items"); err != nil {
+		t.Fatalf("未暂停时安全点应直接通过：%v", err)
+	}
+	if err := PauseSync("job-pause"); err != nil {
+		t.Fatalf("PauseSync 返回错误：%v", err)
+	}
+	// 重复暂停不产生新的暂停
+	if err := PauseSync("job-pause"); err != nil {
+		t.Fatalf("PauseSync 返回错误：%v", err)
+	}
+	first, second := waitResult(run), waitResult(run)
+	assertBlocked(t, first)
+	assertBlocked(t, second)
+
+	if err := ResumeSync("job-pause"); err != nil {
+		t.Fatalf("ResumeSync 返回错误：%v", err)
+	}
+	for _, ch := range []<-chan error{first, second} {
+		if err := receive(t, ch); err != nil {
+			t.Fatalf("恢复后安全点应通过：%v", err)
+		}
+	}
+	logs := strings.Join(res.Logs, "\n")
+	if strings.Count(logs, "同步已暂停") != 1 || strings.Count(logs, "同步已恢复") != 1 {
+		t.Fatalf("每次暂停只应通知一次：\n%s", logs)
+	}
+	if err := ResumeSync("job-pause"); err != nil {
+		t.Fatalf("未暂停时恢复不应报错：%v", err)
+	}
+
+	// 再次暂停会重新通知
+	if err := PauseSync("job-pause"); err != nil {
+		t.Fatalf("PauseSync 返回错误：%v", err)
+	}
+	ch := waitResult(run)
+	assertBlocked(t, ch)
+	if err := ResumeSync("job-pause"); err != nil {
+		t.Fatalf("ResumeSync 返回错误：%v", err)
+	}
+	if err := receive(t, ch); err != nil {
+		t.Fatalf("恢复后安全点应通过：%v", err)
+	}
+	if got := strings.Count(strings.Join(res.Logs, "\n"), "同步已暂停"); got != 2 {
+		t.Fatalf("第二次暂停应重新通知，实际通知 %d 次", got)
+	}
+}
+
+func TestSyncControlCancel(t *testing.T) {
+	control, release := startSyncControl("job-cancel")
+	defer release()
+	run := newSyncRun(NewSyncEngine(Reporter{}), control, SyncConfig{JobID: "job-cancel"}, &SyncResult{}, nil, nil, false, true, "insert_update")
+
+	// 暂停中取消：阻塞在安全点的线程返回取消错误
+	if err := PauseSync("job-cancel"); err != nil {
+		t.Fatalf("PauseSync 返回错误：%v", err)
+	}
+	ch := waitResult(run)
+	assertBlocked(t, ch)
+	if err := CancelSync("job-cancel"); err != nil {
+		t.Fatalf("CancelSync 返回错误：%v", err)
+	}
+	if err := receive(t, ch); err != ErrSyncCancelled {
+		t.Fatalf("取消后安全点应返回 ErrSyncCancelled，实际=%v", err)
+	}
+	if run.ctx.Err() == nil {
+		t.Fatalf("取消后应中止正在应用的批次")
+	}
+
+	// 取消后安全点立即返回，且不能再暂停
+	if err := run.safePoint("items"); err != ErrSyncCancelled {
+		t.Fatalf("取消后安全点应返回 ErrSyncCancelled，实际=%v", err)
+	}
+	if err := PauseSync("job-cancel"); err != ErrSyncCancelled {
+		t.Fatalf("取消后暂停应返回 ErrSyncCancelled，实际=%v", err)
+	}
+	if err := CancelSync("job-cancel"); err != nil {
+		t.Fatalf("重复取消不应报错：%v", err)
+	}
+}
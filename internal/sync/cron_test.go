@@ -0,0 +1,98 @@
+package sync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute, sec int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
+	}
+	cases := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		// 步长与严格晚于 after
+		{"*/15 * * * *", at(2024, 1, 1, 10, 7, 30), at(2024, 1, 1, 10, 15, 0)},
+		{"*/15 * * * *", at(2024, 1, 1, 10, 45, 0), at(2024, 1, 1, 11, 0, 0)},
+		{"5/20 * * * *", at(2024, 1, 1, 10, 26, 0), at(2024, 1, 1, 10, 45, 0)},
+		// 范围加步长，跨天
+		{"0 9-17/4 * * *", at(2024, 1, 1, 13, 0, 0), at(2024, 1, 1, 17, 0, 0)},
+		{"0 9-17/4 * * *", at(2024, 1, 1, 17, 30, 0), at(2024, 1, 2, 9, 0, 0)},
+		// 列表
+		{"0 0 1,15 * *", at(2024, 1, 2, 0, 0, 0), at(2024, 1, 15, 0, 0, 0)},
+		{"0 6 1 jan,jul *", at(2024, 2, 1, 0, 0, 0), at(2024, 7, 1, 6, 0, 0)},
+		// 星期：英文缩写范围，0 与 7 都表示周日（2024-01-01 为周一）
+		{"30 8 * * mon-fri", at(2024, 1, 5, 9, 0, 0), at(2024, 1, 8, 8, 30, 0)},
+		{"0 12 * * 0", at(2024, 1, 1, 0, 0, 0), at(2024, 1, 7, 12, 0, 0)},
+		{"0 12 * * 7", at(2024, 1, 1, 0, 0, 0), at(2024, 1, 7, 12, 0, 0)},
+		// 日、周都被限定时取并集：13 号或周五
+		{"0 0 13 * fri", at(2024, 1, 1, 0, 0, 0), at(2024, 1, 5, 0, 0, 0)},
+		{"0 0 13 * fri", at(2024, 1, 12, 0, 0, 0), at(2024, 1, 13, 0, 0, 0)},
+		// 只限定日期时星期不参与
+		{"0 0 13 * ?", at(2024, 1, 1, 0, 0, 0), at(2024, 1, 13, 0, 0, 0)},
+		// 跨月：2 月没有 31 号
+		{"0 0 31 * *", at(2024, 1, 31, 0, 0, 0), at(2024, 3, 31, 0, 0, 0)},
+		// 跨年
+		{"@yearly", at(2024, 6, 1, 0, 0, 0), at(2025, 1, 1, 0, 0, 0)},
+		{"59 23 31 12 *", at(2024, 12, 31, 23, 59, 0), at(2025, 12, 31, 23, 59, 0)},
+		{"* * * * *", at(2024, 12, 31, 23, 59, 59), at(2025, 1, 1, 0, 0, 0)},
+		// 闰日
+		{"0 0 29 feb *", at(2024, 3, 1, 0, 0, 0), at(2028, 2, 29, 0, 0, 0)},
+		// 永远不会触发
+		{"0 0 30 feb *", at(2024, 1, 1, 0, 0, 0), time.Time{}},
+	}
+	for _, tc := range cases {
+		sched, err := parseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q) 返回错误：%v", tc.expr, err)
+		}
+		if got := sched.next(tc.after); !got.Equal(tc.want) {
+			t.Fatalf("%q 在 %s 之后的触发时间=%s，期望=%s", tc.expr, tc.after, got, tc.want)
+		}
+	}
+}
+
+func TestCronNextKeepsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	sched, err := parseCron("@daily")
+	if err != nil {
+		t.Fatalf("parseCron 返回错误：%v", err)
+	}
+	got := sched.next(time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("UTC 下的触发时间=%s，期望=%s", got, want)
+	}
+	got = sched.next(time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC).In(loc))
+	if want := time.Date(2024, 1, 3, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("应按 after 所在时区计算，实际=%s，期望=%s", got, want)
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1,,2 * * * *",
+		"abc * * * *",
+		"* * * foo *",
+		"@every",
+	}
+	for _, expr := range invalid {
+		if _, err := parseCron(expr); err == nil {
+			t.Fatalf("parseCron(%q) 应返回错误", expr)
+		}
+	}
+}
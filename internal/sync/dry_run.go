@@ -0,0 +1,277 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dryRunInsertChunk 是演练脚本中单条多行 INSERT 包含的最大行数。
+const dryRunInsertChunk = 100
+
+// syncScriptWriter 在演练模式下把同步本应执行的语句写入 SQL 脚本：结构变更按执行顺序写出，
+// 每张表的数据变更包在一个事务中，插入按块合并为多行 INSERT。
+type syncScriptWriter struct {
+	file    *os.File
+	w       *bufio.Writer
+	dialect string
+	target  db.Database // 用于查询 SQL Server 标识列
+
+	table      string
+	inTx       bool
+	identity   map[string]map[string]bool // 表 -> 标识列（小写）
+	statements int
+}
+
+func newSyncScriptWriter(path string, config SyncConfig, target db.Database) (*syncScriptWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	sw := &syncScriptWriter{
+		file:     f,
+		w:        bufio.NewWriterSize(f, 1024*1024),
+		dialect:  db.ResolveDialect(config.TargetConfig),
+		target:   target,
+		identity: make(map[string]map[string]bool),
+	}
+	fmt.Fprintf(sw.w, "-- GoNavi 数据同步演练脚本\n")
+	fmt.Fprintf(sw.w, "-- 源：%s\n", formatConnSummaryForSync(config.SourceConfig))
+	fmt.Fprintf(sw.w, "-- 目标：%s\n", formatConnSummaryForSync(config.TargetConfig))
+	fmt.Fprintf(sw.w, "-- 生成时间：%s\n", time.Now().Format("2006-01-02 15:04:05"))
+	return sw, nil
+}
+
+// beginTable 开始写入一张表的语句，上一张表未提交的事务会先提交。
+func (sw *syncScriptWriter) beginTable(table string) {
+	sw.endTable()
+	sw.table = table
+	fmt.Fprintf(sw.w, "\n-- ========== 表 %s ==========\n", table)
+}
+
+func (sw *syncScriptWriter) endTable() {
+	if !sw.inTx {
+		return
+	}
+	sw.write(commitStatement(sw.dialect))
+	sw.inTx = false
+}
+
+func (sw *syncScriptWriter) write(stmt string) {
+	if stmt == "" {
+		return
+	}
+	sw.w.WriteString(stmt)
+	sw.w.WriteString(";\n")
+}
+
+// exec 记录结构变更、清空表等语句。
+func (sw *syncScriptWriter) exec(stmt string) {
+	stmt = strings.TrimSpace(stmt)
+	if stmt == "" {
+		return
+	}
+	sw.write(stmt)
+	sw.statements++
+}
+
+// writeChanges 按 ApplyChanges 的顺序（删除、更新、插入）写出一批数据变更。
+func (sw *syncScriptWriter) writeChanges(queryTable string, changes connection.ChangeSet) error {
+	if !sw.inTx {
+		sw.write(beginStatement(sw.dialect))
+		sw.inTx = true
+	}
+	caps := db.Capabilities(sw.dialect)
+	table := caps.QuoteQualifiedIdent(queryTable)
+
+	for _, keys := range changes.Deletes {
+		sw.write(fmt.Sprintf("DELETE FROM %s WHERE %s", table, sw.whereClause(keys)))
+		sw.statements++
+	}
+	for _, update := range changes.Updates {
+		cols := sortedKeys(update.Values)
+		if len(cols) == 0 {
+			continue
+		}
+		sets := make([]string, len(cols))
+		for i, col := range cols {
+			sets[i] = fmt.Sprintf("%s = %s", caps.QuoteIdent(col), sqlLiteral(sw.dialect, update.Values[col]))
+		}
+		sw.write(fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(sets, ", "), sw.whereClause(update.Keys)))
+		sw.statements++
+	}
+	return sw.writeInserts(queryTable, table, changes.Inserts)
+}
+
+// writeInserts 把列集合相同的连续行合并为多行 INSERT；Oracle 系与 TDengine 每行一条。
+func (sw *syncScriptWriter) writeInserts(queryTable string, table string, rows []map[string]interface{}) error {
+	caps := db.Capabilities(sw.dialect)
+	multiRow := true
+	switch caps.Family {
+	case db.FamilyOracle, db.FamilyDameng, db.FamilyTDengine:
+		multiRow = false
+	}
+
+	var identityCols map[string]bool
+	if caps.Family == db.FamilySQLServer {
+		cols, err := sw.identityColumns(queryTable)
+		if err != nil {
+			return err
+		}
+		identityCols = cols
+	}
+
+	flush := func(cols []string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		quoted := quoteColumnList(caps, cols)
+		identity := false
+		for _, col := range cols {
+			if identityCols[strings.ToLower(col)] {
+				identity = true
+				break
+			}
+		}
+		if identity {
+			sw.write(fmt.Sprintf("SET IDENTITY_INSERT %s ON", table))
+		}
+		sw.write(fmt.Sprintf("INSERT INTO %s (%s) VALUES\n  %s", table, quoted, strings.Join(values, ",\n  ")))
+		if identity {
+			sw.write(fmt.Sprintf("SET IDENTITY_INSERT %s OFF", table))
+		}
+		sw.statements++
+	}
+
+	var cols []string
+	values := make([]string, 0, dryRunInsertChunk)
+	for _, row := range rows {
+		rowCols := sortedKeys(row)
+		if len(rowCols) == 0 {
+			continue
+		}
+		if !sameStrings(cols, rowCols) || len(values) >= dryRunInsertChunk || (!multiRow && len(values) > 0) {
+			flush(cols, values)
+			values = values[:0]
+			cols = rowCols
+		}
+		literals := make([]string, len(rowCols))
+		for i, col := range rowCols {
+			literals[i] = sqlLiteral(sw.dialect, row[col])
+		}
+		values = append(values, "("+strings.Join(literals, ", ")+")")
+	}
+	flush(cols, values)
+	return nil
+}
+
+func (sw *syncScriptWriter) whereClause(keys map[string]interface{}) string {
+	caps := db.Capabilities(sw.dialect)
+	cols := sortedKeys(keys)
+	conds := make([]string, len(cols))
+	for i, col := range cols {
+		if keys[col] == nil {
+			conds[i] = fmt.Sprintf("%s IS NULL", caps.QuoteIdent(col))
+			continue
+		}
+		conds[i] = fmt.Sprintf("%s = %s", caps.QuoteIdent(col), sqlLiteral(sw.dialect, keys[col]))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// identityColumns 查询目标表的标识列，写入这些列时需要开启 IDENTITY_INSERT。
+func (sw *syncScriptWriter) identityColumns(queryTable string) (map[string]bool, error) {
+	if cols, ok := sw.identity[queryTable]; ok {
+		return cols, nil
+	}
+	quoted := db.Capabilities(sw.dialect).QuoteQualifiedIdent(queryTable)
+	data, _, err := sw.target.Query(fmt.Sprintf("SELECT name FROM sys.identity_columns WHERE object_id = OBJECT_ID(%s)", stringLiteral(db.FamilySQLServer, quoted)))
+	if err != nil {
+		return nil, fmt.Errorf("查询标识列失败: %w", err)
+	}
+	cols := make(map[string]bool, len(data))
+	for _, row := range data {
+		if name := scalarText(lookupColumn(row, "name")); name != "" {
+			cols[strings.ToLower(name)] = true
+		}
+	}
+	sw.identity[queryTable] = cols
+	return cols, nil
+}
+
+func (sw *syncScriptWriter) close() error {
+	sw.endTable()
+	fmt.Fprintf(sw.w, "\n-- 共 %d 条语句\n", sw.statements)
+	if err := sw.w.Flush(); err != nil {
+		sw.file.Close()
+		return err
+	}
+	return sw.file.Close()
+}
+
+func beginStatement(dialect string) string {
+	switch db.Capabilities(dialect).Family {
+	case db.FamilyMySQL:
+		return "START TRANSACTION"
+	case db.FamilyPostgres:
+		return "BEGIN"
+	case db.FamilySQLServer, db.FamilySQLite:
+		return "BEGIN TRANSACTION"
+	default:
+		// Oracle 系隐式开启事务；TDengine 不支持事务
+		return ""
+	}
+}
+
+func commitStatement(dialect string) string {
+	switch db.Capabilities(dialect).Family {
+	case db.FamilyTDengine:
+		return ""
+	case db.FamilySQLServer:
+		return "COMMIT TRANSACTION"
+	default:
+		return "COMMIT"
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// dryRunDatabase 包装目标库：读取照常进行，Exec 改为写入演练脚本，保证演练不修改目标库。
+type dryRunDatabase struct {
+	db.Database
+	script *syncScriptWriter
+}
+
+func (d *dryRunDatabase) Exec(query string) (int64, error) {
+	d.script.exec(query)
+	return 0, nil
+}
+
+func (d *dryRunDatabase) QueryStream(ctx context.Context, query string, batchSize int, handle db.RowBatchHandler) error {
+	return db.StreamQuery(ctx, d.Database, query, batchSize, handle)
+}
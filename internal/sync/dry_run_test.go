@@ -0,0 +1,244 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+)
+
+// fakeScriptTarget 记录对目标库的访问：演练时 Exec 不应被调用，Query 只用于查询 SQL Server 标识列。
+type fakeScriptTarget struct {
+	db.Database
+	identity []string
+	queries  []string
+	execs    []string
+}
+
+func (f *fakeScriptTarget) Query(query string) ([]map[string]interface{}, []string, error) {
+	f.queries = append(f.queries, query)
+	rows := make([]map[string]interface{}, 0, len(f.identity))
+	for _, name := range f.identity {
+		rows = append(rows, map[string]interface{}{"name": name})
+	}
+	return rows, []string{"name"}, nil
+}
+
+func (f *fakeScriptTarget) Exec(query string) (int64, error) {
+	f.execs = append(f.execs, query)
+	return 0, nil
+}
+
+// writeTestScript 按 dialect 创建演练脚本，执行 fn 后关闭并返回脚本内容。
+func writeTestScript(t *testing.T, dialect string, target db.Database, fn func(sw *syncScriptWriter)) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sync.sql")
+	sw, err := newSyncScriptWriter(path, SyncConfig{TargetConfig: connection.ConnectionConfig{Type: dialect}}, target)
+	if err != nil {
+		t.Fatalf("创建演练脚本失败：%v", err)
+	}
+	fn(sw)
+	if err := sw.close(); err != nil {
+		t.Fatalf("关闭演练脚本失败：%v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取演练脚本失败：%v", err)
+	}
+	return string(data)
+}
+
+func TestSyncScriptWriterTransactionPerTable(t *testing.T) {
+	cases := []struct {
+		dialect string
+		begin   string
+		commit  string
+	}{
+		{"mysql", "START TRANSACTION;", "COMMIT;"},
+		{"postgres", "BEGIN;", "COMMIT;"},
+		{"sqlserver", "BEGIN TRANSACTION;", "COMMIT TRANSACTION;"},
+		{"sqlite", "BEGIN TRANSACTION;", "COMMIT;"},
+		{"oracle", "", "COMMIT;"},
+		{"dameng", "", "COMMIT;"},
+		{"tdengine", "", ""},
+	}
+	changes := connection.ChangeSet{Deletes: []map[string]interface{}{{"id": int64(1)}}}
+	for _, tc := range cases {
+		t.Run(tc.dialect, func(t *testing.T) {
+			script := writeTestScript(t, tc.dialect, &fakeScriptTarget{}, func(sw *syncScriptWriter) {
+				for _, table := range []string{"a", "b"} {
+					sw.beginTable(table)
+					if err := sw.writeChanges(table, changes); err != nil {
+						t.Fatalf("返回错误：%v", err)
+					}
+					// 同一张表的多批变更共用一个事务
+					if err := sw.writeChanges(table, changes); err != nil {
+						t.Fatalf("返回错误：%v", err)
+					}
+				}
+			})
+			lines := strings.Split(script, "\n")
+			count := func(stmt string) int {
+				n := 0
+				for _, line := range lines {
+					if line == stmt {
+						n++
+					}
+				}
+				return n
+			}
+			for _, stmt := range []string{"START TRANSACTION;", "BEGIN;", "BEGIN TRANSACTION;", "COMMIT;", "COMMIT TRANSACTION;"} {
+				want := 0
+				if stmt == tc.begin || stmt == tc.commit {
+					want = 2
+				}
+				if got := count(stmt); got != want {
+					t.Fatalf("%s 出现 %d 次，期望 %d 次：\n%s", stmt, got, want, script)
+				}
+			}
+			if !strings.Contains(script, "-- 共 4 条语句") {
+				t.Fatalf("语句计数不正确：\n%s", script)
+			}
+		})
+	}
+}
+
+func TestSyncScriptWriterInsertChunks(t *testing.T) {
+	rows := func(n int) []map[string]interface{} {
+		out := make([]map[string]interface{}, n)
+		for i := range out {
+			out[i] = map[string]interface{}{"id": int64(i + 1), "name": "x"}
+		}
+		return out
+	}
+	cases := []struct {
+		dialect string
+		rows    []map[string]interface{}
+		want    int
+	}{
+		{"mysql", rows(250), 3},
+		{"postgres", rows(100), 1},
+		{"postgres", append(rows(2), map[string]interface{}{"id": int64(9)}), 2},
+		{"oracle", rows(3), 3},
+		{"dameng", rows(3), 3},
+		{"tdengine", rows(3), 3},
+	}
+	for _, tc := range cases {
+		script := writeTestScript(t, tc.dialect, &fakeScriptTarget{}, func(sw *syncScriptWriter) {
+			sw.beginTable("t")
+			if err := sw.writeChanges("t", connection.ChangeSet{Inserts: tc.rows}); err != nil {
+				t.Fatalf("返回错误：%v", err)
+			}
+		})
+		if got := strings.Count(script, "INSERT INTO "); got != tc.want {
+			t.Fatalf("%s 插入 %d 行应生成 %d 条 INSERT，实际 %d 条：\n%s", tc.dialect, len(tc.rows), tc.want, got, script)
+		}
+		if tc.dialect == "mysql" && !strings.Contains(script, "(100, 'x');\nINSERT") {
+			t.Fatalf("每条 INSERT 最多 %d 行：\n%s", dryRunInsertChunk, script)
+		}
+	}
+}
+
+func TestSyncScriptWriterIdentityInsert(t *testing.T) {
+	target := &fakeScriptTarget{identity: []string{"ID"}}
+	script := writeTestScript(t, "sqlserver", target, func(sw *syncScriptWriter) {
+		sw.beginTable("t")
+		for i := 0; i < 2; i++ {
+			if err := sw.writeChanges("t", connection.ChangeSet{Inserts: []map[string]interface{}{{"id": int64(i), "name": "x"}}}); err != nil {
+				t.Fatalf("返回错误：%v", err)
+			}
+		}
+		// 不含标识列的行不需要开启 IDENTITY_INSERT
+		if err := sw.writeChanges("t", connection.ChangeSet{Inserts: []map[string]interface{}{{"name": "y"}}}); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+	})
+	if got := strings.Count(script, "SET IDENTITY_INSERT [t] ON;\nINSERT INTO [t] ([id], [name])"); got != 2 {
+		t.Fatalf("写入标识列的 INSERT 应开启 IDENTITY_INSERT，实际 %d 次：\n%s", got, script)
+	}
+	if got := strings.Count(script, "SET IDENTITY_INSERT [t] OFF;"); got != 2 {
+		t.Fatalf("写入后应关闭 IDENTITY_INSERT，实际 %d 次：\n%s", got, script)
+	}
+	if !strings.Contains(script, "COMMIT TRANSACTION;\n\n-- 共") || !strings.Contains(script, "INSERT INTO [t] ([name]) VALUES\n  (N'y');\nCOMMIT") {
+		t.Fatalf("不含标识列的 INSERT 不应开启 IDENTITY_INSERT：\n%s", script)
+	}
+	if len(target.queries) != 1 {
+		t.Fatalf("标识列应按表缓存，实际查询 %d 次", len(target.queries))
+	}
+}
+
+func TestSyncScriptWriterKeyConditions(t *testing.T) {
+	script := writeTestScript(t, "postgres", &fakeScriptTarget{}, func(sw *syncScriptWriter) {
+		sw.beginTable("t")
+		err := sw.writeChanges("t", connection.ChangeSet{
+			Deletes: []map[string]interface{}{{"a": int64(1), "b": nil}},
+			Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"a": nil, "b": "x"}, Values: map[string]interface{}{"v": nil}}},
+		})
+		if err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+	})
+	for _, want := range []string{
+		`DELETE FROM "t" WHERE "a" = 1 AND "b" IS NULL;`,
+		`UPDATE "t" SET "v" = NULL WHERE "a" IS NULL AND "b" = 'x';`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Fatalf("脚本缺少 %s：\n%s", want, script)
+		}
+	}
+}
+
+func TestDryRunDatabaseNeverExecutesOnTarget(t *testing.T) {
+	target := &fakeScriptTarget{}
+	script := writeTestScript(t, "mysql", target, func(sw *syncScriptWriter) {
+		dry := &dryRunDatabase{Database: target, script: sw}
+		if _, err := dry.Exec("ALTER TABLE `t` ADD COLUMN `c` INT"); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		if _, err := dry.Exec("  "); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		// 读取照常转发到目标库
+		if _, _, err := dry.Query("SELECT 1"); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+	})
+	if len(target.execs) != 0 {
+		t.Fatalf("演练时不应在目标库执行语句：%v", target.execs)
+	}
+	if len(target.queries) != 1 {
+		t.Fatalf("读取应转发到目标库，实际 %d 次", len(target.queries))
+	}
+	if !strings.Contains(script, "ALTER TABLE `t` ADD COLUMN `c` INT;\n") || !strings.Contains(script, "-- 共 1 条语句") {
+		t.Fatalf("结构变更应写入脚本：\n%s", script)
+	}
+}
+
+func TestRunSyncDryRunLeavesTargetUntouched(t *testing.T) {
+	config := newTestJobConfig(t, 3)
+	config.DryRun = true
+	config.ScriptPath = filepath.Join(t.TempDir(), "dry.sql")
+
+	res := NewSyncEngine(Reporter{}).RunSync(config)
+	if !res.Success {
+		t.Fatalf("演练失败：%s", res.Message)
+	}
+	target := openTestSQLiteAt(t, config.TargetConfig.Host)
+	rows, _, err := target.Query("SELECT COUNT(*) AS n FROM items")
+	if err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	if n := scalarText(lookupColumn(rows[0], "n")); n != "0" {
+		t.Fatalf("演练不应写入目标表，实际行数=%s", n)
+	}
+	data, err := os.ReadFile(res.ScriptPath)
+	if err != nil {
+		t.Fatalf("读取演练脚本失败：%v", err)
+	}
+	if !strings.Contains(string(data), `INSERT INTO "items" ("id", "name") VALUES`) {
+		t.Fatalf("插入应写入脚本：\n%s", data)
+	}
+}
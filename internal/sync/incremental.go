@@ -0,0 +1,244 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/db"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// lookupBatchSize 控制按键查找目标行时每条查询携带的键数量（Oracle 的 IN 列表最多 1000 项）。
+const lookupBatchSize = 500
+
+// tableFilter 是读取源表时的过滤条件：自定义 WHERE 与增量水位。
+type tableFilter struct {
+	where     string
+	watermark string // 水位字段（源表字段名），为空表示不做增量同步
+
+	since    interface{} // 上次同步到的水位，hasSince 为 false 表示全量读取
+	hasSince bool
+	upper    interface{} // 本次开始时的最大水位，同步完成后保存
+	hasUpper bool
+}
+
+// resolveTableFilter 按表选项确定过滤条件。useWatermark 为 false（如全量覆盖模式）时忽略水位字段；
+// config.FullRefresh 时不使用已保存的水位，但仍记录本次的最大水位。读取最大水位的查询随 ctx 取消。
+func resolveTableFilter(ctx context.Context, config SyncConfig, side tableSide, tableName string, opts TableOptions, useWatermark bool) (tableFilter, error) {
+	f := tableFilter{where: strings.TrimSpace(opts.Where)}
+	f.where = strings.TrimSpace(strings.TrimSuffix(f.where, ";"))
+	if !useWatermark || strings.TrimSpace(opts.WatermarkColumn) == "" {
+		return f, nil
+	}
+	f.watermark = strings.TrimSpace(opts.WatermarkColumn)
+
+	caps := db.Capabilities(side.dialect)
+	query := fmt.Sprintf("SELECT MAX(%s) AS max_value FROM %s", caps.QuoteIdent(f.watermark), caps.QuoteQualifiedIdent(side.queryTable))
+	if f.where != "" {
+		query += " WHERE (" + f.where + ")"
+	}
+	var row map[string]interface{}
+	err := db.StreamQuery(ctx, side.inst, query, 1, func(batch db.RowBatch) error {
+		if len(batch.Rows) > 0 && row == nil {
+			row = batch.Rows[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return f, fmt.Errorf("读取水位字段 %s 的最大值失败: %w", f.watermark, err)
+	}
+	if upper := lookupColumn(row, "max_value"); upper != nil {
+		f.upper, f.hasUpper = upper, true
+	}
+
+	if config.FullRefresh {
+		return f, nil
+	}
+	wm, ok, err := loadWatermark(watermarkScope(config), tableName)
+	if err != nil || !ok || !strings.EqualFold(wm.Column, f.watermark) {
+		return f, err
+	}
+	values, err := decodeCheckpointValues([]CheckpointValue{wm.Value})
+	if err != nil {
+		return f, fmt.Errorf("已保存的水位无效: %w", err)
+	}
+	f.since, f.hasSince = values[0], true
+	return f, nil
+}
+
+// sourceCondition 返回源表的过滤条件，没有条件时为空。增量读取时水位限定在 (since, upper] 内，
+// 同步过程中新写入的行留到下次同步。
+// 时间水位按驱动返回时所在的时区渲染墙上时间（如 MySQL 的 loc=Local、PG 不带时区的值为 UTC），
+// 不换算时区：水位保存时带有时区偏移，读回后墙上时间不变，与数据库中存储的取值一致。
+func (f tableFilter) sourceCondition(dialect string) string {
+	conds := make([]string, 0, 3)
+	if f.where != "" {
+		conds = append(conds, "("+f.where+")")
+	}
+	if f.hasSince {
+		col := db.Capabilities(dialect).QuoteIdent(f.watermark)
+		conds = append(conds, fmt.Sprintf("%s > %s", col, sqlLiteral(dialect, f.since)))
+		if f.hasUpper {
+			conds = append(conds, fmt.Sprintf("%s <= %s", col, sqlLiteral(dialect, f.upper)))
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// sides 给两端加上过滤条件。增量读取，或 WHERE 因字段映射无法同样作用于目标表时，
+// 目标表改为按键查找对应行，不检测删除；否则目标表按同一条件过滤。
+func (f tableFilter) sides(source tableSide, target tableSide, mapping *tableMapping) (tableSide, tableSide) {
+	source.filter = f.sourceCondition(source.dialect)
+	switch {
+	case f.hasSince || (f.where != "" && !mapping.identity()):
+		source.lookupTarget = true
+	case f.where != "":
+		target.filter = "(" + f.where + ")"
+	}
+	return source, target
+}
+
+// describe 概括过滤条件，用于同步日志，没有条件时为空。
+func (f tableFilter) describe() string {
+	parts := make([]string, 0, 2)
+	if f.where != "" {
+		parts = append(parts, "过滤条件 "+f.where)
+	}
+	switch {
+	case f.watermark == "":
+	case f.hasSince:
+		parts = append(parts, fmt.Sprintf("增量同步 %s > %v", f.watermark, f.since))
+	case f.hasUpper:
+		parts = append(parts, fmt.Sprintf("全量读取，完成后记录水位 %s = %v", f.watermark, f.upper))
+	default:
+		parts = append(parts, fmt.Sprintf("水位字段 %s 没有数据", f.watermark))
+	}
+	return strings.Join(parts, "；")
+}
+
+// commit 在表同步完成后保存本次水位。
+func (f tableFilter) commit(config SyncConfig, tableName string) error {
+	if f.watermark == "" || !f.hasUpper {
+		return nil
+	}
+	return saveWatermark(watermarkScope(config), tableName, TableWatermark{
+		Column: f.watermark,
+		Value:  encodeCheckpointValues([]interface{}{f.upper})[0],
+	})
+}
+
+// diffTableByLookup 按键有序分块读取源表（已过滤的部分行），每块按键到目标表查找对应行，
+// 只给出插入与更新，不检测删除。start 不为空时只对比键大于 start 的部分。
+func diffTableByLookup(ctx context.Context, source tableSide, target tableSide, key matchKey, start []interface{}, chunkSize int, cmp *valueComparer, handle chunkDiffHandler) error {
+	if chunkSize <= 0 {
+		chunkSize = syncChunkSize
+	}
+
+	after := start
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		srcRows, err := fetchKeysetPage(ctx, source, key, after, nil, chunkSize)
+		if err != nil {
+			return fmt.Errorf("读取源表失败: %w", err)
+		}
+		lastChunk := len(srcRows) < chunkSize
+		var upper []interface{}
+		if !lastChunk {
+			upper = key.values(srcRows[len(srcRows)-1])
+		}
+
+		tRows, err := fetchRowsByKeys(ctx, target, key, srcRows)
+		if err != nil {
+			return fmt.Errorf("读取目标表失败: %w", err)
+		}
+		existing := make(map[string]map[string]interface{}, len(tRows))
+		for _, row := range tRows {
+			if k, ok := cmp.rowKey(key, row); ok {
+				existing[k] = row
+			}
+		}
+
+		diff := chunkDiff{sourceRows: len(srcRows), rangeDone: true, rangeEnd: upper}
+		for _, sRow := range srcRows {
+			k, ok := cmp.rowKey(key, sRow)
+			if !ok {
+				continue
+			}
+			tRow, found := existing[k]
+			if !found {
+				diff.inserts = append(diff.inserts, sRow)
+				continue
+			}
+			if changed := cmp.changedColumns(sRow, tRow); len(changed) > 0 {
+				display, _ := key.rowKey(sRow)
+				diff.updates = append(diff.updates, rowChange{key: display, source: sRow, target: tRow, changed: changed})
+			} else {
+				diff.same++
+			}
+		}
+		if err := handle(diff); err != nil {
+			return err
+		}
+
+		if lastChunk {
+			return nil
+		}
+		after = upper
+	}
+}
+
+// fetchRowsByKeys 按 rows 的键读取该端对应的行。
+func fetchRowsByKeys(ctx context.Context, side tableSide, key matchKey, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	sideKey := side.queryKey(key)
+	caps := db.Capabilities(side.dialect)
+	quoted := make([]string, len(sideKey.columns))
+	for i, col := range sideKey.columns {
+		quoted[i] = caps.QuoteIdent(col)
+	}
+
+	out := make([]map[string]interface{}, 0, len(rows))
+	for begin := 0; begin < len(rows); begin += lookupBatchSize {
+		end := begin + lookupBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var cond string
+		if len(quoted) == 1 {
+			literals := make([]string, 0, end-begin)
+			for _, row := range rows[begin:end] {
+				literals = append(literals, sqlLiteral(side.dialect, key.values(row)[0]))
+			}
+			cond = fmt.Sprintf("%s IN (%s)", quoted[0], strings.Join(literals, ", "))
+		} else {
+			branches := make([]string, 0, end-begin)
+			for _, row := range rows[begin:end] {
+				values := key.values(row)
+				parts := make([]string, len(quoted))
+				for i, col := range quoted {
+					parts[i] = fmt.Sprintf("%s = %s", col, sqlLiteral(side.dialect, values[i]))
+				}
+				branches = append(branches, "("+strings.Join(parts, " AND ")+")")
+			}
+			cond = strings.Join(branches, " OR ")
+		}
+
+		query := fmt.Sprintf("SELECT * FROM %s WHERE (%s)", caps.QuoteQualifiedIdent(side.queryTable), cond)
+		if side.filter != "" {
+			query += " AND " + side.filter
+		}
+		batch, err := queryKeysetPage(ctx, side, query, end-begin)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range batch {
+			if side.mapRow != nil {
+				if row, err = side.mapRow(row); err != nil {
+					return nil, err
+				}
+			}
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
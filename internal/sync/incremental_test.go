@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/db"
+)
+
+type fakeWatermarkDB struct {
+	db.Database
+	maxValue interface{}
+	queries  []string
+}
+
+func (f *fakeWatermarkDB) QueryContext(ctx context.Context, query string) ([]map[string]interface{}, []string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	f.queries = append(f.queries, query)
+	return []map[string]interface{}{{"max_value": f.maxValue}}, []string{"max_value"}, nil
+}
+
+func TestResolveTableFilter(t *testing.T) {
+	upper := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	inst := &fakeWatermarkDB{maxValue: upper}
+	side := tableSide{inst: inst, dialect: "mysql", queryTable: "app.orders"}
+	opts := TableOptions{Where: " status = 1; ", WatermarkColumn: "updated_at"}
+
+	f, err := resolveTableFilter(context.Background(), SyncConfig{FullRefresh: true}, side, "orders", opts, true)
+	if err != nil {
+		t.Fatalf("resolveTableFilter 返回错误：%v", err)
+	}
+	if want := "SELECT MAX(`updated_at`) AS max_value FROM `app`.`orders` WHERE (status = 1)"; len(inst.queries) != 1 || inst.queries[0] != want {
+		t.Fatalf("最大水位查询不正确：%q", inst.queries)
+	}
+	if !f.hasUpper || f.upper != upper || f.hasSince {
+		t.Fatalf("水位不正确：%+v", f)
+	}
+
+	// 不使用水位时不查询最大值
+	if f, err := resolveTableFilter(context.Background(), SyncConfig{}, side, "orders", opts, false); err != nil || f.watermark != "" || len(inst.queries) != 1 {
+		t.Fatalf("不使用水位时不应读取最大值：%+v %v", f, err)
+	}
+
+	// 最大水位查询随同步取消而中止
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := resolveTableFilter(ctx, SyncConfig{FullRefresh: true}, side, "orders", opts, true); !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消后应返回 context.Canceled，实际=%v", err)
+	}
+}
+
+func TestTableFilterSourceConditionKeepsDriverZone(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	f := tableFilter{
+		where:     "status = 1",
+		watermark: "updated_at",
+		since:     time.Date(2024, 3, 1, 16, 0, 0, 0, cst),
+		hasSince:  true,
+		upper:     time.Date(2024, 3, 2, 8, 30, 0, 0, time.UTC),
+		hasUpper:  true,
+	}
+	cases := []struct {
+		dialect string
+		want    string
+	}{
+		{"mysql", "(status = 1) AND `updated_at` > '2024-03-01 16:00:00' AND `updated_at` <= '2024-03-02 08:30:00'"},
+		{"sqlserver", "(status = 1) AND [updated_at] > '2024-03-01T16:00:00' AND [updated_at] <= '2024-03-02T08:30:00'"},
+	}
+	for _, tc := range cases {
+		if got := f.sourceCondition(tc.dialect); got != tc.want {
+			t.Fatalf("sourceCondition(%q)：\n实际=%s\n期望=%s", tc.dialect, got, tc.want)
+		}
+	}
+
+	// 驱动以本地时区返回的水位（MySQL loc=Local）保存再读回后，墙上时间不变
+	local := time.Date(2024, 3, 1, 9, 15, 0, 0, time.Local)
+	values, err := decodeCheckpointValues(encodeCheckpointValues([]interface{}{local}))
+	if err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	f = tableFilter{watermark: "updated_at", since: values[0], hasSince: true}
+	if got := f.sourceCondition("mysql"); got != "`updated_at` > '2024-03-01 09:15:00'" {
+		t.Fatalf("读回的本地时区水位不应换算时区：%s", got)
+	}
+
+	// 非时间水位按原值渲染
+	f = tableFilter{watermark: "id", since: int64(100), hasSince: true}
+	if got := f.sourceCondition("postgres"); got != `"id" > 100` {
+		t.Fatalf("数值水位条件不正确：%s", got)
+	}
+}
@@ -0,0 +1,392 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	stdsync "sync"
+	"time"
+)
+
+const (
+	jobsFileName = "sync_jobs.json"
+	runsFileName = "sync_runs.json"
+
+	maxRunRecords  = 500  // 运行记录总保留条数
+	maxRunLogLines = 1000 // 单条运行记录保留的日志行数
+)
+
+// 调度类型
+const (
+	ScheduleManual   = "manual"
+	ScheduleCron     = "cron"
+	ScheduleInterval = "interval"
+)
+
+// JobSchedule 描述同步任务的触发方式。
+type JobSchedule struct {
+	Type            string `json:"type"`                      // manual / cron / interval
+	Cron            string `json:"cron,omitempty"`            // 5 段 cron 表达式，按本地时区
+	IntervalSeconds int    `json:"intervalSeconds,omitempty"` // 固定间隔（秒）
+}
+
+// SyncJob 是保存在后端的具名同步任务。
+type SyncJob struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Config    SyncConfig  `json:"config"`
+	Schedule  JobSchedule `json:"schedule"`
+	Enabled   bool        `json:"enabled"`
+	CreatedAt int64       `json:"createdAt"`           // Unix milli
+	UpdatedAt int64       `json:"updatedAt"`           // Unix milli
+	LastRunAt int64       `json:"lastRunAt,omitempty"` // Unix milli
+	NextRunAt int64       `json:"nextRunAt,omitempty"` // Unix milli，0 表示不自动运行
+	LastRunID string      `json:"lastRunId,omitempty"`
+}
+
+// SyncRunRecord 记录一次任务运行的统计与日志。
+type SyncRunRecord struct {
+	ID           string   `json:"id"` // 即本次运行的 SyncConfig.JobID，与同步事件中的 jobId 一致
+	JobID        string   `json:"jobId"`
+	JobName      string   `json:"jobName"`
+	Trigger      string   `json:"trigger"` // manual / schedule
+	StartedAt    int64    `json:"startedAt"`
+	FinishedAt   int64    `json:"finishedAt"`
+	DurationMs   int64    `json:"durationMs"`
+	Success      bool     `json:"success"`
+	Message      string   `json:"message"`
+	TablesSynced int      `json:"tablesSynced"`
+	RowsInserted int      `json:"rowsInserted"`
+	RowsUpdated  int      `json:"rowsUpdated"`
+	RowsDeleted  int      `json:"rowsDeleted"`
+	Resumable    bool     `json:"resumable,omitempty"` // 未完成，下次运行从本次断点继续
+	Cancelled    bool     `json:"cancelled,omitempty"` // 被手动取消
+	ErrorCount   int      `json:"errorCount"`
+	Errors       []string `json:"errors,omitempty"`
+	Logs         []string `json:"logs,omitempty"`
+}
+
+// JobStore 把同步任务与运行记录持久化为目录下的两个 JSON 文件。
+type JobStore struct {
+	mu   stdsync.Mutex
+	dir  string
+	jobs []SyncJob
+	runs []SyncRunRecord // 按开始时间倒序
+}
+
+// DefaultJobStoreDir 返回默认的任务存储目录（用户配置目录下的 GoNavi/sync）。
+func DefaultJobStoreDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil || strings.TrimSpace(base) == "" {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "GoNavi", "sync")
+}
+
+// OpenJobStore 读取目录下已保存的任务与运行记录，文件不存在时返回空存储。
+func OpenJobStore(dir string) (*JobStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("创建任务存储目录失败: %w", err)
+	}
+	store := &JobStore{dir: dir}
+	if err := readJSONFile(filepath.Join(dir, jobsFileName), &store.jobs); err != nil {
+		return nil, fmt.Errorf("读取同步任务失败: %w", err)
+	}
+	if err := readJSONFile(filepath.Join(dir, runsFileName), &store.runs); err != nil {
+		return nil, fmt.Errorf("读取运行记录失败: %w", err)
+	}
+	return store, nil
+}
+
+// ListJobs 返回全部任务，按名称排序。
+func (st *JobStore) ListJobs() []SyncJob {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	jobs := make([]SyncJob, len(st.jobs))
+	copy(jobs, st.jobs)
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// GetJob 按 ID 查找任务。
+func (st *JobStore) GetJob(id string) (SyncJob, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if idx := st.jobIndex(id); idx >= 0 {
+		return st.jobs[idx], true
+	}
+	return SyncJob{}, false
+}
+
+// SaveJob 新建（ID 为空）或更新任务，并按调度重新计算下次运行时间。
+func (st *JobStore) SaveJob(job SyncJob) (SyncJob, error) {
+	job.Name = strings.TrimSpace(job.Name)
+	if job.Name == "" {
+		return SyncJob{}, errors.New("任务名称不能为空")
+	}
+	if len(job.Config.Tables) == 0 {
+		return SyncJob{}, errors.New("任务未选择任何表")
+	}
+	if err := job.Schedule.validate(); err != nil {
+		return SyncJob{}, err
+	}
+	// 每次运行使用独立的 JobID，保存的配置中不保留
+	job.Config.JobID = ""
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	idx := st.jobIndex(job.ID)
+	if idx < 0 {
+		if strings.TrimSpace(job.ID) == "" {
+			job.ID = fmt.Sprintf("job-%d", now.UnixNano())
+		}
+		job.CreatedAt = now.UnixMilli()
+	} else {
+		prev := st.jobs[idx]
+		job.CreatedAt = prev.CreatedAt
+		job.LastRunAt = prev.LastRunAt
+		job.LastRunID = prev.LastRunID
+	}
+	job.UpdatedAt = now.UnixMilli()
+	job.NextRunAt = 0
+	if job.Enabled {
+		job.NextRunAt = unixMilliOrZero(job.Schedule.next(now))
+	}
+
+	jobs := append([]SyncJob(nil), st.jobs...)
+	if idx < 0 {
+		jobs = append(jobs, job)
+	} else {
+		jobs[idx] = job
+	}
+	if err := st.writeJobs(jobs); err != nil {
+		return SyncJob{}, err
+	}
+	st.jobs = jobs
+	return job, nil
+}
+
+// DeleteJob 删除任务及其运行记录、断点与增量水位。
+func (st *JobStore) DeleteJob(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	idx := st.jobIndex(id)
+	if idx < 0 {
+		return fmt.Errorf("同步任务不存在: %s", id)
+	}
+	if last := st.jobs[idx].LastRunID; last != "" {
+		_ = DeleteCheckpoint(last)
+	}
+	_ = DeleteWatermarks(id)
+	jobs := append(append([]SyncJob(nil), st.jobs[:idx]...), st.jobs[idx+1:]...)
+	if err := st.writeJobs(jobs); err != nil {
+		return err
+	}
+	st.jobs = jobs
+
+	runs := make([]SyncRunRecord, 0, len(st.runs))
+	for _, run := range st.runs {
+		if run.JobID != id {
+			runs = append(runs, run)
+		}
+	}
+	if len(runs) != len(st.runs) {
+		st.runs = runs
+		return st.writeRuns()
+	}
+	return nil
+}
+
+// ListRuns 返回运行记录（最新在前）；jobID 为空时返回全部任务的记录，limit<=0 表示不限制。
+func (st *JobStore) ListRuns(jobID string, limit int) []SyncRunRecord {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	runs := make([]SyncRunRecord, 0)
+	for _, run := range st.runs {
+		if jobID != "" && run.JobID != jobID {
+			continue
+		}
+		runs = append(runs, run)
+		if limit > 0 && len(runs) >= limit {
+			break
+		}
+	}
+	return runs
+}
+
+// AddRun 保存一条运行记录，并更新任务的最近运行信息。
+func (st *JobStore) AddRun(run SyncRunRecord) error {
+	if len(run.Logs) > maxRunLogLines {
+		run.Logs = run.Logs[len(run.Logs)-maxRunLogLines:]
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.runs = append([]SyncRunRecord{run}, st.runs...)
+	if len(st.runs) > maxRunRecords {
+		st.runs = st.runs[:maxRunRecords]
+	}
+	if err := st.writeRuns(); err != nil {
+		return err
+	}
+
+	if idx := st.jobIndex(run.JobID); idx >= 0 {
+		st.jobs[idx].LastRunAt = run.StartedAt
+		st.jobs[idx].LastRunID = run.ID
+		return st.writeJobs(st.jobs)
+	}
+	return nil
+}
+
+// dueJobs 取出下次运行时间已到的启用任务，并把它们的下次运行时间推进到 now 之后；
+// 同时返回其余任务中最早的下次运行时间（没有时为零值）。
+// 应用关闭期间错过的运行不会补跑，只按 now 之后的时间点继续调度。
+func (st *JobStore) dueJobs(now time.Time) ([]SyncJob, time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var due []SyncJob
+	var earliest time.Time
+	changed := false
+	for i := range st.jobs {
+		job := &st.jobs[i]
+		if !job.Enabled || job.NextRunAt == 0 {
+			continue
+		}
+		if job.NextRunAt <= now.UnixMilli() {
+			due = append(due, *job)
+			job.NextRunAt = unixMilliOrZero(job.Schedule.next(now))
+			changed = true
+		}
+		if job.NextRunAt == 0 {
+			continue
+		}
+		if next := time.UnixMilli(job.NextRunAt); earliest.IsZero() || next.Before(earliest) {
+			earliest = next
+		}
+	}
+	if changed {
+		_ = st.writeJobs(st.jobs)
+	}
+	return due, earliest
+}
+
+// reschedule 在启动时按当前时间重新计算所有启用任务的下次运行时间。
+func (st *JobStore) reschedule(now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i := range st.jobs {
+		job := &st.jobs[i]
+		job.NextRunAt = 0
+		if job.Enabled {
+			job.NextRunAt = unixMilliOrZero(job.Schedule.next(now))
+		}
+	}
+	_ = st.writeJobs(st.jobs)
+}
+
+func (st *JobStore) jobIndex(id string) int {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return -1
+	}
+	for i, job := range st.jobs {
+		if job.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (st *JobStore) writeJobs(jobs []SyncJob) error {
+	if err := writeJSONFile(filepath.Join(st.dir, jobsFileName), jobs); err != nil {
+		return fmt.Errorf("保存同步任务失败: %w", err)
+	}
+	return nil
+}
+
+func (st *JobStore) writeRuns() error {
+	if err := writeJSONFile(filepath.Join(st.dir, runsFileName), st.runs); err != nil {
+		return fmt.Errorf("保存运行记录失败: %w", err)
+	}
+	return nil
+}
+
+// validate 校验调度配置。
+func (s JobSchedule) validate() error {
+	switch strings.ToLower(strings.TrimSpace(s.Type)) {
+	case "", ScheduleManual:
+		return nil
+	case ScheduleCron:
+		if _, err := parseCron(s.Cron); err != nil {
+			return fmt.Errorf("cron 表达式无效: %w", err)
+		}
+		return nil
+	case ScheduleInterval:
+		if s.IntervalSeconds < 60 {
+			return errors.New("运行间隔不能小于 60 秒")
+		}
+		return nil
+	default:
+		return fmt.Errorf("未知调度类型: %s", s.Type)
+	}
+}
+
+// next 返回 after 之后的下一次运行时间，手动任务返回零值。
+func (s JobSchedule) next(after time.Time) time.Time {
+	switch strings.ToLower(strings.TrimSpace(s.Type)) {
+	case ScheduleCron:
+		sched, err := parseCron(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return sched.next(after)
+	case ScheduleInterval:
+		if s.IntervalSeconds <= 0 {
+			return time.Time{}
+		}
+		return after.Add(time.Duration(s.IntervalSeconds) * time.Second)
+	default:
+		return time.Time{}
+	}
+}
+
+func unixMilliOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile 先写临时文件再重命名，避免写到一半时损坏原文件。任务中包含连接密码，文件仅当前用户可读。
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobScheduleNext(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC)
+	cases := []struct {
+		schedule JobSchedule
+		want     time.Time
+	}{
+		{JobSchedule{Type: ScheduleCron, Cron: "*/15 * * * *"}, time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{JobSchedule{Type: " CRON ", Cron: "@hourly"}, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{JobSchedule{Type: ScheduleCron, Cron: "61 * * * *"}, time.Time{}},
+		{JobSchedule{Type: ScheduleInterval, IntervalSeconds: 90}, now.Add(90 * time.Second)},
+		{JobSchedule{Type: ScheduleInterval}, time.Time{}},
+		{JobSchedule{Type: ScheduleManual}, time.Time{}},
+		{JobSchedule{}, time.Time{}},
+	}
+	for _, tc := range cases {
+		if got := tc.schedule.next(now); !got.Equal(tc.want) {
+			t.Fatalf("%+v 的下次运行时间=%s，期望=%s", tc.schedule, got, tc.want)
+		}
+	}
+}
+
+func TestJobStoreDueJobs(t *testing.T) {
+	dir := t.TempDir()
+	st, err := OpenJobStore(dir)
+	if err != nil {
+		t.Fatalf("OpenJobStore 返回错误：%v", err)
+	}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute).UnixMilli()
+	soon := now.Add(30 * time.Second)
+	interval := JobSchedule{Type: ScheduleInterval, IntervalSeconds: 60}
+	st.jobs = []SyncJob{
+		{ID: "due-interval", Enabled: true, Schedule: interval, NextRunAt: past},
+		{ID: "soon", Enabled: true, Schedule: interval, NextRunAt: soon.UnixMilli()},
+		{ID: "disabled", Enabled: false, Schedule: interval, NextRunAt: past},
+		{ID: "unscheduled", Enabled: true, Schedule: interval},
+		{ID: "due-cron", Enabled: true, Schedule: JobSchedule{Type: ScheduleCron, Cron: "0 * * * *"}, NextRunAt: now.UnixMilli()},
+	}
+
+	due, earliest := st.dueJobs(now)
+	if len(due) != 2 || due[0].ID != "due-interval" || due[1].ID != "due-cron" {
+		t.Fatalf("到期任务不正确：%+v", due)
+	}
+	if !earliest.Equal(soon) {
+		t.Fatalf("最早的下次运行时间=%s，期望=%s", earliest, soon)
+	}
+
+	// 到期任务推进到 now 之后，错过的运行不补跑
+	wantNext := map[string]int64{
+		"due-interval": now.Add(time.Minute).UnixMilli(),
+		"soon":         soon.UnixMilli(),
+		"disabled":     past,
+		"unscheduled":  0,
+		"due-cron":     now.Add(time.Hour).UnixMilli(),
+	}
+	reopened, err := OpenJobStore(dir)
+	if err != nil {
+		t.Fatalf("OpenJobStore 返回错误：%v", err)
+	}
+	for _, job := range reopened.ListJobs() {
+		if job.NextRunAt != wantNext[job.ID] {
+			t.Fatalf("任务 %s 的下次运行时间=%d，期望=%d", job.ID, job.NextRunAt, wantNext[job.ID])
+		}
+	}
+
+	// 推进后在同一时刻不会再次到期
+	if due, _ := st.dueJobs(now); len(due) != 0 {
+		t.Fatalf("同一时刻不应重复触发：%+v", due)
+	}
+}
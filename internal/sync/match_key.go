@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// matchKey 是匹配源/目标行所用的键列：默认取主键（可为复合主键），无主键时可使用唯一索引。
+type matchKey struct {
+	columns []string
+	index   string // 使用唯一索引匹配时的索引名，主键为空
+}
+
+func (k matchKey) label() string {
+	return strings.Join(k.columns, ",")
+}
+
+func (k matchKey) describe() string {
+	if k.index == "" {
+		return fmt.Sprintf("主键(%s)", k.label())
+	}
+	return fmt.Sprintf("唯一索引 %s(%s)", k.index, k.label())
+}
+
+// rowKey 生成行的键字符串：单列键沿用原值的字符串形式，复合键编码为 JSON 字符串数组。
+// 任一键列为空时返回 false，此类行无法可靠匹配，会被忽略。
+func (k matchKey) rowKey(row map[string]interface{}) (string, bool) {
+	if len(k.columns) == 1 {
+		return rowPKString(row, k.columns[0])
+	}
+	parts := make([]string, len(k.columns))
+	for i, col := range k.columns {
+		val, ok := rowPKString(row, col)
+		if !ok {
+			return "", false
+		}
+		parts[i] = val
+	}
+	encoded, err := json.Marshal(parts)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// keyValues 取出行中的键列，用作 UPDATE/DELETE 的 WHERE 条件。
+func (k matchKey) keyValues(row map[string]interface{}) map[string]interface{} {
+	keys := make(map[string]interface{}, len(k.columns))
+	for _, col := range k.columns {
+		keys[col] = row[col]
+	}
+	return keys
+}
+
+// values 按键列顺序取出行中的键值，用于生成键范围条件。
+func (k matchKey) values(row map[string]interface{}) []interface{} {
+	out := make([]interface{}, len(k.columns))
+	for i, col := range k.columns {
+		out[i] = row[col]
+	}
+	return out
+}
+
+type uniqueIndex struct {
+	name    string
+	columns []string
+}
+
+// resolveMatchKey 确定表的匹配键。preferredIndex 非空时使用指定的唯一索引；
+// 否则优先使用主键，无主键时选用第一个键列全部非空的唯一索引（没有则取第一个唯一索引）。
+// 返回值中的索引名列表为可供选择的唯一索引，仅在查询过索引时填充。
+func resolveMatchKey(dbInst db.Database, schema string, table string, cols []connection.ColumnDefinition, preferredIndex string) (matchKey, []string, error) {
+	pkCols := make([]string, 0, 2)
+	for _, c := range cols {
+		if c.Key == "PRI" || c.Key == "PK" {
+			pkCols = append(pkCols, c.Name)
+		}
+	}
+	preferredIndex = strings.TrimSpace(preferredIndex)
+	if preferredIndex == "" && len(pkCols) > 0 {
+		return matchKey{columns: pkCols}, nil, nil
+	}
+
+	indexes, err := dbInst.GetIndexes(schema, table)
+	if err != nil {
+		if len(pkCols) > 0 {
+			return matchKey{}, nil, fmt.Errorf("获取索引失败，无法使用唯一索引 %s: %w", preferredIndex, err)
+		}
+		return matchKey{}, nil, fmt.Errorf("无主键，且获取唯一索引失败: %w", err)
+	}
+	candidates := collectUniqueIndexes(indexes, cols, pkCols)
+	names := make([]string, 0, len(candidates))
+	for _, idx := range candidates {
+		names = append(names, idx.name)
+	}
+
+	if preferredIndex != "" {
+		for _, idx := range candidates {
+			if strings.EqualFold(idx.name, preferredIndex) {
+				return matchKey{columns: idx.columns, index: idx.name}, names, nil
+			}
+		}
+		return matchKey{}, names, fmt.Errorf("未找到唯一索引 %s", preferredIndex)
+	}
+
+	if len(candidates) == 0 {
+		return matchKey{}, names, fmt.Errorf("无主键且无唯一索引")
+	}
+	nullable := make(map[string]bool, len(cols))
+	for _, c := range cols {
+		nullable[strings.ToLower(strings.TrimSpace(c.Name))] = !strings.EqualFold(strings.TrimSpace(c.Nullable), "NO")
+	}
+	for _, idx := range candidates {
+		allNotNull := true
+		for _, col := range idx.columns {
+			if nullable[strings.ToLower(col)] {
+				allNotNull = false
+				break
+			}
+		}
+		if allNotNull {
+			return matchKey{columns: idx.columns, index: idx.name}, names, nil
+		}
+	}
+	return matchKey{columns: candidates[0].columns, index: candidates[0].name}, names, nil
+}
+
+// collectUniqueIndexes 按索引名汇总唯一索引的键列（按 SeqInIndex 排序），排除与主键相同的索引。
+// 键列名以 GetColumns 返回的写法为准，以便与查询结果的列名对应。
+func collectUniqueIndexes(indexes []connection.IndexDefinition, cols []connection.ColumnDefinition, pkCols []string) []uniqueIndex {
+	colNames := make(map[string]string, len(cols))
+	for _, c := range cols {
+		colNames[strings.ToLower(strings.TrimSpace(c.Name))] = c.Name
+	}
+
+	type indexColumn struct {
+		seq  int
+		name string
+	}
+	grouped := make(map[string][]indexColumn)
+	invalid := make(map[string]bool)
+	order := make([]string, 0)
+	for _, idx := range indexes {
+		name := strings.TrimSpace(idx.Name)
+		if name == "" || idx.NonUnique != 0 || strings.EqualFold(name, "PRIMARY") {
+			continue
+		}
+		if _, seen := grouped[name]; !seen && !invalid[name] {
+			order = append(order, name)
+		}
+		colName, ok := colNames[strings.ToLower(strings.TrimSpace(idx.ColumnName))]
+		if !ok {
+			// 表达式索引等无法映射到列的索引不能用于匹配
+			invalid[name] = true
+			continue
+		}
+		grouped[name] = append(grouped[name], indexColumn{seq: idx.SeqInIndex, name: colName})
+	}
+
+	pkSig := strings.ToLower(strings.Join(sortedCopy(pkCols), ","))
+	sort.Strings(order)
+	out := make([]uniqueIndex, 0, len(order))
+	for _, name := range order {
+		entries := grouped[name]
+		if invalid[name] || len(entries) == 0 {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+		columns := make([]string, len(entries))
+		for i, e := range entries {
+			columns[i] = e.name
+		}
+		if pkSig != "" && strings.ToLower(strings.Join(sortedCopy(columns), ",")) == pkSig {
+			continue
+		}
+		out = append(out, uniqueIndex{name: name, columns: columns})
+	}
+	return out
+}
+
+func sortedCopy(items []string) []string {
+	out := append([]string(nil), items...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+)
+
+type fakeIndexDB struct {
+	db.Database
+	indexes []connection.IndexDefinition
+	err     error
+}
+
+func (f *fakeIndexDB) GetIndexes(dbName, tableName string) ([]connection.IndexDefinition, error) {
+	return f.indexes, f.err
+}
+
+func TestMatchKeyRowKey(t *testing.T) {
+	cases := []struct {
+		name string
+		key  matchKey
+		row  map[string]interface{}
+		want string
+		ok   bool
+	}{
+		{"单列键沿用原值", matchKey{columns: []string{"id"}}, map[string]interface{}{"id": int64(42)}, "42", true},
+		{"单列键去除空白", matchKey{columns: []string{"code"}}, map[string]interface{}{"code": " A1 "}, "A1", true},
+		{"单列键为 NULL", matchKey{columns: []string{"id"}}, map[string]interface{}{"id": nil}, "", false},
+		{"单列键为空串", matchKey{columns: []string{"code"}}, map[string]interface{}{"code": "  "}, "", false},
+		{"复合键编码为 JSON 数组", matchKey{columns: []string{"a", "b"}}, map[string]interface{}{"a": 1, "b": `x"y`}, `["1","x\"y"]`, true},
+		{"复合键任一列为 NULL", matchKey{columns: []string{"a", "b"}}, map[string]interface{}{"a": 1, "b": nil}, "", false},
+		{"复合键缺列", matchKey{columns: []string{"a", "b"}}, map[string]interface{}{"a": 1}, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := tc.key.rowKey(tc.row)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("%s：rowKey=(%q, %v)，期望 (%q, %v)", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	// 复合键的各列拼接后相同也不能冲突
+	k := matchKey{columns: []string{"a", "b"}}
+	k1, _ := k.rowKey(map[string]interface{}{"a": "1,2", "b": "3"})
+	k2, _ := k.rowKey(map[string]interface{}{"a": "1", "b": "2,3"})
+	if k1 == k2 {
+		t.Fatalf("不同的复合键生成了相同的键字符串：%q", k1)
+	}
+}
+
+func TestMatchKeyValues(t *testing.T) {
+	key := matchKey{columns: []string{"tenant", "id"}, index: "uk_tenant_id"}
+	row := map[string]interface{}{"tenant": "t1", "id": int64(3), "name": "x"}
+
+	if got := key.values(row); !reflect.DeepEqual(got, []interface{}{"t1", int64(3)}) {
+		t.Fatalf("values 应按键列顺序取值，实际=%v", got)
+	}
+	if got := key.keyValues(row); !reflect.DeepEqual(got, map[string]interface{}{"tenant": "t1", "id": int64(3)}) {
+		t.Fatalf("keyValues 应只包含键列，实际=%v", got)
+	}
+	if got := key.describe(); got != "唯一索引 uk_tenant_id(tenant,id)" {
+		t.Fatalf("describe 不正确：%s", got)
+	}
+	if got := (matchKey{columns: []string{"id"}}).describe(); got != "主键(id)" {
+		t.Fatalf("describe 不正确：%s", got)
+	}
+}
+
+func TestResolveMatchKey(t *testing.T) {
+	cols := []connection.ColumnDefinition{
+		{Name: "ID", Key: "PRI", Nullable: "NO"},
+		{Name: "Email", Nullable: "YES"},
+		{Name: "Code", Nullable: "NO"},
+		{Name: "Tenant", Nullable: "NO"},
+	}
+	noPK := append([]connection.ColumnDefinition{{Name: "ID", Nullable: "NO"}}, cols[1:]...)
+	indexes := []connection.IndexDefinition{
+		{Name: "PRIMARY", ColumnName: "id", SeqInIndex: 1},
+		{Name: "uk_email", ColumnName: "email", SeqInIndex: 1},
+		{Name: "uk_tenant_code", ColumnName: "code", SeqInIndex: 2},
+		{Name: "uk_tenant_code", ColumnName: "tenant", SeqInIndex: 1},
+		{Name: "idx_code", ColumnName: "code", NonUnique: 1, SeqInIndex: 1},
+		{Name: "uk_expr", ColumnName: "lower(email)", SeqInIndex: 1},
+	}
+	inst := &fakeIndexDB{indexes: indexes}
+
+	cases := []struct {
+		name      string
+		cols      []connection.ColumnDefinition
+		preferred string
+		want      matchKey
+		wantNames []string
+	}{
+		{"有主键时使用主键", cols, "", matchKey{columns: []string{"ID"}}, nil},
+		{"指定唯一索引", cols, " UK_EMAIL ", matchKey{columns: []string{"Email"}, index: "uk_email"}, []string{"uk_email", "uk_tenant_code"}},
+		{"无主键时优先非空唯一索引", noPK, "", matchKey{columns: []string{"Tenant", "Code"}, index: "uk_tenant_code"}, []string{"uk_email", "uk_tenant_code"}},
+	}
+	for _, tc := range cases {
+		key, names, err := resolveMatchKey(inst, "app", "users", tc.cols, tc.preferred)
+		if err != nil {
+			t.Fatalf("%s：返回错误：%v", tc.name, err)
+		}
+		if !reflect.DeepEqual(key, tc.want) || !reflect.DeepEqual(names, tc.wantNames) {
+			t.Fatalf("%s：实际=(%+v, %v)，期望=(%+v, %v)", tc.name, key, names, tc.want, tc.wantNames)
+		}
+	}
+
+	// 只有可空的唯一索引时退回第一个唯一索引
+	nullable := []connection.ColumnDefinition{{Name: "Email", Nullable: "YES"}}
+	key, _, err := resolveMatchKey(&fakeIndexDB{indexes: indexes[1:2]}, "app", "users", nullable, "")
+	if err != nil || !reflect.DeepEqual(key, matchKey{columns: []string{"Email"}, index: "uk_email"}) {
+		t.Fatalf("应退回第一个唯一索引，实际=(%+v, %v)", key, err)
+	}
+
+	if _, _, err := resolveMatchKey(inst, "app", "users", cols, "uk_missing"); err == nil {
+		t.Fatalf("指定不存在的唯一索引应返回错误")
+	}
+	if _, _, err := resolveMatchKey(&fakeIndexDB{}, "app", "users", noPK, ""); err == nil {
+		t.Fatalf("无主键且无唯一索引应返回错误")
+	}
+	if _, _, err := resolveMatchKey(&fakeIndexDB{err: errors.New("denied")}, "app", "users", noPK, ""); err == nil {
+		t.Fatalf("获取索引失败应返回错误")
+	}
+}
+
+func TestCollectUniqueIndexesSkipsPrimaryKeyDuplicate(t *testing.T) {
+	cols := []connection.ColumnDefinition{{Name: "a"}, {Name: "b"}}
+	indexes := []connection.IndexDefinition{
+		{Name: "uk_ba", ColumnName: "b", SeqInIndex: 1},
+		{Name: "uk_ba", ColumnName: "a", SeqInIndex: 2},
+		{Name: "uk_b", ColumnName: "B", SeqInIndex: 1},
+	}
+	got := collectUniqueIndexes(indexes, cols, []string{"a", "b"})
+	want := []uniqueIndex{{name: "uk_b", columns: []string{"b"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("与主键列相同的唯一索引应被排除，实际=%+v", got)
+	}
+}
@@ -2,8 +2,8 @@ package sync
 
 import (
 	"GoNavi-Wails/internal/db"
+	"context"
 	"fmt"
-	"strings"
 )
 
 type PreviewRow struct {
@@ -21,6 +21,7 @@ type PreviewUpdateRow struct {
 type TableDiffPreview struct {
 	Table        string             `json:"table"`
 	PKColumn     string             `json:"pkColumn"`
+	KeyColumns   []string           `json:"keyColumns"`
 	TotalInserts int                `json:"totalInserts"`
 	TotalUpdates int                `json:"totalUpdates"`
 	TotalDeletes int                `json:"totalDeletes"`
@@ -56,54 +57,34 @@ func (s *SyncEngine) Preview(config SyncConfig, tableName string, limit int) (Ta
 	}
 	defer targetDB.Close()
 
+	mapping, err := newTableMapping(tableName, config.TableOptions[tableName])
+	if err != nil {
+		return TableDiffPreview{}, err
+	}
+
 	sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
-	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
+	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, mapping.targetTable)
 	sourceQueryTable := qualifiedNameForQuery(config.SourceConfig.Type, sourceSchema, sourceTable, tableName)
-	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
+	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, mapping.targetTable)
 
 	cols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
 	if err != nil {
 		return TableDiffPreview{}, fmt.Errorf("获取源表字段失败: %w", err)
 	}
 
-	pkCols := make([]string, 0, 2)
-	for _, c := range cols {
-		if c.Key == "PRI" || c.Key == "PK" {
-			pkCols = append(pkCols, c.Name)
-		}
-	}
-	if len(pkCols) == 0 {
-		return TableDiffPreview{}, fmt.Errorf("无主键，不支持数据预览")
-	}
-	if len(pkCols) > 1 {
-		return TableDiffPreview{}, fmt.Errorf("复合主键（%s），暂不支持数据预览", strings.Join(pkCols, ","))
-	}
-	pkCol := pkCols[0]
-
-	sourceRows, _, err := sourceDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.SourceConfig.Type, sourceQueryTable)))
+	sourceKey, _, err := resolveMatchKey(sourceDB, sourceSchema, sourceTable, cols, config.TableOptions[tableName].MatchIndex)
 	if err != nil {
-		return TableDiffPreview{}, fmt.Errorf("读取源表失败: %w", err)
+		return TableDiffPreview{}, fmt.Errorf("%w，不支持数据预览", err)
 	}
-	targetRows, _, err := targetDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.TargetConfig.Type, targetQueryTable)))
+	key, err := mapping.mapKey(sourceKey)
 	if err != nil {
-		return TableDiffPreview{}, fmt.Errorf("读取目标表失败: %w", err)
-	}
-
-	targetMap := make(map[string]map[string]interface{}, len(targetRows))
-	for _, row := range targetRows {
-		if row[pkCol] == nil {
-			continue
-		}
-		pkVal := strings.TrimSpace(fmt.Sprintf("%v", row[pkCol]))
-		if pkVal == "" || pkVal == "<nil>" {
-			continue
-		}
-		targetMap[pkVal] = row
+		return TableDiffPreview{}, err
 	}
 
 	out := TableDiffPreview{
 		Table:        tableName,
-		PKColumn:     pkCol,
+		PKColumn:     key.label(),
+		KeyColumns:   key.columns,
 		TotalInserts: 0,
 		TotalUpdates: 0,
 		TotalDeletes: 0,
@@ -112,52 +93,47 @@ func (s *SyncEngine) Preview(config SyncConfig, tableName string, limit int) (Ta
 		Deletes:      make([]PreviewRow, 0),
 	}
 
-	sourcePKSet := make(map[string]struct{}, len(sourceRows))
-	for _, sRow := range sourceRows {
-		if sRow[pkCol] == nil {
-			continue
-		}
-		pkVal := strings.TrimSpace(fmt.Sprintf("%v", sRow[pkCol]))
-		if pkVal == "" || pkVal == "<nil>" {
-			continue
-		}
-		sourcePKSet[pkVal] = struct{}{}
-
-		if tRow, exists := targetMap[pkVal]; exists {
-			changedColumns := make([]string, 0)
-			for k, v := range sRow {
-				if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", tRow[k]) {
-					changedColumns = append(changedColumns, k)
-				}
-			}
-			if len(changedColumns) > 0 {
-				out.TotalUpdates++
-				if len(out.Updates) < limit {
-					out.Updates = append(out.Updates, PreviewUpdateRow{
-						PK:             pkVal,
-						ChangedColumns: changedColumns,
-						Source:         sRow,
-						Target:         tRow,
-					})
-				}
+	source := mapping.sourceSide(sourceDB, db.ResolveDialect(config.SourceConfig), sourceQueryTable, sourceKey)
+	filter, err := resolveTableFilter(context.Background(), config, source, tableName, config.TableOptions[tableName], normalizeSyncMode(config.Mode) != "full_overwrite")
+	if err != nil {
+		return TableDiffPreview{}, err
+	}
+	source, target := filter.sides(source, tableSide{inst: targetDB, dialect: db.ResolveDialect(config.TargetConfig), queryTable: targetQueryTable}, mapping)
+	source.columnTypes = mapping.columnTypes(cols)
+	target.columnTypes = loadColumnTypes(targetDB, targetSchema, targetTable)
+	_, err = diffTable(context.Background(), config, source, target, key, cols, nil, func(diff chunkDiff) error {
+		out.TotalInserts += len(diff.inserts)
+		out.TotalUpdates += len(diff.updates)
+		out.TotalDeletes += len(diff.deletes)
+		for _, row := range diff.inserts {
+			if len(out.Inserts) >= limit {
+				break
 			}
-			continue
-		}
-
-		out.TotalInserts++
-		if len(out.Inserts) < limit {
-			out.Inserts = append(out.Inserts, PreviewRow{PK: pkVal, Row: sRow})
+			pkVal, _ := key.rowKey(row)
+			out.Inserts = append(out.Inserts, PreviewRow{PK: pkVal, Row: row})
 		}
-	}
-
-	for pkVal, row := range targetMap {
-		if _, ok := sourcePKSet[pkVal]; ok {
-			continue
+		for _, change := range diff.updates {
+			if len(out.Updates) >= limit {
+				break
+			}
+			out.Updates = append(out.Updates, PreviewUpdateRow{
+				PK:             change.key,
+				ChangedColumns: change.changed,
+				Source:         change.source,
+				Target:         change.target,
+			})
 		}
-		out.TotalDeletes++
-		if len(out.Deletes) < limit {
+		for _, row := range diff.deletes {
+			if len(out.Deletes) >= limit {
+				break
+			}
+			pkVal, _ := key.rowKey(row)
 			out.Deletes = append(out.Deletes, PreviewRow{PK: pkVal, Row: row})
 		}
+		return nil
+	})
+	if err != nil {
+		return TableDiffPreview{}, err
 	}
 
 	return out, nil
@@ -2,10 +2,9 @@ package sync
 
 import (
 	"GoNavi-Wails/internal/connection"
-	"fmt"
 )
 
-func filterRowsByPKSelection(pkCol string, rows []map[string]interface{}, enabled bool, selectedPKs []string) []map[string]interface{} {
+func filterRowsByPKSelection(key matchKey, rows []map[string]interface{}, enabled bool, selectedPKs []string) []map[string]interface{} {
 	if !enabled {
 		return nil
 	}
@@ -23,7 +22,10 @@ func filterRowsByPKSelection(pkCol string, rows []map[string]interface{}, enable
 
 	out := make([]map[string]interface{}, 0, len(rows))
 	for _, row := range rows {
-		pkStr := fmt.Sprintf("%v", row[pkCol])
+		pkStr, ok := key.rowKey(row)
+		if !ok {
+			continue
+		}
 		if _, ok := set[pkStr]; ok {
 			out = append(out, row)
 		}
@@ -31,7 +33,7 @@ func filterRowsByPKSelection(pkCol string, rows []map[string]interface{}, enable
 	return out
 }
 
-func filterUpdatesByPKSelection(pkCol string, updates []connection.UpdateRow, enabled bool, selectedPKs []string) []connection.UpdateRow {
+func filterUpdatesByPKSelection(key matchKey, updates []connection.UpdateRow, enabled bool, selectedPKs []string) []connection.UpdateRow {
 	if !enabled {
 		return nil
 	}
@@ -49,7 +51,10 @@ func filterUpdatesByPKSelection(pkCol string, updates []connection.UpdateRow, en
 
 	out := make([]connection.UpdateRow, 0, len(updates))
 	for _, u := range updates {
-		pkStr := fmt.Sprintf("%v", u.Keys[pkCol])
+		pkStr, ok := key.rowKey(u.Keys)
+		if !ok {
+			continue
+		}
 		if _, ok := set[pkStr]; ok {
 			out = append(out, u)
 		}
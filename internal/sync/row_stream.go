@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/db"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// streamTableRows 流式读取整表（或满足 filter 的）数据，每批 batchSize 行回调一次。
+func streamTableRows(ctx context.Context, dbInst db.Database, dbType string, queryTable string, filter string, batchSize int, handle db.RowBatchHandler) error {
+	query := fmt.Sprintf("SELECT * FROM %s", db.QuoteQualifiedIdent(dbType, queryTable))
+	if filter != "" {
+		query += " WHERE " + filter
+	}
+	return db.StreamQuery(ctx, dbInst, query, batchSize, handle)
+}
+
+// streamKeysetPages 按键升序分页读取键大于 after 的源表数据，每页 pageSize 行，回调一次并给出该页最后一行的键。
+func streamKeysetPages(ctx context.Context, side tableSide, key matchKey, after []interface{}, pageSize int, handle func(rows []map[string]interface{}, last []interface{}) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := fetchKeysetPage(ctx, side, key, after, nil, pageSize)
+		if err != nil {
+			return fmt.Errorf("读取源表失败: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		last := key.values(rows[len(rows)-1])
+		if err := handle(rows, last); err != nil {
+			return err
+		}
+		if len(rows) < pageSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// formatKeyValues 把键值渲染为日志中可读的形式。
+func formatKeyValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func rowPKString(row map[string]interface{}, pkCol string) (string, bool) {
+	if row[pkCol] == nil {
+		return "", false
+	}
+	pkVal := strings.TrimSpace(fmt.Sprintf("%v", row[pkCol]))
+	if pkVal == "" || pkVal == "<nil>" {
+		return "", false
+	}
+	return pkVal, true
+}
@@ -0,0 +1,265 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/logger"
+	"fmt"
+	"strings"
+	stdsync "sync"
+	"time"
+)
+
+// 运行触发方式
+const (
+	TriggerManual   = "manual"
+	TriggerSchedule = "schedule"
+)
+
+// schedulerIdleWait 是没有待调度任务时的最长等待时间，任务变更会立即唤醒调度循环。
+const schedulerIdleWait = time.Hour
+
+// stopCancelInterval 是停止调度器时重试取消运行中任务的间隔：任务刚开始运行时尚未登记同步控制器，取消会失败。
+const stopCancelInterval = 50 * time.Millisecond
+
+// JobHooks 在任务运行开始与结束时回调，供上层转发事件。
+type JobHooks struct {
+	OnStart func(job SyncJob, runID string)
+	OnDone  func(job SyncJob, run SyncRunRecord)
+}
+
+// Scheduler 在应用运行期间按 cron 或固定间隔触发已启用的同步任务，并写入运行记录。
+// 同一任务同一时刻只运行一个实例，上一次尚未结束时本次触发会被跳过。
+type Scheduler struct {
+	store    *JobStore
+	reporter Reporter
+	hooks    JobHooks
+
+	mu       stdsync.Mutex
+	running  map[string]string // 任务 ID -> 运行 ID
+	jobs     stdsync.WaitGroup // 正在运行的任务
+	stopping bool              // Stop 进行中或已完成，不再接受新的运行
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewScheduler(store *JobStore, reporter Reporter, hooks JobHooks) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		reporter: reporter,
+		hooks:    hooks,
+		running:  make(map[string]string),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Store 返回调度器使用的任务存储。
+func (sc *Scheduler) Store() *JobStore {
+	return sc.store
+}
+
+// Start 启动调度循环，重复调用无效果。
+func (sc *Scheduler) Start() {
+	sc.mu.Lock()
+	if sc.stop != nil {
+		sc.mu.Unlock()
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	sc.stop, sc.done = stop, done
+	sc.stopping = false
+	sc.mu.Unlock()
+
+	sc.store.reschedule(time.Now())
+	go sc.loop(stop, done)
+}
+
+// Stop 停止调度循环，取消正在运行的任务（定时与手动触发的都包括）并等待它们结束。
+// 被取消的任务按取消处理，已提交的批次与断点保留，下次运行从断点继续。
+func (sc *Scheduler) Stop() {
+	sc.mu.Lock()
+	stop, done := sc.stop, sc.done
+	sc.stop = nil
+	sc.stopping = true
+	sc.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		sc.jobs.Wait()
+		close(finished)
+	}()
+	for {
+		for _, runID := range sc.Running() {
+			_ = CancelSync(runID)
+		}
+		select {
+		case <-finished:
+			return
+		case <-time.After(stopCancelInterval):
+		}
+	}
+}
+
+// Reload 在任务新增、修改或删除后唤醒调度循环，重新计算等待时间。
+func (sc *Scheduler) Reload() {
+	select {
+	case sc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Running 返回正在运行的任务 ID 到运行 ID 的映射。
+func (sc *Scheduler) Running() map[string]string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	running := make(map[string]string, len(sc.running))
+	for k, v := range sc.running {
+		running[k] = v
+	}
+	return running
+}
+
+// loop 在 stop 关闭前按到期时间触发任务，退出时关闭 done。通道由 Start 传入，
+// 避免 Start 之后立即 Stop 时循环读到已被清空的 sc.stop。
+func (sc *Scheduler) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	for {
+		due, next := sc.store.dueJobs(time.Now())
+		for _, job := range due {
+			go func(job SyncJob) {
+				if _, err := sc.RunJob(job.ID, TriggerSchedule); err != nil {
+					logger.Warnf("定时同步任务未运行：任务=%s 原因=%v", job.Name, err)
+				}
+			}(job)
+		}
+
+		wait := schedulerIdleWait
+		if !next.IsZero() {
+			if until := time.Until(next); until < wait {
+				wait = until
+			}
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-sc.wake:
+			timer.Stop()
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// RunJob 立即运行任务并阻塞到结束，返回本次运行记录。任务已在运行时返回错误。
+func (sc *Scheduler) RunJob(id string, trigger string) (SyncRunRecord, error) {
+	job, ok := sc.store.GetJob(id)
+	if !ok {
+		return SyncRunRecord{}, fmt.Errorf("同步任务不存在: %s", id)
+	}
+
+	started := time.Now()
+	runID := fmt.Sprintf("%s-%d", job.ID, started.UnixNano())
+	sc.mu.Lock()
+	if sc.stopping {
+		sc.mu.Unlock()
+		return SyncRunRecord{}, fmt.Errorf("同步任务调度已停止，任务 %s 未运行", job.Name)
+	}
+	if prev, busy := sc.running[job.ID]; busy {
+		sc.mu.Unlock()
+		return SyncRunRecord{}, fmt.Errorf("任务 %s 正在运行（%s）", job.Name, prev)
+	}
+	sc.running[job.ID] = runID
+	sc.jobs.Add(1)
+	sc.mu.Unlock()
+	defer func() {
+		sc.mu.Lock()
+		delete(sc.running, job.ID)
+		sc.mu.Unlock()
+		sc.jobs.Done()
+	}()
+
+	logger.Infof("开始运行同步任务：任务=%s 触发=%s 运行ID=%s", job.Name, trigger, runID)
+	if sc.hooks.OnStart != nil {
+		sc.hooks.OnStart(job, runID)
+	}
+
+	var errMu stdsync.Mutex
+	var errs []string
+	reporter := Reporter{
+		OnLog: func(event SyncLogEvent) {
+			if strings.EqualFold(event.Level, "error") {
+				errMu.Lock()
+				errs = append(errs, event.Message)
+				errMu.Unlock()
+			}
+			if sc.reporter.OnLog != nil {
+				sc.reporter.OnLog(event)
+			}
+		},
+		OnProgress: sc.reporter.OnProgress,
+	}
+
+	config := job.Config
+	config.JobID = runID
+	config.SyncJobID = job.ID
+	config.ResumeFrom = resumableRun(job, config)
+	res := NewSyncEngine(reporter).RunSync(config)
+
+	finished := time.Now()
+	run := SyncRunRecord{
+		ID:           runID,
+		JobID:        job.ID,
+		JobName:      job.Name,
+		Trigger:      trigger,
+		StartedAt:    started.UnixMilli(),
+		FinishedAt:   finished.UnixMilli(),
+		DurationMs:   finished.Sub(started).Milliseconds(),
+		Success:      res.Success,
+		Message:      res.Message,
+		TablesSynced: res.TablesSynced,
+		RowsInserted: res.RowsInserted,
+		RowsUpdated:  res.RowsUpdated,
+		RowsDeleted:  res.RowsDeleted,
+		Resumable:    res.Resumable,
+		Cancelled:    res.Cancelled,
+		ErrorCount:   len(errs),
+		Errors:       errs,
+		Logs:         res.Logs,
+	}
+	if !res.Success && !res.Cancelled && run.ErrorCount == 0 && res.Message != "" {
+		run.ErrorCount = 1
+		run.Errors = []string{res.Message}
+	}
+	if err := sc.store.AddRun(run); err != nil {
+		logger.Error(err, "保存同步任务运行记录失败：任务=%s", job.Name)
+	}
+	logger.Infof("同步任务运行结束：任务=%s 成功=%v 耗时=%s", job.Name, run.Success, finished.Sub(started).Round(time.Millisecond))
+
+	if sc.hooks.OnDone != nil {
+		sc.hooks.OnDone(job, run)
+	}
+	return run, nil
+}
+
+// resumableRun 返回上次未完成且断点仍可复用的运行 ID；任务配置修改过时丢弃旧断点，从头开始。
+func resumableRun(job SyncJob, config SyncConfig) string {
+	if job.LastRunID == "" || config.DryRun {
+		return ""
+	}
+	cp, err := LoadCheckpoint(job.LastRunID)
+	if err != nil || cp == nil {
+		return ""
+	}
+	if cp.Fingerprint != checkpointFingerprint(config) {
+		_ = DeleteCheckpoint(job.LastRunID)
+		return ""
+	}
+	return job.LastRunID
+}
@@ -0,0 +1,200 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+// newTestJobConfig 创建源表有 rows 行、目标表为空的 SQLite 同步配置。
+func newTestJobConfig(t *testing.T, rows int) SyncConfig {
+	t.Helper()
+	sourcePath := filepath.Join(t.TempDir(), "source.db")
+	targetPath := filepath.Join(t.TempDir(), "target.db")
+	stmts := []string{"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)"}
+	for i := 1; i <= rows; i++ {
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO items VALUES (%d, 'n%d')", i, i))
+	}
+	openTestSQLiteAt(t, sourcePath, stmts...)
+	openTestSQLiteAt(t, targetPath, stmts[0])
+	return SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "sqlite", Host: sourcePath},
+		TargetConfig: connection.ConnectionConfig{Type: "sqlite", Host: targetPath},
+		Tables:       []string{"items"},
+		Content:      "data",
+		Mode:         "insert_update",
+	}
+}
+
+func newTestScheduler(t *testing.T, hooks JobHooks) *Scheduler {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	store, err := OpenJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenJobStore 返回错误：%v", err)
+	}
+	return NewScheduler(store, Reporter{}, hooks)
+}
+
+func TestSchedulerRunJob(t *testing.T) {
+	var started []string
+	sc := newTestScheduler(t, JobHooks{OnStart: func(job SyncJob, runID string) { started = append(started, runID) }})
+	job, err := sc.Store().SaveJob(SyncJob{Name: "items", Config: newTestJobConfig(t, 2)})
+	if err != nil {
+		t.Fatalf("SaveJob 返回错误：%v", err)
+	}
+
+	run, err := sc.RunJob(job.ID, TriggerManual)
+	if err != nil {
+		t.Fatalf("RunJob 返回错误：%v", err)
+	}
+	if !run.Success || run.RowsInserted != 2 || run.Trigger != TriggerManual || run.JobID != job.ID {
+		t.Fatalf("运行记录不正确：%+v", run)
+	}
+	if len(started) != 1 || started[0] != run.ID || !strings.HasPrefix(run.ID, job.ID+"-") {
+		t.Fatalf("OnStart 回调的运行 ID 不正确：%v，记录=%s", started, run.ID)
+	}
+	if runs := sc.Store().ListRuns(job.ID, 0); len(runs) != 1 || runs[0].ID != run.ID {
+		t.Fatalf("运行记录未保存：%+v", runs)
+	}
+	if saved, _ := sc.Store().GetJob(job.ID); saved.LastRunID != run.ID {
+		t.Fatalf("任务的上次运行 ID=%s，期望=%s", saved.LastRunID, run.ID)
+	}
+	if len(sc.Running()) != 0 {
+		t.Fatalf("运行结束后不应仍登记为运行中：%v", sc.Running())
+	}
+
+	if _, err := sc.RunJob("missing", TriggerManual); err == nil {
+		t.Fatalf("任务不存在时应返回错误")
+	}
+	sc.running[job.ID] = "busy-run"
+	if _, err := sc.RunJob(job.ID, TriggerManual); err == nil || !strings.Contains(err.Error(), "正在运行") {
+		t.Fatalf("任务正在运行时应返回错误，实际=%v", err)
+	}
+	delete(sc.running, job.ID)
+}
+
+func TestSchedulerLoopRunsDueJobs(t *testing.T) {
+	done := make(chan SyncRunRecord, 1)
+	sc := newTestScheduler(t, JobHooks{OnDone: func(job SyncJob, run SyncRunRecord) { done <- run }})
+	job, err := sc.Store().SaveJob(SyncJob{
+		Name:     "items",
+		Config:   newTestJobConfig(t, 1),
+		Schedule: JobSchedule{Type: ScheduleInterval, IntervalSeconds: 60},
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("SaveJob 返回错误：%v", err)
+	}
+	// 直接启动调度循环（Start 会按当前时间重新计算下次运行时间），让任务立即到期
+	sc.store.jobs[0].NextRunAt = time.Now().Add(-time.Second).UnixMilli()
+	sc.stop = make(chan struct{})
+	sc.done = make(chan struct{})
+	go sc.loop(sc.stop, sc.done)
+	defer sc.Stop()
+
+	select {
+	case run := <-done:
+		if run.Trigger != TriggerSchedule || !run.Success || run.RowsInserted != 1 {
+			t.Fatalf("定时运行记录不正确：%+v", run)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("到期任务未被调度运行")
+	}
+	if saved, _ := sc.Store().GetJob(job.ID); saved.NextRunAt <= time.Now().UnixMilli() {
+		t.Fatalf("运行后下次运行时间应推进到当前时间之后：%d", saved.NextRunAt)
+	}
+}
+
+func TestSchedulerStopCancelsRunningJobs(t *testing.T) {
+	sc := newTestScheduler(t, JobHooks{})
+	config := newTestJobConfig(t, 5)
+	// 限速每秒 1 行：写入首批后需要等待数秒，不取消时不会很快结束
+	config.MaxRowsPerSecond = 1
+	job, err := sc.Store().SaveJob(SyncJob{Name: "slow", Config: config})
+	if err != nil {
+		t.Fatalf("SaveJob 返回错误：%v", err)
+	}
+	sc.Start()
+
+	result := make(chan SyncRunRecord, 1)
+	go func() {
+		run, _ := sc.RunJob(job.ID, TriggerManual)
+		result <- run
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sc.Running()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("任务未开始运行")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	begin := time.Now()
+	sc.Stop()
+	if elapsed := time.Since(begin); elapsed > 3*time.Second {
+		t.Fatalf("Stop 应取消运行中的任务而不是等它完成，耗时 %s", elapsed)
+	}
+	select {
+	case run := <-result:
+		if !run.Cancelled || !run.Resumable {
+			t.Fatalf("任务应被取消：%+v", run)
+		}
+	default:
+		t.Fatalf("Stop 返回时任务应已结束")
+	}
+	if len(sc.Running()) != 0 {
+		t.Fatalf("Stop 后不应有运行中的任务：%v", sc.Running())
+	}
+	if _, err := sc.RunJob(job.ID, TriggerManual); err == nil {
+		t.Fatalf("调度器停止后不应再运行任务")
+	}
+}
+
+func TestResumableRun(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	config := SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "mysql", Host: "src", Database: "app"},
+		TargetConfig: connection.ConnectionConfig{Type: "postgres", Host: "dst", Database: "app"},
+		Tables:       []string{"orders", "users"},
+		JobID:        "job-1-100",
+	}
+	cp, _, err := openCheckpointer(config)
+	if err != nil {
+		t.Fatalf("openCheckpointer 返回错误：%v", err)
+	}
+	cp.complete("users")
+	cp.finish(config.Tables)
+
+	job := SyncJob{ID: "job-1", LastRunID: "job-1-100"}
+	if got := resumableRun(SyncJob{ID: "job-1"}, config); got != "" {
+		t.Fatalf("没有上次运行时不应续传，实际=%q", got)
+	}
+	dryRun := config
+	dryRun.DryRun = true
+	if got := resumableRun(job, dryRun); got != "" {
+		t.Fatalf("演练模式不应续传，实际=%q", got)
+	}
+	if got := resumableRun(job, config); got != "job-1-100" {
+		t.Fatalf("配置未变时应从上次断点继续，实际=%q", got)
+	}
+	if got := resumableRun(SyncJob{ID: "job-1", LastRunID: "job-1-missing"}, config); got != "" {
+		t.Fatalf("断点不存在时不应续传，实际=%q", got)
+	}
+
+	// 配置修改后丢弃旧断点
+	changed := config
+	changed.Tables = []string{"orders"}
+	if got := resumableRun(job, changed); got != "" {
+		t.Fatalf("配置变化后不应续传，实际=%q", got)
+	}
+	if prev, _ := LoadCheckpoint("job-1-100"); prev != nil {
+		t.Fatalf("配置变化后应删除旧断点")
+	}
+}
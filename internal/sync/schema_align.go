@@ -5,34 +5,6 @@ import (
 	"strings"
 )
 
-func collectRequiredColumns(inserts []map[string]interface{}, updates []connection.UpdateRow) map[string]string {
-	// key: lower(columnName), value: original columnName
-	required := make(map[string]string)
-	for _, row := range inserts {
-		for k := range row {
-			key := strings.ToLower(strings.TrimSpace(k))
-			if key == "" {
-				continue
-			}
-			if _, exists := required[key]; !exists {
-				required[key] = k
-			}
-		}
-	}
-	for _, u := range updates {
-		for k := range u.Values {
-			key := strings.ToLower(strings.TrimSpace(k))
-			if key == "" {
-				continue
-			}
-			if _, exists := required[key]; !exists {
-				required[key] = k
-			}
-		}
-	}
-	return required
-}
-
 func filterInsertRows(inserts []map[string]interface{}, allowedLower map[string]struct{}) []map[string]interface{} {
 	if len(inserts) == 0 || len(allowedLower) == 0 {
 		return inserts
@@ -82,16 +54,3 @@ func filterUpdateRows(updates []connection.UpdateRow, allowedLower map[string]st
 	}
 	return out
 }
-
-func sanitizeMySQLColumnType(t string) string {
-	tt := strings.TrimSpace(t)
-	if tt == "" {
-		return "TEXT"
-	}
-
-	// 基础防护：避免把元数据中异常内容拼进 SQL。
-	if strings.ContainsAny(tt, "`;\n\r") {
-		return "TEXT"
-	}
-	return tt
-}
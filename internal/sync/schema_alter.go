@@ -0,0 +1,352 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"strings"
+)
+
+// 结构对比生成的 ALTER/DROP 语句。表名参数均为 qualifiedNameForQuery 得到的目标表名。
+
+var tableOptionValuePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// addColumnFullSQL 生成带完整列定义（类型、默认值、非空、自增）的新增字段语句，
+// 与 addColumnSQL 不同，用于让目标结构与源表完全一致。
+func addColumnFullSQL(sourceDialect string, targetDialect string, targetQueryTable string, col connection.ColumnDefinition) (string, []string) {
+	caps := db.Capabilities(targetDialect)
+	def, notes := columnDefinitionSQL(sourceDialect, targetDialect, col, false)
+	if strings.Contains(def, " NOT NULL") && !strings.Contains(def, " DEFAULT ") {
+		notes = append(notes, fmt.Sprintf("新增字段 %s 为 NOT NULL 且没有默认值，目标表已有数据时会执行失败", col.Name))
+	}
+	table := caps.QuoteQualifiedIdent(targetQueryTable)
+	switch caps.Family {
+	case db.FamilySQLServer, db.FamilyOracle, db.FamilyDameng:
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", table, def), notes
+	default:
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, def), notes
+	}
+}
+
+// modifyColumnSQL 按目标方言生成修改字段的语句，只处理 delta 中发生变化的属性。
+// 返回修改字段的语句、设置注释的语句与提示信息；col.Name 应为目标表中的字段名。
+func modifyColumnSQL(sourceDialect string, targetDialect string, targetQueryTable string, col connection.ColumnDefinition, d columnDelta) ([]string, []string, []string) {
+	caps := db.Capabilities(targetDialect)
+	table := caps.QuoteQualifiedIdent(targetQueryTable)
+	column := caps.QuoteIdent(col.Name)
+	stmts := make([]string, 0, 3)
+	comments := make([]string, 0, 1)
+	notes := make([]string, 0)
+
+	switch caps.Family {
+	case db.FamilyMySQL:
+		// MODIFY COLUMN 需要完整列定义，注释也写在定义中
+		def, defNotes := columnDefinitionSQL(sourceDialect, targetDialect, col, d.isPK)
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, def)}, comments, defNotes
+	case db.FamilyPostgres:
+		if d.typeChanged {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s", table, column, d.toType, column, d.toType))
+		}
+		if d.nullChanged {
+			if d.notNull {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", table, column))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", table, column))
+			}
+		}
+		if d.defaultChanged {
+			if d.defaultSQL == "" {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", table, column))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, column, d.defaultSQL))
+			}
+		}
+		if d.commentChanged {
+			comment := "NULL"
+			if d.comment != "" {
+				comment = stringLiteral(caps.Family, d.comment)
+			}
+			comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, column, comment))
+		}
+	case db.FamilySQLServer:
+		// 字段上有默认值约束时无法修改类型，先删除默认值，改完类型后再按源表重建
+		rebuildDefault := d.defaultChanged || d.typeChanged
+		if rebuildDefault {
+			stmts = append(stmts, sqlServerDropDefaultSQL(targetQueryTable, col.Name))
+		}
+		if d.typeChanged || d.nullChanged {
+			nullability := "NULL"
+			if d.notNull {
+				nullability = "NOT NULL"
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s", table, column, d.toType, nullability))
+		}
+		if rebuildDefault && d.defaultSQL != "" {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD DEFAULT %s FOR %s", table, d.defaultSQL, column))
+		}
+		if d.commentChanged {
+			comments = append(comments, sqlServerDescriptionSQL(targetQueryTable, col.Name, d.comment))
+		}
+	case db.FamilyOracle, db.FamilyDameng:
+		// 分开修改各属性：Oracle 对已是 NOT NULL 的字段再次设置 NOT NULL 会报错
+		if d.typeChanged {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY %s %s", table, column, d.toType))
+		}
+		if d.defaultChanged {
+			def := d.defaultSQL
+			if def == "" {
+				def = "NULL"
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY %s DEFAULT %s", table, column, def))
+		}
+		if d.nullChanged {
+			if d.notNull {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY %s NOT NULL", table, column))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY %s NULL", table, column))
+			}
+		}
+		if d.commentChanged {
+			comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, column, stringLiteral(caps.Family, d.comment)))
+		}
+	case db.FamilyTDengine:
+		if d.typeChanged {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s", table, column, d.toType))
+		}
+	case db.FamilySQLite:
+		notes = append(notes, fmt.Sprintf("SQLite 不支持修改字段 %s（%s），需要重建表", col.Name, d.describe()))
+	default:
+		notes = append(notes, fmt.Sprintf("目标库不支持自动修改字段 %s（%s），请手工处理", col.Name, d.describe()))
+	}
+	return stmts, comments, notes
+}
+
+// dropColumnSQL 生成删除字段的语句；SQL Server 需要先删除字段上的默认值约束。
+func dropColumnSQL(targetDialect string, targetQueryTable string, column string) []string {
+	caps := db.Capabilities(targetDialect)
+	stmt := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", caps.QuoteQualifiedIdent(targetQueryTable), caps.QuoteIdent(column))
+	if caps.Family == db.FamilySQLServer {
+		return []string{sqlServerDropDefaultSQL(targetQueryTable, column), stmt}
+	}
+	return []string{stmt}
+}
+
+// sqlServerDropDefaultSQL 生成删除字段默认值约束的 T-SQL。约束名由系统生成，需要先从 sys.default_constraints 查出。
+// 语句内部不使用分号，作为一条语句执行；变量名按表名与字段名区分，整段脚本作为一个批次执行时也不会重复声明。
+func sqlServerDropDefaultSQL(targetQueryTable string, column string) string {
+	caps := db.Capabilities("sqlserver")
+	table := caps.QuoteQualifiedIdent(sqlServerQualifiedTable(targetQueryTable))
+	variable := fmt.Sprintf("@df_%08x", crc32.ChecksumIEEE([]byte(strings.ToLower(targetQueryTable+"."+column))))
+	return fmt.Sprintf("DECLARE %s sysname\nSELECT %s = dc.name FROM sys.default_constraints dc JOIN sys.columns c ON c.object_id = dc.parent_object_id AND c.column_id = dc.parent_column_id WHERE dc.parent_object_id = OBJECT_ID(%s) AND c.name = %s\nIF %s IS NOT NULL EXEC(%s + QUOTENAME(%s))",
+		variable, variable, stringLiteral(db.FamilySQLServer, table), stringLiteral(db.FamilySQLServer, column),
+		variable, stringLiteral(db.FamilySQLServer, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT ", table)), variable)
+}
+
+// sqlServerDescriptionSQL 设置表或字段（column 非空时）的 MS_Description 扩展属性：已存在则更新，
+// 注释为空时删除。
+func sqlServerDescriptionSQL(targetQueryTable string, column string, comment string) string {
+	schema, table := splitSQLServerTable(targetQueryTable)
+	quoted := db.Capabilities("sqlserver").QuoteQualifiedIdent(schema + "." + table)
+	lit := func(s string) string { return stringLiteral(db.FamilySQLServer, s) }
+	minor := "0"
+	levels := fmt.Sprintf("@level0type = N'SCHEMA', @level0name = %s, @level1type = N'TABLE', @level1name = %s", lit(schema), lit(table))
+	if column != "" {
+		minor = fmt.Sprintf("COLUMNPROPERTY(OBJECT_ID(%s), %s, 'ColumnId')", lit(quoted), lit(column))
+		levels += fmt.Sprintf(", @level2type = N'COLUMN', @level2name = %s", lit(column))
+	}
+	exists := fmt.Sprintf("EXISTS (SELECT 1 FROM sys.extended_properties WHERE class = 1 AND major_id = OBJECT_ID(%s) AND minor_id = %s AND name = N'MS_Description')", lit(quoted), minor)
+	if comment == "" {
+		return fmt.Sprintf("IF %s EXEC sp_dropextendedproperty @name = N'MS_Description', %s", exists, levels)
+	}
+	return fmt.Sprintf("IF %s EXEC sp_updateextendedproperty @name = N'MS_Description', @value = %s, %s ELSE EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, %s",
+		exists, lit(comment), levels, lit(comment), levels)
+}
+
+func splitSQLServerTable(targetQueryTable string) (string, string) {
+	schema, table := "dbo", strings.TrimSpace(targetQueryTable)
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		schema, table = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+	return schema, table
+}
+
+func sqlServerQualifiedTable(targetQueryTable string) string {
+	schema, table := splitSQLServerTable(targetQueryTable)
+	return schema + "." + table
+}
+
+// siblingName 返回与目标表位于同一 schema 的对象名（外键引用表、触发器、Oracle 索引等）。
+func siblingName(targetQueryTable string, name string) string {
+	if i := strings.LastIndex(targetQueryTable, "."); i >= 0 {
+		return targetQueryTable[:i+1] + name
+	}
+	return name
+}
+
+func dropPrimaryKeySQL(targetDialect string, targetQueryTable string, constraint string, script *schemaScript, tableName string) string {
+	caps := db.Capabilities(targetDialect)
+	switch caps.Family {
+	case db.FamilyMySQL:
+		return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", caps.QuoteQualifiedIdent(targetQueryTable))
+	case db.FamilySQLite, db.FamilyTDengine:
+		script.note(tableName, "目标库不支持删除主键，需要重建表")
+		return ""
+	}
+	if constraint == "" {
+		script.note(tableName, "未找到目标表主键约束名，请手工删除原主键")
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", caps.QuoteQualifiedIdent(targetQueryTable), caps.QuoteIdent(constraint))
+}
+
+func addPrimaryKeySQL(targetDialect string, targetQueryTable string, columns []string, script *schemaScript, tableName string) string {
+	caps := db.Capabilities(targetDialect)
+	if caps.Family == db.FamilySQLite || caps.Family == db.FamilyTDengine {
+		script.note(tableName, "目标库不支持添加主键，需要重建表")
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", caps.QuoteQualifiedIdent(targetQueryTable), quoteColumnList(caps, columns))
+}
+
+func quoteColumnList(caps db.DriverCapabilities, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = caps.QuoteIdent(c)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// createIndexSQL 生成建索引语句。MySQL 的全文/空间索引与 PG 的非 B-tree 索引只在同方言族之间保留。
+func createIndexSQL(sourceDialect string, targetDialect string, targetQueryTable string, idx indexShape, script *schemaScript, tableName string) string {
+	caps := db.Capabilities(targetDialect)
+	sameFamily := db.Capabilities(sourceDialect).Family == caps.Family
+	if caps.Family == db.FamilyTDengine {
+		script.note(tableName, fmt.Sprintf("目标库不支持普通索引，已跳过索引 %s", idx.name))
+		return ""
+	}
+
+	kind := "INDEX"
+	if idx.unique {
+		kind = "UNIQUE INDEX"
+	}
+	using := ""
+	switch idx.kind {
+	case "FULLTEXT", "SPATIAL":
+		if !sameFamily || caps.Family != db.FamilyMySQL {
+			script.note(tableName, fmt.Sprintf("索引 %s 为 %s 索引，目标库不支持，已跳过", idx.name, idx.kind))
+			return ""
+		}
+		kind = idx.kind + " INDEX"
+	case "", "BTREE":
+	default:
+		if sameFamily && caps.Family == db.FamilyPostgres {
+			using = " USING " + strings.ToLower(idx.kind)
+		}
+	}
+
+	name := caps.QuoteIdent(idx.name)
+	if caps.Family == db.FamilyOracle || caps.Family == db.FamilyDameng {
+		// Oracle 系索引属于 schema，需与表建在同一用户下
+		name = caps.QuoteQualifiedIdent(siblingName(targetQueryTable, idx.name))
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s%s (%s)", kind, name, caps.QuoteQualifiedIdent(targetQueryTable), using, quoteColumnList(caps, idx.columns))
+}
+
+func dropIndexSQL(targetDialect string, targetQueryTable string, name string) string {
+	caps := db.Capabilities(targetDialect)
+	switch caps.Family {
+	case db.FamilyMySQL, db.FamilySQLServer:
+		return fmt.Sprintf("DROP INDEX %s ON %s", caps.QuoteIdent(name), caps.QuoteQualifiedIdent(targetQueryTable))
+	case db.FamilyPostgres, db.FamilyOracle, db.FamilyDameng:
+		return fmt.Sprintf("DROP INDEX %s", caps.QuoteQualifiedIdent(siblingName(targetQueryTable, name)))
+	default:
+		return fmt.Sprintf("DROP INDEX %s", caps.QuoteIdent(name))
+	}
+}
+
+// addForeignKeySQL 生成添加外键的语句，引用表按目标表所在 schema 定位。
+func addForeignKeySQL(targetDialect string, targetQueryTable string, fk foreignKeyShape, script *schemaScript, tableName string) string {
+	caps := db.Capabilities(targetDialect)
+	switch caps.Family {
+	case db.FamilySQLite:
+		script.note(tableName, fmt.Sprintf("SQLite 不支持为已有表添加外键 %s，需要重建表", fk.name))
+		return ""
+	case db.FamilyTDengine:
+		return ""
+	}
+	refTable := siblingName(targetQueryTable, bareTableName(fk.refTable))
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		caps.QuoteQualifiedIdent(targetQueryTable), caps.QuoteIdent(fk.name), quoteColumnList(caps, fk.columns),
+		caps.QuoteQualifiedIdent(refTable), quoteColumnList(caps, fk.refColumns))
+}
+
+func dropForeignKeySQL(targetDialect string, targetQueryTable string, name string, script *schemaScript, tableName string) string {
+	caps := db.Capabilities(targetDialect)
+	table := caps.QuoteQualifiedIdent(targetQueryTable)
+	switch caps.Family {
+	case db.FamilyMySQL:
+		return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", table, caps.QuoteIdent(name))
+	case db.FamilySQLite:
+		script.note(tableName, fmt.Sprintf("SQLite 不支持删除外键 %s，需要重建表", name))
+		return ""
+	case db.FamilyTDengine:
+		return ""
+	default:
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, caps.QuoteIdent(name))
+	}
+}
+
+func dropTriggerSQL(targetDialect string, targetQueryTable string, name string) string {
+	caps := db.Capabilities(targetDialect)
+	switch caps.Family {
+	case db.FamilyPostgres:
+		return fmt.Sprintf("DROP TRIGGER %s ON %s", caps.QuoteIdent(name), caps.QuoteQualifiedIdent(targetQueryTable))
+	case db.FamilySQLite:
+		return fmt.Sprintf("DROP TRIGGER %s", caps.QuoteIdent(name))
+	case db.FamilyTDengine:
+		return ""
+	default:
+		return fmt.Sprintf("DROP TRIGGER %s", caps.QuoteQualifiedIdent(siblingName(targetQueryTable, name)))
+	}
+}
+
+// createTriggerSQL 重建触发器。触发器体依赖方言，只有 MySQL 系之间能根据 GetTriggers 的结果完整重建，
+// 其它组合记录提示，由用户手工迁移。
+func createTriggerSQL(sourceDialect string, targetDialect string, targetQueryTable string, trig triggerShape, script *schemaScript, tableName string) {
+	caps := db.Capabilities(targetDialect)
+	if db.Capabilities(sourceDialect).Family != db.FamilyMySQL || caps.Family != db.FamilyMySQL || trig.statement == "" || len(trig.events) != 1 {
+		script.note(tableName, fmt.Sprintf("触发器 %s（%s）需要手工迁移", trig.name, trig.describe()))
+		return
+	}
+	script.addBlock(phaseCreateTrigger, fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW %s",
+		caps.QuoteIdent(trig.name), trig.timing, trig.events[0], caps.QuoteQualifiedIdent(targetQueryTable), trig.statement))
+}
+
+// tableOptionSQL 生成设置表选项的语句：MySQL 支持存储引擎、排序规则与表注释，其它方言只支持表注释。
+func tableOptionSQL(targetDialect string, targetQueryTable string, name string, value string) string {
+	caps := db.Capabilities(targetDialect)
+	table := caps.QuoteQualifiedIdent(targetQueryTable)
+	switch caps.Family {
+	case db.FamilyMySQL:
+		switch name {
+		case "ENGINE", "COLLATE":
+			if !tableOptionValuePattern.MatchString(value) {
+				return ""
+			}
+			return fmt.Sprintf("ALTER TABLE %s %s = %s", table, name, value)
+		case "COMMENT":
+			return fmt.Sprintf("ALTER TABLE %s COMMENT = %s", table, stringLiteral(caps.Family, value))
+		}
+	case db.FamilyPostgres, db.FamilyOracle, db.FamilyDameng:
+		if name == "COMMENT" {
+			return fmt.Sprintf("COMMENT ON TABLE %s IS %s", table, stringLiteral(caps.Family, value))
+		}
+	case db.FamilySQLServer:
+		if name == "COMMENT" {
+			return sqlServerDescriptionSQL(targetQueryTable, "", value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+package sync
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestAddColumnFullSQL(t *testing.T) {
+	cases := []struct {
+		source string
+		target string
+		table  string
+		col    connection.ColumnDefinition
+		want   string
+		notes  int
+	}{
+		{
+			source: "mysql", target: "mysql", table: "app.users",
+			col:  connection.ColumnDefinition{Name: "age", Type: "int(11)", Nullable: "NO", Default: strPtr("0"), Comment: "年龄"},
+			want: "ALTER TABLE `app`.`users` ADD COLUMN `age` int(11) DEFAULT 0 NOT NULL COMMENT '年龄'",
+		},
+		{
+			source: "mysql", target: "postgres", table: "public.users",
+			col:  connection.ColumnDefinition{Name: "name", Type: "varchar(64)", Nullable: "YES"},
+			want: `ALTER TABLE "public"."users" ADD COLUMN "name" VARCHAR(64)`,
+		},
+		{
+			source: "mysql", target: "sqlserver", table: "dbo.users",
+			col:   connection.ColumnDefinition{Name: "code", Type: "varchar(10)", Nullable: "NO"},
+			want:  "ALTER TABLE [dbo].[users] ADD [code] NVARCHAR(10) NOT NULL",
+			notes: 1,
+		},
+		{
+			source: "postgres", target: "oracle", table: "APP.USERS",
+			col:  connection.ColumnDefinition{Name: "FLAG", Type: "boolean", Nullable: "NO", Default: strPtr("0")},
+			want: `ALTER TABLE "APP"."USERS" ADD "FLAG" NUMBER(1) DEFAULT 0 NOT NULL`,
+		},
+	}
+	for _, tc := range cases {
+		got, notes := addColumnFullSQL(tc.source, tc.target, tc.table, tc.col)
+		if got != tc.want || len(notes) != tc.notes {
+			t.Fatalf("addColumnFullSQL(%s -> %s)：\n实际=%s 提示=%q\n期望=%s 提示 %d 条", tc.source, tc.target, got, notes, tc.want, tc.notes)
+		}
+	}
+}
+
+func TestDropColumnSQL(t *testing.T) {
+	cases := []struct {
+		target string
+		table  string
+		want   string
+	}{
+		{"mysql", "app.users", "ALTER TABLE `app`.`users` DROP COLUMN `age`"},
+		{"postgres", "public.users", `ALTER TABLE "public"."users" DROP COLUMN "age"`},
+		{"oracle", "APP.USERS", `ALTER TABLE "APP"."USERS" DROP COLUMN "age"`},
+	}
+	for _, tc := range cases {
+		if got := dropColumnSQL(tc.target, tc.table, "age"); !reflect.DeepEqual(got, []string{tc.want}) {
+			t.Fatalf("dropColumnSQL(%s)=%q，期望=%q", tc.target, got, tc.want)
+		}
+	}
+
+	// SQL Server 先删除字段上的默认值约束
+	got := dropColumnSQL("sqlserver", "dbo.users", "age")
+	if len(got) != 2 || !strings.Contains(got[0], "sys.default_constraints") || got[1] != "ALTER TABLE [dbo].[users] DROP COLUMN [age]" {
+		t.Fatalf("SQL Server 删除字段语句不正确：%q", got)
+	}
+}
+
+func TestModifyColumnSQL(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		target   string
+		table    string
+		src      connection.ColumnDefinition
+		tgt      connection.ColumnDefinition
+		stmts    []string
+		comments []string
+	}{
+		{
+			name: "MySQL 使用完整列定义", source: "mysql", target: "mysql", table: "app.users",
+			src:   connection.ColumnDefinition{Name: "name", Type: "varchar(128)", Nullable: "NO", Comment: "姓名"},
+			tgt:   connection.ColumnDefinition{Name: "name", Type: "varchar(64)", Nullable: "YES"},
+			stmts: []string{"ALTER TABLE `app`.`users` MODIFY COLUMN `name` varchar(128) NOT NULL COMMENT '姓名'"},
+		},
+		{
+			name: "PG 分别修改类型、非空、默认值与注释", source: "mysql", target: "postgres", table: "public.users",
+			src: connection.ColumnDefinition{Name: "score", Type: "decimal(10,2)", Nullable: "NO", Default: strPtr("0"), Comment: "分数"},
+			tgt: connection.ColumnDefinition{Name: "score", Type: "integer", Nullable: "YES"},
+			stmts: []string{
+				`ALTER TABLE "public"."users" ALTER COLUMN "score" TYPE NUMERIC(10,2) USING "score"::NUMERIC(10,2)`,
+				`ALTER TABLE "public"."users" ALTER COLUMN "score" SET NOT NULL`,
+				`ALTER TABLE "public"."users" ALTER COLUMN "score" SET DEFAULT 0`,
+			},
+			comments: []string{`COMMENT ON COLUMN "public"."users"."score" IS '分数'`},
+		},
+		{
+			name: "PG 删除默认值并允许 NULL", source: "postgres", target: "postgres", table: "public.users",
+			src: connection.ColumnDefinition{Name: "status", Type: "integer", Nullable: "YES"},
+			tgt: connection.ColumnDefinition{Name: "status", Type: "integer", Nullable: "NO", Default: strPtr("1")},
+			stmts: []string{
+				`ALTER TABLE "public"."users" ALTER COLUMN "status" DROP NOT NULL`,
+				`ALTER TABLE "public"."users" ALTER COLUMN "status" DROP DEFAULT`,
+			},
+		},
+		{
+			name: "Oracle 分开修改各属性", source: "oracle", target: "oracle", table: "APP.USERS",
+			src: connection.ColumnDefinition{Name: "NAME", Type: "VARCHAR2(100)", Nullable: "NO"},
+			tgt: connection.ColumnDefinition{Name: "NAME", Type: "VARCHAR2(50)", Nullable: "NO"},
+			stmts: []string{
+				`ALTER TABLE "APP"."USERS" MODIFY "NAME" VARCHAR2(100)`,
+			},
+		},
+		{
+			name: "MySQL 整数显示宽度不算类型变化", source: "mysql", target: "mysql", table: "app.users",
+			src: connection.ColumnDefinition{Name: "id", Type: "int", Nullable: "NO"},
+			tgt: connection.ColumnDefinition{Name: "id", Type: "int(11)", Nullable: "NO"},
+		},
+	}
+	for _, tc := range cases {
+		d := compareColumn(tc.source, tc.target, tc.src, tc.tgt, false)
+		if tc.stmts == nil && tc.comments == nil {
+			if d.changed() {
+				t.Fatalf("%s：不应有差异，实际=%s", tc.name, d.describe())
+			}
+			continue
+		}
+		stmts, comments, notes := modifyColumnSQL(tc.source, tc.target, tc.table, tc.src, d)
+		if len(comments) == 0 {
+			comments = nil
+		}
+		if !reflect.DeepEqual(stmts, tc.stmts) || !reflect.DeepEqual(comments, tc.comments) || len(notes) != 0 {
+			t.Fatalf("%s：\n语句=%q\n注释=%q\n提示=%q", tc.name, stmts, comments, notes)
+		}
+	}
+}
+
+func TestModifyColumnSQL_SQLServerRebuildsDefault(t *testing.T) {
+	src := connection.ColumnDefinition{Name: "qty", Type: "bigint", Nullable: "NO", Default: strPtr("((0))")}
+	tgt := connection.ColumnDefinition{Name: "qty", Type: "int", Nullable: "NO", Default: strPtr("((0))")}
+	d := compareColumn("sqlserver", "sqlserver", src, tgt, false)
+	if !d.typeChanged || d.defaultChanged {
+		t.Fatalf("差异判断不正确：%+v", d)
+	}
+
+	stmts, _, _ := modifyColumnSQL("sqlserver", "sqlserver", "dbo.items", src, d)
+	if len(stmts) != 3 {
+		t.Fatalf("应先删除默认值约束、修改类型再重建默认值，实际=%q", stmts)
+	}
+	if !strings.Contains(stmts[0], "DROP CONSTRAINT") ||
+		stmts[1] != "ALTER TABLE [dbo].[items] ALTER COLUMN [qty] bigint NOT NULL" ||
+		stmts[2] != "ALTER TABLE [dbo].[items] ADD DEFAULT 0 FOR [qty]" {
+		t.Fatalf("SQL Server 修改字段语句不正确：%q", stmts)
+	}
+
+	// SQLite 不支持修改字段，只给出提示
+	d = compareColumn("mysql", "sqlite", src, connection.ColumnDefinition{Name: "qty", Type: "TEXT"}, false)
+	if stmts, _, notes := modifyColumnSQL("mysql", "sqlite", "items", src, d); len(stmts) != 0 || len(notes) != 1 {
+		t.Fatalf("SQLite 应只返回提示：语句=%q 提示=%q", stmts, notes)
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestOrderTablesForDrop(t *testing.T) {
+	fks := map[string][]foreignKeyShape{
+		"child":      {{name: "fk_child_parent", refTable: "parent"}},
+		"grandchild": {{name: "fk_gc_child", refTable: "main.child"}},
+		"a":          {{name: "fk_a_b", refTable: "b"}},
+		"b":          {{name: "fk_b_a", refTable: "a"}},
+		"self":       {{name: "fk_self", refTable: "self"}},
+		"other":      {{name: "fk_other_kept", refTable: "kept"}},
+	}
+	got := orderTablesForDrop([]string{"a", "b", "child", "grandchild", "other", "parent", "self"}, fks)
+	want := []string{"b", "a", "grandchild", "child", "other", "parent", "self"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("删表顺序不正确：\n实际=%v\n期望=%v", got, want)
+	}
+}
+
+func TestCompareSchemaDropsExtraTablesAfterDependents(t *testing.T) {
+	sourcePath := filepath.Join(t.TempDir(), "source.db")
+	targetPath := filepath.Join(t.TempDir(), "target.db")
+	openTestSQLiteAt(t, sourcePath, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	openTestSQLiteAt(t, targetPath,
+		"CREATE TABLE items (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE a_parent (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE b_child (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES a_parent(id))",
+	)
+
+	result := NewSyncEngine(Reporter{}).CompareSchema(SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "sqlite", Host: sourcePath},
+		TargetConfig: connection.ConnectionConfig{Type: "sqlite", Host: targetPath},
+	})
+	if !result.Success {
+		t.Fatalf("结构对比失败：%s", result.Message)
+	}
+	want := []string{`DROP TABLE "b_child"`, `DROP TABLE "a_parent"`}
+	if !reflect.DeepEqual(result.Statements, want) {
+		t.Fatalf("引用其它表的表应先删除：\n实际=%q\n期望=%q", result.Statements, want)
+	}
+	for _, note := range result.Notes {
+		if strings.Contains(note, "重建表") {
+			t.Fatalf("删除整表时不应提示重建表：%s", note)
+		}
+	}
+}
@@ -0,0 +1,908 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"GoNavi-Wails/internal/logger"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaChange 描述一处结构差异。
+type SchemaChange struct {
+	Object string `json:"object"` // table/column/primaryKey/index/foreignKey/trigger/option
+	Name   string `json:"name"`
+	Action string `json:"action"` // add/drop/modify
+	Detail string `json:"detail,omitempty"`
+}
+
+type TableSchemaDiff struct {
+	Table   string         `json:"table"`
+	Status  string         `json:"status"` // same/changed/missing（目标缺表）/extra（目标多出的表）/error
+	Changes []SchemaChange `json:"changes"`
+	Message string         `json:"message,omitempty"`
+}
+
+type SchemaCompareResult struct {
+	Success    bool              `json:"success"`
+	Message    string            `json:"message"`
+	Tables     []TableSchemaDiff `json:"tables"`
+	Statements []string          `json:"statements"` // 按依赖顺序排列的迁移语句
+	Script     string            `json:"script"`     // 可直接保存或执行的脚本（含提示注释与客户端分隔符）
+	Notes      []string          `json:"notes,omitempty"`
+}
+
+// 迁移语句的执行阶段：先删除依赖对象（外键、触发器、索引、主键）再改字段，
+// 字段与表就绪后再建索引、主键和外键，最后重建触发器并设置注释与表选项。
+const (
+	phaseDropForeignKey = iota
+	phaseDropTrigger
+	phaseDropIndex
+	phaseDropPrimaryKey
+	phaseDropColumn
+	phaseDropTable
+	phaseCreateTable
+	phaseAlterColumn
+	phaseAddPrimaryKey
+	phaseAddIndex
+	phaseAddForeignKey
+	phaseCreateTrigger
+	phaseComment
+	phaseCount
+)
+
+type schemaStatement struct {
+	sql   string
+	block bool // 含分号的复合语句（MySQL 触发器），脚本中需要用 DELIMITER 包裹
+}
+
+// schemaScript 按阶段收集迁移语句，保证跨表的依赖顺序（如所有表的外键都在建表与建索引之后添加）。
+type schemaScript struct {
+	phases [phaseCount][]schemaStatement
+	notes  []string
+}
+
+func (sc *schemaScript) add(phase int, stmts ...string) {
+	for _, stmt := range stmts {
+		if strings.TrimSpace(stmt) != "" {
+			sc.phases[phase] = append(sc.phases[phase], schemaStatement{sql: stmt})
+		}
+	}
+}
+
+func (sc *schemaScript) addBlock(phase int, stmt string) {
+	sc.phases[phase] = append(sc.phases[phase], schemaStatement{sql: stmt, block: true})
+}
+
+func (sc *schemaScript) note(table string, msg string) {
+	sc.notes = append(sc.notes, fmt.Sprintf("表 %s：%s", table, msg))
+}
+
+func (sc *schemaScript) statements() []schemaStatement {
+	out := make([]schemaStatement, 0)
+	for _, phase := range sc.phases {
+		out = append(out, phase...)
+	}
+	return out
+}
+
+// CompareSchema 对比源与目标的表结构，生成把目标结构调整为与源一致的迁移脚本。
+// 未指定表时对比源库全部表，并把目标库多出的表列为待删除。
+func (s *SyncEngine) CompareSchema(config SyncConfig) SchemaCompareResult {
+	result := SchemaCompareResult{Success: true, Tables: []TableSchemaDiff{}, Statements: []string{}}
+	sourceDialect := db.ResolveDialect(config.SourceConfig)
+	targetDialect := db.ResolveDialect(config.TargetConfig)
+	if !schemaSyncSupported(sourceDialect) || !schemaSyncSupported(targetDialect) {
+		return SchemaCompareResult{Success: false, Message: fmt.Sprintf("源类型=%s 目标类型=%s 暂不支持结构对比", config.SourceConfig.Type, config.TargetConfig.Type)}
+	}
+
+	sourceDB, err := db.NewDatabase(config.SourceConfig.Type)
+	if err != nil {
+		logger.Error(err, "初始化源数据库驱动失败：类型=%s", config.SourceConfig.Type)
+		return SchemaCompareResult{Success: false, Message: "初始化源数据库驱动失败: " + err.Error()}
+	}
+	targetDB, err := db.NewDatabase(config.TargetConfig.Type)
+	if err != nil {
+		logger.Error(err, "初始化目标数据库驱动失败：类型=%s", config.TargetConfig.Type)
+		return SchemaCompareResult{Success: false, Message: "初始化目标数据库驱动失败: " + err.Error()}
+	}
+	if err := sourceDB.Connect(config.SourceConfig); err != nil {
+		logger.Error(err, "源数据库连接失败：%s", formatConnSummaryForSync(config.SourceConfig))
+		return SchemaCompareResult{Success: false, Message: "源数据库连接失败: " + err.Error()}
+	}
+	defer sourceDB.Close()
+	if err := targetDB.Connect(config.TargetConfig); err != nil {
+		logger.Error(err, "目标数据库连接失败：%s", formatConnSummaryForSync(config.TargetConfig))
+		return SchemaCompareResult{Success: false, Message: "目标数据库连接失败: " + err.Error()}
+	}
+	defer targetDB.Close()
+
+	tables := config.Tables
+	var extraTables []string
+	if len(tables) == 0 {
+		tables, err = sourceDB.GetTables(config.SourceConfig.Database)
+		if err != nil {
+			return SchemaCompareResult{Success: false, Message: "获取源库表列表失败: " + err.Error()}
+		}
+		targetTables, err := targetDB.GetTables(config.TargetConfig.Database)
+		if err != nil {
+			return SchemaCompareResult{Success: false, Message: "获取目标库表列表失败: " + err.Error()}
+		}
+		extraTables = tablesMissingFrom(targetTables, tables)
+	}
+
+	script := &schemaScript{}
+	total := len(tables) + len(extraTables)
+	s.progress(config.JobID, 0, total, "", "结构对比开始")
+	for i, tableName := range tables {
+		s.progress(config.JobID, i, total, tableName, fmt.Sprintf("对比表结构(%d/%d)", i+1, total))
+		diff := s.compareTableSchema(config, sourceDB, targetDB, sourceDialect, targetDialect, tableName, script)
+		result.Tables = append(result.Tables, diff)
+	}
+	targetCaps := db.Capabilities(targetDialect)
+	extraFKs := make(map[string][]foreignKeyShape, len(extraTables))
+	for _, tableName := range extraTables {
+		targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
+		fks, err := targetDB.GetForeignKeys(targetSchema, targetTable)
+		if err != nil {
+			script.note(tableName, "获取目标表外键失败，删除该表时可能被外键阻止: "+err.Error())
+			continue
+		}
+		extraFKs[tableName] = groupForeignKeys(fks)
+	}
+	for i, tableName := range orderTablesForDrop(extraTables, extraFKs) {
+		s.progress(config.JobID, len(tables)+i, total, tableName, fmt.Sprintf("对比表结构(%d/%d)", len(tables)+i+1, total))
+		targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
+		targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
+		// 先删除待删表上的外键，表之间的引用（含循环引用）不再阻止 DROP TABLE；SQLite 不能删除外键，依赖删表顺序
+		if targetCaps.Family != db.FamilySQLite {
+			for _, fk := range extraFKs[tableName] {
+				script.add(phaseDropForeignKey, dropForeignKeySQL(targetDialect, targetQueryTable, fk.name, script, tableName))
+			}
+		}
+		script.add(phaseDropTable, fmt.Sprintf("DROP TABLE %s", targetCaps.QuoteQualifiedIdent(targetQueryTable)))
+		result.Tables = append(result.Tables, TableSchemaDiff{
+			Table:   tableName,
+			Status:  "extra",
+			Changes: []SchemaChange{{Object: "table", Name: tableName, Action: "drop"}},
+			Message: "源库不存在该表",
+		})
+	}
+	s.progress(config.JobID, total, total, "", "结构对比完成")
+
+	stmts := script.statements()
+	for _, stmt := range stmts {
+		result.Statements = append(result.Statements, stmt.sql)
+	}
+	result.Notes = script.notes
+	result.Script = renderSchemaScript(config, targetDialect, stmts, script.notes)
+
+	changed := 0
+	for _, t := range result.Tables {
+		if t.Status != "same" {
+			changed++
+		}
+	}
+	result.Message = fmt.Sprintf("对比完成：%d 张表存在差异，共生成 %d 条语句", changed, len(result.Statements))
+	return result
+}
+
+// compareTableSchema 对比单表结构，把迁移语句写入 script，返回该表的差异摘要。
+func (s *SyncEngine) compareTableSchema(config SyncConfig, sourceDB db.Database, targetDB db.Database, sourceDialect string, targetDialect string, tableName string, script *schemaScript) TableSchemaDiff {
+	diff := TableSchemaDiff{Table: tableName, Status: "same", Changes: []SchemaChange{}}
+	sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
+	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
+	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
+	caps := db.Capabilities(targetDialect)
+	sameFamily := db.Capabilities(sourceDialect).Family == caps.Family
+	change := func(object, name, action, detail string) {
+		diff.Changes = append(diff.Changes, SchemaChange{Object: object, Name: name, Action: action, Detail: detail})
+	}
+
+	sourceCols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
+	if err != nil || len(sourceCols) == 0 {
+		if err == nil {
+			err = fmt.Errorf("源表没有字段")
+		}
+		diff.Status = "error"
+		diff.Message = "获取源表字段失败: " + err.Error()
+		return diff
+	}
+	// 任一端读取失败时跳过对应对象的对比，避免把目标端已有的对象误判为待删除
+	compareIndexes, compareFKs, compareTriggers := true, true, true
+	sourceIndexes, err := sourceDB.GetIndexes(sourceSchema, sourceTable)
+	if err != nil {
+		compareIndexes = false
+		script.note(tableName, "获取源表索引失败，已跳过索引对比: "+err.Error())
+	}
+	sourceFKs, err := sourceDB.GetForeignKeys(sourceSchema, sourceTable)
+	if err != nil {
+		compareFKs = false
+		script.note(tableName, "获取源表外键失败，已跳过外键对比: "+err.Error())
+	}
+	sourceTriggers, err := sourceDB.GetTriggers(sourceSchema, sourceTable)
+	if err != nil {
+		compareTriggers = false
+		script.note(tableName, "获取源表触发器失败，已跳过触发器对比: "+err.Error())
+	}
+	sourcePK := primaryKeyColumns(sourceCols)
+
+	// 部分驱动查询不存在的表时返回空字段列表而不是错误
+	targetCols, err := targetDB.GetColumns(targetSchema, targetTable)
+	if err != nil || len(targetCols) == 0 {
+		diff.Status = "missing"
+		diff.Message = "目标表不存在"
+		change("table", tableName, "add", "")
+		stmts, notes, err := buildCreateTableSQL(sourceDialect, targetDialect, targetQueryTable, sourceCols)
+		for _, n := range notes {
+			script.note(tableName, n)
+		}
+		if err != nil {
+			diff.Status = "error"
+			diff.Message = "生成建表语句失败: " + err.Error()
+			return diff
+		}
+		script.add(phaseCreateTable, stmts[0])
+		script.add(phaseComment, stmts[1:]...)
+		for _, idx := range groupIndexes(sourceIndexes, sourcePK) {
+			script.add(phaseAddIndex, createIndexSQL(sourceDialect, targetDialect, targetQueryTable, idx, script, tableName))
+		}
+		for _, fk := range groupForeignKeys(sourceFKs) {
+			script.add(phaseAddForeignKey, addForeignKeySQL(targetDialect, targetQueryTable, fk, script, tableName))
+		}
+		for _, trig := range groupTriggers(sourceTriggers) {
+			createTriggerSQL(sourceDialect, targetDialect, targetQueryTable, trig, script, tableName)
+		}
+		s.compareTableOptions(sourceDB, targetDB, sourceDialect, targetDialect, sourceSchema, sourceTable, "", "", targetQueryTable, tableName, script, change)
+		return diff
+	}
+
+	targetIndexes, err := targetDB.GetIndexes(targetSchema, targetTable)
+	if err != nil && compareIndexes {
+		compareIndexes = false
+		script.note(tableName, "获取目标表索引失败，已跳过索引对比: "+err.Error())
+	}
+	targetFKs, err := targetDB.GetForeignKeys(targetSchema, targetTable)
+	if err != nil && compareFKs {
+		compareFKs = false
+		script.note(tableName, "获取目标表外键失败，已跳过外键对比: "+err.Error())
+	}
+	targetTriggers, err := targetDB.GetTriggers(targetSchema, targetTable)
+	if err != nil && compareTriggers {
+		compareTriggers = false
+		script.note(tableName, "获取目标表触发器失败，已跳过触发器对比: "+err.Error())
+	}
+	if !compareIndexes {
+		sourceIndexes, targetIndexes = nil, nil
+	}
+	if !compareFKs {
+		sourceFKs, targetFKs = nil, nil
+	}
+	if !compareTriggers {
+		sourceTriggers, targetTriggers = nil, nil
+	}
+	targetPK := primaryKeyColumns(targetCols)
+
+	// 1) 字段
+	targetByName := make(map[string]connection.ColumnDefinition, len(targetCols))
+	for _, c := range targetCols {
+		targetByName[strings.ToLower(strings.TrimSpace(c.Name))] = c
+	}
+	sourceNames := buildColumnNameSet(sourceCols)
+	retyped := make(map[string]bool)
+	for _, col := range sourceCols {
+		name := strings.TrimSpace(col.Name)
+		if name == "" {
+			continue
+		}
+		tcol, ok := targetByName[strings.ToLower(name)]
+		if !ok {
+			change("column", name, "add", mapColumnType(sourceDialect, targetDialect, col, false))
+			stmt, notes := addColumnFullSQL(sourceDialect, targetDialect, targetQueryTable, col)
+			for _, n := range notes {
+				script.note(tableName, n)
+			}
+			script.add(phaseAlterColumn, stmt)
+			if caps.Family != db.FamilyMySQL {
+				script.add(phaseComment, columnCommentSQL(targetDialect, targetQueryTable, col))
+			}
+			continue
+		}
+		delta := compareColumn(sourceDialect, targetDialect, col, tcol, containsFold(sourcePK, name))
+		if !delta.changed() {
+			continue
+		}
+		if delta.typeChanged {
+			retyped[strings.ToLower(name)] = true
+		}
+		change("column", name, "modify", delta.describe())
+		// 修改语句使用目标表中的字段名写法（Oracle 等带引号的标识符区分大小写）
+		col.Name = tcol.Name
+		stmts, comments, notes := modifyColumnSQL(sourceDialect, targetDialect, targetQueryTable, col, delta)
+		for _, n := range notes {
+			script.note(tableName, n)
+		}
+		script.add(phaseAlterColumn, stmts...)
+		script.add(phaseComment, comments...)
+	}
+	for _, tcol := range targetCols {
+		name := strings.TrimSpace(tcol.Name)
+		if _, ok := sourceNames[strings.ToLower(name)]; ok || name == "" {
+			continue
+		}
+		change("column", name, "drop", tcol.Type)
+		script.add(phaseDropColumn, dropColumnSQL(targetDialect, targetQueryTable, name)...)
+	}
+
+	// 2) 主键
+	sourceIdx := groupIndexes(sourceIndexes, sourcePK)
+	targetIdx := groupIndexes(targetIndexes, targetPK)
+	if !sameColumnSet(sourcePK, targetPK) {
+		change("primaryKey", strings.Join(sourcePK, ","), "modify", fmt.Sprintf("(%s) -> (%s)", strings.Join(targetPK, ","), strings.Join(sourcePK, ",")))
+		if len(targetPK) > 0 {
+			script.add(phaseDropPrimaryKey, dropPrimaryKeySQL(targetDialect, targetQueryTable, primaryKeyName(targetIndexes, targetPK), script, tableName))
+		}
+		if len(sourcePK) > 0 {
+			script.add(phaseAddPrimaryKey, addPrimaryKeySQL(targetDialect, targetQueryTable, sourcePK, script, tableName))
+		}
+	}
+
+	// 3) 索引：改了类型的字段上的索引在 SQL Server 中会阻止 ALTER COLUMN，需要先删后建
+	targetIdxByName := make(map[string]indexShape, len(targetIdx))
+	for _, idx := range targetIdx {
+		targetIdxByName[strings.ToLower(idx.name)] = idx
+	}
+	for _, idx := range sourceIdx {
+		existing, ok := targetIdxByName[strings.ToLower(idx.name)]
+		delete(targetIdxByName, strings.ToLower(idx.name))
+		switch {
+		case !ok:
+			change("index", idx.name, "add", idx.describe())
+		case !idx.equal(existing, sameFamily):
+			change("index", idx.name, "modify", fmt.Sprintf("%s -> %s", existing.describe(), idx.describe()))
+			script.add(phaseDropIndex, dropIndexSQL(targetDialect, targetQueryTable, existing.name))
+		case caps.Family == db.FamilySQLServer && existing.touches(retyped):
+			script.add(phaseDropIndex, dropIndexSQL(targetDialect, targetQueryTable, existing.name))
+		default:
+			continue
+		}
+		script.add(phaseAddIndex, createIndexSQL(sourceDialect, targetDialect, targetQueryTable, idx, script, tableName))
+	}
+	for _, idx := range targetIdx {
+		if _, ok := targetIdxByName[strings.ToLower(idx.name)]; !ok {
+			continue
+		}
+		change("index", idx.name, "drop", idx.describe())
+		script.add(phaseDropIndex, dropIndexSQL(targetDialect, targetQueryTable, idx.name))
+	}
+
+	// 4) 外键：改了类型的字段上的外键需要先删后建，否则 MySQL 等会拒绝修改字段
+	targetFKByName := make(map[string]foreignKeyShape)
+	targetFKList := groupForeignKeys(targetFKs)
+	for _, fk := range targetFKList {
+		targetFKByName[strings.ToLower(fk.name)] = fk
+	}
+	for _, fk := range groupForeignKeys(sourceFKs) {
+		existing, ok := targetFKByName[strings.ToLower(fk.name)]
+		delete(targetFKByName, strings.ToLower(fk.name))
+		switch {
+		case !ok:
+			change("foreignKey", fk.name, "add", fk.describe())
+		case !fk.equal(existing):
+			change("foreignKey", fk.name, "modify", fmt.Sprintf("%s -> %s", existing.describe(), fk.describe()))
+			script.add(phaseDropForeignKey, dropForeignKeySQL(targetDialect, targetQueryTable, existing.name, script, tableName))
+		case existing.touches(retyped):
+			script.add(phaseDropForeignKey, dropForeignKeySQL(targetDialect, targetQueryTable, existing.name, script, tableName))
+		default:
+			continue
+		}
+		script.add(phaseAddForeignKey, addForeignKeySQL(targetDialect, targetQueryTable, fk, script, tableName))
+	}
+	for _, fk := range targetFKList {
+		if _, ok := targetFKByName[strings.ToLower(fk.name)]; !ok {
+			continue
+		}
+		change("foreignKey", fk.name, "drop", fk.describe())
+		script.add(phaseDropForeignKey, dropForeignKeySQL(targetDialect, targetQueryTable, fk.name, script, tableName))
+	}
+
+	// 5) 触发器
+	targetTrigByName := make(map[string]triggerShape)
+	targetTrigList := groupTriggers(targetTriggers)
+	for _, trig := range targetTrigList {
+		targetTrigByName[strings.ToLower(trig.name)] = trig
+	}
+	for _, trig := range groupTriggers(sourceTriggers) {
+		existing, ok := targetTrigByName[strings.ToLower(trig.name)]
+		delete(targetTrigByName, strings.ToLower(trig.name))
+		switch {
+		case !ok:
+			change("trigger", trig.name, "add", trig.describe())
+		case !trig.equal(existing):
+			change("trigger", trig.name, "modify", trig.describe())
+			script.add(phaseDropTrigger, dropTriggerSQL(targetDialect, targetQueryTable, existing.name))
+		default:
+			continue
+		}
+		createTriggerSQL(sourceDialect, targetDialect, targetQueryTable, trig, script, tableName)
+	}
+	for _, trig := range targetTrigList {
+		if _, ok := targetTrigByName[strings.ToLower(trig.name)]; !ok {
+			continue
+		}
+		change("trigger", trig.name, "drop", trig.describe())
+		script.add(phaseDropTrigger, dropTriggerSQL(targetDialect, targetQueryTable, trig.name))
+	}
+
+	// 6) 表选项
+	s.compareTableOptions(sourceDB, targetDB, sourceDialect, targetDialect, sourceSchema, sourceTable, targetSchema, targetTable, targetQueryTable, tableName, script, change)
+
+	if len(diff.Changes) > 0 {
+		diff.Status = "changed"
+	}
+	return diff
+}
+
+// compareTableOptions 对比表级选项。两端驱动都能读取表选项时才对比；跨方言族时只对比表注释。
+// targetTable 为空表示目标表尚未创建，按源表选项全部生成。
+func (s *SyncEngine) compareTableOptions(sourceDB db.Database, targetDB db.Database, sourceDialect string, targetDialect string, sourceSchema string, sourceTable string, targetSchema string, targetTable string, targetQueryTable string, tableName string, script *schemaScript, change func(object, name, action, detail string)) {
+	sourceProvider, ok := sourceDB.(db.TableOptionsProvider)
+	if !ok {
+		return
+	}
+	sourceOpts, err := sourceProvider.GetTableOptions(sourceSchema, sourceTable)
+	if err != nil {
+		script.note(tableName, "获取源表选项失败，已跳过表选项对比: "+err.Error())
+		return
+	}
+	current := make(map[string]string)
+	if targetTable != "" {
+		targetProvider, ok := targetDB.(db.TableOptionsProvider)
+		if !ok {
+			return
+		}
+		targetOpts, err := targetProvider.GetTableOptions(targetSchema, targetTable)
+		if err != nil {
+			script.note(tableName, "获取目标表选项失败，已跳过表选项对比: "+err.Error())
+			return
+		}
+		for _, opt := range targetOpts {
+			current[strings.ToUpper(opt.Name)] = opt.Value
+		}
+	}
+
+	sameFamily := db.Capabilities(sourceDialect).Family == db.Capabilities(targetDialect).Family
+	for _, opt := range sourceOpts {
+		name := strings.ToUpper(strings.TrimSpace(opt.Name))
+		if name != "COMMENT" && !sameFamily {
+			continue
+		}
+		if old, ok := current[name]; ok && old == opt.Value {
+			continue
+		}
+		if targetTable == "" && strings.TrimSpace(opt.Value) == "" {
+			continue
+		}
+		stmt := tableOptionSQL(targetDialect, targetQueryTable, name, opt.Value)
+		if stmt == "" {
+			continue
+		}
+		if targetTable != "" {
+			change("option", name, "modify", fmt.Sprintf("%s -> %s", current[name], opt.Value))
+		}
+		script.add(phaseComment, stmt)
+	}
+}
+
+// renderSchemaScript 拼接可保存/执行的脚本：提示信息写为注释，MySQL 复合语句用 DELIMITER 包裹。
+func renderSchemaScript(config SyncConfig, targetDialect string, stmts []schemaStatement, notes []string) string {
+	var b strings.Builder
+	b.WriteString("-- GoNavi 结构对比迁移脚本\n")
+	b.WriteString(fmt.Sprintf("-- 源：%s\n", formatConnSummaryForSync(config.SourceConfig)))
+	b.WriteString(fmt.Sprintf("-- 目标：%s\n", formatConnSummaryForSync(config.TargetConfig)))
+	b.WriteString(fmt.Sprintf("-- 生成时间：%s\n", time.Now().Format("2006-01-02 15:04:05")))
+	if len(notes) > 0 {
+		b.WriteString("--\n-- 注意：\n")
+		for _, n := range notes {
+			b.WriteString("--   " + strings.ReplaceAll(n, "\n", " ") + "\n")
+		}
+	}
+	if len(stmts) == 0 {
+		b.WriteString("\n-- 结构一致，无需变更\n")
+		return b.String()
+	}
+	mysqlLike := db.Capabilities(targetDialect).Family == db.FamilyMySQL
+	for _, stmt := range stmts {
+		b.WriteString("\n")
+		if stmt.block && mysqlLike {
+			b.WriteString("DELIMITER $$\n")
+			b.WriteString(stmt.sql)
+			b.WriteString("$$\nDELIMITER ;\n")
+			continue
+		}
+		b.WriteString(stmt.sql)
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
+
+// tablesMissingFrom 返回 tables 中在 reference 里找不到同名表（忽略 schema 前缀与大小写）的表。
+func tablesMissingFrom(tables []string, reference []string) []string {
+	known := make(map[string]struct{}, len(reference))
+	for _, t := range reference {
+		known[strings.ToLower(bareTableName(t))] = struct{}{}
+	}
+	out := make([]string, 0)
+	for _, t := range tables {
+		if _, ok := known[strings.ToLower(bareTableName(t))]; !ok {
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// orderTablesForDrop 按外键依赖排列待删除的表：引用其它表的表排在被引用的表之前，
+// 循环引用的表之间保持原有顺序。
+func orderTablesForDrop(tables []string, fks map[string][]foreignKeyShape) []string {
+	byName := make(map[string]string, len(tables))
+	for _, t := range tables {
+		byName[strings.ToLower(bareTableName(t))] = t
+	}
+	referencedBy := make(map[string][]string)
+	for _, t := range tables {
+		for _, fk := range fks[t] {
+			if parent, ok := byName[strings.ToLower(bareTableName(fk.refTable))]; ok && parent != t {
+				referencedBy[parent] = append(referencedBy[parent], t)
+			}
+		}
+	}
+
+	out := make([]string, 0, len(tables))
+	visited := make(map[string]bool, len(tables))
+	var visit func(t string)
+	visit = func(t string) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		for _, child := range referencedBy[t] {
+			visit(child)
+		}
+		out = append(out, t)
+	}
+	for _, t := range tables {
+		visit(t)
+	}
+	return out
+}
+
+func bareTableName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func primaryKeyColumns(cols []connection.ColumnDefinition) []string {
+	out := make([]string, 0, 2)
+	for _, c := range cols {
+		if c.Key == "PRI" || c.Key == "PK" {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
+
+func sameColumnSet(a []string, b []string) bool {
+	return strings.ToLower(strings.Join(sortedCopy(a), ",")) == strings.ToLower(strings.Join(sortedCopy(b), ","))
+}
+
+func containsFold(items []string, name string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnDelta 记录单个字段的差异，修改语句只针对发生变化的属性生成。
+type columnDelta struct {
+	typeChanged    bool
+	nullChanged    bool
+	defaultChanged bool
+	commentChanged bool
+	isPK           bool
+	fromType       string
+	toType         string // 映射到目标方言后的源字段类型
+	notNull        bool
+	defaultSQL     string // 空串表示没有默认值
+	comment        string
+}
+
+func (d columnDelta) changed() bool {
+	return d.typeChanged || d.nullChanged || d.defaultChanged || d.commentChanged
+}
+
+func (d columnDelta) describe() string {
+	parts := make([]string, 0, 4)
+	if d.typeChanged {
+		parts = append(parts, fmt.Sprintf("类型 %s -> %s", d.fromType, d.toType))
+	}
+	if d.nullChanged {
+		if d.notNull {
+			parts = append(parts, "改为 NOT NULL")
+		} else {
+			parts = append(parts, "改为允许 NULL")
+		}
+	}
+	if d.defaultChanged {
+		if d.defaultSQL == "" {
+			parts = append(parts, "删除默认值")
+		} else {
+			parts = append(parts, "默认值 "+d.defaultSQL)
+		}
+	}
+	if d.commentChanged {
+		parts = append(parts, "注释变更")
+	}
+	return strings.Join(parts, "；")
+}
+
+// compareColumn 按目标方言对比字段：类型与默认值都先转换为目标方言的写法再比较。
+func compareColumn(sourceDialect string, targetDialect string, src connection.ColumnDefinition, tgt connection.ColumnDefinition, isPK bool) columnDelta {
+	caps := db.Capabilities(targetDialect)
+	sameFamily := db.Capabilities(sourceDialect).Family == caps.Family
+	mapped := mapColumnType(sourceDialect, targetDialect, src, false)
+	d := columnDelta{
+		isPK:     isPK,
+		fromType: tgt.Type,
+		toType:   mapped,
+		notNull:  isPK || strings.EqualFold(strings.TrimSpace(src.Nullable), "NO"),
+		comment:  strings.TrimSpace(src.Comment),
+	}
+	d.typeChanged = !sameColumnType(targetDialect, mapped, tgt.Type, sameFamily)
+
+	if caps.Family == db.FamilyTDengine {
+		return d
+	}
+	targetNotNull := tgt.Key == "PRI" || tgt.Key == "PK" || strings.EqualFold(strings.TrimSpace(tgt.Nullable), "NO")
+	d.nullChanged = d.notNull != targetNotNull
+
+	if !isAutoIncrementColumn(src) && !isAutoIncrementColumn(tgt) {
+		parsed := parseColumnType(targetDialect, mapped)
+		want, wantOK := mapColumnDefault(sourceDialect, targetDialect, src, parsed)
+		have, haveOK := mapColumnDefault(targetDialect, targetDialect, tgt, parseColumnType(targetDialect, tgt.Type))
+		if wantOK {
+			d.defaultSQL = want
+		}
+		d.defaultChanged = wantOK != haveOK || (wantOK && !strings.EqualFold(want, have))
+	}
+
+	switch caps.Family {
+	case db.FamilyMySQL, db.FamilyPostgres, db.FamilySQLServer, db.FamilyOracle, db.FamilyDameng:
+		d.commentChanged = d.comment != strings.TrimSpace(tgt.Comment)
+	}
+	return d
+}
+
+// sameColumnType 判断两个目标方言类型是否等价。同一方言族优先比较原始文本（保留枚举值等细节），
+// 整数类型忽略 MySQL 显示宽度；跨方言族时比较按目标方言规范化后的类型。
+func sameColumnType(targetDialect string, want string, have string, sameFamily bool) bool {
+	norm := func(s string) string { return strings.Join(strings.Fields(strings.ToLower(s)), "") }
+	if norm(want) == norm(have) {
+		return true
+	}
+	wantType := parseColumnType(targetDialect, want)
+	haveType := parseColumnType(targetDialect, have)
+	if sameFamily && !(wantType.isInteger() && haveType.isInteger()) {
+		return false
+	}
+	if wantType.kind == kindUnknown || haveType.kind == kindUnknown {
+		return false
+	}
+	return renderColumnType(targetDialect, wantType, false) == renderColumnType(targetDialect, haveType, false)
+}
+
+// indexShape 是按索引名汇总后的索引。
+type indexShape struct {
+	name    string
+	columns []string
+	unique  bool
+	kind    string // 索引类型（BTREE/FULLTEXT/GIN 等），仅同方言族时参与对比
+}
+
+func (i indexShape) describe() string {
+	prefix := ""
+	if i.unique {
+		prefix = "UNIQUE "
+	}
+	return fmt.Sprintf("%s(%s)", prefix, strings.Join(i.columns, ", "))
+}
+
+func (i indexShape) equal(other indexShape, sameFamily bool) bool {
+	if i.unique != other.unique || !strings.EqualFold(strings.Join(i.columns, ","), strings.Join(other.columns, ",")) {
+		return false
+	}
+	return !sameFamily || strings.EqualFold(i.kind, other.kind)
+}
+
+func (i indexShape) touches(columns map[string]bool) bool {
+	for _, c := range i.columns {
+		if columns[strings.ToLower(c)] {
+			return true
+		}
+	}
+	return false
+}
+
+// groupIndexes 按索引名汇总 GetIndexes 的逐列结果，排除主键索引（主键单独对比）。
+func groupIndexes(indexes []connection.IndexDefinition, pkCols []string) []indexShape {
+	type indexColumn struct {
+		seq  int
+		name string
+	}
+	grouped := make(map[string][]indexColumn)
+	shapes := make(map[string]*indexShape)
+	order := make([]string, 0)
+	for _, idx := range indexes {
+		name := strings.TrimSpace(idx.Name)
+		if name == "" || strings.EqualFold(name, "PRIMARY") {
+			continue
+		}
+		if _, ok := shapes[name]; !ok {
+			shapes[name] = &indexShape{name: name, unique: idx.NonUnique == 0, kind: strings.ToUpper(strings.TrimSpace(idx.IndexType))}
+			order = append(order, name)
+		}
+		grouped[name] = append(grouped[name], indexColumn{seq: idx.SeqInIndex, name: strings.TrimSpace(idx.ColumnName)})
+	}
+
+	sort.Strings(order)
+	out := make([]indexShape, 0, len(order))
+	for _, name := range order {
+		entries := grouped[name]
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+		shape := shapes[name]
+		for _, e := range entries {
+			shape.columns = append(shape.columns, e.name)
+		}
+		if shape.unique && len(pkCols) > 0 && sameColumnSet(shape.columns, pkCols) {
+			continue
+		}
+		out = append(out, *shape)
+	}
+	return out
+}
+
+// primaryKeyName 在索引列表中查找主键对应的索引名（PG/SQL Server/Oracle 中与主键约束同名）。
+func primaryKeyName(indexes []connection.IndexDefinition, pkCols []string) string {
+	columns := make(map[string][]string)
+	unique := make(map[string]bool)
+	for _, idx := range indexes {
+		name := strings.TrimSpace(idx.Name)
+		if strings.EqualFold(name, "PRIMARY") {
+			return name
+		}
+		columns[name] = append(columns[name], idx.ColumnName)
+		unique[name] = idx.NonUnique == 0
+	}
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if unique[name] && sameColumnSet(columns[name], pkCols) {
+			return name
+		}
+	}
+	return ""
+}
+
+// foreignKeyShape 是按约束名汇总后的外键。
+type foreignKeyShape struct {
+	name       string
+	columns    []string
+	refTable   string
+	refColumns []string
+}
+
+func (f foreignKeyShape) describe() string {
+	return fmt.Sprintf("(%s) -> %s(%s)", strings.Join(f.columns, ", "), f.refTable, strings.Join(f.refColumns, ", "))
+}
+
+func (f foreignKeyShape) equal(other foreignKeyShape) bool {
+	return strings.EqualFold(strings.Join(f.columns, ","), strings.Join(other.columns, ",")) &&
+		strings.EqualFold(bareTableName(f.refTable), bareTableName(other.refTable)) &&
+		strings.EqualFold(strings.Join(f.refColumns, ","), strings.Join(other.refColumns, ","))
+}
+
+func (f foreignKeyShape) touches(columns map[string]bool) bool {
+	for _, c := range f.columns {
+		if columns[strings.ToLower(c)] {
+			return true
+		}
+	}
+	return false
+}
+
+func groupForeignKeys(fks []connection.ForeignKeyDefinition) []foreignKeyShape {
+	shapes := make(map[string]*foreignKeyShape)
+	order := make([]string, 0)
+	for _, fk := range fks {
+		name := strings.TrimSpace(fk.ConstraintName)
+		if name == "" {
+			name = strings.TrimSpace(fk.Name)
+		}
+		if name == "" {
+			continue
+		}
+		shape, ok := shapes[name]
+		if !ok {
+			shape = &foreignKeyShape{name: name, refTable: strings.TrimSpace(fk.RefTableName)}
+			shapes[name] = shape
+			order = append(order, name)
+		}
+		shape.columns = append(shape.columns, strings.TrimSpace(fk.ColumnName))
+		shape.refColumns = append(shape.refColumns, strings.TrimSpace(fk.RefColumnName))
+	}
+	sort.Strings(order)
+	out := make([]foreignKeyShape, 0, len(order))
+	for _, name := range order {
+		out = append(out, *shapes[name])
+	}
+	return out
+}
+
+// triggerShape 是按触发器名汇总后的触发器（PG 每个触发事件返回一行）。
+type triggerShape struct {
+	name      string
+	timing    string
+	events    []string
+	statement string
+}
+
+func (t triggerShape) describe() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", t.timing, strings.Join(t.events, " OR ")))
+}
+
+func (t triggerShape) equal(other triggerShape) bool {
+	if !strings.EqualFold(t.describe(), other.describe()) {
+		return false
+	}
+	norm := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	// 部分驱动不返回触发器定义，只能比较触发时机与事件
+	return t.statement == "" || other.statement == "" || norm(t.statement) == norm(other.statement)
+}
+
+func groupTriggers(triggers []connection.TriggerDefinition) []triggerShape {
+	shapes := make(map[string]*triggerShape)
+	order := make([]string, 0)
+	for _, trig := range triggers {
+		name := strings.TrimSpace(trig.Name)
+		if name == "" {
+			continue
+		}
+		shape, ok := shapes[name]
+		if !ok {
+			shape = &triggerShape{name: name, timing: strings.ToUpper(strings.TrimSpace(trig.Timing)), statement: strings.TrimSpace(trig.Statement)}
+			shapes[name] = shape
+			order = append(order, name)
+		}
+		if event := strings.ToUpper(strings.TrimSpace(trig.Event)); event != "" && !containsFold(shape.events, event) {
+			shape.events = append(shape.events, event)
+		}
+	}
+	sort.Strings(order)
+	out := make([]triggerShape, 0, len(order))
+	for _, name := range order {
+		shape := shapes[name]
+		sort.Strings(shape.events)
+		out = append(out, *shape)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"fmt"
+	"strings"
+)
+
+// schemaSyncSupported 判断方言能否参与结构同步（需要是关系型数据库，且支持 DDL）。
+func schemaSyncSupported(dialect string) bool {
+	caps := db.Capabilities(dialect)
+	return caps.Family != db.FamilyMongoDB && caps.Type != "sphinx"
+}
+
+// buildCreateTableSQL 按源表字段生成目标方言的建表语句及建表后执行的列注释语句。
+// 返回的提示信息记录了无法转换而被忽略的属性（默认值、自增等）。
+func buildCreateTableSQL(sourceDialect string, targetDialect string, targetQueryTable string, cols []connection.ColumnDefinition) ([]string, []string, error) {
+	caps := db.Capabilities(targetDialect)
+	defs := make([]string, 0, len(cols)+1)
+	pkCols := make([]string, 0, 2)
+	notes := make([]string, 0)
+	firstType := ""
+	for _, col := range cols {
+		if strings.TrimSpace(col.Name) == "" {
+			continue
+		}
+		if firstType == "" {
+			firstType = mapColumnType(sourceDialect, targetDialect, col, false)
+		}
+		isPK := col.Key == "PRI" || col.Key == "PK"
+		def, colNotes := columnDefinitionSQL(sourceDialect, targetDialect, col, isPK)
+		defs = append(defs, def)
+		notes = append(notes, colNotes...)
+		if isPK {
+			pkCols = append(pkCols, caps.QuoteIdent(col.Name))
+		}
+	}
+	if len(defs) == 0 {
+		return nil, notes, fmt.Errorf("源表没有可用字段")
+	}
+
+	if caps.Family == db.FamilyTDengine {
+		// TDengine 以首列时间戳作为主键
+		if !strings.EqualFold(firstType, "TIMESTAMP") {
+			return nil, notes, fmt.Errorf("TDengine 目标表的首列必须为时间类型")
+		}
+	} else if len(pkCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkCols, ", ")))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", caps.QuoteQualifiedIdent(targetQueryTable), strings.Join(defs, ",\n  "))}
+	for _, col := range cols {
+		if stmt := columnCommentSQL(targetDialect, targetQueryTable, col); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements, notes, nil
+}
+
+// columnDefinitionSQL 生成建表语句中的单列定义：列名、类型、自增、默认值、非空约束与（MySQL）注释。
+func columnDefinitionSQL(sourceDialect string, targetDialect string, col connection.ColumnDefinition, isPK bool) (string, []string) {
+	caps := db.Capabilities(targetDialect)
+	notes := make([]string, 0)
+	autoIncrement := isAutoIncrementColumn(col)
+	colType := mapColumnType(sourceDialect, targetDialect, col, autoIncrement)
+	parts := []string{caps.QuoteIdent(col.Name), colType}
+	if caps.Family == db.FamilyTDengine {
+		return strings.Join(parts, " "), notes
+	}
+
+	identity := false
+	if autoIncrement {
+		switch caps.Family {
+		case db.FamilyMySQL:
+			identity = isPK
+		case db.FamilyPostgres:
+			// 类型已改写为 SERIAL
+			identity = strings.Contains(strings.ToUpper(colType), "SERIAL")
+		case db.FamilySQLServer:
+			parts = append(parts, "IDENTITY(1,1)")
+			identity = true
+		case db.FamilyOracle:
+			// BY DEFAULT 允许同步写入源表的原值
+			parts = append(parts, "GENERATED BY DEFAULT AS IDENTITY")
+			identity = true
+		}
+		if !identity && caps.Family != db.FamilySQLite {
+			notes = append(notes, fmt.Sprintf("字段 %s 的自增属性未迁移", col.Name))
+		}
+	}
+
+	if col.Default != nil && !autoIncrement {
+		target := parseColumnType(targetDialect, colType)
+		if def, ok := mapColumnDefault(sourceDialect, targetDialect, col, target); ok {
+			parts = append(parts, "DEFAULT "+def)
+		} else if strings.TrimSpace(*col.Default) != "" && !strings.EqualFold(strings.TrimSpace(*col.Default), "NULL") {
+			notes = append(notes, fmt.Sprintf("字段 %s 的默认值 %s 无法转换，已忽略", col.Name, strings.TrimSpace(*col.Default)))
+		}
+	}
+
+	if isPK || strings.EqualFold(strings.TrimSpace(col.Nullable), "NO") {
+		parts = append(parts, "NOT NULL")
+	}
+	if identity && caps.Family == db.FamilyMySQL {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if caps.Family == db.FamilyMySQL && strings.TrimSpace(col.Comment) != "" {
+		parts = append(parts, "COMMENT "+stringLiteral(db.FamilyMySQL, col.Comment))
+	}
+	return strings.Join(parts, " "), notes
+}
+
+// addColumnSQL 生成补齐缺失字段的 ALTER 语句，新增字段统一允许 NULL，不带默认值与自增属性。
+func addColumnSQL(sourceDialect string, targetDialect string, targetQueryTable string, col connection.ColumnDefinition) (string, string) {
+	caps := db.Capabilities(targetDialect)
+	colType := mapColumnType(sourceDialect, targetDialect, col, false)
+	table := caps.QuoteQualifiedIdent(targetQueryTable)
+	column := caps.QuoteIdent(col.Name)
+
+	switch caps.Family {
+	case db.FamilyMySQL:
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL", table, column, colType)
+		if strings.TrimSpace(col.Comment) != "" {
+			stmt += " COMMENT " + stringLiteral(db.FamilyMySQL, col.Comment)
+		}
+		return stmt, colType
+	case db.FamilyPostgres:
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL", table, column, colType), colType
+	case db.FamilySQLite, db.FamilyTDengine:
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, colType), colType
+	case db.FamilySQLServer, db.FamilyOracle, db.FamilyDameng:
+		return fmt.Sprintf("ALTER TABLE %s ADD %s %s NULL", table, column, colType), colType
+	default:
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, colType), colType
+	}
+}
+
+// columnCommentSQL 生成单独设置列注释的语句；MySQL 注释写在列定义中，SQLite/TDengine 不支持列注释，均返回空串。
+func columnCommentSQL(targetDialect string, targetQueryTable string, col connection.ColumnDefinition) string {
+	comment := strings.TrimSpace(col.Comment)
+	if comment == "" {
+		return ""
+	}
+	caps := db.Capabilities(targetDialect)
+	switch caps.Family {
+	case db.FamilyPostgres, db.FamilyOracle, db.FamilyDameng:
+		return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", caps.QuoteQualifiedIdent(targetQueryTable), caps.QuoteIdent(col.Name), stringLiteral(caps.Family, comment))
+	case db.FamilySQLServer:
+		schema, table := "dbo", strings.TrimSpace(targetQueryTable)
+		if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+			schema, table = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+		return fmt.Sprintf("EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, @level0type = N'SCHEMA', @level0name = %s, @level1type = N'TABLE', @level1name = %s, @level2type = N'COLUMN', @level2name = %s",
+			stringLiteral(db.FamilySQLServer, comment), stringLiteral(db.FamilySQLServer, schema), stringLiteral(db.FamilySQLServer, table), stringLiteral(db.FamilySQLServer, col.Name))
+	default:
+		return ""
+	}
+}
@@ -1,68 +1,59 @@
 package sync
 
 import (
+	"GoNavi-Wails/internal/connection"
 	"GoNavi-Wails/internal/db"
 	"fmt"
 	"strings"
 )
 
-func (s *SyncEngine) syncTableSchema(config SyncConfig, res *SyncResult, sourceDB db.Database, targetDB db.Database, tableName string) error {
-	targetType := strings.ToLower(strings.TrimSpace(config.TargetConfig.Type))
-	if targetType != "mysql" {
-		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("目标数据库类型=%s 暂不支持结构同步，已跳过表 %s", config.TargetConfig.Type, tableName))
+// syncTableSchema 确保目标表存在、补齐缺失字段，并把类型或可空性与源表不一致的已有字段改为源表定义，
+// 目标表名与字段名按表映射换算。
+func (s *SyncEngine) syncTableSchema(config SyncConfig, res *SyncResult, sourceDB db.Database, targetDB db.Database, tableName string, mapping *tableMapping) error {
+	sourceDialect := db.ResolveDialect(config.SourceConfig)
+	targetDialect := db.ResolveDialect(config.TargetConfig)
+	if !schemaSyncSupported(targetDialect) || !schemaSyncSupported(sourceDialect) {
+		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("源类型=%s 目标类型=%s 暂不支持结构同步，已跳过表 %s", config.SourceConfig.Type, config.TargetConfig.Type, tableName))
 		return nil
 	}
 
 	sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
-	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
-	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
+	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, mapping.targetTable)
+	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, mapping.targetTable)
 
-	// 1) 获取源表字段
+	// 1) 获取源表字段，并换算为目标字段名
 	sourceCols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
 	if err != nil {
 		return fmt.Errorf("获取源表字段失败: %w", err)
 	}
+	sourceCols = mapping.targetColumns(sourceCols)
 
-	// 2) 确保目标表存在
+	// 2) 确保目标表存在（部分驱动查询不存在的表时返回空字段列表而不是错误）
 	targetCols, err := targetDB.GetColumns(targetSchema, targetTable)
-	if err != nil {
-		sourceType := strings.ToLower(strings.TrimSpace(config.SourceConfig.Type))
-		if sourceType != "mysql" {
-			return fmt.Errorf("目标表不存在且源类型=%s 暂不支持自动建表: %w", config.SourceConfig.Type, err)
+	created := err != nil || len(targetCols) == 0
+	if created {
+		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("目标表 %s 不存在，开始尝试创建表结构", mapping.targetTable))
+		copyCreate := mapping.identity() && mapping.targetTable == tableName
+		if err := s.createTargetTable(config, res, sourceDB, targetDB, sourceDialect, targetDialect, sourceSchema, sourceTable, targetQueryTable, sourceCols, copyCreate); err != nil {
+			return err
 		}
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("目标表创建成功：%s", mapping.targetTable))
 
-		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("目标表 %s 不存在，开始尝试创建表结构", tableName))
-		createSQL, errCreate := sourceDB.GetCreateStatement(sourceSchema, sourceTable)
-		if errCreate != nil || strings.TrimSpace(createSQL) == "" {
-			if errCreate == nil {
-				errCreate = fmt.Errorf("建表语句为空")
+		if config.DryRun {
+			// 演练模式只把建表语句写入脚本，目标表结构即为源表结构
+			targetCols = sourceCols
+		} else {
+			targetCols, err = targetDB.GetColumns(targetSchema, targetTable)
+			if err != nil {
+				return fmt.Errorf("创建目标表后获取字段失败: %w", err)
 			}
-			return fmt.Errorf("获取源表建表语句失败: %w", errCreate)
-		}
-
-		if _, errExec := targetDB.Exec(createSQL); errExec != nil {
-			return fmt.Errorf("创建目标表失败: %w", errExec)
-		}
-		s.appendLog(config.JobID, res, "info", fmt.Sprintf("目标表创建成功：%s", tableName))
-
-		targetCols, err = targetDB.GetColumns(targetSchema, targetTable)
-		if err != nil {
-			return fmt.Errorf("创建目标表后获取字段失败: %w", err)
 		}
 	}
 
-	targetColSet := make(map[string]struct{}, len(targetCols))
-	for _, c := range targetCols {
-		name := strings.ToLower(strings.TrimSpace(c.Name))
-		if name == "" {
-			continue
-		}
-		targetColSet[name] = struct{}{}
-	}
+	targetColSet := buildColumnNameSet(targetCols)
 
 	// 3) 补齐目标缺失字段（安全策略：新增字段统一允许 NULL）
 	missing := make([]string, 0)
-	sourceType := strings.ToLower(strings.TrimSpace(config.SourceConfig.Type))
 	for _, c := range sourceCols {
 		colName := strings.TrimSpace(c.Name)
 		if colName == "" {
@@ -74,28 +65,111 @@ func (s *SyncEngine) syncTableSchema(config SyncConfig, res *SyncResult, sourceD
 		}
 		missing = append(missing, colName)
 
-		colType := "TEXT"
-		if sourceType == "mysql" {
-			colType = sanitizeMySQLColumnType(c.Type)
-		}
-
-		alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL",
-			quoteQualifiedIdentByType("mysql", targetQueryTable),
-			quoteIdentByType("mysql", colName),
-			colType,
-		)
+		alterSQL, colType := addColumnSQL(sourceDialect, targetDialect, targetQueryTable, c)
 		if _, err := targetDB.Exec(alterSQL); err != nil {
 			s.appendLog(config.JobID, res, "error", fmt.Sprintf("  -> 补字段失败：表=%s 字段=%s 错误=%v", tableName, colName, err))
 			continue
 		}
+		if stmt := columnCommentSQL(targetDialect, targetQueryTable, c); stmt != "" {
+			if _, err := targetDB.Exec(stmt); err != nil {
+				s.appendLog(config.JobID, res, "warn", fmt.Sprintf("  -> 设置字段注释失败：表=%s 字段=%s 错误=%v", tableName, colName, err))
+			}
+		}
 		s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 已补齐字段：表=%s 字段=%s 类型=%s", tableName, colName, colType))
 	}
 
-	if len(missing) == 0 {
+	// 4) 修改类型（含长度、精度）或可空性不一致的已有字段，如早期按 TEXT 创建的字段；刚创建的表无需对比
+	modified := 0
+	if !created {
+		modified = s.alterMismatchedColumns(config, res, targetDB, sourceDialect, targetDialect, tableName, targetQueryTable, sourceCols, targetCols)
+	}
+
+	if len(missing) == 0 && modified == 0 {
 		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表结构一致：%s", tableName))
 	} else {
-		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表结构同步完成：%s（新增字段 %d 个）", tableName, len(missing)))
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表结构同步完成：%s（新增字段 %d 个，修改字段 %d 个）", tableName, len(missing), modified))
+	}
+
+	return nil
+}
+
+// alterMismatchedColumns 按 compareColumn 对比源表与目标表都有的字段，类型或可空性不同时用 modifyColumnSQL 修改，
+// 默认值与注释不在数据同步时调整。返回修改成功的字段数；目标方言不支持修改字段时记录提示。
+func (s *SyncEngine) alterMismatchedColumns(config SyncConfig, res *SyncResult, targetDB db.Database, sourceDialect string, targetDialect string, tableName string, targetQueryTable string, sourceCols []connection.ColumnDefinition, targetCols []connection.ColumnDefinition) int {
+	targetByName := make(map[string]connection.ColumnDefinition, len(targetCols))
+	for _, c := range targetCols {
+		targetByName[strings.ToLower(strings.TrimSpace(c.Name))] = c
+	}
+	sourcePK := primaryKeyColumns(sourceCols)
+
+	modified := 0
+	for _, col := range sourceCols {
+		name := strings.TrimSpace(col.Name)
+		tcol, ok := targetByName[strings.ToLower(name)]
+		if name == "" || !ok {
+			continue
+		}
+		delta := compareColumn(sourceDialect, targetDialect, col, tcol, containsFold(sourcePK, name))
+		delta.defaultChanged, delta.commentChanged = false, false
+		if !delta.changed() {
+			continue
+		}
+		// 修改语句使用目标表中的字段名写法（Oracle 等带引号的标识符区分大小写）
+		col.Name = tcol.Name
+		stmts, _, notes := modifyColumnSQL(sourceDialect, targetDialect, targetQueryTable, col, delta)
+		for _, note := range notes {
+			s.appendLog(config.JobID, res, "warn", "  -> "+note)
+		}
+		if len(stmts) == 0 {
+			continue
+		}
+		failed := false
+		for _, stmt := range stmts {
+			if _, err := targetDB.Exec(stmt); err != nil {
+				s.appendLog(config.JobID, res, "error", fmt.Sprintf("  -> 修改字段失败：表=%s 字段=%s 错误=%v", tableName, name, err))
+				failed = true
+				break
+			}
+		}
+		if !failed {
+			modified++
+			s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 已修改字段：表=%s 字段=%s（%s）", tableName, name, delta.describe()))
+		}
+	}
+	return modified
+}
+
+// createTargetTable 在目标库创建表。MySQL 系之间且未配置表名、字段映射时（copyCreate）直接复用源表建表语句
+// （保留索引、引擎等信息），其它情况按类型映射生成建表语句。
+func (s *SyncEngine) createTargetTable(config SyncConfig, res *SyncResult, sourceDB db.Database, targetDB db.Database, sourceDialect string, targetDialect string, sourceSchema string, sourceTable string, targetQueryTable string, sourceCols []connection.ColumnDefinition, copyCreate bool) error {
+	if copyCreate && db.Capabilities(sourceDialect).Family == db.FamilyMySQL && db.Capabilities(targetDialect).Family == db.FamilyMySQL {
+		createSQL, errCreate := sourceDB.GetCreateStatement(sourceSchema, sourceTable)
+		if errCreate != nil || strings.TrimSpace(createSQL) == "" {
+			if errCreate == nil {
+				errCreate = fmt.Errorf("建表语句为空")
+			}
+			return fmt.Errorf("获取源表建表语句失败: %w", errCreate)
+		}
+		if _, errExec := targetDB.Exec(createSQL); errExec != nil {
+			return fmt.Errorf("创建目标表失败: %w", errExec)
+		}
+		return nil
 	}
 
+	statements, notes, err := buildCreateTableSQL(sourceDialect, targetDialect, targetQueryTable, sourceCols)
+	if err != nil {
+		return fmt.Errorf("生成建表语句失败: %w", err)
+	}
+	for _, note := range notes {
+		s.appendLog(config.JobID, res, "warn", "  -> "+note)
+	}
+	if _, err := targetDB.Exec(statements[0]); err != nil {
+		return fmt.Errorf("创建目标表失败: %w", err)
+	}
+	for _, stmt := range statements[1:] {
+		if _, err := targetDB.Exec(stmt); err != nil {
+			s.appendLog(config.JobID, res, "warn", fmt.Sprintf("  -> 设置字段注释失败：%v", err))
+		}
+	}
 	return nil
 }
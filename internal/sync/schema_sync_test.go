@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+)
+
+type fakeSchemaDB struct {
+	db.Database
+	columns []connection.ColumnDefinition
+	execs   []string
+}
+
+func (f *fakeSchemaDB) GetColumns(dbName, tableName string) ([]connection.ColumnDefinition, error) {
+	return f.columns, nil
+}
+
+func (f *fakeSchemaDB) Exec(query string) (int64, error) {
+	f.execs = append(f.execs, query)
+	return 0, nil
+}
+
+func TestSyncTableSchemaAltersMismatchedColumns(t *testing.T) {
+	source := &fakeSchemaDB{columns: []connection.ColumnDefinition{
+		{Name: "id", Type: "int", Key: "PRI", Nullable: "NO"},
+		{Name: "name", Type: "varchar(50)", Nullable: "NO"},
+		{Name: "qty", Type: "int", Nullable: "YES", Default: strPtr("0")},
+		{Name: "note", Type: "text", Nullable: "YES", Comment: "备注"},
+		{Name: "extra", Type: "int", Nullable: "YES"},
+	}}
+	target := &fakeSchemaDB{columns: []connection.ColumnDefinition{
+		{Name: "id", Type: "integer", Key: "PRI", Nullable: "NO"},
+		{Name: "name", Type: "text", Nullable: "YES"},
+		{Name: "qty", Type: "integer", Nullable: "NO"},
+		{Name: "note", Type: "text", Nullable: "YES"},
+	}}
+	mapping, err := newTableMapping("items", TableOptions{})
+	if err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	config := SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "mysql", Database: "app"},
+		TargetConfig: connection.ConnectionConfig{Type: "postgres", Database: "app"},
+	}
+
+	res := &SyncResult{}
+	if err := NewSyncEngine(Reporter{}).syncTableSchema(config, res, source, target, "items", mapping); err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	want := []string{
+		`ALTER TABLE "public"."items" ADD COLUMN "extra" INTEGER NULL`,
+		`ALTER TABLE "public"."items" ALTER COLUMN "name" TYPE VARCHAR(50) USING "name"::VARCHAR(50)`,
+		`ALTER TABLE "public"."items" ALTER COLUMN "name" SET NOT NULL`,
+		`ALTER TABLE "public"."items" ALTER COLUMN "qty" DROP NOT NULL`,
+	}
+	if !reflect.DeepEqual(target.execs, want) {
+		t.Fatalf("执行的语句不正确：\n实际=%q\n期望=%q", target.execs, want)
+	}
+	if last := res.Logs[len(res.Logs)-1]; !strings.Contains(last, "新增字段 1 个，修改字段 2 个") {
+		t.Fatalf("汇总日志不正确：%s", last)
+	}
+
+	// 目标库不支持修改字段时只记录提示
+	config.TargetConfig.Type = "sqlite"
+	target.execs = nil
+	res = &SyncResult{}
+	if err := NewSyncEngine(Reporter{}).syncTableSchema(config, res, source, target, "items", mapping); err != nil {
+		t.Fatalf("返回错误：%v", err)
+	}
+	for _, stmt := range target.execs {
+		if !strings.Contains(stmt, "ADD COLUMN") {
+			t.Fatalf("SQLite 目标不应执行修改字段语句：%s", stmt)
+		}
+	}
+	if !strings.Contains(strings.Join(res.Logs, "\n"), "SQLite 不支持修改字段 name") {
+		t.Fatalf("应提示 SQLite 不支持修改字段：%v", res.Logs)
+	}
+}
@@ -1,6 +1,10 @@
 package sync
 
-import "strings"
+import (
+	"strings"
+
+	"GoNavi-Wails/internal/db"
+)
 
 func normalizeSyncMode(mode string) string {
 	m := strings.ToLower(strings.TrimSpace(mode))
@@ -16,48 +20,6 @@ func normalizeSyncMode(mode string) string {
 	}
 }
 
-func quoteIdentByType(dbType string, ident string) string {
-	if ident == "" {
-		return ident
-	}
-
-	switch dbType {
-	case "mysql", "mariadb", "sphinx":
-		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
-	case "sqlserver":
-		escaped := strings.ReplaceAll(ident, "]", "]]")
-		return "[" + escaped + "]"
-	default:
-		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
-	}
-}
-
-func quoteQualifiedIdentByType(dbType string, ident string) string {
-	raw := strings.TrimSpace(ident)
-	if raw == "" {
-		return raw
-	}
-
-	parts := strings.Split(raw, ".")
-	if len(parts) <= 1 {
-		return quoteIdentByType(dbType, raw)
-	}
-
-	quotedParts := make([]string, 0, len(parts))
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
-		}
-		quotedParts = append(quotedParts, quoteIdentByType(dbType, part))
-	}
-
-	if len(quotedParts) == 0 {
-		return quoteIdentByType(dbType, raw)
-	}
-	return strings.Join(quotedParts, ".")
-}
-
 func normalizeSchemaAndTable(dbType string, dbName string, tableName string) (string, string) {
 	rawTable := strings.TrimSpace(tableName)
 	rawDB := strings.TrimSpace(dbName)
@@ -73,12 +35,11 @@ func normalizeSchemaAndTable(dbType string, dbName string, tableName string) (st
 		}
 	}
 
-	switch strings.ToLower(strings.TrimSpace(dbType)) {
-	case "postgres", "kingbase", "vastbase":
-		return "public", rawTable
-	default:
-		return rawDB, rawTable
+	// SQL Server 的元数据接口以数据库名定位，仅 PG 系需要补默认 schema
+	if caps := db.Capabilities(dbType); caps.Family == db.FamilyPostgres && caps.DefaultSchema != "" {
+		return caps.DefaultSchema, rawTable
 	}
+	return rawDB, rawTable
 }
 
 func qualifiedNameForQuery(dbType string, schema string, table string, original string) string {
@@ -90,17 +51,18 @@ func qualifiedNameForQuery(dbType string, schema string, table string, original
 		return raw
 	}
 
-	switch strings.ToLower(strings.TrimSpace(dbType)) {
-	case "postgres", "kingbase", "vastbase":
+	caps := db.Capabilities(dbType)
+	switch caps.Family {
+	case db.FamilyPostgres:
 		s := strings.TrimSpace(schema)
 		if s == "" {
-			s = "public"
+			s = caps.DefaultSchema
 		}
 		if table == "" {
 			return raw
 		}
 		return s + "." + table
-	case "mysql", "mariadb", "sphinx":
+	case db.FamilyMySQL:
 		s := strings.TrimSpace(schema)
 		if s == "" || table == "" {
 			return table
@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/db"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqlLiteral 把驱动返回的值渲染为目标方言的 SQL 字面量，用于拼接键范围条件等无法使用参数绑定的场景。
+func sqlLiteral(dbType string, v interface{}) string {
+	family := db.Capabilities(dbType).Family
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if family == db.FamilyPostgres {
+			return strconv.FormatBool(val)
+		}
+		if val {
+			return "1"
+		}
+		return "0"
+	case int:
+		return strconv.FormatInt(int64(val), 10)
+	case int8:
+		return strconv.FormatInt(int64(val), 10)
+	case int16:
+		return strconv.FormatInt(int64(val), 10)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case uint:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return timeLiteral(family, val)
+	case []byte:
+		return bytesLiteral(family, val)
+	case string:
+		return stringLiteral(family, val)
+	default:
+		return stringLiteral(family, fmt.Sprintf("%v", val))
+	}
+}
+
+func stringLiteral(family string, s string) string {
+	escaped := strings.ReplaceAll(s, "'", "''")
+	switch family {
+	case db.FamilyMySQL:
+		escaped = strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''")
+	case db.FamilySQLServer:
+		return "N'" + escaped + "'"
+	}
+	return "'" + escaped + "'"
+}
+
+func timeLiteral(family string, t time.Time) string {
+	switch family {
+	case db.FamilyOracle:
+		return fmt.Sprintf("TO_TIMESTAMP('%s', 'YYYY-MM-DD HH24:MI:SS.FF6')", t.Format("2006-01-02 15:04:05.000000"))
+	case db.FamilySQLServer:
+		// ISO 8601 带 T 的格式不受会话 DATEFORMAT/LANGUAGE 影响
+		return "'" + t.Format("2006-01-02T15:04:05.999") + "'"
+	default:
+		return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+	}
+}
+
+func bytesLiteral(family string, b []byte) string {
+	encoded := hex.EncodeToString(b)
+	switch family {
+	case db.FamilyPostgres:
+		return `'\x` + encoded + `'::bytea`
+	case db.FamilySQLServer:
+		if encoded == "" {
+			return "0x"
+		}
+		return "0x" + encoded
+	case db.FamilyOracle, db.FamilyDameng:
+		return "HEXTORAW('" + encoded + "')"
+	default:
+		return "X'" + encoded + "'"
+	}
+}
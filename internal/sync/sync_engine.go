@@ -4,6 +4,7 @@ import (
 	"GoNavi-Wails/internal/connection"
 	"GoNavi-Wails/internal/db"
 	"GoNavi-Wails/internal/logger"
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -18,8 +19,22 @@ type SyncConfig struct {
 	Content        string                      `json:"content,omitempty"` // "data", "schema", "both"
 	Mode           string                      `json:"mode"`              // "insert_update", "insert_only", "full_overwrite"
 	JobID          string                      `json:"jobId,omitempty"`
-	AutoAddColumns bool                        `json:"autoAddColumns,omitempty"` // 自动补齐缺失字段（当前仅 MySQL 目标支持）
+	AutoAddColumns bool                        `json:"autoAddColumns,omitempty"` // 自动补齐缺失字段，字段类型按目标方言映射
 	TableOptions   map[string]TableOptions     `json:"tableOptions,omitempty"`
+	CompareMode    string                      `json:"compareMode,omitempty"` // "row"（默认，逐行对比）、"checksum"（先比较分块校验和）
+	CompareOptions CompareOptions              `json:"compareOptions,omitempty"`
+	DryRun         bool                        `json:"dryRun,omitempty"` // 演练模式：不修改目标库，把将要执行的语句写入 ScriptPath
+	ScriptPath     string                      `json:"scriptPath,omitempty"`
+	ResumeFrom     string                      `json:"resumeFrom,omitempty"`  // 从该 JobID 保存的断点继续，跳过已完成的表
+	SyncJobID      string                      `json:"syncJobId,omitempty"`   // 所属的同步任务，增量同步按任务保存各表水位
+	FullRefresh    bool                        `json:"fullRefresh,omitempty"` // 忽略已保存的水位做一次全量对比（可检测删除），完成后更新水位
+
+	// 并发与限流：Workers 张表同时同步（各自使用独立连接，演练模式固定为 1）；
+	// BatchSize 为每批读取与写入的行数；MaxRowsPerSecond 限制所有表合计的处理速率；BatchPauseMs 为每批之后的暂停。
+	Workers          int `json:"workers,omitempty"`
+	BatchSize        int `json:"batchSize,omitempty"`
+	MaxRowsPerSecond int `json:"maxRowsPerSecond,omitempty"`
+	BatchPauseMs     int `json:"batchPauseMs,omitempty"`
 }
 
 // SyncResult holds the result of the sync operation
@@ -31,6 +46,9 @@ type SyncResult struct {
 	RowsInserted int      `json:"rowsInserted"`
 	RowsUpdated  int      `json:"rowsUpdated"`
 	RowsDeleted  int      `json:"rowsDeleted"`
+	ScriptPath   string   `json:"scriptPath,omitempty"` // 演练模式生成的脚本
+	Resumable    bool     `json:"resumable,omitempty"`  // 有未完成的表，已按本次 JobID 保存断点
+	Cancelled    bool     `json:"cancelled,omitempty"`  // 被 CancelSync 取消，统计为取消前已提交的部分
 }
 
 type SyncEngine struct {
@@ -47,6 +65,8 @@ func (s *SyncEngine) RunSync(config SyncConfig) SyncResult {
 	logger.Infof("开始数据同步：源=%s 目标=%s 表数量=%d", formatConnSummaryForSync(config.SourceConfig), formatConnSummaryForSync(config.TargetConfig), len(config.Tables))
 	totalTables := len(config.Tables)
 	s.progress(config.JobID, 0, totalTables, "", "开始同步")
+	control, release := startSyncControl(config.JobID)
+	defer release()
 
 	contentRaw := strings.ToLower(strings.TrimSpace(config.Content))
 	syncSchema := false
@@ -78,6 +98,9 @@ func (s *SyncEngine) RunSync(config SyncConfig) SyncResult {
 		contentLabel = "仅同步结构"
 	}
 	s.appendLog(config.JobID, &result, "info", fmt.Sprintf("同步内容：%s；模式：%s；自动补字段：%v", contentLabel, defaultMode, config.AutoAddColumns))
+	if desc := config.CompareOptions.describe(); desc != "" {
+		s.appendLog(config.JobID, &result, "info", "对比规则："+desc)
+	}
 
 	sourceDB, err := db.NewDatabase(config.SourceConfig.Type)
 	if err != nil {
@@ -112,382 +135,516 @@ func (s *SyncEngine) RunSync(config SyncConfig) SyncResult {
 	}
 	defer targetDB.Close()
 
-	// Iterate Tables
-	for i, tableName := range config.Tables {
-		func() {
-			tableMode := defaultMode
-			s.appendLog(config.JobID, &result, "info", fmt.Sprintf("正在同步表: %s", tableName))
-			s.progress(config.JobID, i, totalTables, tableName, fmt.Sprintf("同步表(%d/%d)", i+1, totalTables))
-			defer s.progress(config.JobID, i+1, totalTables, tableName, "表处理完成")
-
-			if syncSchema {
-				s.progress(config.JobID, i, totalTables, tableName, "同步表结构")
-				if err := s.syncTableSchema(config, &result, sourceDB, targetDB, tableName); err != nil {
-					s.appendLog(config.JobID, &result, "error", fmt.Sprintf("表结构同步失败：表=%s 错误=%v", tableName, err))
-					return
-				}
+	var script *syncScriptWriter
+	if config.DryRun {
+		if strings.TrimSpace(config.ScriptPath) == "" {
+			return s.fail(config.JobID, totalTables, result, "演练模式未指定脚本文件")
+		}
+		script, err = newSyncScriptWriter(config.ScriptPath, config, targetDB)
+		if err != nil {
+			return s.fail(config.JobID, totalTables, result, "创建演练脚本失败: "+err.Error())
+		}
+		// 读取照常访问目标库，写操作全部改为写入脚本
+		targetDB = &dryRunDatabase{Database: targetDB, script: script}
+		s.appendLog(config.JobID, &result, "info", fmt.Sprintf("演练模式：不修改目标库，将执行的语句写入 %s", config.ScriptPath))
+	}
+
+	// 断点：每张表完成、每个键范围提交后落盘，失败或中断后可从断点继续
+	var cp *checkpointer
+	if !config.DryRun && strings.TrimSpace(config.JobID) != "" {
+		opened, resumed, err := openCheckpointer(config)
+		switch {
+		case err != nil && strings.TrimSpace(config.ResumeFrom) != "":
+			return s.fail(config.JobID, totalTables, result, "无法从断点继续: "+err.Error())
+		case err != nil:
+			s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("无法记录同步断点，任务中断后需重新开始: %v", err))
+		default:
+			cp = opened
+			if resumed {
+				s.appendLog(config.JobID, &result, "info", fmt.Sprintf("从任务 %s 的断点继续：已完成 %d 张表", config.ResumeFrom, len(cp.cp.Completed)))
 			}
-			if !syncData {
-				result.TablesSynced++
-				return
+		}
+	}
+
+	// Iterate Tables
+	run := newSyncRun(s, control, config, &result, cp, script, syncSchema, syncData, defaultMode)
+	s.runTables(run, sourceDB, targetDB)
+
+	if control.cancelled() {
+		result.Success = false
+		result.Cancelled = true
+		result.Message = fmt.Sprintf("同步已取消：已完成 %d/%d 张表，插入 %d 行，更新 %d 行，删除 %d 行", result.TablesSynced, totalTables, result.RowsInserted, result.RowsUpdated, result.RowsDeleted)
+		s.appendLog(config.JobID, &result, "warn", result.Message)
+	}
+
+	if cp.finish(config.Tables) {
+		result.Resumable = true
+		s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("部分表未完成，已保存断点，可从本任务（%s）继续同步", config.JobID))
+	}
+
+	if script != nil {
+		if err := script.close(); err != nil {
+			return s.fail(config.JobID, totalTables, result, "写入演练脚本失败: "+err.Error())
+		}
+		result.ScriptPath = config.ScriptPath
+		if !result.Cancelled {
+			result.Message = fmt.Sprintf("演练完成：共生成 %d 条语句，脚本已写入 %s", script.statements, config.ScriptPath)
+			s.appendLog(config.JobID, &result, "info", result.Message)
+		}
+	}
+
+	if result.Cancelled {
+		s.progress(config.JobID, result.TablesSynced, totalTables, "", "已取消")
+		return result
+	}
+	s.progress(config.JobID, totalTables, totalTables, "", "同步完成")
+	return result
+}
+
+// syncTable 同步单张表，日志与行数计入 res，由调用方汇总到任务结果。
+func (s *SyncEngine) syncTable(run *syncRun, res *SyncResult, sourceDB db.Database, targetDB db.Database, i int, tableName string) {
+	config, cp, script := run.config, run.cp, run.script
+	tableMode := run.defaultMode
+	if script != nil {
+		script.beginTable(tableName)
+	}
+	if cp.completed(tableName) {
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表 %s 已在上次运行中完成，跳过", tableName))
+		res.TablesSynced++
+		return
+	}
+	s.appendLog(config.JobID, res, "info", fmt.Sprintf("正在同步表: %s", tableName))
+	run.progress(tableName, fmt.Sprintf("同步表(%d/%d)", i+1, run.totalTables))
+
+	mapping, err := newTableMapping(tableName, config.TableOptions[tableName])
+	if err != nil {
+		s.appendLog(config.JobID, res, "error", fmt.Sprintf("表 %s 的映射配置无效，已跳过: %v", tableName, err))
+		return
+	}
+	if desc := mapping.describe(tableName); desc != "" {
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表 %s 映射：%s", tableName, desc))
+	}
+
+	if run.syncSchema {
+		run.progress(tableName, "同步表结构")
+		if err := s.syncTableSchema(config, res, sourceDB, targetDB, tableName, mapping); err != nil {
+			s.appendLog(config.JobID, res, "error", fmt.Sprintf("表结构同步失败：表=%s 错误=%v", tableName, err))
+			return
+		}
+	}
+	if !run.syncData {
+		res.TablesSynced++
+		cp.complete(tableName)
+		return
+	}
+
+	sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
+	targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, mapping.targetTable)
+	sourceQueryTable := qualifiedNameForQuery(config.SourceConfig.Type, sourceSchema, sourceTable, tableName)
+	targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, mapping.targetTable)
+
+	// 1. Get Columns & PKs
+	cols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
+	if err != nil {
+		logger.Error(err, "获取源表列信息失败：表=%s", tableName)
+		s.appendLog(config.JobID, res, "error", fmt.Sprintf("获取表 %s 的列信息失败: %v", tableName, err))
+		return
+	}
+	sourceColsByLower := make(map[string]connection.ColumnDefinition, len(cols))
+	for _, col := range mapping.targetColumns(cols) {
+		if strings.TrimSpace(col.Name) == "" {
+			continue
+		}
+		sourceColsByLower[strings.ToLower(strings.TrimSpace(col.Name))] = col
+	}
+
+	sourceKey, _, err := resolveMatchKey(sourceDB, sourceSchema, sourceTable, cols, config.TableOptions[tableName].MatchIndex)
+	if err != nil {
+		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("表 %s %v，已跳过数据同步（避免产生重复数据）", tableName, err))
+		return
+	}
+	key, err := mapping.mapKey(sourceKey)
+	if err != nil {
+		s.appendLog(config.JobID, res, "error", fmt.Sprintf("表 %s 的映射配置无效，已跳过: %v", tableName, err))
+		return
+	}
+	if key.index != "" {
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表 %s 使用%s匹配数据行", tableName, key.describe()))
+	}
+
+	opts := TableOptions{Insert: true, Update: true, Delete: false}
+	if config.TableOptions != nil {
+		if t, ok := config.TableOptions[tableName]; ok {
+			opts = t
+			// 默认防护：如用户未设置任意一个字段，保持 insert/update 默认 true、delete 默认 false
+			if !t.Insert && !t.Update && !t.Delete {
+				opts = t
 			}
+		}
+	}
+	if !opts.Insert && !opts.Update && !opts.Delete {
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("表 %s 未勾选任何操作，已跳过", tableName))
+		return
+	}
+
+	target := tableSyncTarget{
+		tableName:         tableName,
+		targetDB:          targetDB,
+		targetSchema:      targetSchema,
+		targetTable:       targetTable,
+		targetQueryTable:  targetQueryTable,
+		sourceColsByLower: sourceColsByLower,
+		script:            script,
+	}
+	stats := tableSyncStats{}
+
+	if script != nil && tableMode == "insert_update" {
+		// 演练时结构变更只写入脚本，目标表仍不存在，按空表处理：源表全部行都是插入
+		if targetCols, err := targetDB.GetColumns(targetSchema, targetTable); err != nil || len(targetCols) == 0 {
+			s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 目标表 %s 尚不存在，演练时按空表处理", mapping.targetTable))
+			tableMode = "insert_only"
+		}
+	}
+
+	// 过滤条件与增量水位；全量覆盖模式总是读取全部满足过滤条件的行
+	source := mapping.sourceSide(sourceDB, db.ResolveDialect(config.SourceConfig), sourceQueryTable, sourceKey)
+	filter, err := resolveTableFilter(run.ctx, config, source, tableName, opts, tableMode != "full_overwrite")
+	if err != nil {
+		s.appendLog(config.JobID, res, "error", fmt.Sprintf("  -> 表 %s 的过滤条件无效: %v", tableName, err))
+		return
+	}
+	if desc := filter.describe(); desc != "" {
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 表 %s %s", tableName, desc))
+	}
+	source, targetSide := filter.sides(source, tableSide{inst: targetDB, dialect: db.ResolveDialect(config.TargetConfig), queryTable: targetQueryTable}, mapping)
+	source.columnTypes = mapping.columnTypes(cols)
+	targetSide.columnTypes = loadColumnTypes(targetDB, targetSchema, targetTable)
+	if source.lookupTarget && opts.Delete && tableMode == "insert_update" {
+		s.appendLog(config.JobID, res, "warn", fmt.Sprintf("  -> 表 %s 只读取了部分源表行，本次不检测删除（可勾选全量对比检测删除）", tableName))
+	}
 
-			sourceSchema, sourceTable := normalizeSchemaAndTable(config.SourceConfig.Type, config.SourceConfig.Database, tableName)
-			targetSchema, targetTable := normalizeSchemaAndTable(config.TargetConfig.Type, config.TargetConfig.Database, tableName)
-			sourceQueryTable := qualifiedNameForQuery(config.SourceConfig.Type, sourceSchema, sourceTable, tableName)
-			targetQueryTable := qualifiedNameForQuery(config.TargetConfig.Type, targetSchema, targetTable, tableName)
-
-			// 1. Get Columns & PKs
-			cols, err := sourceDB.GetColumns(sourceSchema, sourceTable)
-			if err != nil {
-				logger.Error(err, "获取源表列信息失败：表=%s", tableName)
-				s.appendLog(config.JobID, &result, "error", fmt.Sprintf("获取表 %s 的列信息失败: %v", tableName, err))
-				return
+	if tableMode == "insert_update" {
+		// 2. Compare chunk by chunk in key order and apply each chunk's changes right away,
+		// so memory stays bounded by the chunk size rather than the table size.
+		var tableRows int64
+		resume, start := cp.resumePoint(tableName, key)
+		if start != nil {
+			tableRows = resume.Rows
+			run.addRows(resume.Rows)
+			s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 从断点继续：已处理 %d 行，从键 %s 之后开始", resume.Rows, formatKeyValues(start)))
+		}
+		run.progress(tableName, "分块对比数据")
+		compareStats, err := diffTable(run.ctx, config, source, targetSide, key, cols, start, func(diff chunkDiff) error {
+			if err := run.safePoint(tableName); err != nil {
+				return err
 			}
-			sourceColsByLower := make(map[string]connection.ColumnDefinition, len(cols))
-			for _, col := range cols {
-				if strings.TrimSpace(col.Name) == "" {
-					continue
+			updates := make([]connection.UpdateRow, 0, len(diff.updates))
+			for _, change := range diff.updates {
+				values := make(map[string]interface{}, len(change.changed))
+				for _, col := range change.changed {
+					values[col] = change.source[col]
 				}
-				sourceColsByLower[strings.ToLower(strings.TrimSpace(col.Name))] = col
+				updates = append(updates, connection.UpdateRow{
+					Keys:   key.keyValues(change.source),
+					Values: values,
+				})
 			}
-
-			pkCols := make([]string, 0, 2)
-			for _, col := range cols {
-				if col.Key == "PRI" || col.Key == "PK" {
-					pkCols = append(pkCols, col.Name)
+			var deletes []map[string]interface{}
+			if opts.Delete {
+				deletes = make([]map[string]interface{}, 0, len(diff.deletes))
+				for _, row := range diff.deletes {
+					deletes = append(deletes, key.keyValues(row))
 				}
 			}
 
-			if len(pkCols) == 0 {
-				s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("表 %s 未找到主键，已跳过数据同步（避免产生重复数据）", tableName))
-				return
+			// apply operation selection
+			changeSet := connection.ChangeSet{
+				Inserts: filterRowsByPKSelection(key, diff.inserts, opts.Insert, opts.SelectedInsertPKs),
+				Updates: filterUpdatesByPKSelection(key, updates, opts.Update, opts.SelectedUpdatePKs),
+				Deletes: filterRowsByPKSelection(key, deletes, opts.Delete, opts.SelectedDeletePKs),
 			}
-			if len(pkCols) > 1 {
-				s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("表 %s 为复合主键（%s），当前暂不支持数据同步", tableName, strings.Join(pkCols, ",")))
-				return
+			if err := s.applyTableBatch(run.ctx, config, res, &target, &stats, changeSet); err != nil {
+				return err
 			}
-			pkCol := pkCols[0]
-
-			opts := TableOptions{Insert: true, Update: true, Delete: false}
-			if config.TableOptions != nil {
-				if t, ok := config.TableOptions[tableName]; ok {
-					opts = t
-					// 默认防护：如用户未设置任意一个字段，保持 insert/update 默认 true、delete 默认 false
-					if !t.Insert && !t.Update && !t.Delete {
-						opts = t
-					}
-				}
+			if diff.sourceRows > 0 {
+				tableRows += int64(diff.sourceRows)
+				run.addRows(int64(diff.sourceRows))
+				run.rowProgress(tableName, fmt.Sprintf("对比并应用变更（已处理 %d 行）", tableRows), tableRows)
 			}
-			if !opts.Insert && !opts.Update && !opts.Delete {
-				s.appendLog(config.JobID, &result, "info", fmt.Sprintf("表 %s 未勾选任何操作，已跳过", tableName))
-				return
+			if diff.rangeDone && diff.rangeEnd != nil {
+				cp.advance(tableName, key, diff.rangeEnd, tableRows)
 			}
+			return run.throttle(diff.sourceRows)
+		})
+		if err != nil && run.control.cancelled() {
+			s.tableCancelled(run, res, tableName, stats)
+			return
+		}
+		if err != nil {
+			logger.Error(err, "同步表数据失败：表=%s", tableName)
+			s.appendLog(config.JobID, res, "error", fmt.Sprintf("  -> 同步表 %s 失败: %v", tableName, err))
+			return
+		}
+		if compareStats.fallback != nil {
+			s.appendLog(config.JobID, res, "warn", fmt.Sprintf("  -> 表 %s 校验和对比不可用，已改为逐行对比: %v", tableName, compareStats.fallback))
+		}
+		if compareStats.checksum {
+			s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 表 %s 共 %d 个分块，校验和一致跳过 %d 个", tableName, compareStats.chunks, compareStats.skipped))
+		}
+		if compareStats.unordered {
+			s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 表 %s 两端方言族不同且匹配键不是数值列，排序可能不一致，已改为按键查找对比", tableName))
+		}
 
-			// 2. Fetch Data (MEMORY INTENSIVE - PROTOTYPE ONLY)
-			// TODO: Implement paging/streaming
-			s.progress(config.JobID, i, totalTables, tableName, "读取源表数据")
-			sourceRows, _, err := sourceDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.SourceConfig.Type, sourceQueryTable)))
-			if err != nil {
-				logger.Error(err, "读取源表失败：表=%s", tableName)
-				s.appendLog(config.JobID, &result, "error", fmt.Sprintf("读取源表 %s 失败: %v", tableName, err))
-				return
-			}
-
-			var inserts []map[string]interface{}
-			var updates []connection.UpdateRow
-
-			if tableMode == "insert_update" {
-				s.progress(config.JobID, i, totalTables, tableName, "读取目标表数据")
-				targetRows, _, err := targetDB.Query(fmt.Sprintf("SELECT * FROM %s", quoteQualifiedIdentByType(config.TargetConfig.Type, targetQueryTable)))
-				if err != nil {
-					logger.Error(err, "读取目标表失败：表=%s", tableName)
-					s.appendLog(config.JobID, &result, "error", fmt.Sprintf("读取目标表 %s 失败: %v", tableName, err))
-					return
-				}
-
-				// 3. Compare (In-Memory Hash Map)
-				s.progress(config.JobID, i, totalTables, tableName, "对比差异")
-				targetMap := make(map[string]map[string]interface{})
-				for _, row := range targetRows {
-					if row[pkCol] == nil {
-						continue
-					}
-					pkVal := fmt.Sprintf("%v", row[pkCol])
-					if strings.TrimSpace(pkVal) == "" || pkVal == "<nil>" {
-						continue
-					}
-					targetMap[pkVal] = row
-				}
-				sourcePKSet := make(map[string]struct{}, len(sourceRows))
+		s.logTableStats(config, res, stats)
+		s.commitWatermark(config, res, tableName, filter)
+		res.TablesSynced++
+		cp.complete(tableName)
+		return
+	}
 
-				for _, sRow := range sourceRows {
-					if sRow[pkCol] == nil {
-						continue
-					}
-					pkVal := fmt.Sprintf("%v", sRow[pkCol])
-					if strings.TrimSpace(pkVal) == "" || pkVal == "<nil>" {
-						continue
-					}
-					sourcePKSet[pkVal] = struct{}{}
-
-					if tRow, exists := targetMap[pkVal]; exists {
-						changes := make(map[string]interface{})
-						for k, v := range sRow {
-							if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", tRow[k]) {
-								changes[k] = v
-							}
-						}
-						if len(changes) > 0 {
-							updates = append(updates, connection.UpdateRow{
-								Keys:   map[string]interface{}{pkCol: sRow[pkCol]},
-								Values: changes,
-							})
-						}
-					} else {
-						inserts = append(inserts, sRow)
-					}
-				}
+	// insert_only / full_overwrite: do not compare target, just insert source rows.
+	// With a primary key the source is read in key order page by page so each page can be
+	// checkpointed; a unique-index key may be NULL, so that table is streamed and restarts on resume.
+	keyset := key.index == ""
+	var resume TableCheckpoint
+	var start []interface{}
+	if keyset {
+		resume, start = cp.resumePoint(tableName, key)
+	}
+	cleared := tableMode != "full_overwrite" || resume.Cleared
+	run.progress(tableName, "读取源表数据")
+	var tableRows int64
+	if start != nil {
+		tableRows = resume.Rows
+		run.addRows(resume.Rows)
+		s.appendLog(config.JobID, res, "info", fmt.Sprintf("  -> 从断点继续：已写入 %d 行，从键 %s 之后开始", resume.Rows, formatKeyValues(start)))
+	}
+	insertRows := func(rows []map[string]interface{}) error {
+		if err := run.safePoint(tableName); err != nil {
+			return err
+		}
+		// full_overwrite: clear target table once the source is readable
+		if !cleared {
+			s.appendLog(config.JobID, res, "warn", fmt.Sprintf("  -> 全量覆盖模式：即将清空目标表 %s", mapping.targetTable))
+			run.progress(tableName, "清空目标表")
+			clearSQL := ""
+			if targetSide.filter != "" {
+				// 只清空与源表过滤条件对应的行
+				clearSQL = fmt.Sprintf("DELETE FROM %s WHERE %s", db.QuoteQualifiedIdent(config.TargetConfig.Type, targetQueryTable), targetSide.filter)
+			} else if db.Capabilities(targetSide.dialect).SupportsTruncate {
+				clearSQL = fmt.Sprintf("TRUNCATE TABLE %s", db.QuoteQualifiedIdent(config.TargetConfig.Type, targetQueryTable))
+			} else {
+				clearSQL = fmt.Sprintf("DELETE FROM %s", db.QuoteQualifiedIdent(config.TargetConfig.Type, targetQueryTable))
+			}
+			if _, err := targetDB.Exec(clearSQL); err != nil {
+				return fmt.Errorf("清空目标表失败: %w", err)
+			}
+			cleared = true
+			if keyset {
+				cp.markCleared(tableName, key)
+			}
+		}
 
-				var deletes []map[string]interface{}
-				if opts.Delete {
-					for pkStr, row := range targetMap {
-						if _, ok := sourcePKSet[pkStr]; ok {
-							continue
-						}
-						deletes = append(deletes, map[string]interface{}{pkCol: row[pkCol]})
+		if err := s.applyTableBatch(run.ctx, config, res, &target, &stats, connection.ChangeSet{Inserts: rows}); err != nil {
+			return err
+		}
+		tableRows += int64(len(rows))
+		run.addRows(int64(len(rows)))
+		run.rowProgress(tableName, fmt.Sprintf("写入目标表（已处理 %d 行）", tableRows), tableRows)
+		return nil
+	}
+	if keyset {
+		err = streamKeysetPages(run.ctx, source, key, start, run.batchSize, func(rows []map[string]interface{}, last []interface{}) error {
+			if err := insertRows(rows); err != nil {
+				return err
+			}
+			cp.advance(tableName, key, last, tableRows)
+			return run.throttle(len(rows))
+		})
+	} else {
+		err = streamTableRows(run.ctx, sourceDB, config.SourceConfig.Type, sourceQueryTable, source.filter, run.batchSize, func(batch db.RowBatch) error {
+			rows := batch.Rows
+			if !mapping.identity() {
+				rows = make([]map[string]interface{}, len(batch.Rows))
+				for j, row := range batch.Rows {
+					mapped, err := mapping.apply(row)
+					if err != nil {
+						return err
 					}
+					rows[j] = mapped
 				}
+			}
+			if err := insertRows(rows); err != nil {
+				return err
+			}
+			return run.throttle(len(rows))
+		})
+	}
+	if err != nil && run.control.cancelled() {
+		s.tableCancelled(run, res, tableName, stats)
+		return
+	}
+	if err != nil {
+		logger.Error(err, "同步表数据失败：表=%s", tableName)
+		s.appendLog(config.JobID, res, "error", fmt.Sprintf("  -> 同步表 %s 失败: %v", tableName, err))
+		return
+	}
 
-				// apply operation selection
-				inserts = filterRowsByPKSelection(pkCol, inserts, opts.Insert, opts.SelectedInsertPKs)
-				updates = filterUpdatesByPKSelection(pkCol, updates, opts.Update, opts.SelectedUpdatePKs)
-				deletes = filterRowsByPKSelection(pkCol, deletes, opts.Delete, opts.SelectedDeletePKs)
+	s.logTableStats(config, res, stats)
+	s.commitWatermark(config, res, tableName, filter)
+	res.TablesSynced++
+	cp.complete(tableName)
+}
 
-				changeSet := connection.ChangeSet{
-					Inserts: inserts,
-					Updates: updates,
-					Deletes: deletes,
-				}
+// tableSyncTarget 描述单表同步时目标端的信息，以及字段一致性检查的结果。
+type tableSyncTarget struct {
+	tableName         string
+	targetDB          db.Database
+	targetSchema      string
+	targetTable       string
+	targetQueryTable  string
+	sourceColsByLower map[string]connection.ColumnDefinition
+	script            *syncScriptWriter // 演练模式下写入脚本而不是应用变更
+
+	aligned       bool
+	allowedColumn map[string]struct{} // nil 表示无需过滤字段
+}
 
-				// 4. Align schema (target missing columns)
-				s.progress(config.JobID, i, totalTables, tableName, "检查字段一致性")
-				requiredCols := collectRequiredColumns(changeSet.Inserts, changeSet.Updates)
-				targetCols, err := targetDB.GetColumns(targetSchema, targetTable)
-				if err != nil {
-					s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 获取目标表字段失败，已跳过字段一致性检查: %v", err))
-				} else {
-					targetColSet := make(map[string]struct{}, len(targetCols))
-					for _, c := range targetCols {
-						name := strings.ToLower(strings.TrimSpace(c.Name))
-						if name == "" {
-							continue
-						}
-						targetColSet[name] = struct{}{}
-					}
+type tableSyncStats struct {
+	inserted int
+	updated  int
+	deleted  int
+}
 
-					missing := make([]string, 0)
-					for lower, original := range requiredCols {
-						if _, ok := targetColSet[lower]; !ok {
-							missing = append(missing, original)
-						}
-					}
-					sort.Strings(missing)
-
-					if len(missing) > 0 {
-						if config.AutoAddColumns && strings.ToLower(strings.TrimSpace(config.TargetConfig.Type)) == "mysql" {
-							s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个，开始自动补齐: %s", len(missing), strings.Join(missing, ", ")))
-							added := 0
-							for _, colName := range missing {
-								colLower := strings.ToLower(strings.TrimSpace(colName))
-								colType := "TEXT"
-								if strings.ToLower(strings.TrimSpace(config.SourceConfig.Type)) == "mysql" {
-									if srcCol, ok := sourceColsByLower[colLower]; ok {
-										colType = sanitizeMySQLColumnType(srcCol.Type)
-									}
-								}
-
-								alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL",
-									quoteQualifiedIdentByType("mysql", targetQueryTable),
-									quoteIdentByType("mysql", colName),
-									colType,
-								)
-								if _, err := targetDB.Exec(alterSQL); err != nil {
-									s.appendLog(config.JobID, &result, "error", fmt.Sprintf("  -> 自动补字段失败：字段=%s 错误=%v", colName, err))
-									continue
-								}
-								added++
-							}
-							s.appendLog(config.JobID, &result, "info", fmt.Sprintf("  -> 自动补字段完成：成功=%d 失败=%d", added, len(missing)-added))
-
-							// refresh columns
-							targetCols, err = targetDB.GetColumns(targetSchema, targetTable)
-							if err == nil {
-								targetColSet = make(map[string]struct{}, len(targetCols))
-								for _, c := range targetCols {
-									name := strings.ToLower(strings.TrimSpace(c.Name))
-									if name == "" {
-										continue
-									}
-									targetColSet[name] = struct{}{}
-								}
-							}
-						} else {
-							s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个（未开启自动补齐），将自动忽略：%s", len(missing), strings.Join(missing, ", ")))
-						}
-
-						// filter out still-missing columns to avoid apply failure
-						changeSet.Inserts = filterInsertRows(changeSet.Inserts, targetColSet)
-						changeSet.Updates = filterUpdateRows(changeSet.Updates, targetColSet)
-					}
-				}
+// applyTableBatch 对一批变更做字段一致性检查后写入目标库。整批在一个事务中应用，
+// ctx 取消时正在应用的批次回滚，不计入统计与断点。
+func (s *SyncEngine) applyTableBatch(ctx context.Context, config SyncConfig, result *SyncResult, target *tableSyncTarget, stats *tableSyncStats, changeSet connection.ChangeSet) error {
+	if len(changeSet.Inserts) == 0 && len(changeSet.Updates) == 0 && len(changeSet.Deletes) == 0 {
+		return nil
+	}
 
-				// 5. Apply Changes
-				s.progress(config.JobID, i, totalTables, tableName, "应用变更")
-
-				if len(changeSet.Inserts) > 0 || len(changeSet.Updates) > 0 || len(changeSet.Deletes) > 0 {
-					s.appendLog(config.JobID, &result, "info", fmt.Sprintf("  -> 需插入: %d 行, 需更新: %d 行, 需删除: %d 行", len(changeSet.Inserts), len(changeSet.Updates), len(changeSet.Deletes)))
-
-					if applier, ok := targetDB.(db.BatchApplier); ok {
-						if err := applier.ApplyChanges(targetTable, changeSet); err != nil {
-							s.appendLog(config.JobID, &result, "error", fmt.Sprintf("  -> 应用变更失败: %v", err))
-						} else {
-							result.RowsInserted += len(changeSet.Inserts)
-							result.RowsUpdated += len(changeSet.Updates)
-							result.RowsDeleted += len(changeSet.Deletes)
-						}
-					} else {
-						s.appendLog(config.JobID, &result, "warn", "  -> 目标驱动不支持应用数据变更 (ApplyChanges).")
-					}
-				} else {
-					s.appendLog(config.JobID, &result, "info", "  -> 数据一致，无需变更.")
-				}
+	applier, ok := target.targetDB.(db.BatchApplier)
+	if !ok && target.script == nil {
+		return fmt.Errorf("目标驱动不支持应用数据变更 (ApplyChanges)")
+	}
 
-				result.TablesSynced++
-				return
-			} else {
-				// insert_only / full_overwrite: do not compare target, just insert source rows
-				inserts = sourceRows
-			}
+	if !target.aligned && (len(changeSet.Inserts) > 0 || len(changeSet.Updates) > 0) {
+		target.allowedColumn = s.alignTargetColumns(config, result, target)
+		target.aligned = true
+	}
+	if target.allowedColumn != nil {
+		// filter out still-missing columns to avoid apply failure
+		changeSet.Inserts = filterInsertRows(changeSet.Inserts, target.allowedColumn)
+		changeSet.Updates = filterUpdateRows(changeSet.Updates, target.allowedColumn)
+	}
 
-			// full_overwrite: clear target table first
-			if tableMode == "full_overwrite" {
-				s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 全量覆盖模式：即将清空目标表 %s", tableName))
-				s.progress(config.JobID, i, totalTables, tableName, "清空目标表")
-				clearSQL := ""
-				if strings.ToLower(strings.TrimSpace(config.TargetConfig.Type)) == "mysql" {
-					clearSQL = fmt.Sprintf("TRUNCATE TABLE %s", quoteQualifiedIdentByType(config.TargetConfig.Type, targetQueryTable))
-				} else {
-					clearSQL = fmt.Sprintf("DELETE FROM %s", quoteQualifiedIdentByType(config.TargetConfig.Type, targetQueryTable))
-				}
-				if _, err := targetDB.Exec(clearSQL); err != nil {
-					s.appendLog(config.JobID, &result, "error", fmt.Sprintf("  -> 清空目标表失败: %v", err))
-					return
-				}
-			}
+	if target.script != nil {
+		if err := target.script.writeChanges(target.targetQueryTable, changeSet); err != nil {
+			return fmt.Errorf("写入演练脚本失败: %w", err)
+		}
+	} else if err := db.ApplyChanges(ctx, applier, target.targetTable, changeSet); err != nil {
+		return fmt.Errorf("应用变更失败: %w", err)
+	}
+	stats.inserted += len(changeSet.Inserts)
+	stats.updated += len(changeSet.Updates)
+	stats.deleted += len(changeSet.Deletes)
+	result.RowsInserted += len(changeSet.Inserts)
+	result.RowsUpdated += len(changeSet.Updates)
+	result.RowsDeleted += len(changeSet.Deletes)
+	return nil
+}
 
-			// 4. Align schema (target missing columns)
-			s.progress(config.JobID, i, totalTables, tableName, "检查字段一致性")
-			requiredCols := collectRequiredColumns(inserts, updates)
-			targetCols, err := targetDB.GetColumns(targetSchema, targetTable)
-			if err != nil {
-				s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 获取目标表字段失败，已跳过字段一致性检查: %v", err))
-			} else {
-				targetColSet := make(map[string]struct{}, len(targetCols))
-				for _, c := range targetCols {
-					name := strings.ToLower(strings.TrimSpace(c.Name))
-					if name == "" {
-						continue
-					}
-					targetColSet[name] = struct{}{}
-				}
+// alignTargetColumns 检查目标表是否缺少源表字段，必要时自动补齐。
+// 返回目标表现有字段集合（小写），若无需过滤字段则返回 nil。
+func (s *SyncEngine) alignTargetColumns(config SyncConfig, result *SyncResult, target *tableSyncTarget) map[string]struct{} {
+	targetCols, err := target.targetDB.GetColumns(target.targetSchema, target.targetTable)
+	if err != nil {
+		s.appendLog(config.JobID, result, "warn", fmt.Sprintf("  -> 获取目标表字段失败，已跳过字段一致性检查: %v", err))
+		return nil
+	}
+	targetColSet := buildColumnNameSet(targetCols)
 
-				missing := make([]string, 0)
-				for lower, original := range requiredCols {
-					if _, ok := targetColSet[lower]; !ok {
-						missing = append(missing, original)
-					}
-				}
-				sort.Strings(missing)
-
-				if len(missing) > 0 {
-					if config.AutoAddColumns && strings.ToLower(strings.TrimSpace(config.TargetConfig.Type)) == "mysql" {
-						s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个，开始自动补齐: %s", len(missing), strings.Join(missing, ", ")))
-						added := 0
-						for _, colName := range missing {
-							colLower := strings.ToLower(strings.TrimSpace(colName))
-							colType := "TEXT"
-							if strings.ToLower(strings.TrimSpace(config.SourceConfig.Type)) == "mysql" {
-								if srcCol, ok := sourceColsByLower[colLower]; ok {
-									colType = sanitizeMySQLColumnType(srcCol.Type)
-								}
-							}
-
-							alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL",
-								quoteQualifiedIdentByType("mysql", targetQueryTable),
-								quoteIdentByType("mysql", colName),
-								colType,
-							)
-							if _, err := targetDB.Exec(alterSQL); err != nil {
-								s.appendLog(config.JobID, &result, "error", fmt.Sprintf("  -> 自动补字段失败：字段=%s 错误=%v", colName, err))
-								continue
-							}
-							added++
-						}
-						s.appendLog(config.JobID, &result, "info", fmt.Sprintf("  -> 自动补字段完成：成功=%d 失败=%d", added, len(missing)-added))
-
-						// refresh columns
-						targetCols, err = targetDB.GetColumns(targetSchema, targetTable)
-						if err == nil {
-							targetColSet = make(map[string]struct{}, len(targetCols))
-							for _, c := range targetCols {
-								name := strings.ToLower(strings.TrimSpace(c.Name))
-								if name == "" {
-									continue
-								}
-								targetColSet[name] = struct{}{}
-							}
-						}
-					} else {
-						s.appendLog(config.JobID, &result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个（未开启自动补齐），将自动忽略：%s", len(missing), strings.Join(missing, ", ")))
-					}
+	missing := make([]string, 0)
+	for lower, col := range target.sourceColsByLower {
+		if _, ok := targetColSet[lower]; !ok {
+			missing = append(missing, strings.TrimSpace(col.Name))
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) == 0 {
+		return nil
+	}
 
-					// filter out still-missing columns to avoid apply failure
-					inserts = filterInsertRows(inserts, targetColSet)
-					updates = filterUpdateRows(updates, targetColSet)
-				}
+	if config.AutoAddColumns && schemaSyncSupported(db.ResolveDialect(config.TargetConfig)) {
+		s.appendLog(config.JobID, result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个，开始自动补齐: %s", len(missing), strings.Join(missing, ", ")))
+		added := make([]string, 0, len(missing))
+		for _, colName := range missing {
+			srcCol := target.sourceColsByLower[strings.ToLower(strings.TrimSpace(colName))]
+			alterSQL, _ := addColumnSQL(db.ResolveDialect(config.SourceConfig), db.ResolveDialect(config.TargetConfig), target.targetQueryTable, srcCol)
+			if _, err := target.targetDB.Exec(alterSQL); err != nil {
+				s.appendLog(config.JobID, result, "error", fmt.Sprintf("  -> 自动补字段失败：字段=%s 错误=%v", colName, err))
+				continue
 			}
+			added = append(added, colName)
+		}
+		s.appendLog(config.JobID, result, "info", fmt.Sprintf("  -> 自动补字段完成：成功=%d 失败=%d", len(added), len(missing)-len(added)))
 
-			// 5. Apply Changes
-			s.progress(config.JobID, i, totalTables, tableName, "应用变更")
-			changeSet := connection.ChangeSet{
-				Inserts: inserts,
-				Updates: updates,
-			}
+		// refresh columns
+		if refreshed, err := target.targetDB.GetColumns(target.targetSchema, target.targetTable); err == nil {
+			targetColSet = buildColumnNameSet(refreshed)
+		}
+		// 演练模式下补字段语句只写入了脚本，目标表中还没有这些字段
+		for _, colName := range added {
+			targetColSet[strings.ToLower(strings.TrimSpace(colName))] = struct{}{}
+		}
+	} else {
+		s.appendLog(config.JobID, result, "warn", fmt.Sprintf("  -> 目标表缺少字段 %d 个（未开启自动补齐），将自动忽略：%s", len(missing), strings.Join(missing, ", ")))
+	}
+
+	return targetColSet
+}
 
-			if len(changeSet.Inserts) > 0 || len(changeSet.Updates) > 0 {
-				s.appendLog(config.JobID, &result, "info", fmt.Sprintf("  -> 需插入: %d 行, 需更新: %d 行", len(changeSet.Inserts), len(changeSet.Updates)))
+func buildColumnNameSet(cols []connection.ColumnDefinition) map[string]struct{} {
+	set := make(map[string]struct{}, len(cols))
+	for _, c := range cols {
+		name := strings.ToLower(strings.TrimSpace(c.Name))
+		if name == "" {
+			continue
+		}
+		set[name] = struct{}{}
+	}
+	return set
+}
 
-				if applier, ok := targetDB.(db.BatchApplier); ok {
-					if err := applier.ApplyChanges(targetTable, changeSet); err != nil {
-						s.appendLog(config.JobID, &result, "error", fmt.Sprintf("  -> 应用变更失败: %v", err))
-					} else {
-						result.RowsInserted += len(changeSet.Inserts)
-						result.RowsUpdated += len(changeSet.Updates)
-					}
-				} else {
-					s.appendLog(config.JobID, &result, "warn", "  -> 目标驱动不支持应用数据变更 (ApplyChanges).")
-				}
-			} else {
-				s.appendLog(config.JobID, &result, "info", "  -> 数据一致，无需变更.")
-			}
+func (s *SyncEngine) logTableStats(config SyncConfig, result *SyncResult, stats tableSyncStats) {
+	if stats.inserted == 0 && stats.updated == 0 && stats.deleted == 0 {
+		s.appendLog(config.JobID, result, "info", "  -> 数据一致，无需变更.")
+		return
+	}
+	if config.DryRun {
+		s.appendLog(config.JobID, result, "info", fmt.Sprintf("  -> [演练] 将插入: %d 行, 将更新: %d 行, 将删除: %d 行", stats.inserted, stats.updated, stats.deleted))
+		return
+	}
+	s.appendLog(config.JobID, result, "info", fmt.Sprintf("  -> 已插入: %d 行, 已更新: %d 行, 已删除: %d 行", stats.inserted, stats.updated, stats.deleted))
+}
 
-			result.TablesSynced++
-		}()
+// tableCancelled 记录表在取消前已提交的部分。
+func (s *SyncEngine) tableCancelled(run *syncRun, res *SyncResult, tableName string, stats tableSyncStats) {
+	s.appendLog(run.config.JobID, res, "warn", fmt.Sprintf("  -> 表 %s 已取消：已写入的批次保留，尚未写入的批次已放弃", tableName))
+	if stats.inserted > 0 || stats.updated > 0 || stats.deleted > 0 {
+		s.logTableStats(run.config, res, stats)
 	}
+}
 
-	s.progress(config.JobID, totalTables, totalTables, "", "同步完成")
-	return result
+// commitWatermark 在表同步完成后保存增量水位，演练模式不保存。
+func (s *SyncEngine) commitWatermark(config SyncConfig, result *SyncResult, tableName string, filter tableFilter) {
+	if config.DryRun {
+		return
+	}
+	if err := filter.commit(config, tableName); err != nil {
+		logger.Error(err, "保存增量水位失败：表=%s", tableName)
+		s.appendLog(config.JobID, result, "warn", fmt.Sprintf("  -> 保存表 %s 的增量水位失败，下次将重新读取: %v", tableName, err))
+	}
 }
 
 func formatConnSummaryForSync(config connection.ConnectionConfig) string {
@@ -520,6 +677,11 @@ func (s *SyncEngine) appendLog(jobID string, res *SyncResult, level string, msg
 }
 
 func (s *SyncEngine) progress(jobID string, current, total int, table string, stage string) {
+	s.rowProgress(jobID, current, total, table, stage, 0, 0)
+}
+
+// rowProgress 在表级进度之外附带行级进度，用于大表分块处理时持续反馈。
+func (s *SyncEngine) rowProgress(jobID string, current, total int, table string, stage string, tableRows, totalRows int64) {
 	if s.reporter.OnProgress == nil || strings.TrimSpace(jobID) == "" {
 		return
 	}
@@ -544,6 +706,9 @@ func (s *SyncEngine) progress(jobID string, current, total int, table string, st
 		Total:   total,
 		Table:   table,
 		Stage:   stage,
+
+		TableRows: tableRows,
+		TotalRows: totalRows,
 	})
 }
 
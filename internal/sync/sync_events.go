@@ -21,6 +21,9 @@ type SyncProgressEvent struct {
 	Total   int    `json:"total"`   // 总表数
 	Table   string `json:"table,omitempty"`
 	Stage   string `json:"stage,omitempty"`
+
+	TableRows int64 `json:"tableRows,omitempty"` // 当前表已处理的源表行数
+	TotalRows int64 `json:"totalRows,omitempty"` // 本次任务已处理的源表行数
 }
 
 type Reporter struct {
@@ -0,0 +1,260 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/db"
+	"context"
+	"fmt"
+	stdsync "sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxSyncWorkers   = 8
+	minSyncBatchSize = 100
+	maxSyncBatchSize = 50000
+)
+
+// syncRun 是一次 RunSync 中各工作线程共享的状态。每张表的日志与行数先记在表自己的 SyncResult 中，
+// 表处理完成后再汇总到 result，进度中的已完成表数与累计行数由原子计数维护。
+type syncRun struct {
+	engine      *SyncEngine
+	control     *syncControl
+	ctx         context.Context
+	config      SyncConfig
+	cp          *checkpointer
+	script      *syncScriptWriter
+	syncSchema  bool
+	syncData    bool
+	defaultMode string
+	totalTables int
+	batchSize   int
+	limiter     *syncLimiter
+
+	mu     stdsync.Mutex
+	result *SyncResult
+
+	done      int32 // 已处理完成的表数
+	totalRows int64 // 所有表已处理的源表行数
+}
+
+func newSyncRun(s *SyncEngine, control *syncControl, config SyncConfig, result *SyncResult, cp *checkpointer, script *syncScriptWriter, syncSchema bool, syncData bool, defaultMode string) *syncRun {
+	return &syncRun{
+		engine:      s,
+		control:     control,
+		ctx:         control.ctx,
+		config:      config,
+		cp:          cp,
+		script:      script,
+		syncSchema:  syncSchema,
+		syncData:    syncData,
+		defaultMode: defaultMode,
+		totalTables: len(config.Tables),
+		batchSize:   syncBatchSize(config),
+		limiter:     newSyncLimiter(config),
+		result:      result,
+	}
+}
+
+// syncWorkerCount 返回并发同步的表数。演练脚本按表顺序写入，演练模式固定为 1。
+func syncWorkerCount(config SyncConfig) int {
+	n := config.Workers
+	if n > maxSyncWorkers {
+		n = maxSyncWorkers
+	}
+	if n > len(config.Tables) {
+		n = len(config.Tables)
+	}
+	if n < 1 || config.DryRun {
+		n = 1
+	}
+	return n
+}
+
+// syncBatchSize 返回每批读取与写入的行数，未设置时为 syncChunkSize。
+func syncBatchSize(config SyncConfig) int {
+	switch n := config.BatchSize; {
+	case n <= 0:
+		return syncChunkSize
+	case n < minSyncBatchSize:
+		return minSyncBatchSize
+	case n > maxSyncBatchSize:
+		return maxSyncBatchSize
+	default:
+		return n
+	}
+}
+
+// runTables 用工作线程池同步 config.Tables。第一个线程使用 RunSync 已建立的连接，其余线程各自建立连接。
+func (s *SyncEngine) runTables(run *syncRun, sourceDB db.Database, targetDB db.Database) {
+	config := run.config
+	workers := syncWorkerCount(config)
+	if config.DryRun && config.Workers > 1 {
+		run.log("info", "演练模式按表顺序生成脚本，已改为单线程同步")
+	}
+	if workers > 1 {
+		run.log("info", fmt.Sprintf("并行同步：%d 个工作线程，每个线程使用独立的源、目标连接", workers))
+	}
+	if run.limiter != nil {
+		run.log("info", fmt.Sprintf("限流：每批 %d 行，最大速率 %d 行/秒，每批暂停 %d 毫秒", run.batchSize, config.MaxRowsPerSecond, config.BatchPauseMs))
+	}
+
+	queue := make(chan int)
+	var wg stdsync.WaitGroup
+	for w := 0; w < workers; w++ {
+		src, tgt := sourceDB, targetDB
+		if w > 0 {
+			var err error
+			src, tgt, err = openSyncConnections(config)
+			if err != nil {
+				run.log("warn", fmt.Sprintf("工作线程 %d 建立连接失败，已减少并发: %v", w+1, err))
+				continue
+			}
+		}
+		wg.Add(1)
+		go func(own bool, src db.Database, tgt db.Database) {
+			defer wg.Done()
+			if own {
+				defer src.Close()
+				defer tgt.Close()
+			}
+			for i := range queue {
+				// 取消后不再开始新表，队列中剩余的表直接跳过
+				if err := run.safePoint(config.Tables[i]); err != nil {
+					continue
+				}
+				res := &SyncResult{}
+				s.syncTable(run, res, src, tgt, i, config.Tables[i])
+				run.finishTable(res, config.Tables[i])
+			}
+		}(w > 0, src, tgt)
+	}
+	for i := range config.Tables {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// openSyncConnections 为工作线程建立独立的源、目标连接。
+func openSyncConnections(config SyncConfig) (db.Database, db.Database, error) {
+	sourceDB, err := db.NewDatabase(config.SourceConfig.Type)
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化源数据库驱动失败: %w", err)
+	}
+	targetDB, err := db.NewDatabase(config.TargetConfig.Type)
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化目标数据库驱动失败: %w", err)
+	}
+	if err := sourceDB.Connect(config.SourceConfig); err != nil {
+		return nil, nil, fmt.Errorf("源数据库连接失败: %w", err)
+	}
+	if err := targetDB.Connect(config.TargetConfig); err != nil {
+		sourceDB.Close()
+		return nil, nil, fmt.Errorf("目标数据库连接失败: %w", err)
+	}
+	return sourceDB, targetDB, nil
+}
+
+// log 写入任务级日志。
+func (run *syncRun) log(level string, msg string) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.engine.appendLog(run.config.JobID, run.result, level, msg)
+}
+
+// finishTable 把表的日志与行数汇总到任务结果，并推进已完成表数。
+func (run *syncRun) finishTable(res *SyncResult, tableName string) {
+	run.mu.Lock()
+	run.result.Logs = append(run.result.Logs, res.Logs...)
+	run.result.TablesSynced += res.TablesSynced
+	run.result.RowsInserted += res.RowsInserted
+	run.result.RowsUpdated += res.RowsUpdated
+	run.result.RowsDeleted += res.RowsDeleted
+	run.mu.Unlock()
+
+	done := atomic.AddInt32(&run.done, 1)
+	run.engine.progress(run.config.JobID, int(done), run.totalTables, tableName, "表处理完成")
+}
+
+func (run *syncRun) progress(tableName string, stage string) {
+	run.engine.progress(run.config.JobID, int(atomic.LoadInt32(&run.done)), run.totalTables, tableName, stage)
+}
+
+func (run *syncRun) rowProgress(tableName string, stage string, tableRows int64) {
+	run.engine.rowProgress(run.config.JobID, int(atomic.LoadInt32(&run.done)), run.totalTables, tableName, stage, tableRows, atomic.LoadInt64(&run.totalRows))
+}
+
+func (run *syncRun) addRows(n int64) {
+	atomic.AddInt64(&run.totalRows, n)
+}
+
+// safePoint 是批次之间的安全点：已取消时返回 ErrSyncCancelled，暂停时阻塞到恢复或取消。
+func (run *syncRun) safePoint(tableName string) error {
+	return run.control.wait(func() {
+		run.log("warn", "同步已暂停，当前批次将在恢复后写入")
+		run.progress(tableName, "已暂停")
+	}, func() {
+		run.log("info", "同步已恢复")
+		run.progress(tableName, "继续同步")
+	})
+}
+
+// throttle 在处理完一批 rows 行后按限流设置等待。
+func (run *syncRun) throttle(rows int) error {
+	return run.limiter.wait(run.ctx, rows)
+}
+
+// syncLimiter 限制一次同步所有工作线程合计的处理速率，并在每批之后暂停。nil 表示不限流。
+type syncLimiter struct {
+	rowsPerSecond int
+	pause         time.Duration
+
+	mu   stdsync.Mutex
+	next time.Time // 已处理的行按限速折算后的截止时间
+}
+
+func newSyncLimiter(config SyncConfig) *syncLimiter {
+	if config.MaxRowsPerSecond <= 0 && config.BatchPauseMs <= 0 {
+		return nil
+	}
+	l := &syncLimiter{}
+	if config.MaxRowsPerSecond > 0 {
+		l.rowsPerSecond = config.MaxRowsPerSecond
+	}
+	if config.BatchPauseMs > 0 {
+		l.pause = time.Duration(config.BatchPauseMs) * time.Millisecond
+	}
+	return l
+}
+
+// wait 记录刚处理的 rows 行，等待到累计速率不超过上限，且至少暂停 pause。
+func (l *syncLimiter) wait(ctx context.Context, rows int) error {
+	if l == nil || rows <= 0 {
+		return nil
+	}
+	delay := l.pause
+	if l.rowsPerSecond > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		if l.next.Before(now) {
+			l.next = now
+		}
+		l.next = l.next.Add(time.Duration(rows) * time.Second / time.Duration(l.rowsPerSecond))
+		if d := l.next.Sub(now); d > delay {
+			delay = d
+		}
+		l.mu.Unlock()
+	}
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestSyncWorkerCount(t *testing.T) {
+	tables := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	cases := []struct {
+		name    string
+		workers int
+		tables  int
+		dryRun  bool
+		want    int
+	}{
+		{"未设置", 0, 3, false, 1},
+		{"负数", -2, 3, false, 1},
+		{"按设置", 3, 5, false, 3},
+		{"不超过表数", 4, 2, false, 2},
+		{"不超过上限", 20, 10, false, maxSyncWorkers},
+		{"没有表", 4, 0, false, 1},
+		{"演练模式", 4, 5, true, 1},
+	}
+	for _, tc := range cases {
+		config := SyncConfig{Workers: tc.workers, Tables: tables[:tc.tables], DryRun: tc.dryRun}
+		if got := syncWorkerCount(config); got != tc.want {
+			t.Fatalf("%s：工作线程数=%d，期望=%d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSyncBatchSize(t *testing.T) {
+	cases := []struct {
+		batch int
+		want  int
+	}{
+		{0, syncChunkSize},
+		{-1, syncChunkSize},
+		{10, minSyncBatchSize},
+		{500, 500},
+		{100000, maxSyncBatchSize},
+	}
+	for _, tc := range cases {
+		if got := syncBatchSize(SyncConfig{BatchSize: tc.batch}); got != tc.want {
+			t.Fatalf("BatchSize=%d 时每批行数=%d，期望=%d", tc.batch, got, tc.want)
+		}
+	}
+}
+
+func TestNewSyncRun(t *testing.T) {
+	config := SyncConfig{Tables: []string{"a", "b"}, BatchSize: 300}
+	run := newSyncRun(NewSyncEngine(Reporter{}), newSyncControl(), config, &SyncResult{}, nil, nil, false, true, "insert_update")
+	if run.totalTables != 2 || run.batchSize != 300 || run.limiter != nil || run.ctx != run.control.ctx {
+		t.Fatalf("未设置限流时的运行状态不正确：%+v", run)
+	}
+
+	config.MaxRowsPerSecond = 50
+	config.BatchPauseMs = 20
+	run = newSyncRun(NewSyncEngine(Reporter{}), newSyncControl(), config, &SyncResult{}, nil, nil, false, true, "insert_update")
+	if run.limiter == nil || run.limiter.rowsPerSecond != 50 || run.limiter.pause != 20*time.Millisecond {
+		t.Fatalf("限流设置不正确：%+v", run.limiter)
+	}
+}
+
+func TestSyncLimiterWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("不限流", func(t *testing.T) {
+		var l *syncLimiter
+		if err := l.wait(ctx, 1000); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		l = &syncLimiter{rowsPerSecond: 1}
+		begin := time.Now()
+		if err := l.wait(ctx, 0); err != nil || time.Since(begin) > 50*time.Millisecond {
+			t.Fatalf("没有处理行时不应等待：err=%v 耗时 %s", err, time.Since(begin))
+		}
+	})
+
+	t.Run("按速率累计等待", func(t *testing.T) {
+		l := newSyncLimiter(SyncConfig{MaxRowsPerSecond: 1000})
+		begin := time.Now()
+		// 每次 100 行折算 100 毫秒，两次合计至少 200 毫秒
+		for i := 0; i < 2; i++ {
+			if err := l.wait(ctx, 100); err != nil {
+				t.Fatalf("返回错误：%v", err)
+			}
+		}
+		if elapsed := time.Since(begin); elapsed < 190*time.Millisecond || elapsed > 2*time.Second {
+			t.Fatalf("限速每秒 1000 行处理 200 行，耗时 %s", elapsed)
+		}
+	})
+
+	t.Run("每批暂停", func(t *testing.T) {
+		// 速率很高时仍至少暂停 pause
+		l := newSyncLimiter(SyncConfig{MaxRowsPerSecond: 1000000, BatchPauseMs: 80})
+		begin := time.Now()
+		if err := l.wait(ctx, 1); err != nil {
+			t.Fatalf("返回错误：%v", err)
+		}
+		if elapsed := time.Since(begin); elapsed < 75*time.Millisecond || elapsed > 2*time.Second {
+			t.Fatalf("每批应暂停 80 毫秒，耗时 %s", elapsed)
+		}
+	})
+
+	t.Run("取消时立即返回", func(t *testing.T) {
+		l := newSyncLimiter(SyncConfig{MaxRowsPerSecond: 1})
+		cancelCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(20*time.Millisecond, cancel)
+		begin := time.Now()
+		if err := l.wait(cancelCtx, 10); err != context.Canceled {
+			t.Fatalf("取消后应返回 context.Canceled，实际=%v", err)
+		}
+		if elapsed := time.Since(begin); elapsed > 2*time.Second {
+			t.Fatalf("取消后应立即返回，耗时 %s", elapsed)
+		}
+	})
+}
+
+func TestRunSyncWorkers(t *testing.T) {
+	sourcePath := filepath.Join(t.TempDir(), "source.db")
+	targetPath := filepath.Join(t.TempDir(), "target.db")
+	var sourceStmts, targetStmts []string
+	tables := []string{"t1", "t2", "t3"}
+	for _, table := range tables {
+		create := fmt.Sprintf("CREATE TABLE %s (id INTEGER PRIMARY KEY, name TEXT)", table)
+		sourceStmts = append(sourceStmts, create, fmt.Sprintf("INSERT INTO %s VALUES (1, 'a'), (2, 'b')", table))
+		targetStmts = append(targetStmts, create)
+	}
+	openTestSQLiteAt(t, sourcePath, sourceStmts...)
+	openTestSQLiteAt(t, targetPath, targetStmts...)
+	config := SyncConfig{
+		SourceConfig: connection.ConnectionConfig{Type: "sqlite", Host: sourcePath},
+		TargetConfig: connection.ConnectionConfig{Type: "sqlite", Host: targetPath},
+		Tables:       tables,
+		Content:      "data",
+		Mode:         "insert_update",
+		Workers:      2,
+	}
+
+	res := NewSyncEngine(Reporter{}).RunSync(config)
+	if !res.Success || res.TablesSynced != 3 || res.RowsInserted != 6 {
+		t.Fatalf("并行同步结果不正确：%+v", res)
+	}
+	if !strings.Contains(strings.Join(res.Logs, "\n"), "并行同步：2 个工作线程") {
+		t.Fatalf("应记录工作线程数：\n%s", strings.Join(res.Logs, "\n"))
+	}
+
+	// 演练模式固定单线程
+	config.DryRun = true
+	config.ScriptPath = filepath.Join(t.TempDir(), "dry.sql")
+	res = NewSyncEngine(Reporter{}).RunSync(config)
+	logs := strings.Join(res.Logs, "\n")
+	if !res.Success || !strings.Contains(logs, "演练模式按表顺序生成脚本") || strings.Contains(logs, "并行同步") {
+		t.Fatalf("演练模式应单线程同步：%+v", res)
+	}
+}
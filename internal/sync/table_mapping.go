@@ -0,0 +1,198 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ColumnTransform 描述对目标字段取值的转换，对比与写入都使用转换后的值。
+type ColumnTransform struct {
+	Column string `json:"column"` // 目标字段名（改名后的名称）；常量与表达式可写入源表没有的字段
+	Type   string `json:"type"`   // constant / expression / mask / cast
+	// Value 的含义随 Type 而定：
+	//   constant   常量文本；
+	//   expression 表达式，如 concat(first_name, ' ', last_name)、upper(trim(code))，标识符为源字段名；
+	//   mask       脱敏规则：空（全部替换为 *）、keep:前保留,后保留、email、hash；
+	//   cast       目标类型：string / int / float / bool / date / datetime。
+	Value string `json:"value,omitempty"`
+}
+
+// tableMapping 是单表的名称映射与取值转换：源表行先按它改名、剔除字段并转换取值，
+// 之后的对比、匹配与写入都使用目标字段名。
+type tableMapping struct {
+	targetTable string
+	rename      map[string]string // 源字段（小写）-> 目标字段
+	exclude     map[string]bool   // 源字段（小写）
+	transforms  []compiledTransform
+}
+
+type compiledTransform struct {
+	column string
+	kind   string
+	eval   func(source map[string]interface{}, current interface{}) (interface{}, error)
+}
+
+// newTableMapping 按 TableOptions 构建表映射，转换规则有误时返回错误。
+func newTableMapping(tableName string, opts TableOptions) (*tableMapping, error) {
+	m := &tableMapping{
+		targetTable: strings.TrimSpace(opts.TargetTable),
+		rename:      make(map[string]string, len(opts.ColumnMap)),
+		exclude:     make(map[string]bool, len(opts.ExcludeColumns)),
+	}
+	if m.targetTable == "" {
+		m.targetTable = tableName
+	}
+	for src, dst := range opts.ColumnMap {
+		src, dst = strings.TrimSpace(src), strings.TrimSpace(dst)
+		if src == "" || dst == "" || strings.EqualFold(src, dst) {
+			continue
+		}
+		m.rename[strings.ToLower(src)] = dst
+	}
+	for _, col := range opts.ExcludeColumns {
+		if col = strings.TrimSpace(col); col != "" {
+			m.exclude[strings.ToLower(col)] = true
+		}
+	}
+	for _, t := range opts.Transforms {
+		compiled, err := compileTransform(t)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 的转换规则无效: %w", t.Column, err)
+		}
+		m.transforms = append(m.transforms, compiled)
+	}
+	return m, nil
+}
+
+// identity 表示字段名与取值都不做变换（目标表名可以不同）。
+func (m *tableMapping) identity() bool {
+	return len(m.rename) == 0 && len(m.exclude) == 0 && len(m.transforms) == 0
+}
+
+// targetColumn 返回源字段对应的目标字段名，被排除时返回 false。
+func (m *tableMapping) targetColumn(source string) (string, bool) {
+	lower := strings.ToLower(strings.TrimSpace(source))
+	if m.exclude[lower] {
+		return "", false
+	}
+	if dst, ok := m.rename[lower]; ok {
+		return dst, true
+	}
+	return source, true
+}
+
+// targetColumns 返回映射到目标表的字段定义（改名、剔除排除字段），用于建表与补字段。
+// 仅由常量或表达式生成的字段没有源字段定义，需在目标表中已存在。
+func (m *tableMapping) targetColumns(cols []connection.ColumnDefinition) []connection.ColumnDefinition {
+	out := make([]connection.ColumnDefinition, 0, len(cols))
+	for _, col := range cols {
+		name, ok := m.targetColumn(col.Name)
+		if !ok {
+			continue
+		}
+		col.Name = name
+		out = append(out, col)
+	}
+	return out
+}
+
+// mapKey 把源表匹配键转换为目标字段名。匹配键字段不能被排除或转换，否则两端无法按键对齐。
+func (m *tableMapping) mapKey(key matchKey) (matchKey, error) {
+	mapped := matchKey{columns: make([]string, len(key.columns)), index: key.index}
+	for i, col := range key.columns {
+		name, ok := m.targetColumn(col)
+		if !ok {
+			return matchKey{}, fmt.Errorf("匹配键字段 %s 不能排除", col)
+		}
+		for _, t := range m.transforms {
+			if strings.EqualFold(t.column, name) {
+				return matchKey{}, fmt.Errorf("匹配键字段 %s 不能设置转换", col)
+			}
+		}
+		mapped.columns[i] = name
+	}
+	return mapped, nil
+}
+
+// apply 把源表行转换为目标表行。
+func (m *tableMapping) apply(row map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(row)+len(m.transforms))
+	for col, val := range row {
+		if name, ok := m.targetColumn(col); ok {
+			out[name] = val
+		}
+	}
+	for _, t := range m.transforms {
+		current := lookupMappedColumn(out, t.column)
+		val, err := t.eval(row, current)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 转换失败: %w", t.column, err)
+		}
+		out[mappedColumnName(out, t.column)] = val
+	}
+	return out, nil
+}
+
+// describe 概括映射内容，用于同步日志。
+func (m *tableMapping) describe(tableName string) string {
+	parts := make([]string, 0, 4)
+	if !strings.EqualFold(m.targetTable, tableName) {
+		parts = append(parts, "目标表 "+m.targetTable)
+	}
+	if len(m.rename) > 0 {
+		renames := make([]string, 0, len(m.rename))
+		for src, dst := range m.rename {
+			renames = append(renames, src+"→"+dst)
+		}
+		sort.Strings(renames)
+		parts = append(parts, "字段改名 "+strings.Join(renames, ", "))
+	}
+	if len(m.exclude) > 0 {
+		excluded := make([]string, 0, len(m.exclude))
+		for col := range m.exclude {
+			excluded = append(excluded, col)
+		}
+		sort.Strings(excluded)
+		parts = append(parts, "排除字段 "+strings.Join(excluded, ", "))
+	}
+	if len(m.transforms) > 0 {
+		transforms := make([]string, len(m.transforms))
+		for i, t := range m.transforms {
+			transforms[i] = t.column + "(" + t.kind + ")"
+		}
+		parts = append(parts, "取值转换 "+strings.Join(transforms, ", "))
+	}
+	return strings.Join(parts, "；")
+}
+
+// lookupMappedColumn 按名称取值，忽略大小写。
+func lookupMappedColumn(row map[string]interface{}, name string) interface{} {
+	return row[mappedColumnName(row, name)]
+}
+
+// mappedColumnName 返回行中与 name 忽略大小写相同的列名，没有时返回 name。
+func mappedColumnName(row map[string]interface{}, name string) string {
+	if _, ok := row[name]; ok {
+		return name
+	}
+	for k := range row {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
+
+// sourceSide 构造源表一端：键条件使用源字段名，读出的行转换为目标字段名。
+func (m *tableMapping) sourceSide(inst db.Database, dialect string, queryTable string, sourceKey matchKey) tableSide {
+	side := tableSide{inst: inst, dialect: dialect, queryTable: queryTable}
+	if m.identity() {
+		return side
+	}
+	side.keyColumns = sourceKey.columns
+	side.mapRow = m.apply
+	return side
+}
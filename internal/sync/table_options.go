@@ -10,4 +10,24 @@ type TableOptions struct {
 	SelectedInsertPKs []string `json:"selectedInsertPks,omitempty"`
 	SelectedUpdatePKs []string `json:"selectedUpdatePks,omitempty"`
 	SelectedDeletePKs []string `json:"selectedDeletePks,omitempty"`
+
+	// MatchIndex 指定用于匹配源/目标行的唯一索引；为空时使用主键，无主键时自动选用唯一索引。
+	// 复合键的 Selected*PKs 取值为各键列值组成的 JSON 字符串数组，与差异预览中的 pk 一致。
+	MatchIndex string `json:"matchIndex,omitempty"`
+
+	// TargetTable 为目标表名，为空时与源表同名。
+	TargetTable string `json:"targetTable,omitempty"`
+	// ColumnMap 为字段改名映射：源字段 -> 目标字段，未列出的字段同名对应。
+	ColumnMap map[string]string `json:"columnMap,omitempty"`
+	// ExcludeColumns 为不参与对比与同步的源字段，不能包含匹配键字段。
+	ExcludeColumns []string `json:"excludeColumns,omitempty"`
+	// Transforms 按顺序对目标字段取值做转换，对比与写入都使用转换后的值。
+	Transforms []ColumnTransform `json:"transforms,omitempty"`
+
+	// Where 为读取源表时附加的过滤条件（SQL 片段，不含 WHERE 关键字，使用源表字段名）。
+	// 未配置字段映射时对比也只读取目标表中满足该条件的行，否则不检测删除。
+	Where string `json:"where,omitempty"`
+	// WatermarkColumn 为增量同步的水位字段（如 updated_at 或自增 id，源表字段名）。
+	// 每次同步完成后记录该字段的最大值，下次只读取大于该值的行，且不检测删除；水位为 NULL 的行不会被增量读取。
+	WatermarkColumn string `json:"watermarkColumn,omitempty"`
 }
@@ -0,0 +1,882 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// typeKind 是跨方言的列类型分类，源类型先解析为分类再按目标方言渲染。
+type typeKind int
+
+const (
+	kindUnknown typeKind = iota
+	kindBool
+	kindTinyInt
+	kindSmallInt
+	kindInt
+	kindBigInt
+	kindDecimal
+	kindFloat
+	kindDouble
+	kindChar
+	kindVarchar
+	kindText
+	kindBinary
+	kindVarbinary
+	kindBlob
+	kindDate
+	kindTime
+	kindDateTime
+	kindTimestampTZ
+	kindJSON
+	kindUUID
+)
+
+// columnType 是解析后的列类型。
+type columnType struct {
+	kind      typeKind
+	length    int // 字符/字节长度：0 表示未指定，-1 表示 MAX
+	precision int // 数值精度，0 表示未指定
+	scale     int
+	fsp       int // 时间类型的小数秒位数，-1 表示未指定
+	unsigned  bool
+}
+
+func (t columnType) isInteger() bool {
+	switch t.kind {
+	case kindTinyInt, kindSmallInt, kindInt, kindBigInt:
+		return true
+	}
+	return false
+}
+
+func (t columnType) isNumeric() bool {
+	return t.isInteger() || t.kind == kindDecimal || t.kind == kindFloat || t.kind == kindDouble
+}
+
+func (t columnType) isTemporal() bool {
+	switch t.kind {
+	case kindDate, kindTime, kindDateTime, kindTimestampTZ:
+		return true
+	}
+	return false
+}
+
+// parseColumnType 把 GetColumns 返回的类型文本（如 varchar(50)、NUMBER(10,2)、timestamp(6) with time zone）解析为 columnType。
+func parseColumnType(dialect string, raw string) columnType {
+	family := db.Capabilities(dialect).Family
+	text := strings.ToLower(strings.TrimSpace(raw))
+	ct := columnType{fsp: -1}
+	if strings.HasSuffix(text, "[]") {
+		// PG 数组类型在其它方言中没有对应类型，按文本保存
+		ct.kind = kindText
+		return ct
+	}
+
+	ct.unsigned = strings.Contains(text, "unsigned")
+	text = strings.ReplaceAll(text, "unsigned", "")
+	text = strings.ReplaceAll(text, "zerofill", "")
+
+	var args []string
+	if open := strings.Index(text, "("); open >= 0 {
+		if closeAt := strings.Index(text[open:], ")"); closeAt > 0 {
+			args = strings.Split(text[open+1:open+closeAt], ",")
+			text = text[:open] + " " + text[open+closeAt+1:]
+		}
+	}
+	name := strings.Join(strings.Fields(text), " ")
+	arg := func(i int) int {
+		if i >= len(args) {
+			return 0
+		}
+		v := strings.TrimSpace(args[i])
+		if v == "max" {
+			return -1
+		}
+		// Oracle 字符长度可能带 BYTE/CHAR 语义后缀
+		v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(v, "char"), "byte"))
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+
+	switch name {
+	case "bool", "boolean":
+		ct.kind = kindBool
+	case "bit":
+		if family == db.FamilySQLServer || arg(0) <= 1 {
+			ct.kind = kindBool
+		} else {
+			ct.kind = kindBigInt
+		}
+	case "tinyint":
+		ct.kind = kindTinyInt
+		if family == db.FamilySQLServer {
+			// SQL Server 的 tinyint 取值 0~255
+			ct.unsigned = true
+		}
+	case "smallint", "int2", "year":
+		ct.kind = kindSmallInt
+	case "smallserial", "serial2":
+		ct.kind = kindSmallInt
+	case "mediumint", "int", "integer", "int4", "serial", "serial4", "pls_integer", "binary_integer":
+		ct.kind = kindInt
+		if family == db.FamilySQLite {
+			// SQLite 的 INTEGER 为 64 位
+			ct.kind = kindBigInt
+		}
+	case "bigint", "int8", "bigserial", "serial8":
+		ct.kind = kindBigInt
+	case "decimal", "numeric", "dec", "number":
+		ct.kind = kindDecimal
+		ct.precision = arg(0)
+		ct.scale = arg(1)
+		if (family == db.FamilyOracle || family == db.FamilyDameng) && name == "number" && ct.precision > 0 && ct.scale == 0 {
+			// Oracle 以 NUMBER(p) 表示整数列
+			switch {
+			case ct.precision <= 4:
+				ct.kind = kindSmallInt
+			case ct.precision <= 9:
+				ct.kind = kindInt
+			case ct.precision <= 18:
+				ct.kind = kindBigInt
+			}
+		}
+	case "money":
+		ct.kind, ct.precision, ct.scale = kindDecimal, 19, 4
+	case "smallmoney":
+		ct.kind, ct.precision, ct.scale = kindDecimal, 10, 4
+	case "float":
+		switch n := arg(0); {
+		case n > 0 && n <= 24:
+			ct.kind = kindFloat
+		case n > 24:
+			ct.kind = kindDouble
+		case family == db.FamilyMySQL || family == db.FamilyTDengine:
+			ct.kind = kindFloat
+		default:
+			ct.kind = kindDouble
+		}
+	case "real", "float4", "binary_float":
+		ct.kind = kindFloat
+	case "double", "double precision", "float8", "binary_double":
+		ct.kind = kindDouble
+	case "char", "character", "nchar", "bpchar":
+		ct.kind = kindChar
+		ct.length = arg(0)
+		if ct.length == 0 {
+			ct.length = 1
+		}
+		if family == db.FamilyTDengine {
+			ct.kind = kindVarchar
+		}
+	case "varchar", "character varying", "varchar2", "nvarchar", "nvarchar2", "varying character":
+		ct.kind = kindVarchar
+		ct.length = arg(0)
+		if ct.length <= 0 {
+			ct.kind = kindText
+		}
+	case "text", "tinytext", "mediumtext", "longtext", "clob", "nclob", "ntext", "long", "xml", "citext", "sysname":
+		ct.kind = kindText
+	case "binary":
+		ct.kind = kindBinary
+		ct.length = arg(0)
+		if family == db.FamilyTDengine {
+			// TDengine 的 BINARY 是字节串形式的字符类型
+			ct.kind = kindVarchar
+		}
+	case "varbinary", "raw":
+		ct.kind = kindVarbinary
+		ct.length = arg(0)
+		if ct.length <= 0 {
+			ct.kind = kindBlob
+		}
+	case "blob", "tinyblob", "mediumblob", "longblob", "bytea", "image", "long raw", "bfile", "longvarbinary":
+		ct.kind = kindBlob
+	case "date":
+		ct.kind = kindDate
+		if family == db.FamilyOracle {
+			// Oracle 的 DATE 含时分秒
+			ct.kind = kindDateTime
+			ct.fsp = 0
+		}
+	case "time", "time without time zone", "time with time zone", "timetz":
+		ct.kind = kindTime
+		if len(args) > 0 {
+			ct.fsp = arg(0)
+		}
+	case "timestamp", "rowversion":
+		if family == db.FamilySQLServer {
+			// SQL Server 的 timestamp 是行版本号
+			ct.kind, ct.length = kindBinary, 8
+			break
+		}
+		ct.kind = kindDateTime
+		if len(args) > 0 {
+			ct.fsp = arg(0)
+		}
+	case "datetime", "datetime2", "smalldatetime", "timestamp without time zone":
+		ct.kind = kindDateTime
+		if len(args) > 0 {
+			ct.fsp = arg(0)
+		}
+	case "timestamp with time zone", "timestamptz", "datetimeoffset", "timestamp with local time zone":
+		ct.kind = kindTimestampTZ
+		if len(args) > 0 {
+			ct.fsp = arg(0)
+		}
+	case "json", "jsonb":
+		ct.kind = kindJSON
+	case "uuid", "uniqueidentifier":
+		ct.kind = kindUUID
+	case "enum", "set":
+		ct.kind, ct.length = kindVarchar, 255
+	default:
+		if strings.HasPrefix(name, "interval") {
+			ct.kind, ct.length = kindVarchar, 64
+		} else {
+			ct.kind = kindUnknown
+		}
+	}
+	return ct
+}
+
+// isAutoIncrementColumn 判断源列是否为自增/标识列。
+func isAutoIncrementColumn(col connection.ColumnDefinition) bool {
+	extra := strings.ToLower(col.Extra)
+	if strings.Contains(extra, "auto_increment") || strings.Contains(extra, "identity") {
+		return true
+	}
+	typ := strings.ToLower(strings.TrimSpace(col.Type))
+	if strings.HasPrefix(typ, "serial") || strings.HasPrefix(typ, "bigserial") || strings.HasPrefix(typ, "smallserial") {
+		return true
+	}
+	return col.Default != nil && strings.HasPrefix(strings.ToLower(strings.TrimSpace(*col.Default)), "nextval(")
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// renderColumnType 按目标方言输出列类型；autoIncrement 仅影响以类型表达自增的方言（PG 的 SERIAL）。
+func renderColumnType(dialect string, ct columnType, autoIncrement bool) string {
+	switch db.Capabilities(dialect).Family {
+	case db.FamilyMySQL:
+		return renderMySQLType(ct)
+	case db.FamilyPostgres:
+		return renderPostgresType(ct, autoIncrement)
+	case db.FamilySQLServer:
+		return renderSQLServerType(ct)
+	case db.FamilyOracle:
+		return renderOracleType(ct)
+	case db.FamilyDameng:
+		return renderDamengType(ct)
+	case db.FamilySQLite:
+		return renderSQLiteType(ct)
+	case db.FamilyTDengine:
+		return renderTDengineType(ct)
+	default:
+		return renderGenericType(ct)
+	}
+}
+
+func renderMySQLType(ct columnType) string {
+	unsigned := ""
+	if ct.unsigned {
+		unsigned = " UNSIGNED"
+	}
+	switch ct.kind {
+	case kindBool:
+		return "TINYINT(1)"
+	case kindTinyInt:
+		return "TINYINT" + unsigned
+	case kindSmallInt:
+		return "SMALLINT" + unsigned
+	case kindInt:
+		return "INT" + unsigned
+	case kindBigInt:
+		return "BIGINT" + unsigned
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "DECIMAL(38,10)"
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", clampInt(ct.precision, 1, 65), clampInt(ct.scale, 0, 30))
+	case kindFloat:
+		return "FLOAT"
+	case kindDouble:
+		return "DOUBLE"
+	case kindChar:
+		if ct.length > 0 && ct.length <= 255 {
+			return fmt.Sprintf("CHAR(%d)", ct.length)
+		}
+		return renderMySQLType(columnType{kind: kindVarchar, length: ct.length})
+	case kindVarchar:
+		if ct.length > 0 && ct.length <= 16383 {
+			return fmt.Sprintf("VARCHAR(%d)", ct.length)
+		}
+		return "LONGTEXT"
+	case kindBinary:
+		if ct.length > 0 && ct.length <= 255 {
+			return fmt.Sprintf("BINARY(%d)", ct.length)
+		}
+		return renderMySQLType(columnType{kind: kindVarbinary, length: ct.length})
+	case kindVarbinary:
+		if ct.length > 0 && ct.length <= 16383 {
+			return fmt.Sprintf("VARBINARY(%d)", ct.length)
+		}
+		return "LONGBLOB"
+	case kindBlob:
+		return "LONGBLOB"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return withFsp("TIME", ct.fsp, 6)
+	case kindDateTime, kindTimestampTZ:
+		return withFsp("DATETIME", ct.fsp, 6)
+	case kindJSON:
+		return "JSON"
+	case kindUUID:
+		return "CHAR(36)"
+	default:
+		return "LONGTEXT"
+	}
+}
+
+func renderPostgresType(ct columnType, autoIncrement bool) string {
+	if autoIncrement && ct.isInteger() {
+		switch ct.kind {
+		case kindTinyInt, kindSmallInt:
+			if !ct.unsigned {
+				return "SMALLSERIAL"
+			}
+			return "SERIAL"
+		case kindInt:
+			if !ct.unsigned {
+				return "SERIAL"
+			}
+		}
+		return "BIGSERIAL"
+	}
+	switch ct.kind {
+	case kindBool:
+		return "BOOLEAN"
+	case kindTinyInt:
+		return "SMALLINT"
+	case kindSmallInt:
+		if ct.unsigned {
+			return "INTEGER"
+		}
+		return "SMALLINT"
+	case kindInt:
+		if ct.unsigned {
+			return "BIGINT"
+		}
+		return "INTEGER"
+	case kindBigInt:
+		if ct.unsigned {
+			return "NUMERIC(20)"
+		}
+		return "BIGINT"
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "NUMERIC"
+		}
+		return fmt.Sprintf("NUMERIC(%d,%d)", clampInt(ct.precision, 1, 1000), clampInt(ct.scale, 0, ct.precision))
+	case kindFloat:
+		return "REAL"
+	case kindDouble:
+		return "DOUBLE PRECISION"
+	case kindChar:
+		return fmt.Sprintf("CHAR(%d)", clampInt(ct.length, 1, 10485760))
+	case kindVarchar:
+		if ct.length > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", clampInt(ct.length, 1, 10485760))
+		}
+		return "TEXT"
+	case kindBinary, kindVarbinary, kindBlob:
+		return "BYTEA"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return withFsp("TIME", ct.fsp, 6)
+	case kindDateTime:
+		return withFsp("TIMESTAMP", ct.fsp, 6)
+	case kindTimestampTZ:
+		return withFsp("TIMESTAMPTZ", ct.fsp, 6)
+	case kindJSON:
+		return "JSON"
+	case kindUUID:
+		return "UUID"
+	default:
+		return "TEXT"
+	}
+}
+
+func renderSQLServerType(ct columnType) string {
+	switch ct.kind {
+	case kindBool:
+		return "BIT"
+	case kindTinyInt:
+		if ct.unsigned {
+			return "TINYINT"
+		}
+		return "SMALLINT"
+	case kindSmallInt:
+		if ct.unsigned {
+			return "INT"
+		}
+		return "SMALLINT"
+	case kindInt:
+		if ct.unsigned {
+			return "BIGINT"
+		}
+		return "INT"
+	case kindBigInt:
+		if ct.unsigned {
+			return "DECIMAL(20,0)"
+		}
+		return "BIGINT"
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "DECIMAL(38,10)"
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", clampInt(ct.precision, 1, 38), clampInt(ct.scale, 0, clampInt(ct.precision, 1, 38)))
+	case kindFloat:
+		return "REAL"
+	case kindDouble:
+		return "FLOAT"
+	case kindChar:
+		if ct.length > 0 && ct.length <= 4000 {
+			return fmt.Sprintf("NCHAR(%d)", ct.length)
+		}
+		return "NVARCHAR(MAX)"
+	case kindVarchar:
+		if ct.length > 0 && ct.length <= 4000 {
+			return fmt.Sprintf("NVARCHAR(%d)", ct.length)
+		}
+		return "NVARCHAR(MAX)"
+	case kindBinary:
+		if ct.length > 0 && ct.length <= 8000 {
+			return fmt.Sprintf("BINARY(%d)", ct.length)
+		}
+		return "VARBINARY(MAX)"
+	case kindVarbinary:
+		if ct.length > 0 && ct.length <= 8000 {
+			return fmt.Sprintf("VARBINARY(%d)", ct.length)
+		}
+		return "VARBINARY(MAX)"
+	case kindBlob:
+		return "VARBINARY(MAX)"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return withFsp("TIME", ct.fsp, 7)
+	case kindDateTime:
+		return withFsp("DATETIME2", ct.fsp, 7)
+	case kindTimestampTZ:
+		return withFsp("DATETIMEOFFSET", ct.fsp, 7)
+	case kindUUID:
+		return "UNIQUEIDENTIFIER"
+	default:
+		return "NVARCHAR(MAX)"
+	}
+}
+
+func renderOracleType(ct columnType) string {
+	switch ct.kind {
+	case kindBool:
+		return "NUMBER(1)"
+	case kindTinyInt:
+		return "NUMBER(3)"
+	case kindSmallInt:
+		return "NUMBER(5)"
+	case kindInt:
+		return "NUMBER(10)"
+	case kindBigInt:
+		if ct.unsigned {
+			return "NUMBER(20)"
+		}
+		return "NUMBER(19)"
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "NUMBER"
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", clampInt(ct.precision, 1, 38), clampInt(ct.scale, -84, 127))
+	case kindFloat:
+		return "BINARY_FLOAT"
+	case kindDouble:
+		return "BINARY_DOUBLE"
+	case kindChar:
+		if ct.length > 0 && ct.length <= 2000 {
+			return fmt.Sprintf("CHAR(%d CHAR)", ct.length)
+		}
+		return renderOracleType(columnType{kind: kindVarchar, length: ct.length})
+	case kindVarchar:
+		if ct.length > 0 && ct.length <= 4000 {
+			return fmt.Sprintf("VARCHAR2(%d CHAR)", ct.length)
+		}
+		return "CLOB"
+	case kindBinary, kindVarbinary:
+		if ct.length > 0 && ct.length <= 2000 {
+			return fmt.Sprintf("RAW(%d)", ct.length)
+		}
+		return "BLOB"
+	case kindBlob:
+		return "BLOB"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		// Oracle 没有单独的时间类型
+		return "VARCHAR2(32 CHAR)"
+	case kindDateTime:
+		return withFsp("TIMESTAMP", ct.fsp, 9)
+	case kindTimestampTZ:
+		return withFsp("TIMESTAMP", ct.fsp, 9) + " WITH TIME ZONE"
+	case kindUUID:
+		return "VARCHAR2(36 CHAR)"
+	default:
+		return "CLOB"
+	}
+}
+
+func renderDamengType(ct columnType) string {
+	switch ct.kind {
+	case kindBool:
+		return "BIT"
+	case kindTinyInt:
+		if ct.unsigned {
+			return "SMALLINT"
+		}
+		return "TINYINT"
+	case kindSmallInt:
+		if ct.unsigned {
+			return "INT"
+		}
+		return "SMALLINT"
+	case kindInt:
+		if ct.unsigned {
+			return "BIGINT"
+		}
+		return "INT"
+	case kindBigInt:
+		if ct.unsigned {
+			return "DECIMAL(20,0)"
+		}
+		return "BIGINT"
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "DECIMAL"
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", clampInt(ct.precision, 1, 38), clampInt(ct.scale, 0, clampInt(ct.precision, 1, 38)))
+	case kindFloat:
+		return "REAL"
+	case kindDouble:
+		return "DOUBLE"
+	case kindChar:
+		if ct.length > 0 && ct.length <= 8188 {
+			return fmt.Sprintf("CHAR(%d)", ct.length)
+		}
+		return "CLOB"
+	case kindVarchar:
+		if ct.length > 0 && ct.length <= 8188 {
+			return fmt.Sprintf("VARCHAR(%d)", ct.length)
+		}
+		return "CLOB"
+	case kindBinary:
+		if ct.length > 0 && ct.length <= 8188 {
+			return fmt.Sprintf("BINARY(%d)", ct.length)
+		}
+		return "BLOB"
+	case kindVarbinary:
+		if ct.length > 0 && ct.length <= 8188 {
+			return fmt.Sprintf("VARBINARY(%d)", ct.length)
+		}
+		return "BLOB"
+	case kindBlob:
+		return "BLOB"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return withFsp("TIME", ct.fsp, 6)
+	case kindDateTime:
+		return withFsp("TIMESTAMP", ct.fsp, 6)
+	case kindTimestampTZ:
+		return withFsp("TIMESTAMP", ct.fsp, 6) + " WITH TIME ZONE"
+	case kindUUID:
+		return "VARCHAR(36)"
+	default:
+		return "CLOB"
+	}
+}
+
+func renderSQLiteType(ct columnType) string {
+	switch {
+	case ct.kind == kindBool || ct.isInteger():
+		return "INTEGER"
+	case ct.kind == kindDecimal:
+		return "NUMERIC"
+	case ct.kind == kindFloat || ct.kind == kindDouble:
+		return "REAL"
+	case ct.kind == kindBinary || ct.kind == kindVarbinary || ct.kind == kindBlob:
+		return "BLOB"
+	case ct.kind == kindDate:
+		return "DATE"
+	case ct.kind == kindDateTime || ct.kind == kindTimestampTZ:
+		return "DATETIME"
+	default:
+		return "TEXT"
+	}
+}
+
+func renderTDengineType(ct columnType) string {
+	unsigned := ""
+	if ct.unsigned {
+		unsigned = " UNSIGNED"
+	}
+	switch ct.kind {
+	case kindBool:
+		return "BOOL"
+	case kindTinyInt:
+		return "TINYINT" + unsigned
+	case kindSmallInt:
+		return "SMALLINT" + unsigned
+	case kindInt:
+		return "INT" + unsigned
+	case kindBigInt:
+		return "BIGINT" + unsigned
+	case kindDecimal, kindDouble:
+		return "DOUBLE"
+	case kindFloat:
+		return "FLOAT"
+	case kindChar, kindVarchar:
+		if ct.length > 0 && ct.length <= 4093 {
+			return fmt.Sprintf("NCHAR(%d)", ct.length)
+		}
+		return "NCHAR(4093)"
+	case kindBinary, kindVarbinary, kindBlob:
+		if ct.length > 0 && ct.length <= 16374 {
+			return fmt.Sprintf("BINARY(%d)", ct.length)
+		}
+		return "BINARY(16374)"
+	case kindDate, kindDateTime, kindTimestampTZ:
+		return "TIMESTAMP"
+	case kindUUID:
+		return "NCHAR(36)"
+	default:
+		return "NCHAR(4093)"
+	}
+}
+
+func renderGenericType(ct columnType) string {
+	switch ct.kind {
+	case kindBool:
+		return "BOOLEAN"
+	case kindTinyInt, kindSmallInt:
+		return "SMALLINT"
+	case kindInt:
+		return "INTEGER"
+	case kindBigInt:
+		return "BIGINT"
+	case kindDecimal:
+		if ct.precision <= 0 {
+			return "DECIMAL(38,10)"
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", ct.precision, ct.scale)
+	case kindFloat:
+		return "REAL"
+	case kindDouble:
+		return "DOUBLE PRECISION"
+	case kindChar:
+		return fmt.Sprintf("CHAR(%d)", clampInt(ct.length, 1, 255))
+	case kindVarchar:
+		return fmt.Sprintf("VARCHAR(%d)", clampInt(ct.length, 1, 4000))
+	case kindBinary, kindVarbinary, kindBlob:
+		return "BLOB"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return "TIME"
+	case kindDateTime:
+		return "TIMESTAMP"
+	case kindTimestampTZ:
+		return "TIMESTAMP WITH TIME ZONE"
+	case kindUUID:
+		return "CHAR(36)"
+	default:
+		return "TEXT"
+	}
+}
+
+// withFsp 附加小数秒位数：未指定时沿用目标方言默认值，超过上限时截断。
+func withFsp(base string, fsp int, max int) string {
+	if fsp < 0 {
+		return base
+	}
+	return fmt.Sprintf("%s(%d)", base, clampInt(fsp, 0, max))
+}
+
+// mapColumnType 把源列类型映射为目标方言类型。同一方言族之间沿用源类型文本（PG 自增列除外，需要改写为 SERIAL）。
+func mapColumnType(sourceDialect string, targetDialect string, col connection.ColumnDefinition, autoIncrement bool) string {
+	sourceFamily := db.Capabilities(sourceDialect).Family
+	targetFamily := db.Capabilities(targetDialect).Family
+	if sourceFamily == targetFamily && !(targetFamily == db.FamilyPostgres && autoIncrement) {
+		if raw := sanitizeColumnType(col.Type); raw != "" {
+			return raw
+		}
+	}
+	return renderColumnType(targetDialect, parseColumnType(sourceDialect, col.Type), autoIncrement)
+}
+
+// sanitizeColumnType 校验直接沿用的类型文本，含引号、分号等异常内容时返回空串。
+func sanitizeColumnType(t string) string {
+	tt := strings.TrimSpace(t)
+	if tt == "" || strings.ContainsAny(tt, "`\"[];\n\r") {
+		return ""
+	}
+	return tt
+}
+
+// mapColumnDefault 转换列默认值：字面量按目标类型重新渲染，当前时间函数统一为 CURRENT_TIMESTAMP，
+// 其它表达式（序列、自定义函数等）无法跨方言转换，返回 false。
+func mapColumnDefault(sourceDialect string, targetDialect string, col connection.ColumnDefinition, target columnType) (string, bool) {
+	if col.Default == nil {
+		return "", false
+	}
+	sourceFamily := db.Capabilities(sourceDialect).Family
+	targetFamily := db.Capabilities(targetDialect).Family
+	raw := strings.TrimSpace(*col.Default)
+	if raw == "" || strings.EqualFold(raw, "NULL") {
+		return "", false
+	}
+
+	value, isString, isExpr := raw, false, false
+	if sourceFamily == db.FamilyMySQL {
+		// SHOW COLUMNS 返回的是默认值本身而非表达式
+		lower := strings.ToLower(raw)
+		if strings.HasPrefix(lower, "b'") && strings.HasSuffix(lower, "'") {
+			if n, err := strconv.ParseInt(raw[2:len(raw)-1], 2, 64); err == nil {
+				value = strconv.FormatInt(n, 10)
+			}
+		} else if isCurrentTimestampExpr(lower) {
+			isExpr = true
+		} else {
+			isString = true
+		}
+	} else {
+		value = unwrapDefaultExpr(raw)
+		switch {
+		case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) >= 2:
+			value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+			isString = true
+		case isCurrentTimestampExpr(strings.ToLower(value)):
+			isExpr = true
+		}
+	}
+
+	if isExpr {
+		if !target.isTemporal() || target.kind == kindTime {
+			return "", false
+		}
+		if targetFamily == db.FamilyMySQL && target.kind != kindDate && target.fsp > 0 {
+			return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", clampInt(target.fsp, 0, 6)), true
+		}
+		if targetFamily == db.FamilyMySQL && target.kind == kindDate {
+			return "", false
+		}
+		return "CURRENT_TIMESTAMP", true
+	}
+
+	switch {
+	case target.kind == kindBool:
+		var truthy bool
+		switch strings.ToLower(value) {
+		case "1", "true", "t", "y", "yes":
+			truthy = true
+		case "0", "false", "f", "n", "no":
+			truthy = false
+		default:
+			return "", false
+		}
+		if targetFamily == db.FamilyPostgres {
+			return strings.ToUpper(strconv.FormatBool(truthy)), true
+		}
+		if truthy {
+			return "1", true
+		}
+		return "0", true
+	case target.isNumeric():
+		if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+			if strings.EqualFold(value, "true") {
+				return "1", true
+			}
+			return "0", true
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", false
+		}
+		return value, true
+	case target.kind == kindText || target.kind == kindJSON || target.kind == kindBlob || target.kind == kindBinary || target.kind == kindVarbinary:
+		// MySQL 不允许 TEXT/BLOB/JSON 列带字面量默认值，二进制默认值也无法可靠转换
+		if targetFamily == db.FamilyMySQL || target.kind != kindText || !isString {
+			return "", false
+		}
+		return stringLiteral(targetFamily, value), true
+	case target.isTemporal():
+		if !isString || targetFamily == db.FamilyOracle || targetFamily == db.FamilyDameng {
+			// Oracle 系的日期字面量依赖 NLS 设置，不做转换
+			return "", false
+		}
+		return stringLiteral(targetFamily, value), true
+	case targetFamily == db.FamilyTDengine:
+		return "", false
+	default:
+		if !isString {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "", false
+			}
+		}
+		return stringLiteral(targetFamily, value), true
+	}
+}
+
+// unwrapDefaultExpr 去掉 SQL Server 默认值外层的括号、PG 的类型转换后缀与 N” 前缀。
+func unwrapDefaultExpr(expr string) string {
+	v := strings.TrimSpace(expr)
+	for len(v) >= 2 && strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")") {
+		v = strings.TrimSpace(v[1 : len(v)-1])
+	}
+	if strings.HasPrefix(v, "'") {
+		if idx := strings.LastIndex(v, "'::"); idx > 0 {
+			v = v[:idx+1]
+		}
+	} else if idx := strings.Index(v, "::"); idx > 0 {
+		v = strings.TrimSpace(v[:idx])
+		for len(v) >= 2 && strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")") {
+			v = strings.TrimSpace(v[1 : len(v)-1])
+		}
+	}
+	if (strings.HasPrefix(v, "N'") || strings.HasPrefix(v, "n'")) && strings.HasSuffix(v, "'") {
+		v = v[1:]
+	}
+	return v
+}
+
+func isCurrentTimestampExpr(lower string) bool {
+	lower = strings.TrimSpace(lower)
+	switch lower {
+	case "now()", "getdate()", "sysdate", "systimestamp", "sysdatetime()", "localtimestamp", "localtime", "current_date", "getutcdate()":
+		return true
+	}
+	return strings.HasPrefix(lower, "current_timestamp") || strings.HasPrefix(lower, "localtimestamp(") || strings.HasPrefix(lower, "now(")
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"testing"
+
+	"GoNavi-Wails/internal/connection"
+)
+
+func TestMapColumnType(t *testing.T) {
+	cases := []struct {
+		source  string
+		target  string
+		typ     string
+		autoInc bool
+		want    string
+	}{
+		// 同一方言族沿用源类型文本
+		{"mysql", "mariadb", "enum('a','b')", false, "enum('a','b')"},
+		{"postgres", "kingbase", "numeric(12,2)", false, "numeric(12,2)"},
+		{"mysql", "mysql", "varchar(10); DROP", false, "LONGTEXT"},
+
+		// MySQL -> 其它方言
+		{"mysql", "postgres", "tinyint(1)", false, "SMALLINT"},
+		{"mysql", "postgres", "int unsigned", false, "BIGINT"},
+		{"mysql", "postgres", "bigint unsigned", false, "NUMERIC(20)"},
+		{"mysql", "postgres", "int(11)", true, "SERIAL"},
+		{"mysql", "postgres", "bigint(20)", true, "BIGSERIAL"},
+		{"mysql", "postgres", "decimal(10,2)", false, "NUMERIC(10,2)"},
+		{"mysql", "postgres", "datetime(3)", false, "TIMESTAMP(3)"},
+		{"mysql", "postgres", "varchar(255)", false, "VARCHAR(255)"},
+		{"mysql", "postgres", "longblob", false, "BYTEA"},
+		{"mysql", "postgres", "json", false, "JSON"},
+		{"mysql", "postgres", "enum('a','b')", false, "VARCHAR(255)"},
+		{"mysql", "sqlserver", "varchar(5000)", false, "NVARCHAR(MAX)"},
+		{"mysql", "sqlserver", "tinyint", false, "SMALLINT"},
+		{"mysql", "sqlserver", "datetime(6)", false, "DATETIME2(6)"},
+		{"mysql", "sqlserver", "double", false, "FLOAT"},
+		{"mysql", "oracle", "varchar(100)", false, "VARCHAR2(100 CHAR)"},
+		{"mysql", "oracle", "text", false, "CLOB"},
+		{"mysql", "oracle", "bigint unsigned", false, "NUMBER(20)"},
+		{"mysql", "oracle", "time", false, "VARCHAR2(32 CHAR)"},
+		{"mysql", "oracle", "datetime", false, "TIMESTAMP"},
+		{"mysql", "dameng", "tinyint unsigned", false, "SMALLINT"},
+		{"mysql", "sqlite", "decimal(10,2)", false, "NUMERIC"},
+		{"mysql", "tdengine", "varchar(5000)", false, "NCHAR(4093)"},
+
+		// PostgreSQL -> 其它方言
+		{"postgres", "mysql", "boolean", false, "TINYINT(1)"},
+		{"postgres", "mysql", "character varying(64)", false, "VARCHAR(64)"},
+		{"postgres", "mysql", "character varying", false, "LONGTEXT"},
+		{"postgres", "mysql", "timestamp(6) with time zone", false, "DATETIME(6)"},
+		{"postgres", "mysql", "uuid", false, "CHAR(36)"},
+		{"postgres", "mysql", "integer[]", false, "LONGTEXT"},
+		{"postgres", "mysql", "numeric", false, "DECIMAL(38,10)"},
+		{"postgres", "mysql", "interval", false, "VARCHAR(64)"},
+		{"postgres", "sqlserver", "timestamptz", false, "DATETIMEOFFSET"},
+		{"postgres", "sqlserver", "bytea", false, "VARBINARY(MAX)"},
+		{"postgres", "oracle", "timestamp(3) with time zone", false, "TIMESTAMP(3) WITH TIME ZONE"},
+
+		// SQL Server -> 其它方言
+		{"sqlserver", "mysql", "bit", false, "TINYINT(1)"},
+		{"sqlserver", "mysql", "tinyint", false, "TINYINT UNSIGNED"},
+		{"sqlserver", "mysql", "nvarchar(max)", false, "LONGTEXT"},
+		{"sqlserver", "mysql", "datetime2(7)", false, "DATETIME(6)"},
+		{"sqlserver", "mysql", "money", false, "DECIMAL(19,4)"},
+		{"sqlserver", "mysql", "timestamp", false, "BINARY(8)"},
+		{"sqlserver", "postgres", "uniqueidentifier", false, "UUID"},
+		{"sqlserver", "postgres", "float", false, "DOUBLE PRECISION"},
+
+		// Oracle -> 其它方言
+		{"oracle", "mysql", "NUMBER(10)", false, "BIGINT"},
+		{"oracle", "mysql", "NUMBER(4)", false, "SMALLINT"},
+		{"oracle", "mysql", "NUMBER(10,2)", false, "DECIMAL(10,2)"},
+		{"oracle", "mysql", "DATE", false, "DATETIME(0)"},
+		{"oracle", "postgres", "VARCHAR2(50 BYTE)", false, "VARCHAR(50)"},
+		{"oracle", "postgres", "TIMESTAMP(6) WITH LOCAL TIME ZONE", false, "TIMESTAMPTZ(6)"},
+		{"oracle", "postgres", "RAW(16)", false, "BYTEA"},
+		{"oracle", "sqlserver", "NUMBER", false, "DECIMAL(38,10)"},
+
+		// SQLite / 未知类型
+		{"sqlite", "mysql", "INTEGER", false, "BIGINT"},
+		{"sqlite", "postgres", "geometry", false, "TEXT"},
+		{"mysql", "sqlserver", "geometry", false, "NVARCHAR(MAX)"},
+	}
+	for _, tc := range cases {
+		col := connection.ColumnDefinition{Name: "c", Type: tc.typ}
+		if got := mapColumnType(tc.source, tc.target, col, tc.autoInc); got != tc.want {
+			t.Fatalf("mapColumnType(%s -> %s, %q, 自增=%v)=%q，期望=%q", tc.source, tc.target, tc.typ, tc.autoInc, got, tc.want)
+		}
+	}
+}
+
+func TestMapColumnDefault(t *testing.T) {
+	cases := []struct {
+		source string
+		target string
+		typ    string
+		def    string // 空串表示没有默认值
+		want   string
+		ok     bool
+	}{
+		{"mysql", "postgres", "tinyint(1)", "1", "1", true},
+		{"mysql", "postgres", "varchar(10)", "it's", "'it''s'", true},
+		{"mysql", "postgres", "datetime", "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", true},
+		{"mysql", "sqlserver", "varchar(10)", "abc", "N'abc'", true},
+		{"mysql", "postgres", "bit(1)", "b'1'", "TRUE", true},
+		{"postgres", "mysql", "boolean", "true", "1", true},
+		{"postgres", "mysql", "character varying(10)", "'draft'::character varying", "'draft'", true},
+		{"postgres", "mysql", "timestamp(3) without time zone", "now()", "CURRENT_TIMESTAMP(3)", true},
+		{"postgres", "mysql", "integer", "nextval('t_id_seq'::regclass)", "", false},
+		{"postgres", "mysql", "text", "'x'::text", "", false},
+		{"sqlserver", "postgres", "bit", "((0))", "FALSE", true},
+		{"sqlserver", "postgres", "nvarchar(10)", "(N'a')", "'a'", true},
+		{"sqlserver", "postgres", "datetime2", "(getdate())", "CURRENT_TIMESTAMP", true},
+		{"sqlserver", "oracle", "datetime2", "('2024-01-01')", "", false},
+		{"mysql", "postgres", "int", "", "", false},
+		{"mysql", "postgres", "int", "NULL", "", false},
+	}
+	for _, tc := range cases {
+		col := connection.ColumnDefinition{Name: "c", Type: tc.typ}
+		if tc.def != "" {
+			col.Default = &tc.def
+		}
+		target := parseColumnType(tc.target, mapColumnType(tc.source, tc.target, col, false))
+		got, ok := mapColumnDefault(tc.source, tc.target, col, target)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("mapColumnDefault(%s -> %s, %q, %q)=(%q, %v)，期望=(%q, %v)", tc.source, tc.target, tc.typ, tc.def, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,546 @@
+package sync
+
+import (
+	"GoNavi-Wails/internal/connection"
+	"GoNavi-Wails/internal/db"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CompareOptions 是对比取值时的容差规则。零值为严格比较，但仍按两端字段类型识别同一取值的不同表示
+// （如 DECIMAL "1.50" 与 numeric 1.5、bool 与 tinyint、[]byte 与字符串、定长字符的尾部空格）。
+type CompareOptions struct {
+	FloatEpsilon  float64 `json:"floatEpsilon,omitempty"`  // 浮点字段允许的绝对误差
+	TimePrecision string  `json:"timePrecision,omitempty"` // 时间比较精度：second / millisecond / microsecond，为空时精确比较
+	Whitespace    string  `json:"whitespace,omitempty"`    // 文本空白规则：trim 忽略首尾空白，collapse 另把连续空白视为一个空格
+	IgnoreCase    bool    `json:"ignoreCase,omitempty"`    // 文本比较忽略大小写
+}
+
+// describe 概括容差规则，用于同步日志，没有规则时为空。
+func (o CompareOptions) describe() string {
+	parts := make([]string, 0, 4)
+	if o.FloatEpsilon > 0 {
+		parts = append(parts, fmt.Sprintf("浮点误差 %g", o.FloatEpsilon))
+	}
+	switch normalizeTimePrecision(o.TimePrecision) {
+	case time.Second:
+		parts = append(parts, "时间精确到秒")
+	case time.Millisecond:
+		parts = append(parts, "时间精确到毫秒")
+	case time.Microsecond:
+		parts = append(parts, "时间精确到微秒")
+	}
+	switch normalizeWhitespaceRule(o.Whitespace) {
+	case "trim":
+		parts = append(parts, "忽略首尾空白")
+	case "collapse":
+		parts = append(parts, "忽略首尾空白与连续空白")
+	}
+	if o.IgnoreCase {
+		parts = append(parts, "忽略大小写")
+	}
+	return strings.Join(parts, "，")
+}
+
+func normalizeTimePrecision(p string) time.Duration {
+	switch strings.ToLower(strings.TrimSpace(p)) {
+	case "s", "second":
+		return time.Second
+	case "ms", "millisecond":
+		return time.Millisecond
+	case "us", "microsecond":
+		return time.Microsecond
+	default:
+		return 0
+	}
+}
+
+func normalizeWhitespaceRule(rule string) string {
+	switch strings.ToLower(strings.TrimSpace(rule)) {
+	case "trim":
+		return "trim"
+	case "collapse":
+		return "collapse"
+	default:
+		return ""
+	}
+}
+
+// compareKind 是按字段类型确定的取值比较方式。
+type compareKind int
+
+const (
+	cmpUnknown compareKind = iota // 没有类型信息，按取值本身比较
+	cmpText
+	cmpFixedChar // 定长字符，忽略尾部空格
+	cmpUUID      // 忽略大小写
+	cmpExact     // 整数与定点数，按数值精确比较
+	cmpFloat
+	cmpBool
+	cmpDate      // 只比较日期
+	cmpDateTime  // 无时区的日期时间，比较墙上时间
+	cmpTimestamp // 带时区的时间点，比较绝对时刻
+	cmpTime      // 只比较一天中的时间
+	cmpBinary
+	cmpJSON
+)
+
+// compareKindOf 按 parseColumnType 解析出的类型确定比较方式。
+func compareKindOf(ct columnType) compareKind {
+	switch {
+	case ct.kind == kindUnknown:
+		return cmpUnknown
+	case ct.kind == kindBool:
+		return cmpBool
+	case ct.isInteger() || ct.kind == kindDecimal:
+		return cmpExact
+	case ct.kind == kindFloat || ct.kind == kindDouble:
+		return cmpFloat
+	case ct.kind == kindChar:
+		return cmpFixedChar
+	case ct.kind == kindUUID:
+		return cmpUUID
+	case ct.kind == kindBinary || ct.kind == kindVarbinary || ct.kind == kindBlob:
+		return cmpBinary
+	case ct.kind == kindDate:
+		return cmpDate
+	case ct.kind == kindTime:
+		return cmpTime
+	case ct.kind == kindDateTime:
+		return cmpDateTime
+	case ct.kind == kindTimestampTZ:
+		return cmpTimestamp
+	case ct.kind == kindJSON:
+		return cmpJSON
+	default:
+		return cmpText
+	}
+}
+
+// combineCompareKinds 确定两端类型不同的字段按哪种方式比较，如 bool 与 tinyint 按布尔值、
+// 定点数与浮点数按浮点数、一端不带时区时按墙上时间比较。
+func combineCompareKinds(a compareKind, b compareKind) compareKind {
+	switch {
+	case a == b:
+		return a
+	case a == cmpUnknown:
+		return b
+	case b == cmpUnknown:
+		return a
+	}
+	has := func(k compareKind) bool { return a == k || b == k }
+	isNumeric := func(k compareKind) bool { return k == cmpExact || k == cmpFloat }
+	isDateTime := func(k compareKind) bool { return k == cmpDate || k == cmpDateTime || k == cmpTimestamp }
+	switch {
+	case has(cmpBool) && (isNumeric(a) || isNumeric(b)):
+		return cmpBool
+	case isNumeric(a) && isNumeric(b):
+		return cmpFloat
+	case isDateTime(a) && isDateTime(b):
+		return cmpDateTime
+	case has(cmpJSON):
+		return cmpJSON
+	case has(cmpBinary):
+		return cmpBinary
+	case has(cmpUUID):
+		return cmpUUID
+	case has(cmpFixedChar):
+		return cmpFixedChar
+	default:
+		return cmpText
+	}
+}
+
+// valueComparer 按字段类型与容差规则比较源行与目标行。nil 表示没有类型信息，按取值本身比较。
+type valueComparer struct {
+	opts      CompareOptions
+	precision time.Duration
+	kinds     map[string]compareKind // 目标字段名（小写）-> 比较方式
+}
+
+// newValueComparer 由两端字段类型（字段名均为目标字段名）构建比较器。
+func newValueComparer(opts CompareOptions, source tableSide, target tableSide) *valueComparer {
+	c := &valueComparer{
+		opts:      opts,
+		precision: normalizeTimePrecision(opts.TimePrecision),
+		kinds:     make(map[string]compareKind, len(source.columnTypes)),
+	}
+	for name, typ := range source.columnTypes {
+		c.kinds[name] = compareKindOf(parseColumnType(source.dialect, typ))
+	}
+	for name, typ := range target.columnTypes {
+		c.kinds[name] = combineCompareKinds(c.kinds[name], compareKindOf(parseColumnType(target.dialect, typ)))
+	}
+	return c
+}
+
+// columnTypes 返回映射到目标表的字段类型（小写目标字段名 -> 源字段类型）。
+// 设置了取值转换的字段不再保留源类型，按取值本身比较。
+func (m *tableMapping) columnTypes(cols []connection.ColumnDefinition) map[string]string {
+	types := columnTypeMap(m.targetColumns(cols))
+	for _, t := range m.transforms {
+		delete(types, strings.ToLower(t.column))
+	}
+	return types
+}
+
+func columnTypeMap(cols []connection.ColumnDefinition) map[string]string {
+	types := make(map[string]string, len(cols))
+	for _, col := range cols {
+		if name := strings.TrimSpace(col.Name); name != "" {
+			types[strings.ToLower(name)] = col.Type
+		}
+	}
+	return types
+}
+
+// loadColumnTypes 读取目标表的字段类型，目标表不存在或读取失败时返回 nil（按取值本身比较）。
+func loadColumnTypes(inst db.Database, schema string, table string) map[string]string {
+	cols, err := inst.GetColumns(schema, table)
+	if err != nil || len(cols) == 0 {
+		return nil
+	}
+	return columnTypeMap(cols)
+}
+
+// changedColumns 返回源行中与目标行取值不同的列，按列名排序。
+func (c *valueComparer) changedColumns(sRow map[string]interface{}, tRow map[string]interface{}) []string {
+	changed := make([]string, 0)
+	for k, v := range sRow {
+		if !c.equal(k, v, tRow[k]) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// equal 比较同一字段在两端的取值。
+func (c *valueComparer) equal(column string, a interface{}, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	kind := cmpUnknown
+	if c != nil {
+		kind = c.kinds[strings.ToLower(column)]
+	}
+	switch kind {
+	case cmpBool:
+		if x, ok := compareBool(a); ok {
+			if y, ok := compareBool(b); ok {
+				return x == y
+			}
+		}
+	case cmpExact:
+		if x, ok := compareRat(a); ok {
+			if y, ok := compareRat(b); ok {
+				return x.Cmp(y) == 0
+			}
+		}
+	case cmpFloat:
+		if eq, ok := c.floatEqual(a, b); ok {
+			return eq
+		}
+	case cmpDate, cmpDateTime, cmpTimestamp, cmpTime:
+		if x, ok := compareTime(a); ok {
+			if y, ok := compareTime(b); ok {
+				return c.timeEqual(kind, x, y)
+			}
+		}
+	case cmpBinary:
+		return bytes.Equal(compareBytes(a), compareBytes(b))
+	case cmpJSON:
+		var x, y interface{}
+		if json.Unmarshal(compareBytes(a), &x) == nil && json.Unmarshal(compareBytes(b), &y) == nil {
+			return reflect.DeepEqual(x, y)
+		}
+	case cmpUUID:
+		return strings.EqualFold(strings.TrimSpace(compareText(a)), strings.TrimSpace(compareText(b)))
+	case cmpFixedChar:
+		return c.textEqual(strings.TrimRight(compareText(a), " "), strings.TrimRight(compareText(b), " "))
+	case cmpText:
+		return c.textEqual(compareText(a), compareText(b))
+	default:
+		return c.untypedEqual(a, b)
+	}
+	// 取值无法按字段类型解析时按文本比较
+	return c.textEqual(compareText(a), compareText(b))
+}
+
+// rowKey 生成用于匹配两端行的键字符串：键值先按字段类型归一化，使同一取值的不同表示
+// （如 DECIMAL "1.50" 与 1.5、不同时区表示的同一时刻、UUID 大小写）得到相同的键，再按 matchKey.rowKey 编码。
+func (c *valueComparer) rowKey(key matchKey, row map[string]interface{}) (string, bool) {
+	normalized := make(map[string]interface{}, len(key.columns))
+	for _, col := range key.columns {
+		normalized[col] = c.keyValue(col, row[col])
+	}
+	return key.rowKey(normalized)
+}
+
+// keyValue 把键值转换为与取值表示无关的形式，无法按字段类型解析时保持原值。
+// 文本键不套用空白与大小写容差，避免两行合并为同一个键。
+func (c *valueComparer) keyValue(column string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	kind := cmpUnknown
+	if c != nil {
+		kind = c.kinds[strings.ToLower(column)]
+	}
+	switch kind {
+	case cmpBool:
+		if b, ok := compareBool(v); ok {
+			if b {
+				return "1"
+			}
+			return "0"
+		}
+	case cmpExact, cmpFloat:
+		if r, ok := compareRat(v); ok {
+			return r.RatString()
+		}
+	case cmpDate, cmpDateTime, cmpTimestamp, cmpTime:
+		if t, ok := compareTime(v); ok {
+			return c.keyTime(kind, t)
+		}
+	case cmpUUID:
+		return strings.ToLower(strings.TrimSpace(compareText(v)))
+	case cmpFixedChar:
+		return strings.TrimRight(compareText(v), " ")
+	case cmpBinary:
+		return string(compareBytes(v))
+	case cmpUnknown:
+		if t, ok := v.(time.Time); ok {
+			return c.keyTime(cmpTimestamp, t)
+		}
+		if isGoNumber(v) {
+			if r, ok := compareRat(v); ok {
+				return r.RatString()
+			}
+		}
+	}
+	return v
+}
+
+// keyTime 按 timeEqual 的规则把时间键格式化为文本：带时区的时刻换算到 UTC，其余只保留墙上时间。
+func (c *valueComparer) keyTime(kind compareKind, t time.Time) string {
+	if kind == cmpTimestamp {
+		t = t.UTC()
+	} else {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	if c != nil && c.precision > 0 {
+		t = t.Truncate(c.precision)
+	}
+	switch kind {
+	case cmpDate:
+		return t.Format("2006-01-02")
+	case cmpTime:
+		return t.Format("15:04:05.999999999")
+	default:
+		return t.Format("2006-01-02 15:04:05.999999999")
+	}
+}
+
+// untypedEqual 在没有字段类型时比较：时间按时刻比较，数值与数值文本按数值比较，其余按文本比较。
+func (c *valueComparer) untypedEqual(a interface{}, b interface{}) bool {
+	if x, ok := a.(time.Time); ok {
+		if y, ok := compareTime(b); ok {
+			return c.timeEqual(cmpTimestamp, x, y)
+		}
+	}
+	if y, ok := b.(time.Time); ok {
+		if x, ok := compareTime(a); ok {
+			return c.timeEqual(cmpTimestamp, x, y)
+		}
+	}
+	if isGoNumber(a) || isGoNumber(b) {
+		if x, ok := compareRat(a); ok {
+			if y, ok := compareRat(b); ok {
+				return x.Cmp(y) == 0
+			}
+		}
+	}
+	return c.textEqual(compareText(a), compareText(b))
+}
+
+func (c *valueComparer) floatEqual(a interface{}, b interface{}) (bool, bool) {
+	x, okA := compareFloat(a)
+	y, okB := compareFloat(b)
+	if !okA || !okB {
+		return false, false
+	}
+	// 单精度取值换算成 float64 后末位不同，按单精度比较
+	_, singleA := a.(float32)
+	_, singleB := b.(float32)
+	if singleA || singleB {
+		x, y = float64(float32(x)), float64(float32(y))
+	}
+	if c != nil && c.opts.FloatEpsilon > 0 {
+		return math.Abs(x-y) <= c.opts.FloatEpsilon, true
+	}
+	return x == y, true
+}
+
+func (c *valueComparer) timeEqual(kind compareKind, x time.Time, y time.Time) bool {
+	if kind != cmpTimestamp {
+		// 墙上时间：去掉时区，只比较各字段
+		x = time.Date(x.Year(), x.Month(), x.Day(), x.Hour(), x.Minute(), x.Second(), x.Nanosecond(), time.UTC)
+		y = time.Date(y.Year(), y.Month(), y.Day(), y.Hour(), y.Minute(), y.Second(), y.Nanosecond(), time.UTC)
+	}
+	switch kind {
+	case cmpDate:
+		return x.Year() == y.Year() && x.YearDay() == y.YearDay()
+	case cmpTime:
+		x = time.Date(2000, 1, 1, x.Hour(), x.Minute(), x.Second(), x.Nanosecond(), time.UTC)
+		y = time.Date(2000, 1, 1, y.Hour(), y.Minute(), y.Second(), y.Nanosecond(), time.UTC)
+	}
+	if c != nil && c.precision > 0 {
+		x, y = x.Truncate(c.precision), y.Truncate(c.precision)
+	}
+	return x.Equal(y)
+}
+
+func (c *valueComparer) textEqual(x string, y string) bool {
+	if c == nil {
+		return x == y
+	}
+	switch normalizeWhitespaceRule(c.opts.Whitespace) {
+	case "trim":
+		x, y = strings.TrimSpace(x), strings.TrimSpace(y)
+	case "collapse":
+		x, y = strings.Join(strings.Fields(x), " "), strings.Join(strings.Fields(y), " ")
+	}
+	if c.opts.IgnoreCase {
+		return strings.EqualFold(x, y)
+	}
+	return x == y
+}
+
+func compareText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func compareBytes(v interface{}) []byte {
+	switch val := v.(type) {
+	case []byte:
+		return val
+	case string:
+		return []byte(val)
+	default:
+		return []byte(fmt.Sprintf("%v", val))
+	}
+}
+
+func isGoNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func compareRat(v interface{}) (*big.Rat, bool) {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return big.NewRat(1, 1), true
+		}
+		return new(big.Rat), true
+	case float32:
+		return new(big.Rat).SetString(fmt.Sprintf("%v", val))
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(fmt.Sprintf("%v", val))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return new(big.Rat).SetString(fmt.Sprintf("%d", val))
+	case string, []byte:
+		return new(big.Rat).SetString(strings.TrimSpace(compareText(val)))
+	default:
+		return nil, false
+	}
+}
+
+func compareFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	}
+	r, ok := compareRat(v)
+	if !ok {
+		return 0, false
+	}
+	f, _ := r.Float64()
+	return f, true
+}
+
+func compareBool(v interface{}) (bool, bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case []byte:
+		// BIT(1) 以单字节返回
+		if len(val) == 1 && val[0] <= 1 {
+			return val[0] == 1, true
+		}
+	}
+	if isGoNumber(v) {
+		r, _ := compareRat(v)
+		return r.Sign() != 0, true
+	}
+	switch strings.ToLower(strings.TrimSpace(compareText(v))) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, true
+	case "0", "f", "false", "n", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+var compareTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+}
+
+func compareTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string, []byte:
+		text := strings.TrimSpace(compareText(val))
+		for _, layout := range compareTimeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
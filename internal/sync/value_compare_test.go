@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestComparer(opts CompareOptions, sourceDialect, sourceType, targetDialect, targetType string) *valueComparer {
+	source := tableSide{dialect: sourceDialect, columnTypes: map[string]string{"c": sourceType}}
+	target := tableSide{dialect: targetDialect, columnTypes: map[string]string{"c": targetType}}
+	return newValueComparer(opts, source, target)
+}
+
+func TestValueComparerEqual(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	ts := func(loc *time.Location, hour, nsec int) time.Time {
+		return time.Date(2024, 3, 1, hour, 0, 0, nsec, loc)
+	}
+	cases := []struct {
+		name       string
+		opts       CompareOptions
+		srcDialect string
+		srcType    string
+		tgtDialect string
+		tgtType    string
+		a, b       interface{}
+		want       bool
+	}{
+		// 数值：定点数按数值精确比较，忽略标度差异
+		{"定点数标度不同", CompareOptions{}, "mysql", "decimal(10,2)", "postgres", "numeric(10,3)", "1.50", "1.500", true},
+		{"定点数取值不同", CompareOptions{}, "mysql", "decimal(10,2)", "postgres", "numeric(10,2)", "1.50", "1.51", false},
+		{"定点数与浮点数", CompareOptions{}, "mysql", "decimal(10,2)", "postgres", "double precision", "0.1", 0.1, true},
+		{"大整数不经浮点数比较", CompareOptions{}, "mysql", "bigint", "postgres", "bigint", int64(9007199254740993), "9007199254740992", false},
+		{"整数与整数文本", CompareOptions{}, "mysql", "int", "postgres", "integer", int64(42), []byte("42"), true},
+		{"单精度按单精度比较", CompareOptions{}, "mysql", "float", "postgres", "double precision", float32(0.1), 0.1, true},
+		{"浮点数严格比较", CompareOptions{}, "mysql", "double", "postgres", "double precision", 1.0, 1.0000001, false},
+		{"浮点数容差", CompareOptions{FloatEpsilon: 1e-6}, "mysql", "double", "postgres", "double precision", 1.0, 1.0000001, true},
+
+		// 布尔：bool 与 tinyint、BIT(1)
+		{"布尔与整数 1", CompareOptions{}, "postgres", "boolean", "mysql", "tinyint(1)", true, int64(1), true},
+		{"布尔与整数 0", CompareOptions{}, "postgres", "boolean", "mysql", "tinyint(1)", true, int64(0), false},
+		{"BIT(1) 字节", CompareOptions{}, "mysql", "bit(1)", "postgres", "boolean", []byte{1}, true, true},
+		{"布尔文本", CompareOptions{}, "sqlserver", "bit", "postgres", "boolean", "t", true, true},
+
+		// 时间：无时区比较墙上时间，带时区比较时刻
+		{"日期时间比较墙上时间", CompareOptions{}, "mysql", "datetime", "postgres", "timestamp", ts(time.UTC, 10, 0), ts(cst, 10, 0), true},
+		{"带时区比较时刻", CompareOptions{}, "postgres", "timestamptz", "sqlserver", "datetimeoffset", ts(time.UTC, 10, 0), ts(cst, 18, 0), true},
+		{"带时区时刻不同", CompareOptions{}, "postgres", "timestamptz", "sqlserver", "datetimeoffset", ts(time.UTC, 10, 0), ts(cst, 10, 0), false},
+		{"一端不带时区按墙上时间", CompareOptions{}, "postgres", "timestamptz", "mysql", "datetime", ts(time.UTC, 10, 0), ts(cst, 10, 0), true},
+		{"小数秒精确比较", CompareOptions{}, "mysql", "datetime(6)", "mysql", "datetime(3)", ts(time.UTC, 10, 123456000), ts(time.UTC, 10, 123000000), false},
+		{"按毫秒比较", CompareOptions{TimePrecision: "millisecond"}, "mysql", "datetime(6)", "mysql", "datetime(3)", ts(time.UTC, 10, 123456000), ts(time.UTC, 10, 123000000), true},
+		{"按秒比较", CompareOptions{TimePrecision: "s"}, "mysql", "datetime(6)", "oracle", "DATE", ts(time.UTC, 10, 999999000), ts(time.UTC, 10, 0), true},
+		{"按秒比较不进位", CompareOptions{TimePrecision: "second"}, "mysql", "datetime(6)", "mysql", "datetime", ts(time.UTC, 10, 999999000), "2024-03-01 10:00:01", false},
+		{"日期与文本", CompareOptions{}, "mysql", "date", "postgres", "date", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "2024-03-01", true},
+		{"日期忽略时分秒", CompareOptions{}, "mysql", "date", "postgres", "date", ts(time.UTC, 10, 0), "2024-03-01", true},
+		{"时间与文本", CompareOptions{}, "mysql", "time", "postgres", "time", time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC), "10:00:00", true},
+
+		// 文本与二进制
+		{"字节与字符串", CompareOptions{}, "mysql", "varchar(10)", "postgres", "varchar(10)", []byte("abc"), "abc", true},
+		{"文本区分大小写", CompareOptions{}, "mysql", "varchar(10)", "postgres", "text", "abc", "ABC", false},
+		{"文本忽略大小写", CompareOptions{IgnoreCase: true}, "mysql", "varchar(10)", "postgres", "text", "abc", "ABC", true},
+		{"文本忽略首尾空白", CompareOptions{Whitespace: "trim"}, "mysql", "text", "postgres", "text", " a  b ", "a  b", true},
+		{"文本合并连续空白", CompareOptions{Whitespace: "collapse"}, "mysql", "text", "postgres", "text", " a  b ", "a b", true},
+		{"定长字符忽略尾部空格", CompareOptions{}, "postgres", "char(5)", "mysql", "varchar(5)", "ab   ", "ab", true},
+		{"定长字符不忽略首部空格", CompareOptions{}, "postgres", "char(5)", "mysql", "varchar(5)", "  ab", "ab", false},
+		{"二进制与字符串", CompareOptions{}, "mysql", "varbinary(4)", "postgres", "bytea", "ab", []byte("ab"), true},
+		{"二进制取值不同", CompareOptions{}, "mysql", "blob", "postgres", "bytea", []byte{1, 2}, []byte{1, 3}, false},
+		{"UUID 忽略大小写", CompareOptions{}, "postgres", "uuid", "sqlserver", "uniqueidentifier", "6f9619ff-8b86-d011-b42d-00c04fc964ff", "6F9619FF-8B86-D011-B42D-00C04FC964FF", true},
+		{"JSON 忽略键顺序与空白", CompareOptions{}, "mysql", "json", "postgres", "jsonb", `{"a":1,"b":[1,2]}`, []byte(`{"b": [1, 2], "a": 1}`), true},
+		{"JSON 取值不同", CompareOptions{}, "mysql", "json", "postgres", "jsonb", `{"a":1}`, `{"a":"1"}`, false},
+
+		// NULL
+		{"NULL 与空串", CompareOptions{}, "mysql", "varchar(10)", "postgres", "text", nil, "", false},
+		{"NULL 与 NULL", CompareOptions{}, "mysql", "varchar(10)", "postgres", "text", nil, nil, true},
+	}
+	for _, tc := range cases {
+		c := newTestComparer(tc.opts, tc.srcDialect, tc.srcType, tc.tgtDialect, tc.tgtType)
+		if got := c.equal("C", tc.a, tc.b); got != tc.want {
+			t.Fatalf("%s：equal(%#v, %#v)=%v，期望=%v", tc.name, tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestValueComparerWithoutTypes(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{int64(1), "1", true},
+		{int64(1), "1.0", true},
+		{"1.0", "1", false},
+		{[]byte("abc"), "abc", true},
+		{at, "2024-03-01T10:00:00Z", true},
+		{"2024-03-01 18:00:00 +0800", at, true},
+		{at, "not a time", false},
+		{true, "true", true},
+	}
+	var c *valueComparer
+	for _, tc := range cases {
+		if got := c.equal("c", tc.a, tc.b); got != tc.want {
+			t.Fatalf("无类型信息时 equal(%#v, %#v)=%v，期望=%v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestValueComparerChangedColumns(t *testing.T) {
+	source := tableSide{dialect: "mysql", columnTypes: map[string]string{"id": "int", "price": "decimal(10,2)", "name": "varchar(10)"}}
+	target := tableSide{dialect: "postgres", columnTypes: map[string]string{"id": "integer", "price": "numeric(10,2)", "name": "varchar(10)"}}
+	c := newValueComparer(CompareOptions{}, source, target)
+
+	sRow := map[string]interface{}{"id": int64(1), "price": "9.90", "name": "b", "note": "x"}
+	tRow := map[string]interface{}{"id": int32(1), "price": 9.9, "name": "a", "note": "x"}
+	got := c.changedColumns(sRow, tRow)
+	if len(got) != 1 || got[0] != "name" {
+		t.Fatalf("变化的字段不正确：%v", got)
+	}
+}
+
+func TestValueComparerRowKey(t *testing.T) {
+	cases := []struct {
+		name          string
+		sourceDialect string
+		sourceType    string
+		targetDialect string
+		targetType    string
+		a             interface{}
+		b             interface{}
+	}{
+		{"定点数尾部零", "mysql", "decimal(10,2)", "postgres", "numeric", "1.50", 1.5},
+		{"整数与整数文本", "postgres", "bigint", "oracle", "NUMBER(19)", int64(42), "42"},
+		{"不同时区的同一时刻", "postgres", "timestamptz", "postgres", "timestamptz", time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600))},
+		{"无时区的日期时间按墙上时间", "mysql", "datetime", "postgres", "timestamp", time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local), "2024-03-01 08:00:00"},
+		{"UUID 大小写", "postgres", "uuid", "sqlserver", "uniqueidentifier", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11"},
+		{"定长字符尾部空格", "oracle", "CHAR(5)", "mysql", "char(5)", "ab   ", "ab"},
+	}
+	key := matchKey{columns: []string{"c"}}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cmp := newTestComparer(CompareOptions{}, c.sourceDialect, c.sourceType, c.targetDialect, c.targetType)
+			x, okA := cmp.rowKey(key, map[string]interface{}{"c": c.a})
+			y, okB := cmp.rowKey(key, map[string]interface{}{"c": c.b})
+			if !okA || !okB || x != y {
+				t.Fatalf("同一取值应得到相同的键：%q(%v) 与 %q(%v)", x, okA, y, okB)
+			}
+		})
+	}
+
+	// 文本键不套用大小写容差，大小写不同的两行仍是不同的键
+	cmp := newTestComparer(CompareOptions{IgnoreCase: true}, "mysql", "varchar(10)", "postgres", "text")
+	x, _ := cmp.rowKey(key, map[string]interface{}{"c": "Ab"})
+	y, _ := cmp.rowKey(key, map[string]interface{}{"c": "ab"})
+	if x == y {
+		t.Fatalf("文本键不应忽略大小写，实际都为 %q", x)
+	}
+	if _, ok := cmp.rowKey(key, map[string]interface{}{"c": nil}); ok {
+		t.Fatalf("空键值应返回 false")
+	}
+}
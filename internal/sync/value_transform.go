@@ -0,0 +1,488 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// compileTransform 解析转换规则，返回可对每行求值的转换。
+func compileTransform(t ColumnTransform) (compiledTransform, error) {
+	column := strings.TrimSpace(t.Column)
+	if column == "" {
+		return compiledTransform{}, fmt.Errorf("未指定字段")
+	}
+	kind := strings.ToLower(strings.TrimSpace(t.Type))
+	out := compiledTransform{column: column, kind: kind}
+
+	switch kind {
+	case "constant":
+		value := t.Value
+		out.eval = func(map[string]interface{}, interface{}) (interface{}, error) { return value, nil }
+	case "expression":
+		expr, err := parseTransformExpr(t.Value)
+		if err != nil {
+			return compiledTransform{}, err
+		}
+		out.eval = func(source map[string]interface{}, _ interface{}) (interface{}, error) { return expr.eval(source) }
+	case "mask":
+		mask, err := compileMask(t.Value)
+		if err != nil {
+			return compiledTransform{}, err
+		}
+		out.eval = func(_ map[string]interface{}, current interface{}) (interface{}, error) { return mask(current), nil }
+	case "cast":
+		target := strings.ToLower(strings.TrimSpace(t.Value))
+		if !castTargets[target] {
+			return compiledTransform{}, fmt.Errorf("不支持转换为类型 %q", t.Value)
+		}
+		out.eval = func(_ map[string]interface{}, current interface{}) (interface{}, error) {
+			return castValue(current, target)
+		}
+	default:
+		return compiledTransform{}, fmt.Errorf("未知转换类型 %q", t.Type)
+	}
+	return out, nil
+}
+
+// transformText 把值转为文本，用于字符串函数、脱敏与类型转换。
+func transformText(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Nanosecond() == 0 {
+			return val.Format("2006-01-02")
+		}
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// compileMask 解析脱敏规则：空或 all 全部替换为 *；keep:前,后 保留首尾若干字符；
+// email 只保留用户名首字符与域名；hash 替换为 SHA-256 十六进制摘要。NULL 保持不变。
+func compileMask(spec string) (func(interface{}) interface{}, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	keep := func(head, tail int) func(interface{}) interface{} {
+		return func(v interface{}) interface{} {
+			if v == nil {
+				return nil
+			}
+			runes := []rune(transformText(v))
+			for i := range runes {
+				if i >= head && i < len(runes)-tail {
+					runes[i] = '*'
+				}
+			}
+			return string(runes)
+		}
+	}
+
+	switch {
+	case spec == "" || spec == "all":
+		return keep(0, 0), nil
+	case spec == "hash":
+		return func(v interface{}) interface{} {
+			if v == nil {
+				return nil
+			}
+			sum := sha256.Sum256([]byte(transformText(v)))
+			return hex.EncodeToString(sum[:])
+		}, nil
+	case spec == "email":
+		return func(v interface{}) interface{} {
+			if v == nil {
+				return nil
+			}
+			text := transformText(v)
+			at := strings.LastIndex(text, "@")
+			if at <= 0 {
+				return keep(1, 0)(text)
+			}
+			return keep(1, 0)(text[:at]).(string) + text[at:]
+		}, nil
+	case strings.HasPrefix(spec, "keep:"):
+		parts := strings.Split(strings.TrimPrefix(spec, "keep:"), ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("脱敏规则应为 keep:前保留位数,后保留位数")
+		}
+		head, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+		tail, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err1 != nil || err2 != nil || head < 0 || tail < 0 {
+			return nil, fmt.Errorf("脱敏规则 %q 中的位数无效", spec)
+		}
+		return keep(head, tail), nil
+	default:
+		return nil, fmt.Errorf("未知脱敏规则 %q", spec)
+	}
+}
+
+var castTargets = map[string]bool{"string": true, "int": true, "float": true, "bool": true, "date": true, "datetime": true}
+
+var castDateLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"20060102",
+}
+
+// castValue 把值转换为指定类型，NULL 保持不变，空字符串转换为数值、日期时视为 NULL。
+func castValue(v interface{}, target string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if target == "string" {
+		return transformText(v), nil
+	}
+	if t, ok := v.(time.Time); ok {
+		switch target {
+		case "date":
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+		case "datetime":
+			return t, nil
+		}
+	}
+
+	text := strings.TrimSpace(transformText(v))
+	if text == "" {
+		return nil, nil
+	}
+	switch target {
+	case "int":
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是整数", text)
+		}
+		return int64(f), nil
+	case "float":
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是数值", text)
+		}
+		return f, nil
+	case "bool":
+		switch strings.ToLower(text) {
+		case "1", "true", "t", "yes", "y", "on":
+			return true, nil
+		case "0", "false", "f", "no", "n", "off":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q 不是布尔值", text)
+	default:
+		for _, layout := range castDateLayouts {
+			t, err := time.ParseInLocation(layout, text, time.Local)
+			if err != nil {
+				continue
+			}
+			if target == "date" {
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("%q 不是有效的日期时间", text)
+	}
+}
+
+// transformExpr 是表达式转换的语法树：字面量、源字段引用或函数调用。
+type transformExpr struct {
+	literal  interface{}
+	isLit    bool
+	column   string
+	function string
+	args     []*transformExpr
+}
+
+var transformFuncs = map[string]func(args []interface{}) (interface{}, error){
+	"upper": stringFunc(strings.ToUpper),
+	"lower": stringFunc(strings.ToLower),
+	"trim":  stringFunc(strings.TrimSpace),
+	"concat": func(args []interface{}) (interface{}, error) {
+		var b strings.Builder
+		for _, a := range args {
+			b.WriteString(transformText(a))
+		}
+		return b.String(), nil
+	},
+	"coalesce": func(args []interface{}) (interface{}, error) {
+		for _, a := range args {
+			if a != nil {
+				return a, nil
+			}
+		}
+		return nil, nil
+	},
+	"replace": func(args []interface{}) (interface{}, error) {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("replace 需要 3 个参数")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		return strings.ReplaceAll(transformText(args[0]), transformText(args[1]), transformText(args[2])), nil
+	},
+	"substr": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 && len(args) != 3 {
+			return nil, fmt.Errorf("substr 需要 2 或 3 个参数")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		runes := []rune(transformText(args[0]))
+		start, err := intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		// 与 SQL 一致，起始位置从 1 开始
+		from := start - 1
+		if from < 0 {
+			from = 0
+		}
+		if from > len(runes) {
+			from = len(runes)
+		}
+		to := len(runes)
+		if len(args) == 3 {
+			n, err := intArg(args[2])
+			if err != nil {
+				return nil, err
+			}
+			if from+n < to {
+				to = from + n
+			}
+		}
+		if to < from {
+			to = from
+		}
+		return string(runes[from:to]), nil
+	},
+	"left": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("left 需要 2 个参数")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		runes := []rune(transformText(args[0]))
+		n, err := intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if n < len(runes) {
+			runes = runes[:max(n, 0)]
+		}
+		return string(runes), nil
+	},
+	"right": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("right 需要 2 个参数")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		runes := []rune(transformText(args[0]))
+		n, err := intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if n < len(runes) {
+			runes = runes[len(runes)-max(n, 0):]
+		}
+		return string(runes), nil
+	},
+}
+
+func stringFunc(fn func(string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("需要 1 个参数")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		return fn(transformText(args[0])), nil
+	}
+}
+
+func intArg(v interface{}) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(transformText(v)))
+	if err != nil {
+		return 0, fmt.Errorf("%v 不是整数", v)
+	}
+	return n, nil
+}
+
+func (e *transformExpr) eval(source map[string]interface{}) (interface{}, error) {
+	switch {
+	case e.isLit:
+		return e.literal, nil
+	case e.function == "":
+		name := mappedColumnName(source, e.column)
+		v, ok := source[name]
+		if !ok {
+			return nil, fmt.Errorf("源表没有字段 %s", e.column)
+		}
+		return v, nil
+	}
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		v, err := arg.eval(source)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := transformFuncs[e.function](args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.function, err)
+	}
+	return v, nil
+}
+
+// parseTransformExpr 解析表达式：字符串用单引号（连续两个单引号表示一个单引号），字段名可用双引号或反引号包裹，
+// 支持数字、null 与函数 upper/lower/trim/concat/coalesce/replace/substr/left/right。
+func parseTransformExpr(text string) (*transformExpr, error) {
+	p := &exprParser{src: []rune(text)}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("表达式第 %d 个字符附近有多余内容", p.pos+1)
+	}
+	return expr, nil
+}
+
+type exprParser struct {
+	src []rune
+	pos int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) parseExpr() (*transformExpr, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	ch := p.src[p.pos]
+	switch {
+	case ch == '\'':
+		s, err := p.parseQuoted('\'')
+		if err != nil {
+			return nil, err
+		}
+		return &transformExpr{literal: s, isLit: true}, nil
+	case ch == '"' || ch == '`':
+		name, err := p.parseQuoted(ch)
+		if err != nil {
+			return nil, err
+		}
+		return &transformExpr{column: name}, nil
+	case ch == '-' || unicode.IsDigit(ch):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		text := string(p.src[start:p.pos])
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &transformExpr{literal: n, isLit: true}, nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效数字 %q", text)
+		}
+		return &transformExpr{literal: f, isLit: true}, nil
+	case ch == '_' || unicode.IsLetter(ch):
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || p.src[p.pos] == '$' || unicode.IsLetter(p.src[p.pos]) || unicode.IsDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		name := string(p.src[start:p.pos])
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '(' {
+			return p.parseCall(name)
+		}
+		if strings.EqualFold(name, "null") {
+			return &transformExpr{isLit: true}, nil
+		}
+		return &transformExpr{column: name}, nil
+	default:
+		return nil, fmt.Errorf("表达式第 %d 个字符 %q 无效", p.pos+1, ch)
+	}
+}
+
+func (p *exprParser) parseCall(name string) (*transformExpr, error) {
+	fn := strings.ToLower(name)
+	if _, ok := transformFuncs[fn]; !ok {
+		return nil, fmt.Errorf("不支持函数 %s", name)
+	}
+	p.pos++ // (
+	call := &transformExpr{function: fn}
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == ')' {
+		p.pos++
+		return call, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("函数 %s 缺少右括号", name)
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return call, nil
+		default:
+			return nil, fmt.Errorf("表达式第 %d 个字符 %q 无效", p.pos+1, p.src[p.pos])
+		}
+	}
+}
+
+func (p *exprParser) parseQuoted(quote rune) (string, error) {
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		p.pos++
+		if ch != quote {
+			b.WriteRune(ch)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == quote {
+			b.WriteRune(quote)
+			p.pos++
+			continue
+		}
+		return b.String(), nil
+	}
+	return "", fmt.Errorf("引号未闭合")
+}
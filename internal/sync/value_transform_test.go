@@ -0,0 +1,154 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileTransform(t *testing.T) {
+	row := map[string]interface{}{
+		"First_Name": "Ada",
+		"last_name":  "Lovelace",
+		"email":      "ada@example.com",
+		"phone":      "13812345678",
+		"code":       "  ab-01 ",
+		"nick":       nil,
+		"amount":     "12.7",
+		"born":       time.Date(1815, 12, 10, 0, 0, 0, 0, time.UTC),
+	}
+	cases := []struct {
+		typ   string
+		value string
+		field string
+		want  interface{}
+	}{
+		{"constant", "fixed", "", "fixed"},
+		{"expression", "concat(first_name, ' ', \"last_name\")", "", "Ada Lovelace"},
+		{"expression", "upper(trim(code))", "", "AB-01"},
+		{"expression", "replace(trim(code), '-', '')", "", "ab01"},
+		{"expression", "coalesce(nick, left(first_name, 1), 'x')", "", "A"},
+		{"expression", "substr(last_name, 3, 4)", "", "vela"},
+		{"expression", "substr(last_name, 5)", "", "lace"},
+		{"expression", "right(phone, 4)", "", "5678"},
+		{"expression", "left(phone, 20)", "", "13812345678"},
+		{"expression", "concat('it''s ', `born`)", "", "it's 1815-12-10"},
+		{"expression", "upper(nick)", "", nil},
+		{"expression", "null", "", nil},
+		{"expression", "-1.5", "", -1.5},
+		{"mask", "", "phone", "***********"},
+		{"mask", "keep:3,4", "phone", "138****5678"},
+		{"mask", "keep:0, 20", "phone", "13812345678"},
+		{"mask", "email", "email", "a**@example.com"},
+		{"mask", "email", "phone", "1**********"},
+		{"mask", "hash", "nick", nil},
+		{"cast", "int", "amount", int64(12)},
+		{"cast", "float", "amount", 12.7},
+		{"cast", "string", "born", "1815-12-10"},
+		{"cast", "INT", "nick", nil},
+	}
+	for _, tc := range cases {
+		tr, err := compileTransform(ColumnTransform{Column: "out", Type: tc.typ, Value: tc.value})
+		if err != nil {
+			t.Fatalf("compileTransform(%s %q) 返回错误：%v", tc.typ, tc.value, err)
+		}
+		got, err := tr.eval(row, row[tc.field])
+		if err != nil {
+			t.Fatalf("%s %q 求值失败：%v", tc.typ, tc.value, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s %q 的结果=%#v，期望=%#v", tc.typ, tc.value, got, tc.want)
+		}
+	}
+
+	// hash 输出 SHA-256 十六进制摘要
+	tr, _ := compileTransform(ColumnTransform{Column: "out", Type: "mask", Value: "hash"})
+	got, _ := tr.eval(row, "abc")
+	if got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Fatalf("hash 脱敏结果不正确：%v", got)
+	}
+}
+
+func TestCompileTransformRejectsInvalid(t *testing.T) {
+	invalid := []ColumnTransform{
+		{Column: "", Type: "constant"},
+		{Column: "c", Type: "unknown"},
+		{Column: "c", Type: "cast", Value: "decimal"},
+		{Column: "c", Type: "mask", Value: "keep:1"},
+		{Column: "c", Type: "mask", Value: "keep:-1,2"},
+		{Column: "c", Type: "mask", Value: "stars"},
+		{Column: "c", Type: "expression", Value: ""},
+		{Column: "c", Type: "expression", Value: "upper(name"},
+		{Column: "c", Type: "expression", Value: "md5(name)"},
+		{Column: "c", Type: "expression", Value: "'abc"},
+		{Column: "c", Type: "expression", Value: "name name"},
+		{Column: "c", Type: "expression", Value: "upper(name; x)"},
+	}
+	for _, tr := range invalid {
+		if _, err := compileTransform(tr); err == nil {
+			t.Fatalf("compileTransform(%+v) 应返回错误", tr)
+		}
+	}
+
+	// 运行时错误：字段不存在、参数个数或类型不对
+	row := map[string]interface{}{"name": "x"}
+	for _, expr := range []string{"missing", "upper(name, name)", "left(name, 'a')", "replace(name, 'x')"} {
+		tr, err := compileTransform(ColumnTransform{Column: "c", Type: "expression", Value: expr})
+		if err != nil {
+			t.Fatalf("compileTransform(%q) 返回错误：%v", expr, err)
+		}
+		if _, err := tr.eval(row, nil); err == nil {
+			t.Fatalf("表达式 %q 求值应返回错误", expr)
+		}
+	}
+}
+
+func TestCastValue(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	cases := []struct {
+		in     interface{}
+		target string
+		want   interface{}
+	}{
+		{"42", "int", int64(42)},
+		{[]byte(" 42 "), "int", int64(42)},
+		{"42.9", "int", int64(42)},
+		{"", "int", nil},
+		{"1e3", "float", 1000.0},
+		{"Yes", "bool", true},
+		{int64(0), "bool", false},
+		{at, "date", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{at, "datetime", at},
+		{at, "string", "2024-03-01 10:30:00"},
+		{"2024-03-01 10:30:00", "datetime", time.Date(2024, 3, 1, 10, 30, 0, 0, time.Local)},
+		{"2024/03/01", "date", time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"20240301", "datetime", time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"  ", "date", nil},
+		{nil, "string", nil},
+	}
+	for _, tc := range cases {
+		got, err := castValue(tc.in, tc.target)
+		if err != nil {
+			t.Fatalf("castValue(%#v, %s) 返回错误：%v", tc.in, tc.target, err)
+		}
+		if want, ok := tc.want.(time.Time); ok {
+			if gotTime, ok := got.(time.Time); !ok || !gotTime.Equal(want) {
+				t.Fatalf("castValue(%#v, %s)=%v，期望=%v", tc.in, tc.target, got, want)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("castValue(%#v, %s)=%#v，期望=%#v", tc.in, tc.target, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		in     string
+		target string
+	}{{"abc", "int"}, {"abc", "float"}, {"maybe", "bool"}, {"03/01/2024", "date"}} {
+		if _, err := castValue(tc.in, tc.target); err == nil || !strings.Contains(err.Error(), tc.in) {
+			t.Fatalf("castValue(%q, %s) 应返回包含原值的错误，实际=%v", tc.in, tc.target, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	stdsync "sync"
+	"time"
+)
+
+// TableWatermark 记录增量同步中某张表已同步到的水位。
+type TableWatermark struct {
+	Column    string          `json:"column"`
+	Value     CheckpointValue `json:"value"`
+	UpdatedAt int64           `json:"updatedAt"` // Unix milli
+}
+
+const watermarksFileName = "sync_watermarks.json"
+
+// watermarkFile 的结构：范围（任务或源、目标连接）-> 表 -> 水位。
+type watermarkFile map[string]map[string]TableWatermark
+
+var watermarkMu stdsync.Mutex
+
+func watermarkPath() string {
+	return filepath.Join(DefaultJobStoreDir(), watermarksFileName)
+}
+
+// watermarkScope 返回保存水位的范围：同步任务按任务 ID，临时同步按源、目标连接。
+func watermarkScope(config SyncConfig) string {
+	if id := strings.TrimSpace(config.SyncJobID); id != "" {
+		return "job:" + id
+	}
+	sum := sha256.Sum256([]byte(connFingerprint(config.SourceConfig) + "\n" + connFingerprint(config.TargetConfig)))
+	return "adhoc:" + hex.EncodeToString(sum[:8])
+}
+
+// loadWatermark 读取表的水位，没有记录时返回 false。
+func loadWatermark(scope string, table string) (TableWatermark, bool, error) {
+	watermarkMu.Lock()
+	defer watermarkMu.Unlock()
+	var file watermarkFile
+	if err := readJSONFile(watermarkPath(), &file); err != nil {
+		return TableWatermark{}, false, fmt.Errorf("读取增量水位失败: %w", err)
+	}
+	wm, ok := file[scope][table]
+	return wm, ok, nil
+}
+
+// saveWatermark 保存表的水位。
+func saveWatermark(scope string, table string, wm TableWatermark) error {
+	watermarkMu.Lock()
+	defer watermarkMu.Unlock()
+	var file watermarkFile
+	if err := readJSONFile(watermarkPath(), &file); err != nil {
+		return fmt.Errorf("读取增量水位失败: %w", err)
+	}
+	if file == nil {
+		file = make(watermarkFile)
+	}
+	if file[scope] == nil {
+		file[scope] = make(map[string]TableWatermark)
+	}
+	wm.UpdatedAt = time.Now().UnixMilli()
+	file[scope][table] = wm
+	if err := os.MkdirAll(DefaultJobStoreDir(), 0o700); err != nil {
+		return err
+	}
+	return writeJSONFile(watermarkPath(), file)
+}
+
+// DeleteWatermarks 删除同步任务保存的全部增量水位。
+func DeleteWatermarks(jobID string) error {
+	watermarkMu.Lock()
+	defer watermarkMu.Unlock()
+	var file watermarkFile
+	if err := readJSONFile(watermarkPath(), &file); err != nil {
+		return err
+	}
+	scope := "job:" + strings.TrimSpace(jobID)
+	if _, ok := file[scope]; !ok {
+		return nil
+	}
+	delete(file, scope)
+	return writeJSONFile(watermarkPath(), file)
+}
@@ -32,6 +32,7 @@ func main() {
 		BackgroundColour: &options.RGBA{R: 0, G: 0, B: 0, A: 0},
 		OnStartup:        application.Startup,
 		OnShutdown:       application.Shutdown,
+		OnBeforeClose:    application.BeforeClose,
 		Bind: []interface{}{
 			application,
 		},